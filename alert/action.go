@@ -0,0 +1,251 @@
+package alert
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/hellodex/tradingbot/api"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/queue"
+	"github.com/hellodex/tradingbot/store"
+	"github.com/hellodex/tradingbot/trade"
+	"github.com/hellodex/tradingbot/util"
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cast"
+)
+
+// user alert actions, sort by created time
+func UserActions(chatId int64) []model.AlertAction {
+	data, err := store.UserGetAlertActions(chatId)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return nil
+	}
+
+	actions := make([]model.AlertAction, 0, len(data))
+	for _, v := range data {
+		var a model.AlertAction
+		if err := json.Unmarshal([]byte(v), &a); err != nil {
+			log.Error().Err(err).Send()
+			continue
+		}
+		actions = append(actions, a)
+	}
+	slices.SortFunc(actions, func(a, b model.AlertAction) int {
+		return cmp.Compare(a.CreatedAt, b.CreatedAt)
+	})
+	return actions
+}
+
+func UserAction(chatId int64, id string) (model.AlertAction, bool) {
+	for _, a := range UserActions(chatId) {
+		if a.Id == id {
+			return a, true
+		}
+	}
+	return model.AlertAction{}, false
+}
+
+// RuleAction action attached to rule of token and monitor type
+func RuleAction(chatId int64, chainCode, token, monitorType string) (model.AlertAction, bool) {
+	key := model.AlertOpKey(chainCode, token, monitorType)
+	for _, a := range UserActions(chatId) {
+		if a.RuleKey() == key {
+			return a, true
+		}
+	}
+	return model.AlertAction{}, false
+}
+
+func SaveAction(chatId int64, a model.AlertAction) error {
+	data, err := a.JsonB()
+	if err != nil {
+		return err
+	}
+	return store.UserAddAlertAction(chatId, a.Id, data)
+}
+
+// RemoveRuleAction remove action after its rule is deleted
+func RemoveRuleAction(chatId int64, chainCode, token, monitorType string) {
+	a, ok := RuleAction(chatId, chainCode, token, monitorType)
+	if !ok {
+		return
+	}
+	if err := store.UserDeleteAlertAction(chatId, a.Id); err != nil {
+		log.Error().Err(err).Send()
+	}
+}
+
+// ActionCount executions of action today
+func ActionCount(chatId int64, a model.AlertAction) int64 {
+	day := UserDelivery(chatId).Day(time.Now())
+	n, err := store.UserGetAlertActionCount(chatId, a.Id, day)
+	if err != nil {
+		log.Error().Err(err).Send()
+	}
+	return min(n, int64(a.DailyCap))
+}
+
+// Act run enabled action of rule in background after the rule triggers
+func Act(chatId int64, chainCode, token, monitorType string) {
+	a, ok := RuleAction(chatId, chainCode, token, monitorType)
+	if !ok || !a.Enabled {
+		return
+	}
+	go act(chatId, a)
+}
+
+func notifyAction(b *bot.Bot, chatId int64, key string, args ...any) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	util.QuickMessage(ctx, b, chatId, i18n.L(chatId, key, args...))
+}
+
+func act(chatId int64, a model.AlertAction) {
+	b, ok := pushBot(chatId, 0)
+	if !ok || b == nil {
+		log.Error().Err(errNoSender).Int64("chatId", chatId).Msg("alert action")
+		return
+	}
+	symbol := a.Symbol
+	if symbol == "" {
+		symbol = util.ShortAddress(a.Token)
+	}
+
+	// 先占用当天的次数, 没有下单时退回
+	day := UserDelivery(chatId).Day(time.Now())
+	n, err := store.UserIncrAlertActionCount(chatId, a.Id, day, 1)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+	if n > int64(a.DailyCap) {
+		// 只在第一次超出时提醒
+		if n == int64(a.DailyCap)+1 {
+			notifyAction(b, chatId, "alert.action_cap", symbol, a.DailyCap)
+		}
+		return
+	}
+	release := func() {
+		if _, err := store.UserIncrAlertActionCount(chatId, a.Id, day, -1); err != nil {
+			log.Error().Err(err).Send()
+		}
+	}
+	skip := func(reason string) {
+		release()
+		notifyAction(b, chatId, "alert.action_skip", symbol, reason)
+	}
+
+	userInfo, err := api.GetUserProfile(chatId)
+	if err != nil {
+		log.Error().Err(err).Send()
+		skip(i18n.L(chatId, "common.error"))
+		return
+	}
+	wallet, ok := trade.Wallet(userInfo, a.ChainCode, a.WalletId)
+	if !ok {
+		skip(i18n.L(chatId, "alert.action_reason_wallet"))
+		return
+	}
+
+	pos, err := api.GetPositionByWalletAddress(wallet.Wallet, a.Token, a.ChainCode, userInfo)
+	if err != nil || pos.Data.BaseToken.Address == "" {
+		log.Error().Err(err).Str("token", a.Token).Msg("alert action get pair")
+		skip(i18n.L(chatId, "common.error"))
+		return
+	}
+	baseToken, quoteToken := pos.Data.BaseToken, pos.Data.QuoteToken
+
+	swap := model.Swap{
+		WalletId:   wallet.WalletId,
+		WalletKey:  wallet.WalletKey,
+		Slippage:   userInfo.Data.Slippage,
+		Price:      pos.Data.Price,
+		TradeType:  trade.SwapTradeType,
+		ProfitFlag: 0,
+	}
+
+	amount, _ := decimal.NewFromString(a.Amount)
+	var userInputAmount string
+	if a.IsBuy() {
+		chains, err := api.GetChainConfigs()
+		if err != nil {
+			log.Error().Err(err).Send()
+			skip(i18n.L(chatId, "common.error"))
+			return
+		}
+		// 买入金额按原生币计算
+		if cfg, ok := chains.Get(a.ChainCode); !ok || !cfg.IsNative(quoteToken.Address) {
+			skip(i18n.L(chatId, "alert.action_reason_quote"))
+			return
+		}
+		swap.Type = trade.SwapBuy
+		swap.FromTokenAddress, swap.FromTokenDecimals = quoteToken.Address, cast.ToInt(quoteToken.Decimals)
+		swap.ToTokenAddress, swap.ToTokenDecimals = baseToken.Address, cast.ToInt(baseToken.Decimals)
+		swap.Amount = amount.Shift(int32(swap.FromTokenDecimals)).Truncate(0).String()
+		userInputAmount = amount.String()
+	} else {
+		holding, err := api.GetTokenInfoByWalletAddress(baseToken.Address, wallet.Wallet, a.ChainCode, userInfo)
+		if err != nil {
+			log.Error().Err(err).Send()
+			skip(i18n.L(chatId, "common.error"))
+			return
+		}
+		balance, _ := decimal.NewFromString(holding.Amount)
+		sell := balance
+		if amount.LessThan(decimal.NewFromInt(100)) {
+			sell = balance.Mul(amount).Div(decimal.NewFromInt(100)).Truncate(0)
+		}
+		if !sell.IsPositive() {
+			skip(i18n.L(chatId, "alert.action_reason_balance"))
+			return
+		}
+		swap.Type = trade.SwapSell
+		swap.FromTokenAddress, swap.FromTokenDecimals = baseToken.Address, cast.ToInt(baseToken.Decimals)
+		swap.ToTokenAddress, swap.ToTokenDecimals = quoteToken.Address, cast.ToInt(quoteToken.Decimals)
+		swap.Amount = sell.String()
+		userInputAmount = util.ShiftLeftStr(swap.Amount, baseToken.Decimals)
+	}
+
+	raw, _ := decimal.NewFromString(swap.Amount)
+	if sf, err := trade.Precheck(chatId, userInfo, wallet, pos.Data, a.IsBuy(), raw); err != nil {
+		if errors.Is(err, trade.ErrSafetyBlocked) {
+			release()
+			notifyAction(b, chatId, "safety.blocked", baseToken.Symbol, sf.Score)
+			return
+		}
+		log.Debug().Err(err).Int64("chatId", chatId).Msg("alert action precheck")
+		skip(trade.Reason(chatId, err, baseToken.Symbol, sf))
+		return
+	}
+
+	if a.IsBuy() {
+		notifyAction(b, chatId, "alert.action_buy", baseToken.Symbol, userInputAmount, quoteToken.Symbol, n, a.DailyCap)
+	} else {
+		notifyAction(b, chatId, "alert.action_sell", baseToken.Symbol, userInputAmount, baseToken.Symbol, n, a.DailyCap)
+	}
+
+	// 成交后由交易队列发送带交易链接的确认消息
+	err = queue.AddProcessingSwapQueue(&queue.SwapPayload{
+		B:               b,
+		SwapBody:        swap,
+		BaseToken:       baseToken,
+		QuoteToken:      quoteToken,
+		UserInfo:        userInfo,
+		UserID:          chatId,
+		HandleWallet:    wallet,
+		UserInputAmount: userInputAmount,
+		Auto:            true,
+	})
+	if err != nil {
+		log.Error().Err(err).Int64("chatId", chatId).Msg("add alert action swap")
+		skip(i18n.L(chatId, "common.error"))
+	}
+}
@@ -0,0 +1,542 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hellodex/tradingbot/api"
+	"github.com/hellodex/tradingbot/config"
+	"github.com/hellodex/tradingbot/entity"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/store"
+	"github.com/hellodex/tradingbot/template"
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cast"
+	"github.com/tidwall/gjson"
+)
+
+var (
+	// 从后端重新加载用户的监控
+	reloadInterval = time.Minute
+	// 没有推送的时候主动查询价格
+	pollInterval = 10 * time.Second
+	// 同时加载规则的用户数量
+	loadWorkers = 8
+	// 同时查询价格的代币数量
+	pollWorkers = 8
+)
+
+var chains = []string{"SOLANA", "BSC"}
+
+var ticks = make(chan model.AlertTick, 1024)
+
+type compound struct {
+	ChatId int64
+	Alert  model.CompoundAlert
+}
+
+type engine struct {
+	mu sync.Mutex
+	// rules group by token key
+	rules map[string][]model.AlertRule
+	// last metric of rule, key is chatId + rule key
+	prev map[string]decimal.Decimal
+	// compound alerts group by token key
+	compounds map[string][]compound
+	// last polled tick of token, used by cross condition
+	last map[string]model.AlertTick
+}
+
+var e = &engine{
+	rules:     map[string][]model.AlertRule{},
+	prev:      map[string]decimal.Decimal{},
+	compounds: map[string][]compound{},
+	last:      map[string]model.AlertTick{},
+}
+
+// Enabled local alert engine is on
+func Enabled() bool {
+	return config.Get().Env.LocalAlert
+}
+
+// Saved track user and save op of price rule after monitor is saved to backend
+func Saved(chatId int64, req model.AISubscribeReqData) {
+	if !Enabled() {
+		return
+	}
+	if req.MonitorType == "price" && req.Op != "" {
+		op := req.Op
+		if op == model.AlertAuto {
+			op = ""
+		}
+		field := model.AlertOpKey(req.ChainCode, req.BaseAddress, req.MonitorType)
+		if err := store.UserSetAlertOp(chatId, field, op); err != nil {
+			log.Error().Err(err).Send()
+		}
+	}
+	Track(chatId)
+}
+
+// Track evaluate monitors of user in local engine
+func Track(chatId int64) {
+	if err := store.AddAlertUser(chatId); err != nil {
+		log.Error().Err(err).Send()
+	}
+}
+
+// Feed send tick to engine, dropped if engine is busy
+func Feed(t model.AlertTick) {
+	select {
+	case ticks <- t:
+	default:
+		log.Warn().Str("token", t.Token).Msg("alert tick dropped")
+	}
+}
+
+// Run evaluate rules on ticks until ctx done
+func Run(ctx context.Context) {
+	reload := time.NewTicker(reloadInterval)
+	defer reload.Stop()
+	poll := time.NewTicker(pollInterval)
+	defer poll.Stop()
+
+	// 加载规则和查询价格比较慢, 单独执行不阻塞推送的 tick
+	go func() {
+		e.load()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-reload.C:
+				e.load()
+			}
+		}
+	}()
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-poll.C:
+				e.poll()
+			}
+		}
+	}()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case t := <-ticks:
+			e.feed(t)
+		}
+	}
+}
+
+// user rules of all chains from backend
+func userRules(chatId int64) ([]model.AlertRule, error) {
+	userInfo, err := api.GetUserProfile(chatId)
+	if err != nil {
+		return nil, err
+	}
+	ops, err := store.UserGetAlertOps(chatId)
+	if err != nil {
+		log.Error().Err(err).Send()
+	}
+
+	var rules []model.AlertRule
+	for _, chainCode := range chains {
+		data, err := api.ListUserTokenSubscribe(chainCode, userInfo)
+		if err != nil {
+			return nil, err
+		}
+		raw, ok := data.([]byte)
+		if !ok {
+			continue
+		}
+		var subList []model.TokenSubscribeInfo
+		if err := json.Unmarshal([]byte(gjson.GetBytes(raw, "data.subscribeList").Raw), &subList); err != nil {
+			continue
+		}
+		for _, s := range subList {
+			op := ops[model.AlertOpKey(s.ChainCode, s.BaseAddress, s.Type)]
+			if r, ok := model.RuleFromSubscribe(chatId, s, op); ok && r.NoticeType != 0 {
+				rules = append(rules, r)
+			}
+		}
+	}
+	return rules, nil
+}
+
+// loadUser rules and compound alerts of user, old rules are kept if backend fails
+func (e *engine) loadUser(chatId int64) ([]model.AlertRule, []compound) {
+	var compounds []compound
+	for _, c := range UserCompoundAlerts(chatId) {
+		if c.Vaild() {
+			compounds = append(compounds, compound{ChatId: chatId, Alert: c})
+		}
+	}
+
+	list, err := userRules(chatId)
+	if err != nil {
+		log.Error().Err(err).Int64("chatId", chatId).Msg("load alert rules")
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		for _, old := range e.rules {
+			for _, r := range old {
+				if r.ChatId == chatId {
+					list = append(list, r)
+				}
+			}
+		}
+		return list, compounds
+	}
+	if len(list) == 0 && len(compounds) == 0 {
+		if err := store.RemoveAlertUser(chatId); err != nil {
+			log.Error().Err(err).Send()
+		}
+	}
+	return list, compounds
+}
+
+// load rules of users hosted by this instance, other users are evaluated by their instances
+func (e *engine) load() {
+	users, err := store.AlertUsers()
+	if err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, loadWorkers)
+	rules := map[string][]model.AlertRule{}
+	compounds := map[string][]compound{}
+	for _, chatId := range users {
+		if !Hosted(chatId) {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(chatId int64) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			list, cs := e.loadUser(chatId)
+			mu.Lock()
+			defer mu.Unlock()
+			for _, r := range list {
+				rules[r.TokenKey()] = append(rules[r.TokenKey()], r)
+			}
+			for _, c := range cs {
+				compounds[c.Alert.TokenKey()] = append(compounds[c.Alert.TokenKey()], c)
+			}
+		}(chatId)
+	}
+	wg.Wait()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.rules, e.compounds = rules, compounds
+	// 删除已经不存在的规则状态
+	keep := map[string]bool{}
+	for _, list := range rules {
+		for _, r := range list {
+			keep[cast.ToString(r.ChatId)+":"+r.Key()] = true
+		}
+	}
+	for key := range e.prev {
+		if !keep[key] {
+			delete(e.prev, key)
+		}
+	}
+	for key := range e.last {
+		if _, ok := compounds[key]; !ok {
+			delete(e.last, key)
+		}
+	}
+}
+
+type pollToken struct {
+	ChainCode string
+	Token     string
+	Pair      string
+	// need 1h volume from kline
+	Volume bool
+}
+
+// poll price and stats of tokens with price, chg or compound rules.
+// instances with the same bots load the same users, so each token is polled by one of them every round
+func (e *engine) poll() {
+	e.mu.Lock()
+	tokens := map[string]pollToken{}
+	for key, list := range e.rules {
+		for _, r := range list {
+			if r.Type == "price" || r.Type == "chg" {
+				tokens[key] = pollToken{ChainCode: r.ChainCode, Token: r.Token}
+				break
+			}
+		}
+	}
+	for key, list := range e.compounds {
+		p := pollToken{ChainCode: list[0].Alert.ChainCode, Token: list[0].Alert.Token, Pair: list[0].Alert.PairAddress}
+		for _, c := range list {
+			if c.Alert.Expr.Uses(model.MetricVolume1h) {
+				p.Volume = true
+			}
+		}
+		tokens[key] = p
+	}
+	e.mu.Unlock()
+
+	round := time.Now().Unix() / int64(pollInterval.Seconds())
+	prefix := fmt.Sprintf("alertPoll:%s:%d:", botsKey(), round)
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, pollWorkers)
+	for key, p := range tokens {
+		claimed, err := store.ClaimOnce(prefix+key, 2*pollInterval)
+		if err != nil {
+			log.Error().Err(err).Send()
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(p pollToken) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if t, ok := pollTick(p); ok {
+				e.feed(t)
+			}
+		}(p)
+	}
+	wg.Wait()
+}
+
+// botsKey bots of this instance, instances running the same bots share polled tokens
+func botsKey() string {
+	ids := make([]string, 0, len(entity.BotMap))
+	for id := range entity.BotMap {
+		ids = append(ids, strconv.FormatInt(id, 10))
+	}
+	slices.Sort(ids)
+	return strings.Join(ids, ",")
+}
+
+func pollTick(p pollToken) (model.AlertTick, bool) {
+	info := api.SearchTokenInfo(p.Token)
+	price, err := decimal.NewFromString(info.Price)
+	if err != nil || !price.IsPositive() {
+		return model.AlertTick{}, false
+	}
+	t := model.AlertTick{
+		ChainCode: p.ChainCode,
+		Token:     p.Token,
+		Symbol:    info.Symbol,
+		Price:     price,
+		Polled:    true,
+		Time:      time.Now(),
+	}
+	t.Chg, _ = decimal.NewFromString(info.Chg5m)
+	t.Chg1h, _ = decimal.NewFromString(info.Chg1h)
+	t.MarketCap, _ = decimal.NewFromString(info.MarketCap)
+	t.Holders, _ = decimal.NewFromString(info.Holders.String())
+
+	if p.Volume {
+		pair := p.Pair
+		if info.PairAddress != "" {
+			pair = info.PairAddress
+		}
+		// 最近 12 根 5m k 线
+		kline, err := api.GetKline(pair, p.ChainCode, "5m", 12)
+		if err != nil {
+			log.Debug().Err(err).Str("token", p.Token).Msg("alert get kline")
+			return model.AlertTick{}, false
+		}
+		for _, k := range kline.Data {
+			v, _ := decimal.NewFromString(k.Volume)
+			t.Volume1h = t.Volume1h.Add(v)
+		}
+	}
+	return t, true
+}
+
+func (e *engine) feed(t model.AlertTick) {
+	e.mu.Lock()
+	var fired []model.AlertRule
+	for _, r := range e.rules[t.TokenKey()] {
+		cur, ok := r.Metric(t)
+		if !ok {
+			continue
+		}
+		// 每笔交易单独判断
+		if t.Trade {
+			if r.Eval(decimal.Zero, cur, false) {
+				fired = append(fired, r)
+			}
+			continue
+		}
+		key := cast.ToString(r.ChatId) + ":" + r.Key()
+		prev, hasPrev := e.prev[key]
+		e.prev[key] = cur
+		if r.Eval(prev, cur, hasPrev) {
+			fired = append(fired, r)
+		}
+	}
+
+	// 组合条件只用查询到的完整数据判断
+	var compounds []compound
+	var holds []bool
+	if t.Polled {
+		last, hasLast := e.last[t.TokenKey()]
+		e.last[t.TokenKey()] = t
+		for _, c := range e.compounds[t.TokenKey()] {
+			compounds = append(compounds, c)
+			holds = append(holds, c.Alert.Expr.Eval(last, t, hasLast))
+		}
+	}
+	e.mu.Unlock()
+
+	for _, r := range fired {
+		e.notify(r, t)
+	}
+	// 条件从不满足变成满足时提醒, 上次的状态存在 redis 里, 只由托管用户的实例更新
+	for i, c := range compounds {
+		if !Hosted(c.ChatId) {
+			continue
+		}
+		held, err := store.UserGetAlertHold(c.ChatId, c.Alert.NoticeKey())
+		if err != nil {
+			log.Error().Err(err).Send()
+			continue
+		}
+		if holds[i] == held {
+			continue
+		}
+		if err := store.UserSetAlertHold(c.ChatId, c.Alert.NoticeKey(), holds[i]); err != nil {
+			log.Error().Err(err).Send()
+			continue
+		}
+		if holds[i] {
+			e.notifyCompound(c, t)
+		}
+	}
+}
+
+// claim alert of rule in instances, pushed ticks reach every instance running the bot.
+// false on redis error, alert may trigger a trade
+func claim(chatId int64, key string) bool {
+	ok, err := store.ClaimOnce(fmt.Sprintf("alert:%d:%s", chatId, key), pollInterval)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return false
+	}
+	return ok
+}
+
+// saveNotice before trade and delivery, so a failed delivery never triggers the action again
+func saveNotice(chatId int64, key string, now time.Time) bool {
+	if err := store.UserSetAlertNotice(chatId, key, now.Unix()); err != nil {
+		log.Error().Err(err).Int64("chatId", chatId).Msg("save alert notice")
+		return false
+	}
+	return true
+}
+
+func (e *engine) notify(r model.AlertRule, t model.AlertTick) {
+	if !Hosted(r.ChatId) {
+		return
+	}
+	notices, err := store.UserGetAlertNotices(r.ChatId)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+	var last time.Time
+	if at := cast.ToInt64(notices[r.Key()]); at > 0 {
+		last = time.Unix(at, 0)
+	}
+	now := time.Now()
+	if !r.CanNotify(now, last) {
+		return
+	}
+	if !t.Trade && !claim(r.ChatId, r.Key()) {
+		return
+	}
+	if !saveNotice(r.ChatId, r.Key(), now) {
+		return
+	}
+	Act(r.ChatId, r.ChainCode, r.Token, r.Type)
+
+	text, err := template.RenderAlertPush(i18n.UserLang(r.ChatId), r, t)
+	if err != nil {
+		return
+	}
+	value, _ := r.Metric(t)
+	err = Deliver(model.AlertPush{
+		ChatId: r.ChatId,
+		Key:    r.TokenKey() + ":" + r.Type,
+		Text:   text,
+		Symbol: r.Symbol,
+		Type:   r.Type,
+		Value:  value.String(),
+		Req: &model.PusherHandlerReqData{
+			ChainCode:   r.ChainCode,
+			BaseAddress: r.Token,
+			MonitorType: r.Type,
+		},
+	})
+	if err != nil {
+		log.Error().Err(err).Int64("chatId", r.ChatId).Msg("send alert")
+	}
+}
+
+func (e *engine) notifyCompound(c compound, t model.AlertTick) {
+	if !Hosted(c.ChatId) {
+		return
+	}
+	notices, err := store.UserGetAlertNotices(c.ChatId)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+	var last time.Time
+	if at := cast.ToInt64(notices[c.Alert.NoticeKey()]); at > 0 {
+		last = time.Unix(at, 0)
+	}
+	now := time.Now()
+	if !model.CanNotice(c.Alert.NoticeType, now, last) {
+		return
+	}
+	if !claim(c.ChatId, c.Alert.NoticeKey()) || !saveNotice(c.ChatId, c.Alert.NoticeKey(), now) {
+		return
+	}
+
+	text, err := template.RenderCompoundAlertPush(i18n.UserLang(c.ChatId), c.Alert, t)
+	if err != nil {
+		return
+	}
+	err = Deliver(model.AlertPush{
+		ChatId: c.ChatId,
+		Key:    c.Alert.NoticeKey(),
+		Text:   text,
+		Symbol: c.Alert.Symbol,
+		Type:   model.AlertTypeCompound,
+		Value:  t.Price.String(),
+	})
+	if err != nil {
+		log.Error().Err(err).Int64("chatId", c.ChatId).Msg("send compound alert")
+	}
+}
@@ -0,0 +1,55 @@
+package alert
+
+import (
+	"cmp"
+	"encoding/json"
+	"slices"
+
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/store"
+	"github.com/rs/zerolog/log"
+)
+
+// user compound alerts, sort by created time
+func UserCompoundAlerts(chatId int64) []model.CompoundAlert {
+	data, err := store.UserGetCompoundAlerts(chatId)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return nil
+	}
+
+	alerts := make([]model.CompoundAlert, 0, len(data))
+	for _, v := range data {
+		var c model.CompoundAlert
+		if err := json.Unmarshal([]byte(v), &c); err != nil {
+			log.Error().Err(err).Send()
+			continue
+		}
+		alerts = append(alerts, c)
+	}
+	slices.SortFunc(alerts, func(a, b model.CompoundAlert) int {
+		return cmp.Compare(a.CreatedAt, b.CreatedAt)
+	})
+	return alerts
+}
+
+func UserCompoundAlert(chatId int64, id string) (model.CompoundAlert, bool) {
+	for _, c := range UserCompoundAlerts(chatId) {
+		if c.Id == id {
+			return c, true
+		}
+	}
+	return model.CompoundAlert{}, false
+}
+
+func SaveCompoundAlert(chatId int64, c model.CompoundAlert) error {
+	data, err := c.JsonB()
+	if err != nil {
+		return err
+	}
+	if err := store.UserAddCompoundAlert(chatId, c.Id, data); err != nil {
+		return err
+	}
+	Track(chatId)
+	return nil
+}
@@ -0,0 +1,304 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/entity"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/store"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	// 检查免打扰结束和汇总时间
+	flushInterval = time.Minute
+	// 汇总消息里最多展示的提醒数量
+	maxDigestItems = 8
+)
+
+var (
+	errNoSender     = errors.New("alert sender not init")
+	errBotNotHosted = errors.New("bot of user not in this instance")
+)
+
+// SendFunc send push message to user by b, bot.SendPush
+type SendFunc func(b *bot.Bot, chatId int64, message string, req model.PusherHandlerReqData, kb *models.InlineKeyboardMarkup) (*models.Message, error)
+
+type sender struct {
+	// default bot for users without push bot
+	b    *bot.Bot
+	send SendFunc
+}
+
+var out sender
+
+// InitDelivery set default bot used to deliver alerts, send is used by alerts of backend monitors
+func InitDelivery(b *bot.Bot, send SendFunc) {
+	out = sender{b: b, send: send}
+}
+
+// SetPushBot remember bot of user, alerts of local engine are sent by it
+func SetPushBot(chatId int64, botId int64) {
+	if err := store.UserSetPushBot(chatId, botId); err != nil {
+		log.Error().Err(err).Send()
+	}
+}
+
+// pushBot bot of user in entity.BotMap, false if the bot runs in another instance
+func pushBot(chatId int64, botId int64) (*bot.Bot, bool) {
+	if botId == 0 {
+		id, err := store.UserGetPushBot(chatId)
+		if err != nil {
+			log.Error().Err(err).Send()
+		}
+		botId = id
+	}
+	if botId == 0 {
+		return out.b, out.b != nil
+	}
+	b, ok := entity.BotMap[botId]
+	return b, ok
+}
+
+// UserBot bot used to message and trade for user, false if the bot runs in another instance
+func UserBot(chatId int64) (*bot.Bot, bool) {
+	return pushBot(chatId, 0)
+}
+
+// Hosted whether alerts of user can be sent by this instance
+func Hosted(chatId int64) bool {
+	_, ok := UserBot(chatId)
+	return ok
+}
+
+// UserDelivery delivery settings of user, default timezone is UTC+8 for chinese
+func UserDelivery(chatId int64) model.AlertDelivery {
+	var d model.AlertDelivery
+	if i18n.UserLang(chatId) == i18n.ZH {
+		d.UtcOffset = 8
+	}
+	data, err := store.UserGetAlertDelivery(chatId)
+	if err != nil {
+		if err != redis.Nil {
+			log.Error().Err(err).Send()
+		}
+		return d
+	}
+	if err := json.Unmarshal([]byte(data), &d); err != nil {
+		log.Error().Err(err).Send()
+	}
+	return d
+}
+
+func SaveDelivery(chatId int64, d model.AlertDelivery) error {
+	data, err := d.JsonB()
+	if err != nil {
+		return err
+	}
+	return store.UserSetAlertDelivery(chatId, data)
+}
+
+// Deliver push alert to user, it may be dropped by cooldown or queued by quiet hours and digest
+func Deliver(p model.AlertPush) error {
+	if _, ok := pushBot(p.ChatId, p.BotId); !ok {
+		return errBotNotHosted
+	}
+	d := UserDelivery(p.ChatId)
+	now := time.Now()
+	p.At = now.Unix()
+
+	if d.CooldownMin > 0 && p.Key != "" {
+		last, err := store.UserGetAlertCooldown(p.ChatId, p.Key)
+		if err != nil {
+			log.Error().Err(err).Send()
+		}
+		if last > 0 && now.Sub(time.Unix(last, 0)) < time.Duration(d.CooldownMin)*time.Minute {
+			record(p, model.AlertStatusSuppressed, nil)
+			return store.UserIncrAlertSuppressed(p.ChatId)
+		}
+	}
+	if p.Key != "" {
+		if err := store.UserSetAlertCooldown(p.ChatId, p.Key, now.Unix()); err != nil {
+			log.Error().Err(err).Send()
+		}
+	}
+
+	if d.Queued(now) {
+		data, err := json.Marshal(p)
+		if err != nil {
+			return err
+		}
+		return store.UserQueueAlert(p.ChatId, data)
+	}
+	return sendOne(p)
+}
+
+// suppressed note of next delivery
+func suppressedNote(chatId int64) string {
+	n, err := store.UserTakeAlertSuppressed(chatId)
+	if err != nil {
+		log.Error().Err(err).Send()
+	}
+	if n <= 0 {
+		return ""
+	}
+	return i18n.L(chatId, "alert.suppressed", n) + "\n"
+}
+
+func sendOne(p model.AlertPush) error {
+	b, ok := pushBot(p.ChatId, p.BotId)
+	if !ok || b == nil || out.send == nil {
+		record(p, model.AlertStatusFailed, errNoSender)
+		return errNoSender
+	}
+	p.Text = suppressedNote(p.ChatId) + p.Text
+	return send(b, p)
+}
+
+// send push by b, failed push is retried or moved to dead letter
+func send(b *bot.Bot, p model.AlertPush) error {
+	var err error
+	if p.Req != nil {
+		_, err = out.send(b, p.ChatId, p.Text, *p.Req, p.Markup)
+	} else {
+		err = sendHTML(b, p.ChatId, p.Text)
+	}
+	if err != nil {
+		return fail(p, err)
+	}
+	record(p, model.AlertStatusSent, nil)
+	return nil
+}
+
+func sendHTML(b *bot.Bot, chatId int64, text string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store.BotMessageAdd()
+	_, err := b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:    chatId,
+		Text:      text,
+		ParseMode: models.ParseModeHTML,
+		LinkPreviewOptions: &models.LinkPreviewOptions{
+			IsDisabled: bot.True(),
+		},
+	})
+	return err
+}
+
+func sendDigest(chatId int64, pushes []model.AlertPush) error {
+	b, ok := pushBot(chatId, pushes[len(pushes)-1].BotId)
+	if !ok || b == nil {
+		for _, p := range pushes {
+			record(p, model.AlertStatusFailed, errNoSender)
+		}
+		return errNoSender
+	}
+	texts := make([]string, 0, maxDigestItems)
+	for i, p := range pushes {
+		if i >= maxDigestItems {
+			break
+		}
+		texts = append(texts, strings.TrimSpace(p.Text))
+	}
+	text := i18n.L(chatId, "alert.digest", len(pushes)) + "\n" + suppressedNote(chatId) + "\n" + strings.Join(texts, "\n\n➖➖➖➖➖\n\n")
+	if more := len(pushes) - len(texts); more > 0 {
+		text += "\n\n" + i18n.L(chatId, "alert.digest_more", more)
+	}
+	if err := sendHTML(b, chatId, text); err != nil {
+		failDigest(pushes, err)
+		return err
+	}
+	for _, p := range pushes {
+		record(p, model.AlertStatusDigest, nil)
+	}
+	return nil
+}
+
+// RunDelivery deliver queued alerts after quiet hours, send digest and retry failed pushes until ctx done
+func RunDelivery(ctx context.Context) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	retryTicker := time.NewTicker(retryInterval)
+	defer retryTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			flush()
+		case <-retryTicker.C:
+			retry()
+		}
+	}
+}
+
+func flush() {
+	users, err := store.AlertQueuedUsers()
+	if err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+	now := time.Now()
+	for _, chatId := range users {
+		// 由运行用户 bot 的实例发送
+		if !Hosted(chatId) {
+			continue
+		}
+		d := UserDelivery(chatId)
+		if d.Quiet(now) {
+			continue
+		}
+		if d.DigestMin > 0 {
+			last, err := store.UserGetAlertDigestAt(chatId)
+			if err != nil {
+				log.Error().Err(err).Send()
+				continue
+			}
+			if now.Sub(time.Unix(last, 0)) < time.Duration(d.DigestMin)*time.Minute {
+				continue
+			}
+		}
+
+		items, err := store.UserTakeAlertQueue(chatId)
+		if err != nil {
+			log.Error().Err(err).Send()
+			continue
+		}
+		pushes := make([]model.AlertPush, 0, len(items))
+		for _, v := range items {
+			var p model.AlertPush
+			if err := json.Unmarshal([]byte(v), &p); err != nil {
+				log.Error().Err(err).Send()
+				continue
+			}
+			pushes = append(pushes, p)
+		}
+		if len(pushes) == 0 {
+			continue
+		}
+
+		// 免打扰结束后只有一条时正常推送
+		if len(pushes) == 1 && d.DigestMin == 0 {
+			err = sendOne(pushes[0])
+		} else {
+			err = sendDigest(chatId, pushes)
+		}
+		if err != nil {
+			log.Error().Err(err).Int64("chatId", chatId).Msg("deliver queued alerts")
+		}
+		if d.DigestMin > 0 {
+			if err := store.UserSetAlertDigestAt(chatId, now.Unix()); err != nil {
+				log.Error().Err(err).Send()
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package alert
+
+import (
+	"cmp"
+	"encoding/json"
+	"slices"
+
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/store"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cast"
+)
+
+// record save delivery result of push to history and stats of its rule
+func record(p model.AlertPush, status string, err error) {
+	r := p.Record(status, err)
+	data, err := r.JsonB()
+	if err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+	if err := store.UserAddAlertRecord(p.ChatId, data, model.MaxAlertHistoryLen); err != nil {
+		log.Error().Err(err).Send()
+	}
+	if p.Key == "" {
+		return
+	}
+	if err := store.UserAddAlertStat(p.ChatId, p.Key, data); err != nil {
+		log.Error().Err(err).Send()
+	}
+}
+
+// UserRecords alert history of page, newest first
+func UserRecords(chatId int64, page int) ([]model.AlertRecord, int64) {
+	start := int64(page * model.AlertHistoryPageSize)
+	list, total, err := store.UserGetAlertRecords(chatId, start, start+model.AlertHistoryPageSize-1)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return nil, 0
+	}
+
+	records := make([]model.AlertRecord, 0, len(list))
+	for _, v := range list {
+		var r model.AlertRecord
+		if err := json.Unmarshal([]byte(v), &r); err != nil {
+			log.Error().Err(err).Send()
+			continue
+		}
+		records = append(records, r)
+	}
+	return records, total
+}
+
+// UserStats trigger stats of rules, recently triggered first
+func UserStats(chatId int64) []model.AlertStat {
+	counts, last, err := store.UserGetAlertStats(chatId)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return nil
+	}
+
+	stats := make([]model.AlertStat, 0, len(last))
+	for key, v := range last {
+		s := model.AlertStat{Count: cast.ToInt64(counts[key])}
+		if err := json.Unmarshal([]byte(v), &s.Last); err != nil {
+			log.Error().Err(err).Send()
+			continue
+		}
+		stats = append(stats, s)
+	}
+	slices.SortFunc(stats, func(a, b model.AlertStat) int {
+		return cmp.Compare(b.Last.At, a.Last.At)
+	})
+	return stats
+}
+
+// QueuedLen alerts waiting for quiet hours or digest
+func QueuedLen(chatId int64) int64 {
+	n, err := store.UserAlertQueueLen(chatId)
+	if err != nil {
+		log.Error().Err(err).Send()
+	}
+	return n
+}
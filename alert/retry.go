@@ -0,0 +1,141 @@
+package alert
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/store"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	// 检查到期重试的间隔
+	retryInterval = 5 * time.Second
+	// 第一次重试的等待时间, 之后每次翻倍
+	retryBase       = 10 * time.Second
+	maxPushAttempts = 6
+	maxPushDeadLen  = int64(1000)
+)
+
+// retryDelay wait before next attempt, false if err is permanent or attempts run out
+func retryDelay(err error, attempt int) (time.Duration, bool) {
+	var tooMany *bot.TooManyRequestsError
+	switch {
+	case errors.Is(err, bot.ErrorForbidden), errors.Is(err, bot.ErrorBadRequest):
+		// 用户屏蔽了 bot 或者消息本身有问题, 重试没有意义
+		return 0, false
+	case attempt >= maxPushAttempts:
+		return 0, false
+	case errors.As(err, &tooMany):
+		return time.Duration(max(tooMany.RetryAfter, 1)) * time.Second, true
+	}
+	return retryBase << (attempt - 1), true
+}
+
+// fail schedule retry of push, nil if it will be retried
+func fail(p model.AlertPush, err error) error {
+	p.Attempt++
+	delay, ok := retryDelay(err, p.Attempt)
+	if !ok {
+		dead(p, err)
+		return err
+	}
+	data, e := json.Marshal(p)
+	if e == nil {
+		e = store.PushRetryAdd(data, time.Now().Add(delay).Unix())
+	}
+	if e != nil {
+		log.Error().Err(e).Send()
+		dead(p, err)
+		return err
+	}
+	log.Warn().Err(err).Int64("chatId", p.ChatId).Int("attempt", p.Attempt).Dur("delay", delay).Msg("push retry")
+	return nil
+}
+
+// failDigest pushes of failed digest are queued again for next digest
+func failDigest(pushes []model.AlertPush, err error) {
+	for _, p := range pushes {
+		p.Attempt++
+		if _, ok := retryDelay(err, p.Attempt); !ok {
+			dead(p, err)
+			continue
+		}
+		data, e := json.Marshal(p)
+		if e == nil {
+			e = store.UserQueueAlert(p.ChatId, data)
+		}
+		if e != nil {
+			log.Error().Err(e).Send()
+			dead(p, err)
+		}
+	}
+}
+
+// dead move push to dead letter, it can be checked by /push_dead
+func dead(p model.AlertPush, err error) {
+	record(p, model.AlertStatusFailed, err)
+	d := model.PushDead{Push: p, Error: err.Error(), At: time.Now().Unix()}
+	data, e := d.JsonB()
+	if e == nil {
+		e = store.PushDeadAdd(data, maxPushDeadLen)
+	}
+	if e != nil {
+		log.Error().Err(e).Send()
+	}
+	log.Error().Err(err).Int64("chatId", p.ChatId).Int("attempt", p.Attempt).Msg("push dead")
+}
+
+// DeadPushes recent dead pushes, newest first
+func DeadPushes(n int64) ([]model.PushDead, int64) {
+	list, total, err := store.PushDeadList(0, n-1)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return nil, 0
+	}
+	pushes := make([]model.PushDead, 0, len(list))
+	for _, v := range list {
+		var d model.PushDead
+		if err := json.Unmarshal([]byte(v), &d); err != nil {
+			log.Error().Err(err).Send()
+			continue
+		}
+		pushes = append(pushes, d)
+	}
+	return pushes, total
+}
+
+func retry() {
+	items, err := store.PushRetryDue(time.Now().Unix(), 100)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+	for _, v := range items {
+		var p model.AlertPush
+		if err := json.Unmarshal([]byte(v), &p); err != nil {
+			log.Error().Err(err).Send()
+			store.PushRetryTake(v)
+			continue
+		}
+		// 由运行用户 bot 的实例重试
+		b, ok := pushBot(p.ChatId, p.BotId)
+		if !ok || b == nil {
+			continue
+		}
+		taken, err := store.PushRetryTake(v)
+		if err != nil {
+			log.Error().Err(err).Send()
+			continue
+		}
+		if !taken {
+			continue
+		}
+		if err := send(b, p); err != nil {
+			log.Error().Err(err).Int64("chatId", p.ChatId).Msg("retry push")
+		}
+	}
+}
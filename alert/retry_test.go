@@ -0,0 +1,36 @@
+package alert
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-telegram/bot"
+)
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		attempt int
+		delay   time.Duration
+		ok      bool
+	}{
+		{"first retry", errors.New("timeout"), 1, retryBase, true},
+		{"backoff", errors.New("timeout"), 3, retryBase * 4, true},
+		{"attempts run out", errors.New("timeout"), maxPushAttempts, 0, false},
+		{"blocked by user", fmt.Errorf("send: %w", bot.ErrorForbidden), 1, 0, false},
+		{"bad request", fmt.Errorf("send: %w", bot.ErrorBadRequest), 1, 0, false},
+		{"too many requests", &bot.TooManyRequestsError{RetryAfter: 30}, 1, 30 * time.Second, true},
+		{"too many requests without wait", &bot.TooManyRequestsError{}, 2, time.Second, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, ok := retryDelay(tt.err, tt.attempt)
+			if ok != tt.ok || delay != tt.delay {
+				t.Errorf("retryDelay() = %v %v, want %v %v", delay, ok, tt.delay, tt.ok)
+			}
+		})
+	}
+}
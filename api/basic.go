@@ -21,7 +21,7 @@ import (
 )
 
 func BuildBasicUrl() string {
-	return config.YmlConfig.Env.ApiEndpoint
+	return config.Get().Env.ApiEndpoint
 }
 
 func HttpGet(path string) *netutil.HttpRequest {
@@ -33,7 +33,7 @@ func HttpGet(path string) *netutil.HttpRequest {
 
 func signAppInfo(ts string) string {
 	sha256Hash := sha256.New()
-	params := config.YmlConfig.App.Appid + ts + config.YmlConfig.App.Ver + config.YmlConfig.App.Appkey
+	params := config.Get().App.Appid + ts + config.Get().App.Ver + config.Get().App.Appkey
 
 	sha256Hash.Write([]byte(params))
 	digest := sha256Hash.Sum(nil)
@@ -42,9 +42,9 @@ func signAppInfo(ts string) string {
 }
 
 func calculateSign(ts int64) string {
-	code := config.YmlConfig.Channel.Code
-	version := config.YmlConfig.Channel.Version
-	key := config.YmlConfig.Channel.Key
+	code := config.Get().Channel.Code
+	version := config.Get().Channel.Version
+	key := config.Get().Channel.Key
 	sign := cryptor.Sha256(code + convertor.ToString(ts) + version + key)
 	return sign
 }
@@ -57,8 +57,8 @@ func makeHeader() http.Header {
 	header.Add("Content-Type", "application/json")
 	header.Add("Sign", sign)
 	header.Add("Ts", cast.ToString(ts))
-	header.Add("Channel", config.YmlConfig.Channel.Code)
-	header.Add("Version", config.YmlConfig.Channel.Version)
+	header.Add("Channel", config.Get().Channel.Code)
+	header.Add("Version", config.Get().Channel.Version)
 
 	return header
 }
@@ -82,15 +82,15 @@ func fetchUserProfile(userID int64) (model.GetUserResp, error) {
 	signTs := signAppInfo(ts)
 	requestBody := map[string]any{
 		"accountId": ec,
-		"ver":       config.YmlConfig.App.Ver,
-		"appId":     config.YmlConfig.App.Appid,
+		"ver":       config.Get().App.Ver,
+		"appId":     config.Get().App.Appid,
 		"sign":      signTs,
 		"ts":        ts,
 	}
 	header.Add("SIG", signTs)
 	header.Add("TS", ts)
-	header.Add("VER", config.YmlConfig.App.Ver)
-	header.Add("APP_ID", config.YmlConfig.App.Appid)
+	header.Add("VER", config.Get().App.Ver)
+	header.Add("APP_ID", config.Get().App.Appid)
 
 	bodyBytes, err := json.Marshal(requestBody)
 	if err != nil {
@@ -145,16 +145,16 @@ func BindUserInvitationCode(userID int64, code string) (model.GetUserResp, error
 	signTs := signAppInfo(ts)
 	requestBody := map[string]any{
 		"accountId":      ec,
-		"ver":            config.YmlConfig.App.Ver,
-		"appId":          config.YmlConfig.App.Appid,
+		"ver":            config.Get().App.Ver,
+		"appId":          config.Get().App.Appid,
 		"sign":           signTs,
 		"ts":             ts,
 		"invitationCode": code,
 	}
 	header.Add("SIG", signTs)
 	header.Add("TS", ts)
-	header.Add("VER", config.YmlConfig.App.Ver)
-	header.Add("APP_ID", config.YmlConfig.App.Appid)
+	header.Add("VER", config.Get().App.Ver)
+	header.Add("APP_ID", config.Get().App.Appid)
 
 	bodyBytes, err := json.Marshal(requestBody)
 	if err != nil {
@@ -21,9 +21,9 @@ type BotConfigResp struct {
 func ListBotConfigsSwitch() []entity.BotConfig {
 
 	if util.IsDebug() {
-		botName := config.YmlConfig.Env.BotName
-		userID := cast.ToInt64(config.YmlConfig.Env.BotMaker)
-		apiKey := config.YmlConfig.Env.BotApiKey
+		botName := config.Get().Env.BotName
+		userID := cast.ToInt64(config.Get().Env.BotMaker)
+		apiKey := config.Get().Env.BotApiKey
 		botConfigs := make([]entity.BotConfig, 0)
 		bc := entity.BotConfig{
 			UserId:  userID,
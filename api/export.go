@@ -0,0 +1,41 @@
+package api
+
+const (
+	ExportPageSize = 100
+	// 防止接口不支持分页时死循环
+	exportMaxPage = 50
+	// ListAllPages 最多返回的记录数
+	ExportMaxRecords = exportMaxPage * ExportPageSize
+)
+
+// ListAllPages page through list api until the last page,
+// key is used to drop duplicate records between pages,
+// stop (can be nil) ends paging after the page has a matched record, list is newest first.
+// truncated is true when exportMaxPage is reached and there may be more records
+func ListAllPages[T any](fetch func(pageNum int) ([]T, error), key func(T) string, stop func(T) bool) (all []T, truncated bool, err error) {
+	seen := make(map[string]struct{})
+	for pageNum := 1; pageNum <= exportMaxPage; pageNum++ {
+		data, err := fetch(pageNum)
+		if err != nil {
+			return all, false, err
+		}
+
+		added, done := 0, false
+		for _, v := range data {
+			if stop != nil && stop(v) {
+				done = true
+			}
+			k := key(v)
+			if _, ok := seen[k]; ok {
+				continue
+			}
+			seen[k] = struct{}{}
+			all = append(all, v)
+			added++
+		}
+		if done || len(data) < ExportPageSize || added == 0 {
+			return all, false, nil
+		}
+	}
+	return all, true, nil
+}
@@ -11,14 +11,29 @@ import (
 	"github.com/tidwall/gjson"
 )
 
-func ListTradeHistory(walletID float64, userInfo model.GetUserResp) (model.TradeHistory, error) {
+// Page page of history list, backend returns its default page without it
+type Page struct {
+	Num  int
+	Size int
+}
+
+// add pageNum and pageSize to request if page is given
+func withPage(reqData map[string]any, page []Page) map[string]any {
+	if len(page) > 0 {
+		reqData["pageNum"] = page[0].Num
+		reqData["pageSize"] = page[0].Size
+	}
+	return reqData
+}
+
+func ListTradeHistory(walletID float64, userInfo model.GetUserResp, page ...Page) (model.TradeHistory, error) {
 	var result model.TradeHistory
 
 	header := makeHeader()
 	AddBeaer(&header, userInfo)
-	reqData := map[string]any{
+	reqData := withPage(map[string]any{
 		"walletId": walletID,
-	}
+	}, page)
 
 	log.Debug().Interface("listTradeHistories requestData", reqData).Send()
 	jsonData, err := json.Marshal(reqData)
@@ -61,15 +76,15 @@ func ListTradeHistory(walletID float64, userInfo model.GetUserResp) (model.Trade
 	return result, nil
 }
 
-func ListTransferHistory(walletID float64, chainCode string, userInfo model.GetUserResp) (model.TransferHistory, error) {
+func ListTransferHistory(walletID float64, chainCode string, userInfo model.GetUserResp, page ...Page) (model.TransferHistory, error) {
 	var result model.TransferHistory
 
 	header := makeHeader()
 	AddBeaer(&header, userInfo)
-	reqData := map[string]any{
+	reqData := withPage(map[string]any{
 		"walletId":  walletID,
 		"chainCode": chainCode,
-	}
+	}, page)
 
 	log.Debug().Interface("listTransferHistory requestData", reqData).Send()
 	jsonData, err := json.Marshal(reqData)
@@ -162,14 +177,14 @@ func ListOpeningOrders(walletID float64, userInfo model.GetUserResp) (model.Open
 	return result, nil
 }
 
-func ListHistoryOrders(walletID float64, userInfo model.GetUserResp) (model.OpenOrdersHistory, error) {
+func ListHistoryOrders(walletID float64, userInfo model.GetUserResp, page ...Page) (model.OpenOrdersHistory, error) {
 	var result model.OpenOrdersHistory
 
 	header := makeHeader()
 	AddBeaer(&header, userInfo)
-	reqData := map[string]any{
+	reqData := withPage(map[string]any{
 		"walletId": walletID,
-	}
+	}, page)
 
 	log.Debug().Interface("listHistoryOrdersHistory requestData", reqData).Send()
 	jsonData, err := json.Marshal(reqData)
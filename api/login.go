@@ -27,15 +27,15 @@ func GetTg2WebLoginToken(userID int64, platform string) (any, error) {
 	requestBody := map[string]any{
 		"accountId": ec,
 		"platform":  platform,
-		"ver":       config.YmlConfig.App.Ver,
-		"appId":     config.YmlConfig.App.Appid,
+		"ver":       config.Get().App.Ver,
+		"appId":     config.Get().App.Appid,
 		"sign":      signTs,
 		"ts":        ts,
 	}
 	header.Add("SIG", signTs)
 	header.Add("TS", ts)
-	header.Add("VER", config.YmlConfig.App.Ver)
-	header.Add("APP_ID", config.YmlConfig.App.Appid)
+	header.Add("VER", config.Get().App.Ver)
+	header.Add("APP_ID", config.Get().App.Appid)
 
 	bodyBytes, err := json.Marshal(requestBody)
 	if err != nil {
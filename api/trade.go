@@ -384,3 +384,37 @@ func SendSwap(swap model.Swap, userInfo model.GetUserResp) ([]byte, error) {
 
 	return body, nil
 }
+
+func GetKline(pairAddress, chainCode, timeType string, limit int) (model.KlineResp, error) {
+	var result model.KlineResp
+	header := makeHeader()
+	requestBody := map[string]any{
+		"pairAddress": pairAddress,
+		"chainCode":   chainCode,
+		"timeType":    timeType,
+		"limit":       limit,
+	}
+	bodyBytes, err := json.Marshal(requestBody)
+	if err != nil {
+		return result, fmt.Errorf("构建请求体失败: %w", err)
+	}
+	req := &netutil.HttpRequest{
+		RawURL:  BuildBasicUrl() + "/api/appv2/getKline",
+		Method:  "POST",
+		Headers: header,
+		Body:    bodyBytes,
+	}
+
+	client := netutil.NewHttpClient()
+	resp, err := client.SendRequest(req)
+	if err != nil {
+		return result, err
+	}
+
+	derr := client.DecodeResponse(resp, &result)
+	if derr != nil {
+		return result, derr
+	}
+
+	return result, nil
+}
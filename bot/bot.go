@@ -123,7 +123,7 @@ func StartBots(ctx context.Context, bots []*bot.Bot) {
 		go func(b *bot.Bot, botCtx context.Context, botCancel context.CancelFunc) {
 			defer botCancel()
 			// TODO: finish webhook mode
-			if cfg.YmlConfig.Env.WebHookOpen {
+			if cfg.Get().Env.WebHookOpen {
 				b.DeleteWebhook(ctx, &bot.DeleteWebhookParams{
 					DropPendingUpdates: true,
 				})
@@ -162,7 +162,7 @@ func initMainBotOptions() []bot.Option {
 	// TODO: 注意是不是命令
 	textHandlerOpt := bot.WithDefaultHandler(handler.TextHandler)
 	mainBotOptions = append(mainBotOptions, textHandlerOpt)
-	botTokenOptions := bot.WithWebhookSecretToken(cfg.YmlConfig.Env.TgHookToken)
+	botTokenOptions := bot.WithWebhookSecretToken(cfg.Get().Env.TgHookToken)
 
 	allOptions = append(allOptions, chOpt, workerOpt, botTokenOptions, bot.WithSkipGetMe())
 	allOptions = append(allOptions, mainBotOptions...)
@@ -2,12 +2,17 @@ package bot
 
 import (
 	"context"
+	"encoding/json"
 	"sync"
 
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/store"
 	"github.com/hellodex/tradingbot/util"
 	"github.com/rs/zerolog/log"
+	"github.com/spf13/cast"
 )
 
 var (
@@ -27,11 +32,18 @@ func InitBotWarpServer(b *bot.Bot) {
 	})
 }
 
-func SendMessage(chatId int64, message string) (*models.Message, error) {
+// SendMessage send push message by b, nil b is the bot of InitBotWarpServer
+func SendMessage(b *bot.Bot, chatId int64, message string) (*models.Message, error) {
+	return sendMessage(b, chatId, message, util.NewAiMonitorPusherButton(i18n.UserLang(chatId)))
+}
+
+func sendMessage(b *bot.Bot, chatId int64, message string, kb models.InlineKeyboardMarkup) (*models.Message, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	kb := util.NewAiMonitorPusherButton()
-	sendmessage, err := bbbb.SendMessage(ctx, &bot.SendMessageParams{
+	if b == nil {
+		b = bbbb
+	}
+	sendmessage, err := b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID:    chatId,
 		Text:      message,
 		ParseMode: "HTML",
@@ -48,6 +60,25 @@ func SendMessage(chatId int64, message string) (*models.Message, error) {
 	return sendmessage, nil
 }
 
+// SendPush send backend push and save token info of message for pusher buttons, nil kb is keyboard of AI monitor
+func SendPush(b *bot.Bot, chatId int64, message string, req model.PusherHandlerReqData, kb *models.InlineKeyboardMarkup) (*models.Message, error) {
+	var markup models.InlineKeyboardMarkup
+	if kb != nil {
+		markup = *kb
+	} else {
+		markup = util.NewAiMonitorPusherButton(i18n.UserLang(chatId))
+	}
+	sendMsg, err := sendMessage(b, chatId, message, markup)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(req); err == nil {
+		store.NewPusherMessage(chatId, cast.ToString(sendMsg.ID), string(data))
+	}
+	return sendMsg, nil
+}
+
 func UnsafeNewUpdate(u *models.Update) {
 	update <- u
 }
@@ -0,0 +1,154 @@
+package chart
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"strconv"
+
+	"github.com/hellodex/tradingbot/model"
+)
+
+const (
+	Width  = 800
+	Height = 450
+
+	paddingTop    = 20
+	paddingBottom = 20
+	paddingLeft   = 10
+	axisWidth     = 90 // 右侧价格标签
+	gridCount     = 5
+)
+
+var (
+	ErrNoKline = errors.New("chart: no kline data")
+
+	colorBackground = color.RGBA{0x13, 0x17, 0x22, 0xff}
+	colorGrid       = color.RGBA{0x2a, 0x2e, 0x39, 0xff}
+	colorLabel      = color.RGBA{0x9a, 0xa0, 0xab, 0xff}
+	colorUp         = color.RGBA{0x26, 0xa6, 0x9a, 0xff}
+	colorDown       = color.RGBA{0xef, 0x53, 0x50, 0xff}
+
+	ColorEntry = color.RGBA{0xf5, 0xc5, 0x42, 0xff}
+	ColorOrder = color.RGBA{0x42, 0x8b, 0xf5, 0xff}
+)
+
+// horizontal price line, like entry price or limit order
+type Line struct {
+	Price float64
+	Color color.RGBA
+}
+
+// RenderCandles draw candlestick chart to png
+func RenderCandles(klines []model.Kline, lines []Line) ([]byte, error) {
+	if len(klines) == 0 {
+		return nil, ErrNoKline
+	}
+
+	low, high := math.MaxFloat64, -math.MaxFloat64
+	for _, k := range klines {
+		_, h, l, _ := k.OHLC()
+		low = math.Min(low, l)
+		high = math.Max(high, h)
+	}
+	if high <= 0 {
+		return nil, ErrNoKline
+	}
+
+	// 价格线离K线太远时不画, 否则K线会被压扁
+	var visible []Line
+	span := high - low
+	for _, line := range lines {
+		if line.Price <= 0 || line.Price < low-span || line.Price > high+span {
+			continue
+		}
+		visible = append(visible, line)
+		low = math.Min(low, line.Price)
+		high = math.Max(high, line.Price)
+	}
+
+	if high == low {
+		high, low = high*1.01, low*0.99
+	}
+	pad := (high - low) * 0.05
+	high, low = high+pad, low-pad
+
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorBackground}, image.Point{}, draw.Src)
+
+	plotW := Width - paddingLeft - axisWidth
+	plotH := Height - paddingTop - paddingBottom
+	priceY := func(p float64) int {
+		return paddingTop + int(math.Round((high-p)/(high-low)*float64(plotH)))
+	}
+
+	// grid and price label
+	for i := 0; i <= gridCount; i++ {
+		p := high - (high-low)*float64(i)/gridCount
+		y := priceY(p)
+		fillRect(img, paddingLeft, y, plotW, 1, colorGrid)
+		drawText(img, paddingLeft+plotW+8, y-glyphHeight/2, formatPrice(p), colorLabel)
+	}
+
+	// candles
+	step := float64(plotW) / float64(len(klines))
+	bodyW := max(1, int(step*0.7))
+	for i, k := range klines {
+		o, h, l, c := k.OHLC()
+		col := colorUp
+		if c < o {
+			col = colorDown
+		}
+		cx := paddingLeft + int(step*float64(i)+step/2)
+
+		// wick
+		fillRect(img, cx, priceY(h), 1, max(1, priceY(l)-priceY(h)), col)
+		// body
+		top, bottom := priceY(math.Max(o, c)), priceY(math.Min(o, c))
+		fillRect(img, cx-bodyW/2, top, bodyW, max(1, bottom-top), col)
+	}
+
+	// price lines, dashed
+	for _, line := range visible {
+		y := priceY(line.Price)
+		for x := paddingLeft; x < paddingLeft+plotW; x += 8 {
+			fillRect(img, x, y, 5, 1, line.Color)
+		}
+		label := formatPrice(line.Price)
+		fillRect(img, paddingLeft+plotW+4, y-glyphHeight/2-3, textWidth(label)+6, glyphHeight+6, line.Color)
+		drawText(img, paddingLeft+plotW+8, y-glyphHeight/2, label, colorBackground)
+	}
+
+	// last price
+	_, _, _, last := klines[len(klines)-1].OHLC()
+	lastCol := colorUp
+	if o, _, _, _ := klines[len(klines)-1].OHLC(); last < o {
+		lastCol = colorDown
+	}
+	y := priceY(last)
+	label := formatPrice(last)
+	fillRect(img, paddingLeft+plotW+4, y-glyphHeight/2-3, textWidth(label)+6, glyphHeight+6, lastCol)
+	drawText(img, paddingLeft+plotW+8, y-glyphHeight/2, label, color.White)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func fillRect(img *image.RGBA, x, y, w, h int, c color.Color) {
+	draw.Draw(img, image.Rect(x, y, x+w, y+h), &image.Uniform{c}, image.Point{}, draw.Src)
+}
+
+// 5 位有效数字, 很小的价格用科学计数
+func formatPrice(p float64) string {
+	if p != 0 && math.Abs(p) < 0.0001 {
+		return strconv.FormatFloat(p, 'e', 3, 64)
+	}
+	return strconv.FormatFloat(p, 'g', 5, 64)
+}
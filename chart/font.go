@@ -0,0 +1,52 @@
+package chart
+
+import (
+	"image"
+	"image/color"
+)
+
+// 3x5 点阵字体, 只用于价格标签
+var glyphs = map[rune][5]string{
+	'0': {"111", "101", "101", "101", "111"},
+	'1': {"010", "110", "010", "010", "111"},
+	'2': {"111", "001", "111", "100", "111"},
+	'3': {"111", "001", "111", "001", "111"},
+	'4': {"101", "101", "111", "001", "001"},
+	'5': {"111", "100", "111", "001", "111"},
+	'6': {"111", "100", "111", "101", "111"},
+	'7': {"111", "001", "010", "010", "010"},
+	'8': {"111", "101", "111", "101", "111"},
+	'9': {"111", "101", "111", "001", "111"},
+	'.': {"000", "000", "000", "000", "010"},
+	'-': {"000", "000", "111", "000", "000"},
+	'+': {"000", "010", "111", "010", "000"},
+	'e': {"000", "111", "111", "100", "111"},
+}
+
+const (
+	glyphScale   = 2
+	glyphWidth   = 3 * glyphScale
+	glyphHeight  = 5 * glyphScale
+	glyphSpacing = glyphScale
+)
+
+func textWidth(s string) int {
+	return len(s) * (glyphWidth + glyphSpacing)
+}
+
+// drawText left top is (x, y), unknown char is blank
+func drawText(img *image.RGBA, x, y int, s string, c color.Color) {
+	for _, r := range s {
+		if g, ok := glyphs[r]; ok {
+			for row, line := range g {
+				for col, bit := range line {
+					if bit != '1' {
+						continue
+					}
+					fillRect(img, x+col*glyphScale, y+row*glyphScale, glyphScale, glyphScale, c)
+				}
+			}
+		}
+		x += glyphWidth + glyphSpacing
+	}
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"sync"
 
 	"gopkg.in/yaml.v2"
 )
@@ -20,21 +21,25 @@ type Config struct {
 	} `yaml:"app"`
 
 	Env struct {
-		ApiEndpoint  string `yaml:"api_endpoint"`
-		SolRpc       string `yaml:"sol_rpc"`
-		BscRpc       string `yaml:"bsc_rpc"`
-		Debug        string `yaml:"debug"`
-		BotName      string `yaml:"bot_name"`
-		BotApiKey    string `yaml:"bot_api_key"`
-		BotMaker     int64  `yaml:"bot_maker"`
-		AesKey       string `yaml:"aes_key"`
-		Nonce        string `yaml:"nonce"`
-		Encrypt_open bool   `yaml:"encrypt_open"`
-		KchartUrl    string `yaml:"kchart_url"`
-		TgHook       string `yaml:"tg_hook"`
-		WebHookOpen  bool   `yaml:"web_hook_open"`
-		TgHookToken  string `yaml:"tg_hook_token"`
-		LocalHost    string `yaml:"local_host"`
+		ApiEndpoint  string  `yaml:"api_endpoint"`
+		SolRpc       string  `yaml:"sol_rpc"`
+		BscRpc       string  `yaml:"bsc_rpc"`
+		SolWs        string  `yaml:"sol_ws"`
+		BscWs        string  `yaml:"bsc_ws"`
+		Debug        string  `yaml:"debug"`
+		BotName      string  `yaml:"bot_name"`
+		BotApiKey    string  `yaml:"bot_api_key"`
+		BotMaker     int64   `yaml:"bot_maker"`
+		AesKey       string  `yaml:"aes_key"`
+		Nonce        string  `yaml:"nonce"`
+		Encrypt_open bool    `yaml:"encrypt_open"`
+		KchartUrl    string  `yaml:"kchart_url"`
+		TgHook       string  `yaml:"tg_hook"`
+		WebHookOpen  bool    `yaml:"web_hook_open"`
+		TgHookToken  string  `yaml:"tg_hook_token"`
+		LocalHost    string  `yaml:"local_host"`
+		LocalAlert   bool    `yaml:"local_alert"` // 在 bot 里计算 AI 监控提醒
+		Admins       []int64 `yaml:"admins"`      // 可以使用运维命令的用户
 	} `yaml:"env"`
 
 	Redis struct {
@@ -52,10 +57,23 @@ type Config struct {
 		Username  string `yaml:"username"`
 		Passwd    string `yaml:"passwd"`
 		MessageCh string `yaml:"message_channel"`
+		// 配置后从 stream 消费推送, 否则订阅 MessageCh
+		Stream string `yaml:"message_stream"`
+		Group  string `yaml:"stream_group"`
 	} `yaml:"redis_push"`
+
+	// 推荐的聪明钱钱包, 用户可以一键关注
+	SmartWallets []struct {
+		ChainCode string `yaml:"chain_code"`
+		Address   string `yaml:"address"`
+		Label     string `yaml:"label"`
+	} `yaml:"smart_wallets"`
 }
 
-var YmlConfig *Config
+var (
+	ymlConfig *Config
+	loadOnce  sync.Once
+)
 
 func LoadConfig(filename string) (*Config, error) {
 	data, err := os.ReadFile(filename)
@@ -71,16 +89,21 @@ func LoadConfig(filename string) (*Config, error) {
 
 	return &config, nil
 }
-func init() {
-	var confFilePath string
-	if configFilePathFromEnv := os.Getenv("TGBOT_APP_ENV"); configFilePathFromEnv != "" {
-		confFilePath = configFilePathFromEnv
-	} else {
-		confFilePath = "./prod.yml"
-	}
-	cfg, err := LoadConfig(confFilePath)
-	if err != nil {
-		panic(err)
-	}
-	YmlConfig = cfg
+
+// Get config loaded on first use from TGBOT_APP_ENV or ./prod.yml, panic if it can not be loaded
+func Get() *Config {
+	loadOnce.Do(func() {
+		var confFilePath string
+		if configFilePathFromEnv := os.Getenv("TGBOT_APP_ENV"); configFilePathFromEnv != "" {
+			confFilePath = configFilePathFromEnv
+		} else {
+			confFilePath = "./prod.yml"
+		}
+		cfg, err := LoadConfig(confFilePath)
+		if err != nil {
+			panic(err)
+		}
+		ymlConfig = cfg
+	})
+	return ymlConfig
 }
@@ -0,0 +1,364 @@
+package copytrade
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/hellodex/tradingbot/alert"
+	"github.com/hellodex/tradingbot/api"
+	"github.com/hellodex/tradingbot/entity"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/queue"
+	"github.com/hellodex/tradingbot/rpc"
+	"github.com/hellodex/tradingbot/store"
+	"github.com/hellodex/tradingbot/trade"
+	"github.com/hellodex/tradingbot/util"
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cast"
+)
+
+var (
+	pollInterval = 15 * time.Second
+	// 多个实例托管同一个 bot 时每笔交易只跟一次
+	mirrorClaimTTL = 24 * time.Hour
+	// 每个用户待跟的交易数量上限, 超过时丢弃
+	mirrorQueueLen = 100
+	// 用户的跟单协程空闲后退出
+	mirrorIdle = time.Minute
+)
+
+type mirrorJob struct {
+	f   follower
+	s   rpc.WalletSwap
+	cfg model.ChainConfig
+}
+
+// chatId -> mirror jobs of user
+var (
+	mirrorMu     sync.Mutex
+	mirrorQueues = map[int64]chan mirrorJob{}
+)
+
+type follower struct {
+	ChatId int64
+	Trade  model.CopyTrade
+	B      *bot.Bot
+}
+
+// user copy trades, sort by created time
+func UserCopyTrades(chatId int64) []model.CopyTrade {
+	data, err := store.UserGetCopyTrades(chatId)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return nil
+	}
+
+	trades := make([]model.CopyTrade, 0, len(data))
+	for _, v := range data {
+		var c model.CopyTrade
+		if err := json.Unmarshal([]byte(v), &c); err != nil {
+			log.Error().Err(err).Send()
+			continue
+		}
+		trades = append(trades, c)
+	}
+	slices.SortFunc(trades, func(a, b model.CopyTrade) int {
+		return cmp.Compare(a.CreatedAt, b.CreatedAt)
+	})
+	return trades
+}
+
+func UserCopyTrade(chatId int64, id string) (model.CopyTrade, bool) {
+	for _, c := range UserCopyTrades(chatId) {
+		if c.Id == id {
+			return c, true
+		}
+	}
+	return model.CopyTrade{}, false
+}
+
+func SaveCopyTrade(chatId int64, c model.CopyTrade) error {
+	data, err := c.JsonB()
+	if err != nil {
+		return err
+	}
+	return store.UserAddCopyTrade(chatId, c.Id, data)
+}
+
+// Run poll followed wallets and mirror their swaps until ctx done
+func Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			poll(ctx)
+		}
+	}
+}
+
+// followers hosted by this instance, group by leader key and bot,
+// so every instance keeps its own cursor of the leader
+func followers() map[string][]follower {
+	users, err := store.CopyTradeUsers()
+	if err != nil {
+		log.Error().Err(err).Send()
+		return nil
+	}
+
+	groups := map[string][]follower{}
+	for _, chatId := range users {
+		b, ok := alert.UserBot(chatId)
+		if !ok {
+			continue
+		}
+		for _, c := range UserCopyTrades(chatId) {
+			if !c.Enabled {
+				continue
+			}
+			key := c.LeaderKey() + ":" + entity.BotIdOf(b)
+			groups[key] = append(groups[key], follower{ChatId: chatId, Trade: c, B: b})
+		}
+	}
+	return groups
+}
+
+func chainConfigs() map[string]model.ChainConfig {
+	chains, err := api.GetChainConfigs()
+	if err != nil {
+		log.Error().Err(err).Send()
+		return nil
+	}
+	cfgs := map[string]model.ChainConfig{}
+	for _, c := range chains.Data {
+		cfgs[c.ChainCode] = c
+	}
+	return cfgs
+}
+
+func poll(ctx context.Context) {
+	groups := followers()
+	if len(groups) == 0 {
+		return
+	}
+	cfgs := chainConfigs()
+	if cfgs == nil {
+		return
+	}
+
+	for key, fs := range groups {
+		leader := fs[0].Trade
+		cfg, ok := cfgs[leader.ChainCode]
+		if !ok {
+			continue
+		}
+
+		cursor := store.GetCopyTradeCursor(key)
+		swaps, next, err := rpc.PollWalletSwaps(leader.ChainCode, leader.Leader, cfg.Wrapped, cursor)
+		if err != nil {
+			log.Error().Err(err).Str("leader", key).Msg("poll copy trade wallet")
+		}
+		if next != cursor {
+			if err := store.SetCopyTradeCursor(key, next); err != nil {
+				log.Error().Err(err).Send()
+			}
+		}
+		if len(swaps) == 0 {
+			continue
+		}
+
+		for _, f := range fs {
+			for _, s := range swaps {
+				enqueue(ctx, mirrorJob{f: f, s: s, cfg: cfg})
+			}
+		}
+	}
+}
+
+// enqueue mirror swap in worker of user, swaps of one user are mirrored in order
+// and delay of one user does not block polling
+func enqueue(ctx context.Context, j mirrorJob) {
+	mirrorMu.Lock()
+	defer mirrorMu.Unlock()
+	ch, ok := mirrorQueues[j.f.ChatId]
+	if !ok {
+		ch = make(chan mirrorJob, mirrorQueueLen)
+		mirrorQueues[j.f.ChatId] = ch
+		go mirrorWorker(ctx, j.f.ChatId, ch)
+	}
+	select {
+	case ch <- j:
+	default:
+		log.Warn().Int64("chatId", j.f.ChatId).Str("tx", j.s.Tx).Msg("copy trade queue full, drop swap")
+	}
+}
+
+func mirrorWorker(ctx context.Context, chatId int64, ch chan mirrorJob) {
+	idle := time.NewTimer(mirrorIdle)
+	defer idle.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case j := <-ch:
+			mirror(ctx, j.f, j.s, j.cfg)
+			idle.Reset(mirrorIdle)
+		case <-idle.C:
+			mirrorMu.Lock()
+			if len(ch) > 0 {
+				mirrorMu.Unlock()
+				idle.Reset(mirrorIdle)
+				continue
+			}
+			delete(mirrorQueues, chatId)
+			mirrorMu.Unlock()
+			return
+		}
+	}
+}
+
+func notify(ctx context.Context, b *bot.Bot, chatId int64, key string, args ...any) {
+	util.QuickMessage(ctx, b, chatId, i18n.L(chatId, key, args...))
+}
+
+func mirror(ctx context.Context, f follower, s rpc.WalletSwap, cfg model.ChainConfig) {
+	c, chatId, b := f.Trade, f.ChatId, f.B
+	if c.Blacklisted(s.Token) || (!s.IsBuy && !c.FollowSell) {
+		return
+	}
+	claimed, err := store.ClaimOnce("copy:"+cast.ToString(chatId)+":"+s.Tx+":"+s.Token, mirrorClaimTTL)
+	if err != nil {
+		log.Error().Err(err).Int64("chatId", chatId).Msg("claim copy trade")
+		return
+	}
+	if !claimed {
+		return
+	}
+	if c.DelaySec > 0 {
+		select {
+		case <-time.After(time.Duration(c.DelaySec) * time.Second):
+		case <-ctx.Done():
+			return
+		}
+	}
+
+	leader := util.ShortAddress(c.Leader)
+	userInfo, err := api.GetUserProfile(chatId)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+	wallet, ok := trade.Wallet(userInfo, c.ChainCode, c.WalletId)
+	if !ok {
+		notify(ctx, b, chatId, "copytrade.skip_wallet", leader)
+		return
+	}
+
+	pos, err := api.GetPositionByWalletAddress(wallet.Wallet, s.Token, c.ChainCode, userInfo)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+	baseToken, quoteToken := pos.Data.BaseToken, pos.Data.QuoteToken
+	if baseToken.Address == "" {
+		return
+	}
+	if !c.LiquidityOK(pos.Data.Tvl) {
+		notify(ctx, b, chatId, "copytrade.skip_liquidity", leader, baseToken.Symbol)
+		return
+	}
+
+	swap := model.Swap{
+		WalletId:   wallet.WalletId,
+		WalletKey:  wallet.WalletKey,
+		Slippage:   userInfo.Data.Slippage,
+		Price:      pos.Data.Price,
+		TradeType:  trade.SwapTradeType,
+		ProfitFlag: 0,
+	}
+
+	var userInputAmount string
+	if s.IsBuy {
+		// 跟单金额按原生币计算, 非原生币交易对无法换算
+		if !cfg.IsNative(quoteToken.Address) {
+			notify(ctx, b, chatId, "copytrade.skip_quote", leader, baseToken.Symbol)
+			return
+		}
+		amount := c.BuyAmount(s.QuoteAmount)
+		if !amount.IsPositive() {
+			return
+		}
+		swap.Type = trade.SwapBuy
+		swap.FromTokenAddress, swap.FromTokenDecimals = quoteToken.Address, cast.ToInt(quoteToken.Decimals)
+		swap.ToTokenAddress, swap.ToTokenDecimals = baseToken.Address, cast.ToInt(baseToken.Decimals)
+		swap.Amount = amount.Shift(int32(swap.FromTokenDecimals)).Truncate(0).String()
+		userInputAmount = amount.String()
+	} else {
+		holding, err := api.GetTokenInfoByWalletAddress(baseToken.Address, wallet.Wallet, c.ChainCode, userInfo)
+		if err != nil {
+			log.Error().Err(err).Send()
+			return
+		}
+		balance, _ := decimal.NewFromString(holding.Amount)
+		// 没有持仓不需要跟卖
+		if !balance.IsPositive() {
+			return
+		}
+		amount := balance
+		// 带单钱包几乎清仓时全部卖出, 避免留下零头
+		if s.SellRatio.LessThan(decimal.NewFromFloat(0.99)) {
+			amount = balance.Mul(s.SellRatio).Truncate(0)
+		}
+		if !amount.IsPositive() {
+			return
+		}
+		swap.Type = trade.SwapSell
+		swap.FromTokenAddress, swap.FromTokenDecimals = baseToken.Address, cast.ToInt(baseToken.Decimals)
+		swap.ToTokenAddress, swap.ToTokenDecimals = quoteToken.Address, cast.ToInt(quoteToken.Decimals)
+		swap.Amount = amount.String()
+		userInputAmount = util.ShiftLeftStr(swap.Amount, baseToken.Decimals)
+	}
+
+	raw, _ := decimal.NewFromString(swap.Amount)
+	if sf, err := trade.Precheck(chatId, userInfo, wallet, pos.Data, s.IsBuy, raw); err != nil {
+		if errors.Is(err, trade.ErrSafetyBlocked) {
+			notify(ctx, b, chatId, "copytrade.skip_safety", leader, baseToken.Symbol, sf.Score)
+			return
+		}
+		log.Debug().Err(err).Int64("chatId", chatId).Msg("copy trade precheck")
+		notify(ctx, b, chatId, "copytrade.skip_check", leader, baseToken.Symbol, trade.Reason(chatId, err, baseToken.Symbol, sf))
+		return
+	}
+
+	if s.IsBuy {
+		notify(ctx, b, chatId, "copytrade.mirror_buy", leader, baseToken.Symbol, userInputAmount, quoteToken.Symbol)
+	} else {
+		notify(ctx, b, chatId, "copytrade.mirror_sell", leader, baseToken.Symbol, userInputAmount, baseToken.Symbol)
+	}
+
+	err = queue.AddProcessingSwapQueue(&queue.SwapPayload{
+		B:               b,
+		SwapBody:        swap,
+		BaseToken:       baseToken,
+		QuoteToken:      quoteToken,
+		UserInfo:        userInfo,
+		UserID:          chatId,
+		HandleWallet:    wallet,
+		UserInputAmount: userInputAmount,
+		Auto:            true,
+	})
+	if err != nil {
+		log.Error().Err(err).Int64("chatId", chatId).Msg("add copy trade swap")
+	}
+}
@@ -2,6 +2,7 @@ package entity
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/go-telegram/bot"
 )
@@ -20,3 +21,13 @@ var BotConfigs []BotConfig
 var BotMap = make(map[int64]*bot.Bot)
 var BotConfigMap = make(map[int64]BotConfig)
 var UserBotConfigMap = make(map[int64][]BotConfig)
+
+// BotIdOf telegram id of bot in BotMap, empty if not found
+func BotIdOf(b *bot.Bot) string {
+	for id, v := range BotMap {
+		if v == b {
+			return strconv.FormatInt(id, 10)
+		}
+	}
+	return ""
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/i18n"
 )
 
 type BOT_CALLBACK_DATA_CODE = string
@@ -18,11 +19,27 @@ const (
 	SWITCH_PUBLIC_CHAIN   BOT_CALLBACK_DATA_CODE = "code::switch_public_chain"
 	TRANSFER_OUT          BOT_CALLBACK_DATA_CODE = "code::transfer_out"
 	SETTING_SLIPPY        BOT_CALLBACK_DATA_CODE = "code::setting_slippy"
+	SETTING_TRADE_PRESET  BOT_CALLBACK_DATA_CODE = "code::setting_trade_preset"
+	SETTING_ADDRESS_BOOK  BOT_CALLBACK_DATA_CODE = "code::setting_address_book"
+	SETTING_SECURITY      BOT_CALLBACK_DATA_CODE = "code::setting_security"
 
 	ORDER_FOLLOW          BOT_CALLBACK_DATA_CODE = "code::order_follow"
 	ADD_ORDER_FOLLOW      BOT_CALLBACK_DATA_CODE = "code::add_order_follow"
 	ORDER_FOLLOW_ALL_STOP BOT_CALLBACK_DATA_CODE = "code::order_follow_all_stop"
 
+	SNIPER    BOT_CALLBACK_DATA_CODE = "code::sniper"
+	ADD_SNIPE BOT_CALLBACK_DATA_CODE = "code::add_snipe"
+
+	COMPOUND_ALERT     BOT_CALLBACK_DATA_CODE = "code::compound_alert"
+	ADD_COMPOUND_ALERT BOT_CALLBACK_DATA_CODE = "code::add_compound_alert"
+	ALERT_DELIVERY     BOT_CALLBACK_DATA_CODE = "code::alert_delivery"
+	ALERT_HISTORY      BOT_CALLBACK_DATA_CODE = "code::alert_history"
+
+	SMART_MONEY      BOT_CALLBACK_DATA_CODE = "code::smart_money"
+	ADD_SMART_WALLET BOT_CALLBACK_DATA_CODE = "code::add_smart_wallet"
+
+	TOKEN_FEED BOT_CALLBACK_DATA_CODE = "code::token_feed"
+
 	ORDER_PENDING             BOT_CALLBACK_DATA_CODE = "code::order_pending"
 	ADD_ORDER_PENDING         BOT_CALLBACK_DATA_CODE = "code::add_order_pending"
 	ORDER_PENDING_IN_Progress BOT_CALLBACK_DATA_CODE = "code::order_pending_in_progress"
@@ -61,11 +78,27 @@ var CallbackTextMap = map[BOT_CALLBACK_DATA_CODE]string{
 	SWITCH_PUBLIC_CHAIN:   "切换公链",
 	TRANSFER_OUT:          "转出",
 	SETTING_SLIPPY:        "滑点设置",
+	SETTING_TRADE_PRESET:  "快捷买卖设置",
+	SETTING_ADDRESS_BOOK:  "📒地址簿",
+	SETTING_SECURITY:      "🔐安全设置",
 
 	ORDER_FOLLOW:          "跟单",
 	ADD_ORDER_FOLLOW:      "新增跟单",
 	ORDER_FOLLOW_ALL_STOP: "全部暂停",
 
+	SNIPER:    "🎯狙击",
+	ADD_SNIPE: "新增狙击",
+
+	COMPOUND_ALERT:     "🧩组合监控",
+	ADD_COMPOUND_ALERT: "新增组合监控",
+	ALERT_DELIVERY:     "🔔提醒设置",
+	ALERT_HISTORY:      "📜提醒记录",
+
+	SMART_MONEY:      "🐋聪明钱",
+	ADD_SMART_WALLET: "关注钱包",
+
+	TOKEN_FEED: "🆕新币推送",
+
 	ORDER_PENDING:             "挂单",
 	ADD_ORDER_PENDING:         "添加挂单",
 	ORDER_PENDING_IN_Progress: "进行中的挂单",
@@ -115,6 +148,22 @@ func GetCallbackButton(code BOT_CALLBACK_DATA_CODE) CallbackButton {
 	}
 }
 
+// button text with user language, fallback to CallbackTextMap
+func CallbackTextLang(code BOT_CALLBACK_DATA_CODE, lang string) string {
+	if i18n.Has(lang, code) {
+		return i18n.T(lang, code)
+	}
+	return CallbackTextMap[code]
+}
+
+// build callback button with user language
+func GetCallbackButtonLang(code BOT_CALLBACK_DATA_CODE, lang string) CallbackButton {
+	return CallbackButton{
+		CallbackData: code,
+		Text:         CallbackTextLang(code, lang),
+	}
+}
+
 // split callback data
 func SplitCallbackData(code BOT_CALLBACK_DATA_CODE) []string {
 	return strings.Split(code, "::")
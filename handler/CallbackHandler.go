@@ -3,6 +3,7 @@ package handler
 import (
 	"context"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/go-telegram/bot"
@@ -11,6 +12,7 @@ import (
 	"github.com/hellodex/tradingbot/entity"
 	"github.com/hellodex/tradingbot/handler/callback"
 	"github.com/hellodex/tradingbot/handler/commands"
+	"github.com/hellodex/tradingbot/i18n"
 	"github.com/hellodex/tradingbot/model"
 	"github.com/hellodex/tradingbot/session"
 	"github.com/hellodex/tradingbot/store"
@@ -31,10 +33,16 @@ func DebugMiddlewares(next bot.HandlerFunc) bot.HandlerFunc {
 
 func SetBotHandler(next bot.HandlerFunc) bot.HandlerFunc {
 	return func(ctx context.Context, bot *bot.Bot, update *models.Update) {
+		// group member don't need profile
+		if update.Message != nil && update.Message.Chat.Type != models.ChatTypePrivate {
+			next(ctx, bot, update)
+			return
+		}
+
 		userId := util.EffectId(update)
 		userInfo, err := api.GetUserProfile(userId)
 		if err == nil {
-			err = store.UserSetBot(userId, userInfo.Data.UUID)
+			err = store.UserSetBot(userId, entity.BotIdOf(bot), userInfo.Data.UUID)
 			if err != nil {
 				log.Error().Err(err).Send()
 			}
@@ -46,6 +54,14 @@ func SetBotHandler(next bot.HandlerFunc) bot.HandlerFunc {
 	}
 }
 
+// remember telegram language_code, fallback when user not set language
+func LangMiddleware(next bot.HandlerFunc) bot.HandlerFunc {
+	return func(ctx context.Context, bot *bot.Bot, update *models.Update) {
+		i18n.RememberTgLang(update)
+		next(ctx, bot, update)
+	}
+}
+
 var limitCount = int64(75)
 
 func Limit(next bot.HandlerFunc) bot.HandlerFunc {
@@ -68,6 +84,7 @@ func Limit(next bot.HandlerFunc) bot.HandlerFunc {
 
 func GetCallbackHandler() []bot.Option {
 	botOptions := []bot.Option{
+		bot.WithMiddlewares(LangMiddleware),
 		bot.WithMiddlewares(Limit),
 		bot.WithMiddlewares(SetBotHandler),
 		// // debug update msg
@@ -104,6 +121,21 @@ func GetCallbackHandler() []bot.Option {
 		// setting handler
 		bot.WithCallbackQueryDataHandler(entity.SETTING, bot.MatchTypeExact, callback.SettingHandler),
 		bot.WithCallbackQueryDataHandler(entity.SETTING_SLIPPY, bot.MatchTypeExact, callback.SlippyHandler),
+		bot.WithCallbackQueryDataHandler(entity.SETTING_TRADE_PRESET, bot.MatchTypeExact, callback.TradePresetHandler),
+		bot.WithCallbackQueryDataHandler("tpChain::", bot.MatchTypePrefix, callback.CallbackTradePresetChain),
+		bot.WithCallbackQueryDataHandler("tpSet::", bot.MatchTypePrefix, callback.CallbackTradePresetSet),
+
+		// address book
+		bot.WithCallbackQueryDataHandler(entity.SETTING_ADDRESS_BOOK, bot.MatchTypeExact, callback.AddressBookHandler),
+		bot.WithCallbackQueryDataHandler("ab::", bot.MatchTypePrefix, callback.CallbackAddressBook),
+		bot.WithCallbackQueryDataHandler("abTx::", bot.MatchTypePrefix, AddressBookTransferCallBack),
+		bot.WithCallbackQueryDataHandler("abWd::", bot.MatchTypePrefix, callback.CallbackAddressBookWithdrawal),
+		bot.WithCallbackQueryDataHandler(entity.SETTING_SECURITY, bot.MatchTypeExact, callback.SecurityHandler),
+		bot.WithCallbackQueryDataHandler("pin::", bot.MatchTypePrefix, callback.CallbackPin),
+		bot.WithCallbackQueryDataHandler("wl::", bot.MatchTypePrefix, callback.CallbackWhitelist),
+		bot.WithCallbackQueryDataHandler("sf::", bot.MatchTypePrefix, callback.CallbackSafety),
+		bot.WithCallbackQueryDataHandler(entity.LANG, bot.MatchTypeExact, callback.LangHandler),
+		bot.WithCallbackQueryDataHandler("lang::", bot.MatchTypePrefix, callback.CallbackSelectLang),
 
 		// setting Assets
 		bot.WithCallbackQueryDataHandler(entity.ASSETS, bot.MatchTypeExact, callback.AssetsHandler),
@@ -116,11 +148,39 @@ func GetCallbackHandler() []bot.Option {
 		bot.WithCallbackQueryDataHandler("list_swap", bot.MatchTypeExact, commands.TradeHistoryHandler),
 		bot.WithCallbackQueryDataHandler("list_transfer", bot.MatchTypeExact, commands.TransferHistoryHandler),
 		bot.WithCallbackQueryDataHandler(entity.HistoryOrder, bot.MatchTypeExact, callback.OrdersList),
+		bot.WithCallbackQueryDataHandler("hist::", bot.MatchTypePrefix, callback.CallbackHistory),
+		bot.WithCallbackQueryDataHandler("exp::", bot.MatchTypePrefix, callback.CallbackExport),
 
-		// order flow
-		bot.WithCallbackQueryDataHandler(entity.OrderTrade, bot.MatchTypeExact, callback.CallbackOrderFollow),
+		// portfolio
+		bot.WithCallbackQueryDataHandler("pf::", bot.MatchTypePrefix, callback.CallbackPortfolio),
 
-		bot.WithCallbackQueryDataHandler(entity.BUY_SELL, bot.MatchTypeExact, callback.CallbackOrderFollow),
+		// order flow
+		bot.WithCallbackQueryDataHandler(entity.OrderTrade, bot.MatchTypeExact, callback.CallbackInputContractAddress),
+
+		bot.WithCallbackQueryDataHandler(entity.BUY_SELL, bot.MatchTypeExact, callback.CallbackInputContractAddress),
+
+		// copy trade
+		bot.WithCallbackQueryDataHandler(entity.ORDER_FOLLOW, bot.MatchTypeExact, callback.CallbackOrderFollow),
+		bot.WithCallbackQueryDataHandler(entity.ADD_ORDER_FOLLOW, bot.MatchTypeExact, callback.CallbackAddOrderFollow),
+		bot.WithCallbackQueryDataHandler(entity.ORDER_FOLLOW_ALL_STOP, bot.MatchTypeExact, callback.CallbackOrderFollowAllStop),
+		bot.WithCallbackQueryDataHandler("cp::", bot.MatchTypePrefix, callback.CallbackCopyTrade),
+		bot.WithCallbackQueryDataHandler(entity.SNIPER, bot.MatchTypeExact, callback.CallbackSniper),
+		bot.WithCallbackQueryDataHandler(entity.ADD_SNIPE, bot.MatchTypeExact, callback.CallbackAddSnipe),
+		bot.WithCallbackQueryDataHandler("sn::", bot.MatchTypePrefix, callback.CallbackSnipe),
+		bot.WithCallbackQueryDataHandler(entity.COMPOUND_ALERT, bot.MatchTypeExact, callback.CallbackCompoundAlert),
+		bot.WithCallbackQueryDataHandler(entity.ADD_COMPOUND_ALERT, bot.MatchTypeExact, callback.CallbackAddCompoundAlert),
+		bot.WithCallbackQueryDataHandler("ca::", bot.MatchTypePrefix, callback.CallbackCompoundAlertAction),
+		bot.WithCallbackQueryDataHandler(entity.ALERT_DELIVERY, bot.MatchTypeExact, callback.CallbackAlertDelivery),
+		bot.WithCallbackQueryDataHandler("ad::", bot.MatchTypePrefix, callback.CallbackAlertDeliveryAction),
+		bot.WithCallbackQueryDataHandler("aa::", bot.MatchTypePrefix, callback.CallbackAlertAction),
+		bot.WithCallbackQueryDataHandler(entity.ALERT_HISTORY, bot.MatchTypeExact, callback.CallbackAlertHistory),
+		bot.WithCallbackQueryDataHandler("ah::", bot.MatchTypePrefix, callback.CallbackAlertHistoryAction),
+		bot.WithCallbackQueryDataHandler(entity.SMART_MONEY, bot.MatchTypeExact, callback.CallbackSmartMoney),
+		bot.WithCallbackQueryDataHandler(entity.ADD_SMART_WALLET, bot.MatchTypeExact, callback.CallbackAddSmartWallet),
+		bot.WithCallbackQueryDataHandler("sm::", bot.MatchTypePrefix, callback.CallbackSmartWallet),
+		bot.WithCallbackQueryDataHandler("smq::", bot.MatchTypePrefix, callback.CallbackSmartMoneyBuy),
+		bot.WithCallbackQueryDataHandler(entity.TOKEN_FEED, bot.MatchTypeExact, callback.CallbackTokenFeed),
+		bot.WithCallbackQueryDataHandler("tf::", bot.MatchTypePrefix, callback.CallbackTokenFeedAction),
 
 		// invite handler
 		bot.WithCallbackQueryDataHandler(entity.InviteButton, bot.MatchTypeExact, callback.InviteHandler),
@@ -131,6 +191,12 @@ func GetCallbackHandler() []bot.Option {
 		// reflash
 		bot.WithCallbackQueryDataHandler(entity.ReflashTokenInfo, bot.MatchTypeExact, callback.ReflashTokenInfo),
 
+		// watchlist
+		bot.WithCallbackQueryDataHandler("watch::", bot.MatchTypePrefix, callback.CallbackWatchlist),
+
+		// chart
+		bot.WithCallbackQueryDataHandler("chart::", bot.MatchTypePrefix, callback.CallbackChart),
+
 		bot.WithCallbackQueryDataHandler("go_menu", bot.MatchTypeExact, commands.MenuHandler),
 
 		// ai monitor handler
@@ -161,6 +227,9 @@ func GetCallbackHandler() []bot.Option {
 		bot.WithCallbackQueryDataHandler("enable_current_", bot.MatchTypePrefix, callback.CallbackHandlerEnableTokenAiMonitor),
 		bot.WithCallbackQueryDataHandler("delete_current_", bot.MatchTypePrefix, callback.CallBackDeleteCurrentAimonitor),
 		bot.WithCallbackQueryDataHandler("aiL:", bot.MatchTypePrefix, callback.CallbackHendlerSelectEditAimonitor),
+
+		// group setting
+		bot.WithCallbackQueryDataHandler("grp::", bot.MatchTypePrefix, callback.CallbackGroupSetting),
 	}
 
 	return botOptions
@@ -171,7 +240,8 @@ func WrapHandlerCallback(next bot.HandlerFunc) bot.HandlerFunc {
 		chatID := util.EffectId(update)
 
 		// handle user send new token address
-		if update.Message != nil && update.Message.Text != "" {
+		// group message is handled by GroupMessageHandler
+		if update.Message != nil && update.Message.Text != "" && update.Message.Chat.Type == models.ChatTypePrivate {
 			if util.IsCryproAddress(update.Message.Text) {
 				session.GetSessionManager().Set(chatID, session.UserSelectTokenAddressCache, update.Message.Text)
 				v, has := store.Get(chatID, store.TradeSession)
@@ -198,6 +268,12 @@ func WrapHandlerCallback(next bot.HandlerFunc) bot.HandlerFunc {
 			callback := update.CallbackQuery
 			util.CallBackAnswer(ctx, b, callback)
 
+			// never trade in group, only group setting button works
+			if callback.Message.Message != nil && callback.Message.Message.Chat.Type != models.ChatTypePrivate &&
+				!strings.HasPrefix(callback.Data, "grp::") {
+				return
+			}
+
 			onCallbackTime := callback.Message.Message.Date
 			initTime := os.Getenv("BOT_INIT_TIMESTAMPS")
 			if initTime != "" {
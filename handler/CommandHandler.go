@@ -3,11 +3,15 @@ package handler
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/config"
 	"github.com/hellodex/tradingbot/handler/callback"
 	"github.com/hellodex/tradingbot/handler/commands"
+	"github.com/hellodex/tradingbot/i18n"
 	"github.com/rs/zerolog/log"
 )
 
@@ -28,23 +32,37 @@ const (
 	ordersHistory   command = "/order_history"
 	currentOrders   command = "/current_orders"
 	aiMonitor       command = "/ai_monitor"
+	watchlist       command = "/watchlist"
+	portfolio       command = "/portfolio"
+	copyTrade       command = "/copy_trade"
+	sniper          command = "/sniper"
+	alertsHistory   command = "/alerts_history"
+
+	// admin
+	replayPush command = "/replay_push"
+	pushDead   command = "/push_dead"
 )
 
 type cmd struct {
 	Name command
-	Desc string
+	Desc string // i18n key
 }
 
 var commandDescList = []cmd{
-	{Name: start, Desc: "开始使用机器人"},
-	{Name: menu, Desc: "功能菜单"},
-	{Name: assets, Desc: "管理资产"},
-	{Name: wallets, Desc: "管理钱包"},
-	{Name: tradeHistory, Desc: "历史交易记录"},
-	{Name: transferHistory, Desc: "历史转账记录"},
-	{Name: ordersHistory, Desc: "历史委托记录"},
-	{Name: currentOrders, Desc: "当前委托"},
-	{Name: aiMonitor, Desc: "AI监控"},
+	{Name: start, Desc: "cmd.start"},
+	{Name: menu, Desc: "cmd.menu"},
+	{Name: assets, Desc: "cmd.assets"},
+	{Name: wallets, Desc: "cmd.wallets"},
+	{Name: tradeHistory, Desc: "cmd.trade_history"},
+	{Name: transferHistory, Desc: "cmd.transfer_history"},
+	{Name: ordersHistory, Desc: "cmd.order_history"},
+	{Name: currentOrders, Desc: "cmd.current_orders"},
+	{Name: aiMonitor, Desc: "cmd.ai_monitor"},
+	{Name: watchlist, Desc: "cmd.watchlist"},
+	{Name: portfolio, Desc: "cmd.portfolio"},
+	{Name: copyTrade, Desc: "cmd.copy_trade"},
+	{Name: sniper, Desc: "cmd.sniper"},
+	{Name: alertsHistory, Desc: "cmd.alerts_history"},
 }
 
 //	var commandDesc = map[string]string{
@@ -70,6 +88,21 @@ var commandHandlerMap = map[string]commandHandler{
 	ordersHistory:   commands.OpenOrdersHistoryHandler,
 	currentOrders:   commands.OpenOrdersHandler,
 	aiMonitor:       callback.CallbackAIMonitorMenu,
+	watchlist:       callback.WatchlistHandler,
+	portfolio:       callback.PortfolioHandler,
+	copyTrade:       callback.CallbackOrderFollow,
+	sniper:          callback.CallbackSniper,
+	alertsHistory:   callback.CallbackAlertHistory,
+}
+
+// admin commands take args and are not in bot commands, only for config env.admins
+var adminCommandMap = map[string]commandHandler{
+	replayPush: commands.ReplayPushHandler,
+	pushDead:   commands.PushDeadHandler,
+}
+
+func isAdmin(chatId int64) bool {
+	return slices.Contains(config.Get().Env.Admins, chatId)
 }
 
 var _ = func() any {
@@ -87,28 +120,55 @@ func CommandHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 
 	if handler, exists := commandHandlerMap[update.Message.Text]; exists {
 		handler(ctx, b, update)
+		return
 	}
-}
 
-func SetBotCommand(ctx context.Context, bots []*bot.Bot) {
-	cmds := make([]models.BotCommand, 0, len(bots))
+	name := strings.Fields(update.Message.Text)[0]
+	if handler, exists := adminCommandMap[name]; exists && isAdmin(update.Message.Chat.ID) {
+		handler(ctx, b, update)
+	}
+}
 
-	for _, cmd := range commandDescList {
+func botCommands(list []cmd, lang string) []models.BotCommand {
+	cmds := make([]models.BotCommand, 0, len(list))
+	for _, cmd := range list {
 		cmds = append(cmds, models.BotCommand{
 			Command:     cmd.Name,
-			Description: cmd.Desc,
+			Description: i18n.T(lang, cmd.Desc),
 		})
 	}
+	return cmds
+}
 
+func SetBotCommand(ctx context.Context, bots []*bot.Bot) {
 	for _, b := range bots {
-		ok, err := b.SetMyCommands(ctx, &bot.SetMyCommandsParams{
-			Commands: cmds,
-		})
-		if err != nil {
-			log.Error().Err(err).Msg("bot set commands err")
-		}
-		if ok {
-			log.Info().Msg("bot command all set!")
+		for _, lang := range i18n.Langs {
+			// default language without language_code, used by all other users
+			languageCode := lang
+			if lang == i18n.Default {
+				languageCode = ""
+			}
+
+			ok, err := b.SetMyCommands(ctx, &bot.SetMyCommandsParams{
+				Commands:     botCommands(commandDescList, lang),
+				Scope:        &models.BotCommandScopeAllPrivateChats{},
+				LanguageCode: languageCode,
+			})
+			if err != nil {
+				log.Error().Err(err).Str("lang", lang).Msg("bot set commands err")
+			}
+			if ok {
+				log.Info().Str("lang", lang).Msg("bot command all set!")
+			}
+
+			_, err = b.SetMyCommands(ctx, &bot.SetMyCommandsParams{
+				Commands:     botCommands(groupCommandDescList, lang),
+				Scope:        &models.BotCommandScopeAllGroupChats{},
+				LanguageCode: languageCode,
+			})
+			if err != nil {
+				log.Error().Err(err).Str("lang", lang).Msg("bot set group commands err")
+			}
 		}
 	}
 }
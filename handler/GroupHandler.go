@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/handler/callback"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/store"
+	"github.com/hellodex/tradingbot/util"
+)
+
+const (
+	groupPrice    command = "/p"
+	groupSettings command = "/groupsettings"
+)
+
+var groupCommandDescList = []cmd{
+	{Name: groupPrice, Desc: "cmd.group_price"},
+	{Name: groupSettings, Desc: "cmd.group_settings"},
+}
+
+// find first token address in text
+func findTokenAddress(text string) string {
+	for _, word := range strings.Fields(text) {
+		if util.IsNativeCoion(word) {
+			continue
+		}
+		if _, err := util.CheckValidAddress(word); err == nil && util.IsCryproAddress(word) {
+			return word
+		}
+	}
+	return ""
+}
+
+// group and supergroup message, only show token card, never trade here
+func GroupMessageHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	message := update.Message
+	text := message.Text
+	if text == "" {
+		text = message.Caption
+	}
+	if text == "" {
+		return
+	}
+	botUserName := store.GetEnv(store.BOT_USERNAME)
+
+	if util.IsCommand(text) {
+		fields := strings.Fields(text)
+		// /p@botname
+		name, target, _ := strings.Cut(fields[0], "@")
+		if target != "" && !strings.EqualFold(target, botUserName) {
+			return
+		}
+
+		switch name {
+		case groupSettings:
+			callback.GroupSettingHandler(ctx, b, update)
+		case groupPrice:
+			address := findTokenAddress(strings.Join(fields[1:], " "))
+			if address == "" {
+				util.QuickMessage(ctx, b, message.Chat.ID, i18n.L(util.EffectId(update), "group.usage_price"))
+				return
+			}
+			callback.SendGroupTokenCard(ctx, b, message, address, true)
+		}
+		return
+	}
+
+	address := findTokenAddress(text)
+	if address == "" {
+		return
+	}
+
+	if callback.GroupSettingOf(message.Chat.ID).Quiet {
+		if botUserName == "" || !strings.Contains(strings.ToLower(text), "@"+strings.ToLower(botUserName)) {
+			return
+		}
+		callback.SendGroupTokenCard(ctx, b, message, address, true)
+		return
+	}
+
+	callback.SendGroupTokenCard(ctx, b, message, address, false)
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/api"
+	"github.com/hellodex/tradingbot/handler/callback"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/store"
+	"github.com/hellodex/tradingbot/template"
+	"github.com/hellodex/tradingbot/util"
+	"github.com/rs/zerolog/log"
+)
+
+// max token cards in one inline answer
+const inlineMaxResults = 5
+
+// @bot <address or symbol>
+func InlineQueryHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	if update.InlineQuery == nil {
+		return
+	}
+	userID := util.EffectId(update)
+	query := strings.TrimSpace(update.InlineQuery.Query)
+	botUserName := store.GetEnv(store.BOT_USERNAME)
+	lang := i18n.UserLang(userID)
+
+	answer := &bot.AnswerInlineQueryParams{
+		InlineQueryID: update.InlineQuery.ID,
+		CacheTime:     30,
+		IsPersonal:    true,
+		Results:       []models.InlineQueryResult{},
+		Button: &models.InlineQueryResultsButton{
+			Text:           i18n.T(lang, "inline.open_bot"),
+			StartParameter: "inline",
+		},
+	}
+
+	if query == "" {
+		if _, err := b.AnswerInlineQuery(ctx, answer); err != nil {
+			log.Error().Err(err).Send()
+		}
+		return
+	}
+
+	// 没有注册的用户也可以查询, 只是分享链接不带邀请码
+	inviteCode := ""
+	userInfo, err := api.GetUserProfile(userID)
+	if err == nil {
+		inviteCode = userInfo.Data.InviteCode
+	}
+
+	addresses := func() []string {
+		if _, err := util.CheckValidAddress(query); err == nil && !util.IsNativeCoion(query) {
+			return []string{query}
+		}
+
+		// search symbol in user default wallet tokens
+		if userInfo.Data.UUID == "" {
+			return nil
+		}
+		dw, _, chainCode := callback.UserDefaultWalletInfo(userInfo)
+		tokens, err := api.GetTokensByWalletAddress(dw.Wallet, chainCode, userInfo)
+		if err != nil {
+			log.Error().Err(err).Send()
+			return nil
+		}
+		var result []string
+		for _, t := range tokens.Data {
+			if util.IsNativeCoion(t.Address) {
+				continue
+			}
+			if strings.HasPrefix(strings.ToUpper(t.Symbol), strings.ToUpper(query)) {
+				result = append(result, t.Address)
+			}
+			if len(result) >= inlineMaxResults {
+				break
+			}
+		}
+		return result
+	}()
+
+	for _, address := range addresses {
+		info := api.SearchTokenInfoSwitch(address)
+		if info.Symbol == "" {
+			continue
+		}
+		if info.BaseAddress == "" {
+			info.BaseAddress = address
+		}
+
+		startLink := util.TokenStartLink(botUserName, info.BaseAddress, inviteCode)
+		text, err := template.RanderInlineTokenCard(lang, info, startLink)
+		if err != nil {
+			log.Error().Err(err).Send()
+			continue
+		}
+
+		kb := models.InlineKeyboardMarkup{
+			InlineKeyboard: [][]models.InlineKeyboardButton{
+				{
+					util.UrlButton(i18n.T(lang, "inline.trade"), startLink),
+					util.UrlButton(i18n.T(lang, "token.kline"), fmt.Sprintf("https://hellodex.io/k/%s?chainCode=%s&timeType=15m", info.PairAddress, info.ChainCode)),
+				},
+			},
+		}
+
+		answer.Results = append(answer.Results, &models.InlineQueryResultArticle{
+			ID:          info.BaseAddress,
+			Title:       fmt.Sprintf("%s (%s)", info.Symbol, api.GetChainNameFallbackCode(info.ChainCode)),
+			Description: i18n.T(lang, "inline.description", util.FormatNumber(info.Price), util.FormatNumber(info.MarketCap)),
+			InputMessageContent: &models.InputTextMessageContent{
+				MessageText: text,
+				ParseMode:   models.ParseModeHTML,
+				LinkPreviewOptions: &models.LinkPreviewOptions{
+					IsDisabled: bot.True(),
+				},
+			},
+			ReplyMarkup: kb,
+		})
+	}
+
+	if _, err := b.AnswerInlineQuery(ctx, answer); err != nil {
+		log.Error().Err(err).Send()
+	}
+}
@@ -10,11 +10,21 @@ import (
 )
 
 func TextHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	// inline mode: @bot <address or symbol>
+	if update.InlineQuery != nil {
+		InlineQueryHandler(ctx, b, update)
+		return
+	}
+
 	if update.Message == nil {
 		return
 	}
 
 	if update.Message.Chat.Type != "private" {
+		// group chat only show token card
+		if update.Message.Chat.Type == models.ChatTypeGroup || update.Message.Chat.Type == models.ChatTypeSupergroup {
+			GroupMessageHandler(ctx, b, update)
+		}
 		return
 	}
 
@@ -32,6 +42,46 @@ func TextHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 			callback.HandleSlippyReply(ctx, b, update)
 			return
 		}
+
+		// 快捷买卖设置
+		if callback.IsTradePresetReply(chatID, update.Message.ReplyToMessage.ID) {
+			callback.HandleTradePresetReply(ctx, b, update)
+			return
+		}
+
+		// 地址簿添加地址
+		if callback.IsAddressBookReply(chatID, update.Message.ReplyToMessage.ID) {
+			callback.HandleAddressBookReply(ctx, b, update)
+			return
+		}
+		if callback.IsPinReply(chatID, update.Message.ReplyToMessage.ID) {
+			callback.HandlePinReply(ctx, b, update)
+			return
+		}
+		if callback.IsCopyTradeReply(chatID, update.Message.ReplyToMessage.ID) {
+			callback.HandleCopyTradeReply(ctx, b, update)
+			return
+		}
+		if callback.IsSnipeReply(chatID, update.Message.ReplyToMessage.ID) {
+			callback.HandleSnipeReply(ctx, b, update)
+			return
+		}
+		if callback.IsCompoundAlertReply(chatID, update.Message.ReplyToMessage.ID) {
+			callback.HandleCompoundAlertReply(ctx, b, update)
+			return
+		}
+		if callback.IsAlertDeliveryReply(chatID, update.Message.ReplyToMessage.ID) {
+			callback.HandleAlertDeliveryReply(ctx, b, update)
+			return
+		}
+		if callback.IsAlertActionReply(chatID, update.Message.ReplyToMessage.ID) {
+			callback.HandleAlertActionReply(ctx, b, update)
+			return
+		}
+		if callback.IsSmartWalletReply(chatID, update.Message.ReplyToMessage.ID) {
+			callback.HandleSmartWalletReply(ctx, b, update)
+			return
+		}
 	}
 
 	TokenInfoHandler(ctx, b, update)
@@ -9,11 +9,12 @@ import (
 	"strings"
 	"sync"
 
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/alert"
 	"github.com/hellodex/tradingbot/api"
 	"github.com/hellodex/tradingbot/handler/callback"
+	"github.com/hellodex/tradingbot/i18n"
 	"github.com/hellodex/tradingbot/logger"
 	"github.com/hellodex/tradingbot/model"
 	"github.com/hellodex/tradingbot/queue"
@@ -21,6 +22,7 @@ import (
 	"github.com/hellodex/tradingbot/session"
 	"github.com/hellodex/tradingbot/store"
 	"github.com/hellodex/tradingbot/template"
+	"github.com/hellodex/tradingbot/trade"
 	"github.com/hellodex/tradingbot/util"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -112,7 +114,7 @@ func TokenInfoHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 	userInfo, err := api.GetUserProfile(chatId)
 	if err != nil {
 		log.Error().Err(err).Send()
-		util.QuickMessage(ctx, b, chatId, "出错了，请联系客服")
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 
@@ -226,7 +228,7 @@ func TokenInfoHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 			store.BotMessageAdd()
 			_, err := b.SendMessage(ctx, &bot.SendMessageParams{
 				ChatID:    chatId,
-				Text:      "请选择对应的公链：",
+				Text:      i18n.L(chatId, "trade.select_chain"),
 				ParseMode: "HTML",
 				ReplyMarkup: &models.InlineKeyboardMarkup{
 					InlineKeyboard: buttons,
@@ -261,6 +263,7 @@ func TokenInfoHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 	buyCallBackData := fmt.Sprintf(BUY_BUTTON, tokenInfo.Data.PairAddress, tokenInfo.Data.ChainCode)
 	sellCallBackData := fmt.Sprintf(SELL_BUTTON, tokenInfo.Data.PairAddress, tokenInfo.Data.ChainCode)
 
+	tradePreset := callback.UserTradePreset(chatId, tokenInfo.Data.ChainCode)
 	kb := util.BuySellKeyBoard(util.BuySellKeyBoardData{
 		PoolAddress:         tokenInfo.Data.PairAddress,
 		BuyCallBackData:     buyCallBackData,
@@ -271,6 +274,9 @@ func TokenInfoHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 		QuoteToken:          tokenInfo.Data.QuoteToken.Symbol,
 		QuoteTokenChainCode: tokenInfo.Data.QuoteToken.ChainCode,
 		QuoteTokenAddress:   tokenInfo.Data.QuoteToken.Address,
+		BuyPresets:          tradePreset.Buy,
+		SellPresets:         tradePreset.Sell,
+		Lang:                i18n.UserLang(chatId),
 	})
 
 	// check PairAddress
@@ -281,10 +287,10 @@ func TokenInfoHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 
 	session.GetSessionManager().Set(chatId, session.UserSelectTokenAddressCache, tokenInfo.Data.BaseToken.Address)
 
-	textTemplate, err := template.RanderTokenInfo(tokenInfo)
+	textTemplate, err := template.RanderTokenInfo(i18n.UserLang(chatId), tokenInfo)
 	if err != nil {
 		log.Error().Err(err).Send()
-		util.QuickMessage(ctx, b, chatId, "出错了，请联系客服")
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 	store.BotMessageAdd()
@@ -386,6 +392,7 @@ func CallBackUserSelectChainForToken(ctx context.Context, b *bot.Bot, update *mo
 	buyCallBackData := fmt.Sprintf(BUY_BUTTON, tokenInfo.Data.PairAddress, tokenInfo.Data.ChainCode)
 	sellCallBackData := fmt.Sprintf(SELL_BUTTON, tokenInfo.Data.PairAddress, tokenInfo.Data.ChainCode)
 
+	tradePreset := callback.UserTradePreset(chatId, tokenInfo.Data.ChainCode)
 	kb := util.BuySellKeyBoard(util.BuySellKeyBoardData{
 		PoolAddress:         tokenInfo.Data.PairAddress,
 		BuyCallBackData:     buyCallBackData,
@@ -396,11 +403,14 @@ func CallBackUserSelectChainForToken(ctx context.Context, b *bot.Bot, update *mo
 		QuoteToken:          tokenInfo.Data.QuoteToken.Symbol,
 		QuoteTokenChainCode: tokenInfo.Data.QuoteToken.ChainCode,
 		QuoteTokenAddress:   tokenInfo.Data.QuoteToken.Address,
+		BuyPresets:          tradePreset.Buy,
+		SellPresets:         tradePreset.Sell,
+		Lang:                i18n.UserLang(chatId),
 	})
-	textTemplate, err := template.RanderTokenInfo(tokenInfo)
+	textTemplate, err := template.RanderTokenInfo(i18n.UserLang(chatId), tokenInfo)
 	if err != nil {
 		log.Error().Err(err).Send()
-		util.QuickMessage(ctx, b, chatId, "出错了，请联系客服")
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 	store.BotMessageAdd()
@@ -455,7 +465,7 @@ func CallbackSwitchWalletInChain(ctx context.Context, b *bot.Bot, u *models.Upda
 	for index, wallet := range ws {
 		addr := wallet.Wallet
 		if len(addr) > 10 {
-			displayText := fmt.Sprintf("钱包%d %s....%s", index+1, addr[:4], addr[len(addr)-4:])
+			displayText := i18n.L(userId, "wallet.button", index+1, addr[:4], addr[len(addr)-4:])
 			callbackData := fmt.Sprintf("selectForTrade::%v::%s", userId, wallet.WalletId)
 
 			button := models.InlineKeyboardButton{
@@ -472,7 +482,7 @@ func CallbackSwitchWalletInChain(ctx context.Context, b *bot.Bot, u *models.Upda
 	store.BotMessageAdd()
 	message, err := b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID:      userId,
-		Text:        fmt.Sprintf("你的默认钱包不是当前交易的链,请选择 %s链的钱包", chainCode),
+		Text:        i18n.L(userId, "trade.wallet_not_in_chain", chainCode),
 		ReplyMarkup: keyboard,
 		LinkPreviewOptions: &models.LinkPreviewOptions{
 			IsDisabled: bot.True(),
@@ -511,7 +521,7 @@ func MessageComfromSellectWallet(ctx context.Context, b *bot.Bot, u *models.Upda
 	}
 
 	if dw.Wallet == "" {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 
@@ -550,6 +560,7 @@ func MessageComfromSellectWallet(ctx context.Context, b *bot.Bot, u *models.Upda
 	buyCallBackData := fmt.Sprintf(BUY_BUTTON, tokenInfo.Data.PairAddress, tokenInfo.Data.ChainCode)
 	sellCallBackData := fmt.Sprintf(SELL_BUTTON, tokenInfo.Data.PairAddress, tokenInfo.Data.ChainCode)
 
+	tradePreset := callback.UserTradePreset(chatId, tokenInfo.Data.ChainCode)
 	kb := util.BuySellKeyBoard(util.BuySellKeyBoardData{
 		PoolAddress:         tokenInfo.Data.PairAddress,
 		BuyCallBackData:     buyCallBackData,
@@ -560,11 +571,14 @@ func MessageComfromSellectWallet(ctx context.Context, b *bot.Bot, u *models.Upda
 		QuoteToken:          tokenInfo.Data.QuoteToken.Symbol,
 		QuoteTokenChainCode: tokenInfo.Data.QuoteToken.ChainCode,
 		QuoteTokenAddress:   tokenInfo.Data.QuoteToken.Address,
+		BuyPresets:          tradePreset.Buy,
+		SellPresets:         tradePreset.Sell,
+		Lang:                i18n.UserLang(chatId),
 	})
-	textTemplate, err := template.RanderTokenInfo(tokenInfo)
+	textTemplate, err := template.RanderTokenInfo(i18n.UserLang(chatId), tokenInfo)
 	if err != nil {
 		log.Error().Err(err).Send()
-		util.QuickMessage(ctx, b, chatId, "出错了，请联系客服")
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 	store.BotMessageAdd()
@@ -632,7 +646,7 @@ func BuyCallBackHandler(ctx context.Context, b *bot.Bot, update *models.Update)
 		store.BotMessageAdd()
 		message, err := b.SendMessage(ctx, &bot.SendMessageParams{
 			ChatID:      chatId,
-			Text:        fmt.Sprintf("请输入买入数量，如20则购买20 %s，输入数量后立刻购买 %s ", symbol, symbol),
+			Text:        i18n.L(chatId, "trade.input_buy", symbol, symbol),
 			ReplyMarkup: reply,
 		})
 		if err != nil {
@@ -675,7 +689,7 @@ func SellCallBackHandler(ctx context.Context, b *bot.Bot, update *models.Update)
 		store.BotMessageAdd()
 		message, err := b.SendMessage(ctx, &bot.SendMessageParams{
 			ChatID:      chatId,
-			Text:        fmt.Sprintf("请输入卖出数量，如20则卖出20 %s，输入数量后立刻卖出 %s ", symbol, symbol),
+			Text:        i18n.L(chatId, "trade.input_sell", symbol, symbol),
 			ReplyMarkup: reply,
 		})
 		if err != nil {
@@ -710,7 +724,7 @@ func SellCallBackHandler(ctx context.Context, b *bot.Bot, update *models.Update)
 		store.BotMessageAdd()
 		message, err := b.SendMessage(ctx, &bot.SendMessageParams{
 			ChatID:      chatId,
-			Text:        fmt.Sprintf("请输入卖出百分比，如20则卖出20%% %s，输入数量后立刻卖出 %s ", symbol, symbol),
+			Text:        i18n.L(chatId, "trade.input_sell_percent", symbol, symbol),
 			ReplyMarkup: reply,
 		})
 		if err != nil {
@@ -762,7 +776,7 @@ func TransferToCallBack(ctx context.Context, b *bot.Bot, update *models.Update)
 	store.BotMessageAdd()
 	_, err := b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID:      chatId,
-		Text:        fmt.Sprintf("请输入转出 %s 数量", symbol),
+		Text:        i18n.L(chatId, "transfer.input_amount", symbol),
 		ReplyMarkup: reply,
 	})
 	if err != nil {
@@ -775,9 +789,9 @@ func TransferToCallBack(ctx context.Context, b *bot.Bot, update *models.Update)
 }
 
 const (
-	BUY        string  = "0"
-	SELL       string  = "1"
-	TRADE_TYPE string  = "M"
+	BUY        string  = trade.SwapBuy
+	SELL       string  = trade.SwapSell
+	TRADE_TYPE string  = trade.SwapTradeType
 	PROFITFLAG float64 = 0
 )
 
@@ -850,7 +864,7 @@ func processSwap(ctx context.Context, b *bot.Bot, update *models.Update, isBuy b
 		store.BotMessageAdd()
 		b.SendMessage(ctx, &bot.SendMessageParams{
 			ChatID: chatId,
-			Text:   "请选择钱包",
+			Text:   i18n.L(chatId, "trade.select_wallet"),
 		})
 		return
 	}
@@ -913,7 +927,7 @@ func processSwap(ctx context.Context, b *bot.Bot, update *models.Update, isBuy b
 
 			if err != nil {
 				log.Error().Err(err).Send()
-				util.QuickMessage(ctx, b, chatId, "出错了，请联系客服！")
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error_render"))
 			}
 
 			hasAmount, _ := decimal.NewFromString(tokenInfo.Amount)
@@ -950,15 +964,26 @@ func processSwap(ctx context.Context, b *bot.Bot, update *models.Update, isBuy b
 	// WARN: 无法知道是否能够覆盖这个 swap 的手续费问题
 	//  sell       not enough balance
 	if !isBuy && insufficientBalance {
-		util.QuickMessage(ctx, b, chatId, "你卖出余额不足！")
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "trade.sell_insufficient"))
+		return
+	}
+	// 和自动交易共用的检查: 钱包链, 余额, 风险分
+	amount, _ := decimal.NewFromString(swap.Amount)
+	if sf, err := trade.Precheck(chatId, userInfo, *wallet, tokenInfo.Data, isBuy, amount); err != nil {
+		if errors.Is(err, trade.ErrSafetyBlocked) {
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "safety.blocked", baseToken.Symbol, sf.Score))
+			return
+		}
+		log.Debug().Err(err).Int64("chatId", chatId).Msg("swap precheck")
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "trade.check_failed", trade.Reason(chatId, err, baseToken.Symbol, sf)))
 		return
 	}
 	// WARN:
 	msgqq := func() string {
 		if isBuy {
-			return fmt.Sprintf("🚀 %s 买 %s %s，正在交易中", baseToken.Symbol, userInputAmount, quoteToken.Symbol)
+			return i18n.L(chatId, "trade.buying", baseToken.Symbol, userInputAmount, quoteToken.Symbol)
 		}
-		return fmt.Sprintf("🚀 %s 卖 %s %s，正在交易中", baseToken.Symbol, userInputAmount, baseToken.Symbol)
+		return i18n.L(chatId, "trade.selling", baseToken.Symbol, userInputAmount, baseToken.Symbol)
 	}
 	util.QuickMessage(ctx, b, chatId, msgqq())
 
@@ -1010,11 +1035,11 @@ func processTransferTo(ctx context.Context, b *bot.Bot, update *models.Update) {
 		if !transfer.IsAmountSet() {
 			f, err := cast.ToFloat64E(update.Message.Text)
 			if err != nil {
-				util.QuickMessage(ctx, b, chatId, "数量不能是 0 或负数，请重新转账")
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "transfer.invalid_amount"))
 				return
 			}
 			if f < 0 {
-				util.QuickMessage(ctx, b, chatId, "数量不能是 0 或负数，请重新转账")
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "transfer.invalid_amount"))
 				return
 			}
 			amount := util.ShiftRightStr(update.Message.Text, tokenInfo.Data.BaseToken.Decimals)
@@ -1027,9 +1052,10 @@ func processTransferTo(ctx context.Context, b *bot.Bot, update *models.Update) {
 			store.BotMessageAdd()
 			b.SendMessage(ctx, &bot.SendMessageParams{
 				ChatID:      chatId,
-				Text:        "请输入接收地址：",
+				Text:        i18n.L(chatId, "transfer.input_address"),
 				ReplyMarkup: reply,
 			})
+			callback.SendAddressBookPicker(ctx, b, chatId, tokenInfo.Data.ChainCode, "abTx")
 			// 保存更新后的 transfer
 			session.GetSessionManager().Set(chatId, session.UserInTransferToCache, transfer)
 			return
@@ -1039,13 +1065,10 @@ func processTransferTo(ctx context.Context, b *bot.Bot, update *models.Update) {
 		if !transfer.IsToAddressSet() {
 			address := update.Message.Text
 
-			isSolana, err := util.CheckValidAddress(address)
-			if err != nil {
-
-				// util.QuickMessage(ctx, b, chatId, "接收钱包格式不正确，请检查后重新点击转出")
+			if err := util.CheckChainAddress(tokenInfo.Data.ChainCode, address); err != nil {
 				line := []models.InlineKeyboardButton{
 					{
-						Text:         "🔴转出",
+						Text:         i18n.L(chatId, "trade.transfer"),
 						CallbackData: "tx_" + tokenInfo.Data.BaseToken.Address,
 					},
 				}
@@ -1053,146 +1076,204 @@ func processTransferTo(ctx context.Context, b *bot.Bot, update *models.Update) {
 				store.BotMessageAdd()
 				message, err := b.SendMessage(ctx, &bot.SendMessageParams{
 					ChatID: chatId,
-					Text:   "接收钱包格式不正确，请检查后重新点击转出",
+					Text:   callback.AddressErrText(chatId, err),
 					ReplyMarkup: models.InlineKeyboardMarkup{
 						InlineKeyboard: keyboard,
 					},
 				})
-
-				messageWrap := model.NewMessageWrap(chatId, *message, *tokenInfo)
-				sm := session.GetSessionManager()
-				sm.Set(chatId, session.UserLastSwapMessage, messageWrap)
 				if err != nil {
 					log.Debug().Err(err).Send()
+					return
 				}
-				return
-			}
-
-			if isSolana {
-				transfer.ToAddress = update.Message.Text
-				// check valid evm address
-			} else if common.IsHexAddress(address) {
-				transfer.ToAddress = update.Message.Text
-			} else {
-				// util.QuickMessage(ctx, b, chatId, "接收钱包格式不正确，请检查后重新点击转出")
-				line := []models.InlineKeyboardButton{
-					{
-						Text:         "🔴转出",
-						CallbackData: "tx_" + tokenInfo.Data.BaseToken.Address,
-					},
-				}
-				keyboard := [][]models.InlineKeyboardButton{line}
-				store.BotMessageAdd()
-				message, err := b.SendMessage(ctx, &bot.SendMessageParams{
-					ChatID: chatId,
-					Text:   "接收钱包格式不正确，请检查后重新点击转出",
-					ReplyMarkup: models.InlineKeyboardMarkup{
-						InlineKeyboard: keyboard,
-					},
-				})
 
 				messageWrap := model.NewMessageWrap(chatId, *message, *tokenInfo)
 				sm := session.GetSessionManager()
 				sm.Set(chatId, session.UserLastSwapMessage, messageWrap)
-				if err != nil {
-					log.Debug().Err(err).Send()
-				}
 				return
 			}
-
+			transfer.ToAddress = address
 		}
 
-		userInfo, err := api.GetUserProfile(chatId)
-		if err != nil {
-			log.Error().Err(err).Send()
-			util.QuickMessage(ctx, b, chatId, "出错了，请联系客服")
+		confirmTransferTo(ctx, b, chatId, transfer, tokenInfo)
+		return
+	}
+
+	session.GetSessionManager().Delete(chatId, session.UserInTransferToCache)
+	session.GetSessionManager().Delete(chatId, session.UserSelectWalletCache)
+}
+
+// 检查白名单, 首次转到该地址时先提示用户确认
+func confirmTransferTo(ctx context.Context, b *bot.Bot, chatId int64, transfer *api.TransferTo, tokenInfo *model.PositionByWalletAddress) {
+	if !callback.CheckTransferWhitelist(ctx, b, chatId, tokenInfo.Data.ChainCode, transfer.ToAddress) {
+		// 清空地址, 让用户从地址簿重新选择
+		transfer.ToAddress = ""
+		session.GetSessionManager().Set(chatId, session.UserInTransferToCache, transfer)
+		callback.SendAddressBookPicker(ctx, b, chatId, tokenInfo.Data.ChainCode, "abTx")
+		return
+	}
+
+	if callback.IsKnownAddress(chatId, tokenInfo.Data.ChainCode, transfer.ToAddress) {
+		callback.RequirePin(ctx, b, chatId, func(ctx context.Context) {
+			sendTransferTo(ctx, b, chatId, transfer, tokenInfo)
+		})
+		return
+	}
+
+	session.GetSessionManager().Set(chatId, session.UserInTransferToCache, transfer)
+	store.BotMessageAdd()
+	b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:    chatId,
+		Text:      i18n.L(chatId, "addressbook.new_address_warn", transfer.ToAddress),
+		ParseMode: models.ParseModeHTML,
+		ReplyMarkup: models.InlineKeyboardMarkup{
+			InlineKeyboard: [][]models.InlineKeyboardButton{{
+				util.NewCallbackDataButton(i18n.L(chatId, "addressbook.confirm_send"), "abTx::ok"),
+				util.NewCallbackDataButton(i18n.L(chatId, "addressbook.cancel_send"), "abTx::no"),
+			}},
+		},
+	})
+}
+
+// prefix: abTx::<address book id> / abTx::ok / abTx::no
+func AddressBookTransferCallBack(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatId := util.EffectId(update)
+	action := strings.TrimPrefix(update.CallbackQuery.Data, "abTx::")
+
+	sm := session.GetSessionManager()
+	v, ok := sm.Get(chatId, session.UserInTransferToCache)
+	if !ok {
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "addressbook.transfer_expired"))
+		return
+	}
+	transfer, ok := v.(*api.TransferTo)
+	if !ok || !transfer.IsAmountSet() {
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "addressbook.transfer_expired"))
+		return
+	}
+	t, ok := sm.Get(chatId, session.UserLastSelectTokenCache)
+	if !ok {
+		return
+	}
+	tokenInfo, ok := t.(*model.PositionByWalletAddress)
+	if !ok {
+		return
+	}
+
+	switch action {
+	case "no":
+		sm.Delete(chatId, session.UserInTransferToCache)
+		sm.Delete(chatId, session.UserSelectWalletCache)
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "addressbook.send_cancelled"))
+	case "ok":
+		if !transfer.IsToAddressSet() {
 			return
 		}
+		// 防止重复点击
+		sm.Delete(chatId, session.UserInTransferToCache)
+		callback.RequirePin(ctx, b, chatId, func(ctx context.Context) {
+			sendTransferTo(ctx, b, chatId, transfer, tokenInfo)
+		})
+	default:
+		entry, ok := callback.UserAddressEntry(chatId, action)
+		if !ok || entry.ChainCode != tokenInfo.Data.ChainCode {
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "addressbook.err.chain"))
+			return
+		}
+		transfer.ToAddress = entry.Address
+		sm.Delete(chatId, session.UserInTransferToCache)
+		confirmTransferTo(ctx, b, chatId, transfer, tokenInfo)
+	}
+}
 
-		dw, _, _ := callback.UserDefaultWalletInfo(userInfo)
-		if dw.ChainCode != tokenInfo.Data.ChainCode {
-			log.Debug().Msg("chainCode not match")
-			value, has := session.GetSessionManager().Get(chatId, session.UserSelectWalletCache)
-			if has {
-				wallet, ok := value.(model.Wallet)
-				if ok {
-					dw = wallet
-				}
+func sendTransferTo(ctx context.Context, b *bot.Bot, chatId int64, transfer *api.TransferTo, tokenInfo *model.PositionByWalletAddress) {
+	userInfo, err := api.GetUserProfile(chatId)
+	if err != nil {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+		return
+	}
+
+	dw, _, _ := callback.UserDefaultWalletInfo(userInfo)
+	if dw.ChainCode != tokenInfo.Data.ChainCode {
+		log.Debug().Msg("chainCode not match")
+		value, has := session.GetSessionManager().Get(chatId, session.UserSelectWalletCache)
+		if has {
+			wallet, ok := value.(model.Wallet)
+			if ok {
+				dw = wallet
 			}
 		}
-		transfer.UserInfo = userInfo
-		transfer.WalletId = dw.WalletId
-		transfer.WalletKey = dw.WalletKey
+	}
+	transfer.UserInfo = userInfo
+	transfer.WalletId = dw.WalletId
+	transfer.WalletKey = dw.WalletKey
 
-		// check balance
-		from := cast.ToFloat64(transfer.RawAmount)
-		has := cast.ToFloat64(tokenInfo.Data.RawAmount)
-		fromView := util.ShiftLeftStr(transfer.RawAmount, cast.ToString(tokenInfo.Data.BaseToken.Decimals))
-		hasView := util.ShiftLeftStr(tokenInfo.Data.RawAmount, tokenInfo.Data.BaseToken.Decimals)
-		if from > has {
-			msg := fmt.Sprintf("%s余额不足，余额：%s，转出数量：%s", tokenInfo.Data.BaseToken.Symbol, hasView, fromView)
-			util.QuickMessage(ctx, b, chatId, msg)
-			return
-		}
+	// check balance
+	from := cast.ToFloat64(transfer.RawAmount)
+	has := cast.ToFloat64(tokenInfo.Data.RawAmount)
+	fromView := util.ShiftLeftStr(transfer.RawAmount, cast.ToString(tokenInfo.Data.BaseToken.Decimals))
+	hasView := util.ShiftLeftStr(tokenInfo.Data.RawAmount, tokenInfo.Data.BaseToken.Decimals)
+	if from > has {
+		msg := i18n.L(chatId, "transfer.insufficient", tokenInfo.Data.BaseToken.Symbol, hasView, fromView)
+		util.QuickMessage(ctx, b, chatId, msg)
+		return
+	}
 
-		util.QuickMessage(ctx, b, chatId, "正在转出中")
+	util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "transfer.sending"))
 
-		tx, err := transfer.Send()
-		if err != nil {
-			log.Error().Err(err).Send()
-			if errors.Is(err, api.ErrTransferToAmount) {
-				util.QuickMessage(ctx, b, chatId, err.Error())
-				return
-			} else if errors.Is(err, api.ErrTransferFail) {
-				util.QuickMessage(ctx, b, chatId, err.Error())
-				return
-			}
-			// util.QuickMessage(ctx, b, chatId, "出错了，请联系客服")
-			errMsg := fmt.Sprintf("出错了，%s", util.AdminUrl)
-			util.QuickMessage(ctx, b, chatId, errMsg)
+	tx, err := transfer.Send()
+	if err != nil {
+		log.Error().Err(err).Send()
+		if errors.Is(err, api.ErrTransferToAmount) {
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "transfer.min_amount"))
+			return
+		} else if errors.Is(err, api.ErrTransferFail) {
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "transfer.failed"))
 			return
 		}
+		// util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+		errMsg := i18n.L(chatId, "common.error")
+		util.QuickMessage(ctx, b, chatId, errMsg)
+		return
+	}
 
-		// PollTransactionStatus
-		msg := fmt.Sprintf("交易hash：\n <code>%s</code>", tx)
-		chainCode := func() string {
-			for _, w := range userInfo.Data.Wallets {
-				for _, wallet := range w {
-					if transfer.WalletId == wallet.WalletId {
-						log.Debug().Interface("transfer wallet", wallet).Send()
-						return wallet.ChainCode
-					}
+	callback.MarkAddressUsed(chatId, tokenInfo.Data.ChainCode, transfer.ToAddress)
+
+	// PollTransactionStatus
+	msg := i18n.L(chatId, "transfer.tx_hash", tx)
+	chainCode := func() string {
+		for _, w := range userInfo.Data.Wallets {
+			for _, wallet := range w {
+				if transfer.WalletId == wallet.WalletId {
+					log.Debug().Interface("transfer wallet", wallet).Send()
+					return wallet.ChainCode
 				}
 			}
-			return ""
-		}()
-		scanUrl := util.GetChainScanUrl(chainCode, tx)
-		button := util.UrlButton("点击打开区块浏览器", scanUrl)
-		util.QuickMessageWithButton(ctx, b, chatId, msg, button)
+		}
+		return ""
+	}()
+	scanUrl := util.GetChainScanUrl(chainCode, tx)
+	button := util.UrlButton(i18n.L(chatId, "transfer.open_scan"), scanUrl)
+	util.QuickMessageWithButton(ctx, b, chatId, msg, button)
 
-		util.QuickMessage(ctx, b, chatId, "链上确认中")
+	util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "transfer.confirming"))
 
-		go func() {
-			if chainCode == "" {
-				log.Error().Err(errors.New("get user wallet chainCode err in transferTo")).Send()
-				util.QuickMessage(ctx, b, chatId, "出错了，请联系客服")
-				return
-			}
-			err := rpc.PollTransactionStatus(chainCode, tx)
-			// err := rpc.SOL_PollTransactionStatus(tx)
-			if err != nil {
-				if errors.Is(err, rpc.ErrPollTxMaxRetry) {
-					util.QuickMessage(ctx, b, chatId, err.Error())
-				}
-				return
+	go func() {
+		if chainCode == "" {
+			log.Error().Err(errors.New("get user wallet chainCode err in transferTo")).Send()
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+			return
+		}
+		err := rpc.PollTransactionStatus(chainCode, tx)
+		// err := rpc.SOL_PollTransactionStatus(tx)
+		if err != nil {
+			if errors.Is(err, rpc.ErrPollTxMaxRetry) {
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "transfer.poll_timeout"))
 			}
-			util.QuickMessage(ctx, b, chatId, "交易成功")
-		}()
-		return
-	}
-
-	session.GetSessionManager().Delete(chatId, session.UserInTransferToCache)
-	session.GetSessionManager().Delete(chatId, session.UserSelectWalletCache)
+			return
+		}
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "transfer.success"))
+	}()
 }
 
 func processWithdrawl(ctx context.Context, b *bot.Bot, update *models.Update) {
@@ -1212,42 +1293,42 @@ func processWithdrawl(ctx context.Context, b *bot.Bot, update *models.Update) {
 	subReq := map[string]string{}
 	err := json.Unmarshal(v, &subReq)
 	if err != nil {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 
 	userInfo, err := api.GetUserProfile(chatId)
 	if err != nil {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 
 	cmInfo, err := api.GetMyCommissionSummary(userInfo)
 	if err != nil {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 	withdrawableCommissionAmount := gjson.GetBytes(cmInfo, "data.withdrawableCommissionAmount").String()
 	if subReq["amount"] == "" {
 		f, err := cast.ToFloat64E(update.Message.Text)
 		if err != nil {
-			util.QuickMessage(ctx, b, chatId, "目标价格不能是 0 或负数，请重新提现")
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "withdrawal.invalid_amount"))
 			store.UserDeleteCommissionInfo(chatId)
 			return
 		}
 		if f < 0 {
-			util.QuickMessage(ctx, b, chatId, "目标价格不能是 0 或负数，请重新提现")
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "withdrawal.invalid_amount"))
 			store.UserDeleteCommissionInfo(chatId)
 			return
 		}
 		if f < 10 {
-			util.QuickMessage(ctx, b, chatId, "最少提现 10 U，请重新提现")
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "withdrawal.min_amount"))
 			store.UserDeleteCommissionInfo(chatId)
 			return
 		}
 		if f > cast.ToFloat64(withdrawableCommissionAmount) {
 			store.UserDeleteCommissionInfo(chatId)
-			util.QuickMessage(ctx, b, chatId, "你的可提现余额不足，请重新提现")
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "withdrawal.insufficient"))
 			return
 		}
 
@@ -1260,51 +1341,33 @@ func processWithdrawl(ctx context.Context, b *bot.Bot, update *models.Update) {
 		store.BotMessageAdd()
 		b.SendMessage(ctx, &bot.SendMessageParams{
 			ChatID:      chatId,
-			Text:        "请输入接收地址：",
+			Text:        i18n.L(chatId, "transfer.input_address"),
 			ReplyMarkup: reply,
 		})
 		// 保存更新后的 transfer
 		ddn, err := json.Marshal(subReq)
 		if err != nil {
-			util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 			return
 		}
 		store.UserSetCommissionInfo(chatId, ddn)
+		callback.SendAddressBookPicker(ctx, b, chatId, subReq["chainCode"], "abWd")
 		return
 	}
 
 	if subReq["walletAddress"] == "" {
 		address := update.Message.Text
-		_, err := util.CheckValidAddress(address)
-		if err != nil {
-			util.QuickMessage(ctx, b, chatId, "地址不正确,无法完成提现,请重新提现")
+		if err := util.CheckChainAddress(subReq["chainCode"], address); err != nil {
+			text := i18n.L(chatId, "withdrawal.invalid_address")
+			if errors.Is(err, util.ErrAddressChainNotMatch) {
+				text = i18n.L(chatId, "addressbook.err.chain")
+			}
+			util.QuickMessage(ctx, b, chatId, text)
 			store.UserDeleteCommissionInfo(chatId)
 			return
 		}
 		subReq["walletAddress"] = address
-		ddn, err := json.Marshal(subReq)
-		if err != nil {
-			util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
-			store.UserDeleteCommissionInfo(chatId)
-			return
-		}
-		store.UserSetCommissionInfo(chatId, ddn)
-
-		text := `
-提现金额: $%s
-提现网络: %s
-提现地址: <code>%s</code>
-到账时间: 每天晚上审核通过后
-		`
-		kb := util.WithdrawalKeyBoard()
-
-		store.BotMessageAdd()
-		b.SendMessage(ctx, &bot.SendMessageParams{
-			Text:        fmt.Sprintf(text, subReq["amount"], api.GetChainNameFallbackCode(subReq["chainCode"]), subReq["walletAddress"]),
-			ChatID:      chatId,
-			ReplyMarkup: kb,
-			ParseMode:   "HTML",
-		})
+		callback.SendWithdrawalConfirm(ctx, b, chatId, subReq)
 	}
 }
 
@@ -1324,7 +1387,7 @@ func processAImonitor(ctx context.Context, b *bot.Bot, update *models.Update) {
 	subReq := &model.AISubscribeReqData{}
 	err := json.Unmarshal(v, subReq)
 	if err != nil {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 
@@ -1333,28 +1396,23 @@ func processAImonitor(ctx context.Context, b *bot.Bot, update *models.Update) {
 
 		_, err := util.CheckValidAddress(update.Message.Text)
 		if err != nil {
-			util.QuickMessage(ctx, b, chatId, "输入的代币合约不正确")
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.invalid_token"))
 			return
 		}
 
 		userInfo, err := api.GetUserProfile(chatId)
 		if err != nil {
-			util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 			return
 		}
 
 		monitorType := subReq.MonitorType
 		result, hasSubScribe, err := api.GetUserTokenSubscribe(chatId, subReq.ChainCode, subReq.BaseAddress, monitorType, userInfo)
 		if err != nil {
-			util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 			return
 		}
 
-		templ := `
-%s
-当前价格:$%s
-		`
-
 		log.Debug().RawJSON("getUserTokenSubscribe info", result).Bool("has", hasSubScribe).Send()
 
 		if hasSubScribe {
@@ -1378,37 +1436,16 @@ func processAImonitor(ctx context.Context, b *bot.Bot, update *models.Update) {
 				EnableWeb: lo.Contains(userInfo.Data.SubscribeSetting, "web"),
 				EnableApp: lo.Contains(userInfo.Data.SubscribeSetting, "app"),
 				Frequency: freq_Map[subTokenInfo.NoticeType],
+				Lang:      i18n.UserLang(chatId),
 			}
 			kb := util.AiMonitor_EditSettingsKeyBoard(keyBoardData, monitorType, int(subTokenInfo.NoticeType))
-			tmplll := func() string {
-				switch monitorType {
-				case "price":
-					return `
-HelloDex: AI监控
-当前价格: $%s
-目标价格: $%s
-						`
-				case "chg":
-					return `
-HelloDex: AI监控
-目标涨跌幅: %s
-						`
-				case "buy":
-					return `
-HelloDex: AI监控
-买入交易额: %s
-						`
-				case "sell":
-					return `
-HelloDex: AI监控
-卖出交易额: %s
-						`
-				}
-				return ""
-			}()
+			tmplll := i18n.L(chatId, "aimonitor.data_info."+monitorType)
+			if monitorType == "price" {
+				tmplll = i18n.L(chatId, "aimonitor.price_info")
+			}
 			aiMonitoryInfoByte, has := store.UserGetAiMonitorInfo(chatId)
 			if !has {
-				util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 				return
 			}
 
@@ -1416,7 +1453,7 @@ HelloDex: AI监控
 
 			err := json.Unmarshal(aiMonitoryInfoByte, &subReq)
 			if err != nil {
-				util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 				return
 			}
 
@@ -1441,14 +1478,14 @@ HelloDex: AI监控
 				msg, err := b.SendMessage(ctx, sendParams)
 				if err != nil {
 					log.Error().Err(err).Send()
-					util.QuickMessage(ctx, b, chatId, "出错了,请联系客服")
+					util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 					return
 				}
 				subReq.SessionMessageID = msg.ID
 				dataSendParams, err := json.Marshal(sendParams)
 				if err != nil {
 					log.Error().Err(err).Send()
-					util.QuickMessage(ctx, b, chatId, "出错了,请联系客服")
+					util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 					return
 				}
 				store.RedisSetSendMessageParams(chatId, msg.ID, dataSendParams)
@@ -1462,14 +1499,14 @@ HelloDex: AI监控
 				msg, err := b.SendMessage(ctx, sendParams)
 				if err != nil {
 					log.Error().Err(err).Send()
-					util.QuickMessage(ctx, b, chatId, "出错了,请联系客服")
+					util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 					return
 				}
 				subReq.SessionMessageID = msg.ID
 				dataSendParams, err := json.Marshal(sendParams)
 				if err != nil {
 					log.Error().Err(err).Send()
-					util.QuickMessage(ctx, b, chatId, "出错了,请联系客服")
+					util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 					return
 				}
 				store.RedisSetSendMessageParams(chatId, msg.ID, dataSendParams)
@@ -1477,7 +1514,7 @@ HelloDex: AI监控
 			log.Debug().Msg("user already subscribe token")
 			newData, err := subReq.JsonB()
 			if err != nil {
-				util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 				return
 			}
 			store.UserSetAiMonitorInfo(chatId, newData)
@@ -1498,36 +1535,40 @@ HelloDex: AI监控
 		case "price":
 			reply = models.ForceReply{
 				ForceReply:            true,
-				InputFieldPlaceholder: "请输入目标价格，到达后将会推送消息",
+				InputFieldPlaceholder: i18n.L(chatId, "aimonitor.placeholder.price"),
 			}
 		case "chg":
 			reply = models.ForceReply{
 				ForceReply:            true,
-				InputFieldPlaceholder: "请输入涨跌幅，可以是负数，到达后将会推送消息",
+				InputFieldPlaceholder: i18n.L(chatId, "aimonitor.placeholder.chg"),
 			}
 
 		case "buy":
 			reply = models.ForceReply{
 				ForceReply:            true,
-				InputFieldPlaceholder: "请输入交易额，单笔买入触达后 会推送消息",
+				InputFieldPlaceholder: i18n.L(chatId, "aimonitor.placeholder.buy"),
 			}
 		case "sell":
 			reply = models.ForceReply{
 				ForceReply:            true,
-				InputFieldPlaceholder: "请输入交易额，单笔卖出触达后 会推送消息",
+				InputFieldPlaceholder: i18n.L(chatId, "aimonitor.placeholder.sell"),
 			}
 		}
 
+		text := i18n.L(chatId, "aimonitor.token_price", symbol, price)
+		if subReq.MonitorType == "price" && alert.Enabled() {
+			text += "\n" + i18n.L(chatId, "aimonitor.price_op_hint")
+		}
 		store.BotMessageAdd()
 		b.SendMessage(ctx, &bot.SendMessageParams{
 			ChatID:      chatId,
-			Text:        fmt.Sprintf(templ, symbol, price),
+			Text:        text,
 			ReplyMarkup: reply,
 		})
 		// 保存更新后的
 		newData, err := subReq.JsonB()
 		if err != nil {
-			util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 			return
 		}
 
@@ -1539,24 +1580,28 @@ HelloDex: AI监控
 	// handle price type
 	case "price":
 		if subReq.TargetPrice == "" {
-			f, err := cast.ToFloat64E(update.Message.Text)
+			text := update.Message.Text
+			if alert.Enabled() {
+				subReq.Op, text = model.ParseAlertTarget(text)
+			}
+			f, err := cast.ToFloat64E(text)
 			if err != nil {
-				util.QuickMessage(ctx, b, chatId, "目标价格不能是 0 或负数，请重新添加监听")
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.invalid.price"))
 				return
 			}
 			if f < 0 {
-				util.QuickMessage(ctx, b, chatId, "目标价格不能是 0 或负数，请重新添加监听")
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.invalid.price"))
 				return
 			}
 
-			subReq.TargetPrice = update.Message.Text
+			subReq.TargetPrice = text
 			subReq.NoticeType = 1
 			subReq.MonitorType = "price"
 			subReq.UserId = chatId
 
 			userInfo, err := api.GetUserProfile(chatId)
 			if err != nil {
-				util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 				return
 			}
 
@@ -1565,14 +1610,11 @@ HelloDex: AI监控
 				EnableWeb: lo.Contains(userInfo.Data.SubscribeSetting, "web"),
 				EnableApp: lo.Contains(userInfo.Data.SubscribeSetting, "app"),
 				Frequency: "once", // for default
+				Lang:      i18n.UserLang(chatId),
 			}
 			kb := util.AiMonitorSettingsKeyBoard(keyBoardData)
 
-			tmplll := `
-HelloDex: AI监控
-当前价格: $%s
-目标价格: $%s
-		`
+			tmplll := i18n.L(chatId, "aimonitor.new_info.price")
 
 			store.BotMessageAdd()
 			msg, err := b.SendMessage(ctx, &bot.SendMessageParams{
@@ -1581,13 +1623,13 @@ HelloDex: AI监控
 				ReplyMarkup: kb,
 			})
 			if err != nil {
-				util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 				return
 			}
 			subReq.SessionMessageID = msg.ID
 			newData, err := subReq.JsonB()
 			if err != nil {
-				util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 				return
 			}
 			store.UserSetAiMonitorInfo(chatId, newData)
@@ -1598,11 +1640,11 @@ HelloDex: AI监控
 		if subReq.Data == "" {
 			f, err := cast.ToFloat64E(update.Message.Text)
 			if err != nil {
-				util.QuickMessage(ctx, b, chatId, "目标涨幅不能是 0 或负数，请重新添加监听")
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.invalid.chg"))
 				return
 			}
 			if f < 0 {
-				util.QuickMessage(ctx, b, chatId, "目标涨幅不能是 0 或负数，请重新添加监听")
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.invalid.chg"))
 				return
 			}
 
@@ -1613,7 +1655,7 @@ HelloDex: AI监控
 
 			userInfo, err := api.GetUserProfile(chatId)
 			if err != nil {
-				util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 				return
 			}
 
@@ -1622,14 +1664,11 @@ HelloDex: AI监控
 				EnableWeb: lo.Contains(userInfo.Data.SubscribeSetting, "web"),
 				EnableApp: lo.Contains(userInfo.Data.SubscribeSetting, "app"),
 				Frequency: "once", // for default
+				Lang:      i18n.UserLang(chatId),
 			}
 			kb := util.AiMonitorSettingsKeyBoard(keyBoardData)
 
-			tmplll := `
-HelloDex: AI监控
-当前价格: $%s
-目标涨跌幅: %s
-		`
+			tmplll := i18n.L(chatId, "aimonitor.new_info.chg")
 
 			store.BotMessageAdd()
 			msg, err := b.SendMessage(ctx, &bot.SendMessageParams{
@@ -1638,13 +1677,13 @@ HelloDex: AI监控
 				ReplyMarkup: kb,
 			})
 			if err != nil {
-				util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 				return
 			}
 			subReq.SessionMessageID = msg.ID
 			newData, err := subReq.JsonB()
 			if err != nil {
-				util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 				return
 			}
 			store.UserSetAiMonitorInfo(chatId, newData)
@@ -1654,11 +1693,11 @@ HelloDex: AI监控
 		if subReq.Data == "" {
 			f, err := cast.ToFloat64E(update.Message.Text)
 			if err != nil {
-				util.QuickMessage(ctx, b, chatId, "目标交易额不能是 0 或负数，请重新添加监听")
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.invalid.volume"))
 				return
 			}
 			if f < 0 {
-				util.QuickMessage(ctx, b, chatId, "目标交易额不能是 0 或负数，请重新添加监听")
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.invalid.volume"))
 				return
 			}
 
@@ -1669,7 +1708,7 @@ HelloDex: AI监控
 
 			userInfo, err := api.GetUserProfile(chatId)
 			if err != nil {
-				util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 				return
 			}
 
@@ -1678,14 +1717,11 @@ HelloDex: AI监控
 				EnableWeb: lo.Contains(userInfo.Data.SubscribeSetting, "web"),
 				EnableApp: lo.Contains(userInfo.Data.SubscribeSetting, "app"),
 				Frequency: "once", // for default
+				Lang:      i18n.UserLang(chatId),
 			}
 			kb := util.AiMonitorSettingsKeyBoard(keyBoardData)
 
-			tmplll := `
-HelloDex: AI监控
-当前价格: $%s
-买入交易额: %s
-		`
+			tmplll := i18n.L(chatId, "aimonitor.new_info.buy")
 
 			store.BotMessageAdd()
 			msg, err := b.SendMessage(ctx, &bot.SendMessageParams{
@@ -1694,13 +1730,13 @@ HelloDex: AI监控
 				ReplyMarkup: kb,
 			})
 			if err != nil {
-				util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 				return
 			}
 			subReq.SessionMessageID = msg.ID
 			newData, err := subReq.JsonB()
 			if err != nil {
-				util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 				return
 			}
 			store.UserSetAiMonitorInfo(chatId, newData)
@@ -1710,11 +1746,11 @@ HelloDex: AI监控
 		if subReq.Data == "" {
 			f, err := cast.ToFloat64E(update.Message.Text)
 			if err != nil {
-				util.QuickMessage(ctx, b, chatId, "目标交易额不能是 0 或负数，请重新添加监听")
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.invalid.volume"))
 				return
 			}
 			if f < 0 {
-				util.QuickMessage(ctx, b, chatId, "目标交易额不能是 0 或负数，请重新添加监听")
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.invalid.volume"))
 				return
 			}
 
@@ -1725,7 +1761,7 @@ HelloDex: AI监控
 
 			userInfo, err := api.GetUserProfile(chatId)
 			if err != nil {
-				util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 				return
 			}
 
@@ -1734,14 +1770,11 @@ HelloDex: AI监控
 				EnableWeb: lo.Contains(userInfo.Data.SubscribeSetting, "web"),
 				EnableApp: lo.Contains(userInfo.Data.SubscribeSetting, "app"),
 				Frequency: "once", // for default
+				Lang:      i18n.UserLang(chatId),
 			}
 			kb := util.AiMonitorSettingsKeyBoard(keyBoardData)
 
-			tmplll := `
-HelloDex: AI监控
-当前价格: $%s
-卖出交易额: %s
-		`
+			tmplll := i18n.L(chatId, "aimonitor.new_info.sell")
 
 			store.BotMessageAdd()
 			msg, err := b.SendMessage(ctx, &bot.SendMessageParams{
@@ -1750,13 +1783,13 @@ HelloDex: AI监控
 				ReplyMarkup: kb,
 			})
 			if err != nil {
-				util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 				return
 			}
 			subReq.SessionMessageID = msg.ID
 			newData, err := subReq.JsonB()
 			if err != nil {
-				util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 				return
 			}
 			store.UserSetAiMonitorInfo(chatId, newData)
@@ -1788,11 +1821,11 @@ func extractSwapData(callbackData string) SwapCallbackData {
 
 func CallbackLimitOrder(ctx context.Context, b *bot.Bot, update *models.Update) {
 	chatId := util.EffectId(update)
-	kb := util.LimitOrderKeyBoard()
+	kb := util.LimitOrderKeyBoard(i18n.UserLang(chatId))
 	store.BotMessageAdd()
 	_, err := b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID:      chatId,
-		Text:        "请选择挂单类型：\n\n选择后进行输入数量即可",
+		Text:        i18n.L(chatId, "order.select_type"),
 		ParseMode:   models.ParseModeHTML,
 		ReplyMarkup: kb,
 		LinkPreviewOptions: &models.LinkPreviewOptions{
@@ -1822,20 +1855,16 @@ func ConfirmLimitOrder(ctx context.Context, b *bot.Bot, update *models.Update) {
 		return nil
 	}()
 	if tokenInfo == nil {
-		util.QuickMessage(ctx, b, chatId, "出错了，请联系客服！")
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error_render"))
 		return
 	}
-	textTemplate := `
-当前 %s 币 价格 $%s
-请输入%s%s
-	`
-
-	text := fmt.Sprintf(
-		textTemplate,
+	lang := i18n.UserLang(chatId)
+	text := i18n.T(
+		lang,
+		"order.input_price",
 		tokenInfo.Data.BaseToken.Symbol,
 		util.FormatNumber(tokenInfo.Data.Price),
-		util.GetLimitOrderPrefixText(callbackData),
-		"价格($)",
+		util.GetLimitOrderPrefixText(lang, callbackData),
 	)
 
 	log.Debug().Str("action", action).Str("limitType", limitType).Msg("user ConfirmLimitOrder")
@@ -1901,11 +1930,11 @@ func processLimitOrder(ctx context.Context, b *bot.Bot, update *models.Update) {
 			targetPrice := update.Message.Text
 			f, err := cast.ToFloat64E(targetPrice)
 			if err != nil {
-				util.QuickMessage(ctx, b, chatId, "金额不能是 0 或负数，请重新转账")
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "order.invalid_price"))
 				return
 			}
 			if f < 0 {
-				util.QuickMessage(ctx, b, chatId, "金额不能是 0 或负数，请重新转账")
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "order.invalid_price"))
 				return
 			}
 			order.TargetPrice = targetPrice
@@ -1916,7 +1945,7 @@ func processLimitOrder(ctx context.Context, b *bot.Bot, update *models.Update) {
 			store.BotMessageAdd()
 			b.SendMessage(ctx, &bot.SendMessageParams{
 				ChatID:      chatId,
-				Text:        "请输入数量",
+				Text:        i18n.L(chatId, "order.input_amount"),
 				ReplyMarkup: reply,
 			})
 			// 保存更新后的 transfer
@@ -1928,11 +1957,11 @@ func processLimitOrder(ctx context.Context, b *bot.Bot, update *models.Update) {
 		if !order.IsAmountSet() {
 			f, err := cast.ToFloat64E(update.Message.Text)
 			if err != nil {
-				util.QuickMessage(ctx, b, chatId, "数量不能是 0 或负数，请重新转账")
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "transfer.invalid_amount"))
 				return
 			}
 			if f < 0 {
-				util.QuickMessage(ctx, b, chatId, "数量不能是 0 或负数，请重新转账")
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "transfer.invalid_amount"))
 				return
 			}
 			amount := util.ShiftRightStr(update.Message.Text, tokenInfo.Data.BaseToken.Decimals)
@@ -2002,7 +2031,7 @@ func processLimitOrder(ctx context.Context, b *bot.Bot, update *models.Update) {
 			fromView := util.ShiftLeftStr(order.FromTokenAmount, cast.ToString(order.FromTokenDecimals))
 			hasView := util.ShiftLeftStr(tokenInfo.Data.RawAmount, tokenInfo.Data.BaseToken.Decimals)
 			if from > has {
-				msg := fmt.Sprintf("%s余额不足，余额：%s，交易数量：%s", baseToken.Symbol, hasView, fromView)
+				msg := i18n.L(chatId, "order.insufficient", baseToken.Symbol, hasView, fromView)
 				util.QuickMessage(ctx, b, chatId, msg)
 				return
 			}
@@ -2012,13 +2041,13 @@ func processLimitOrder(ctx context.Context, b *bot.Bot, update *models.Update) {
 		if err != nil {
 			log.Error().Err(err).Send()
 			if errors.Is(err, api.ErrNewOrder) {
-				util.QuickMessage(ctx, b, chatId, api.ErrNewOrder.Error())
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "order.failed"))
 				return
 			}
-			util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了，%s", util.AdminUrl))
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 			return
 		} else {
-			util.QuickMessage(ctx, b, chatId, "挂单成功")
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "order.success"))
 			return
 		}
 	}
@@ -2033,7 +2062,7 @@ func processCurrentAimonitor(ctx context.Context, b *bot.Bot, update *models.Upd
 	}
 	state, has := store.RedisGetState(chatId, "edit_current")
 	if !has {
-		util.QuickMessage(ctx, b, chatId, "消息过期,请重新编辑")
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.expired"))
 		return
 	}
 	logger.StdLogger().Info().Str("state", state).Send()
@@ -2041,32 +2070,36 @@ func processCurrentAimonitor(ctx context.Context, b *bot.Bot, update *models.Upd
 	var subReq model.AISubscribeReqData
 	dataB, exists := store.UserGetAiMonitorInfo(chatId)
 	if !exists {
-		util.QuickMessage(ctx, b, chatId, "出错了看日志")
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 	err := json.Unmarshal(dataB, &subReq)
 	if err != nil {
-		util.QuickMessage(ctx, b, chatId, "出错了看日志")
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 	switch state {
 	case "price":
-		f, err := cast.ToFloat64E(update.Message.Text)
+		text := update.Message.Text
+		if alert.Enabled() {
+			subReq.Op, text = model.ParseAlertTarget(text)
+		}
+		f, err := cast.ToFloat64E(text)
 		if err != nil {
 			logger.StdLogger().Error().Err(err).Send()
-			util.QuickMessage(ctx, b, chatId, "目标价格不能是 0 或负数，请重新编辑监听")
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.edit_invalid.price"))
 			return
 		}
 		if f < 0 {
 			logger.StdLogger().Error().Err(err).Send()
-			util.QuickMessage(ctx, b, chatId, "目标价格不能是 0 或负数，请重新编辑监听")
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.edit_invalid.price"))
 			return
 		}
-		subReq.TargetPrice = update.Message.Text
+		subReq.TargetPrice = text
 		newData, err := subReq.JsonB()
 		if err != nil {
 			logger.StdLogger().Error().Err(err).Send()
-			util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 			return
 		}
 		store.UserSetAiMonitorInfo(chatId, newData)
@@ -2074,18 +2107,18 @@ func processCurrentAimonitor(ctx context.Context, b *bot.Bot, update *models.Upd
 		sendMessageByte, err := store.RedisGetSendMessageParams(chatId, subReq.SessionMessageID)
 		if err != nil {
 			logger.StdLogger().Error().Err(err).Send()
-			util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 			return
 		}
 		var sendMsg bot.SendMessageParams
 		err = json.Unmarshal(sendMessageByte, &sendMsg)
 		if err != nil {
-			util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 			logger.StdLogger().Error().Err(err).Send()
 			return
 		}
 
-		textTmpl := edit_current_tmpl(subReq.MonitorType)
+		textTmpl := edit_current_tmpl(i18n.UserLang(chatId), subReq.MonitorType)
 		textSend := ""
 		currentPrice := util.FormatNumber(subReq.CurrentPrice)
 		targetPrice := util.FormatNumber(subReq.TargetPrice)
@@ -2099,7 +2132,7 @@ func processCurrentAimonitor(ctx context.Context, b *bot.Bot, update *models.Upd
 		store.BotMessageAdd()
 		_, err = b.SendMessage(ctx, &sendMsg)
 		if err != nil {
-			util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 			logger.StdLogger().Error().Err(err).Send()
 			return
 		}
@@ -2114,7 +2147,7 @@ func processCurrentAimonitor(ctx context.Context, b *bot.Bot, update *models.Upd
 		newData, err := subReq.JsonB()
 		if err != nil {
 			logger.StdLogger().Error().Err(err).Send()
-			util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 			return
 		}
 		store.UserSetAiMonitorInfo(chatId, newData)
@@ -2122,18 +2155,18 @@ func processCurrentAimonitor(ctx context.Context, b *bot.Bot, update *models.Upd
 		sendMessageByte, err := store.RedisGetSendMessageParams(chatId, subReq.SessionMessageID)
 		if err != nil {
 			logger.StdLogger().Error().Err(err).Send()
-			util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 			return
 		}
 		var sendMsg bot.SendMessageParams
 		err = json.Unmarshal(sendMessageByte, &sendMsg)
 		if err != nil {
-			util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 			logger.StdLogger().Error().Err(err).Send()
 			return
 		}
 
-		textTmpl := edit_current_tmpl(subReq.MonitorType)
+		textTmpl := edit_current_tmpl(i18n.UserLang(chatId), subReq.MonitorType)
 		textSend := ""
 		currentPrice := util.FormatNumber(subReq.CurrentPrice)
 		targetPrice := util.FormatNumber(subReq.TargetPrice)
@@ -2147,7 +2180,7 @@ func processCurrentAimonitor(ctx context.Context, b *bot.Bot, update *models.Upd
 		store.BotMessageAdd()
 		_, err = b.SendMessage(ctx, &sendMsg)
 		if err != nil {
-			util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 			logger.StdLogger().Error().Err(err).Send()
 			return
 		}
@@ -2160,18 +2193,18 @@ func processCurrentAimonitor(ctx context.Context, b *bot.Bot, update *models.Upd
 	case "buy", "sell":
 		f, err := cast.ToFloat64E(update.Message.Text)
 		if err != nil {
-			util.QuickMessage(ctx, b, chatId, "交易额不能是 0 或负数，请重新编辑监听")
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.edit_invalid.volume"))
 			return
 		}
 		if f < 0 {
-			util.QuickMessage(ctx, b, chatId, "交易额不能是 0 或负数，请重新编辑监听")
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.edit_invalid.volume"))
 			return
 		}
 		subReq.Data = update.Message.Text
 		newData, err := subReq.JsonB()
 		if err != nil {
 			logger.StdLogger().Error().Err(err).Send()
-			util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 			return
 		}
 		store.UserSetAiMonitorInfo(chatId, newData)
@@ -2179,18 +2212,18 @@ func processCurrentAimonitor(ctx context.Context, b *bot.Bot, update *models.Upd
 		sendMessageByte, err := store.RedisGetSendMessageParams(chatId, subReq.SessionMessageID)
 		if err != nil {
 			logger.StdLogger().Error().Err(err).Send()
-			util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 			return
 		}
 		var sendMsg bot.SendMessageParams
 		err = json.Unmarshal(sendMessageByte, &sendMsg)
 		if err != nil {
-			util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 			logger.StdLogger().Error().Err(err).Send()
 			return
 		}
 
-		textTmpl := edit_current_tmpl(subReq.MonitorType)
+		textTmpl := edit_current_tmpl(i18n.UserLang(chatId), subReq.MonitorType)
 		textSend := ""
 		currentPrice := util.FormatNumber(subReq.CurrentPrice)
 		targetPrice := util.FormatNumber(subReq.TargetPrice)
@@ -2204,7 +2237,7 @@ func processCurrentAimonitor(ctx context.Context, b *bot.Bot, update *models.Upd
 		store.BotMessageAdd()
 		_, err = b.SendMessage(ctx, &sendMsg)
 		if err != nil {
-			util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 			logger.StdLogger().Error().Err(err).Send()
 			return
 		}
@@ -2216,33 +2249,10 @@ func processCurrentAimonitor(ctx context.Context, b *bot.Bot, update *models.Upd
 	}
 }
 
-func edit_current_tmpl(monitorType string) string {
+func edit_current_tmpl(lang string, monitorType string) string {
 	switch monitorType {
-	case "price":
-		return `
-HelloDex: AI监控
-当前价格: $%s
-目标价格: $%s
-推送设置已变动，请点击【保存更新】
-						`
-	case "chg":
-		return `
-HelloDex: AI监控
-目标涨跌幅: %s
-推送设置已变动，请点击【保存更新】
-						`
-	case "buy":
-		return `
-HelloDex: AI监控
-买入交易额: %s
-推送设置已变动，请点击【保存更新】
-						`
-	case "sell":
-		return `
-HelloDex: AI监控
-卖出交易额: %s
-推送设置已变动，请点击【保存更新】
-						`
+	case "price", "chg", "buy", "sell":
+		return i18n.T(lang, "aimonitor.edit_info."+monitorType)
 	}
 	return ""
 }
@@ -0,0 +1,292 @@
+package callback
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/api"
+	"github.com/hellodex/tradingbot/entity"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/session"
+	"github.com/hellodex/tradingbot/store"
+	"github.com/hellodex/tradingbot/util"
+	"github.com/rs/zerolog/log"
+)
+
+type addressBookReply struct {
+	MessageID int
+	ChainCode string
+}
+
+// user address book, sort by added time
+func UserAddressBook(chatId int64) []model.AddressEntry {
+	data, err := store.UserGetAddressBook(chatId)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return nil
+	}
+
+	entries := make([]model.AddressEntry, 0, len(data))
+	for _, v := range data {
+		var e model.AddressEntry
+		if err := json.Unmarshal([]byte(v), &e); err != nil {
+			log.Error().Err(err).Send()
+			continue
+		}
+		entries = append(entries, e)
+	}
+	slices.SortFunc(entries, func(a, b model.AddressEntry) int {
+		return cmp.Compare(a.AddedAt, b.AddedAt)
+	})
+	return entries
+}
+
+func UserAddressEntry(chatId int64, id string) (model.AddressEntry, bool) {
+	for _, e := range UserAddressBook(chatId) {
+		if e.Id == id {
+			return e, true
+		}
+	}
+	return model.AddressEntry{}, false
+}
+
+func inAddressBook(chatId int64, key string) bool {
+	return slices.ContainsFunc(UserAddressBook(chatId), func(e model.AddressEntry) bool {
+		return e.Key() == key
+	})
+}
+
+// address in address book or has been sent to before
+func IsKnownAddress(chatId int64, chainCode, address string) bool {
+	key := model.AddressKey(chainCode, address)
+	return store.UserAddressUsed(chatId, key) || inAddressBook(chatId, key)
+}
+
+func MarkAddressUsed(chatId int64, chainCode, address string) {
+	if err := store.UserMarkAddressUsed(chatId, model.AddressKey(chainCode, address)); err != nil {
+		log.Error().Err(err).Send()
+	}
+}
+
+// send saved addresses of chain as buttons, callback data: <prefix>::<id>
+func SendAddressBookPicker(ctx context.Context, b *bot.Bot, chatId int64, chainCode string, prefix string) {
+	var buttons [][]models.InlineKeyboardButton
+	for _, e := range UserAddressBook(chatId) {
+		if e.ChainCode != chainCode {
+			continue
+		}
+		buttons = append(buttons, []models.InlineKeyboardButton{
+			util.NewCallbackDataButton(addressEntryText(e), prefix+"::"+e.Id),
+		})
+	}
+	if len(buttons) == 0 {
+		return
+	}
+
+	store.BotMessageAdd()
+	b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:      chatId,
+		Text:        i18n.L(chatId, "addressbook.pick"),
+		ReplyMarkup: models.InlineKeyboardMarkup{InlineKeyboard: buttons},
+	})
+}
+
+func addressEntryText(e model.AddressEntry) string {
+	addr := e.Address
+	return "📒" + e.Label + " " + addr[:4] + ".." + addr[len(addr)-4:]
+}
+
+// trigger by entity.SETTING_ADDRESS_BOOK
+func AddressBookHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatId := util.EffectId(update)
+	lang := i18n.UserLang(chatId)
+	entries := UserAddressBook(chatId)
+
+	text := i18n.T(lang, "addressbook.title", len(entries), model.MaxAddressBookLen)
+	if len(entries) == 0 {
+		text += "\n\n" + i18n.T(lang, "addressbook.empty")
+	}
+
+	var buttons [][]models.InlineKeyboardButton
+	for _, e := range entries {
+		text += "\n\n📒 <b>" + e.Label + "</b> (" + api.GetChainNameFallbackCode(e.ChainCode) + ")\n<code>" + e.Address + "</code>"
+		buttons = append(buttons, []models.InlineKeyboardButton{
+			util.NewCallbackDataButton("❌"+e.Label, "ab::rm::"+e.Id),
+		})
+	}
+	buttons = append(buttons, []models.InlineKeyboardButton{
+		util.NewCallbackDataButton(i18n.T(lang, "addressbook.add"), "ab::add"),
+		entity.GetCallbackButtonLang(entity.SETTING, lang),
+	})
+
+	store.BotMessageAdd()
+	b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:      chatId,
+		Text:        text,
+		ParseMode:   models.ParseModeHTML,
+		ReplyMarkup: models.InlineKeyboardMarkup{InlineKeyboard: buttons},
+	})
+}
+
+// prefix: ab::add / ab::chain::<chainCode> / ab::rm::<id>
+func CallbackAddressBook(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatId := util.EffectId(update)
+	params := strings.Split(update.CallbackQuery.Data, "::")
+	if len(params) < 2 {
+		return
+	}
+
+	switch params[1] {
+	case "add":
+		if len(UserAddressBook(chatId)) >= model.MaxAddressBookLen {
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "addressbook.full", model.MaxAddressBookLen))
+			return
+		}
+		chainCfgs, err := api.GetChainConfigs()
+		if err != nil {
+			log.Error().Err(err).Send()
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+			return
+		}
+		slices.SortFunc(chainCfgs.Data, func(a, b model.ChainConfig) int {
+			return cmp.Compare(a.Sort, b.Sort)
+		})
+		var buttons [][]models.InlineKeyboardButton
+		for _, c := range chainCfgs.Data {
+			buttons = append(buttons, []models.InlineKeyboardButton{
+				util.NewCallbackDataButton(c.Chain, "ab::chain::"+c.ChainCode),
+			})
+		}
+		store.BotMessageAdd()
+		b.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID:      chatId,
+			Text:        i18n.L(chatId, "addressbook.select_chain"),
+			ReplyMarkup: models.InlineKeyboardMarkup{InlineKeyboard: buttons},
+		})
+	case "chain":
+		if len(params) != 3 {
+			return
+		}
+		store.BotMessageAdd()
+		message, err := b.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID: chatId,
+			Text:   i18n.L(chatId, "addressbook.input", api.GetChainNameFallbackCode(params[2])),
+			ReplyMarkup: models.ForceReply{
+				ForceReply:            true,
+				InputFieldPlaceholder: "Binance 0x...",
+			},
+		})
+		if err != nil {
+			log.Error().Err(err).Send()
+			return
+		}
+		session.GetSessionManager().Set(chatId, session.UserAddressBookReply, addressBookReply{
+			MessageID: message.ID,
+			ChainCode: params[2],
+		})
+	case "rm":
+		if len(params) != 3 {
+			return
+		}
+		if err := store.UserDeleteAddressBook(chatId, params[2]); err != nil {
+			log.Error().Err(err).Send()
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+			return
+		}
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "addressbook.removed"))
+		AddressBookHandler(ctx, b, update)
+	}
+}
+
+// check if user replay to address book input message
+func IsAddressBookReply(chatId int64, replyToID int) bool {
+	v, ok := session.GetSessionManager().Get(chatId, session.UserAddressBookReply)
+	if !ok {
+		return false
+	}
+	reply, ok := v.(addressBookReply)
+	return ok && reply.MessageID == replyToID
+}
+
+// model err -> i18n key
+var addressBookErrKeys = map[error]string{
+	model.ErrAddressBookFormat:   "addressbook.err.format",
+	model.ErrAddressLabelTooLong: "addressbook.err.label_too_long",
+	model.ErrAddressBookExist:    "addressbook.err.exist",
+	util.ErrAddressChainNotMatch: "addressbook.err.chain",
+}
+
+func HandleAddressBookReply(ctx context.Context, b *bot.Bot, update *models.Update) {
+	if update.Message == nil || update.Message.ReplyToMessage == nil {
+		return
+	}
+	chatId := update.Message.Chat.ID
+
+	v, ok := session.GetSessionManager().Get(chatId, session.UserAddressBookReply)
+	if !ok {
+		return
+	}
+	reply, ok := v.(addressBookReply)
+	if !ok || reply.MessageID != update.Message.ReplyToMessage.ID {
+		return
+	}
+
+	entry, err := model.ParseAddressEntryInput(reply.ChainCode, update.Message.Text)
+	if err == nil && inAddressBook(chatId, entry.Key()) {
+		err = model.ErrAddressBookExist
+	}
+	if err != nil {
+		text := i18n.L(chatId, "addressbook.err.address")
+		if key, ok := addressBookErrKeys[err]; ok {
+			text = i18n.L(chatId, key)
+		}
+		if err == model.ErrAddressLabelTooLong {
+			text = i18n.L(chatId, "addressbook.err.label_too_long", model.MaxAddressLabelRunes)
+		}
+		util.QuickMessage(ctx, b, chatId, "❌ "+text)
+		return
+	}
+	if len(UserAddressBook(chatId)) >= model.MaxAddressBookLen {
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "addressbook.full", model.MaxAddressBookLen))
+		return
+	}
+
+	// 冷却结束时间在添加时确定, 之后缩短冷却时间不影响已添加的地址
+	w, err := UserWhitelist(chatId)
+	if err == nil {
+		entry.UsableAt = w.UsableAt(entry).Unix()
+		var data []byte
+		data, err = entry.JsonB()
+		if err == nil {
+			err = store.UserAddAddressBook(chatId, entry.Id, data)
+		}
+	}
+	if err != nil {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+		return
+	}
+
+	session.GetSessionManager().Delete(chatId, session.UserAddressBookReply)
+	text := i18n.L(chatId, "addressbook.added", entry.Label)
+	if tip := whitelistAddedTip(chatId, w, entry); tip != "" {
+		text += "\n" + tip
+	}
+	util.QuickMessage(ctx, b, chatId, text)
+	AddressBookHandler(ctx, b, update)
+}
+
+// error of address check for transfer and withdrawal
+func AddressErrText(chatId int64, err error) string {
+	if errors.Is(err, util.ErrAddressChainNotMatch) {
+		return i18n.L(chatId, "addressbook.err.chain")
+	}
+	return i18n.L(chatId, "transfer.invalid_address")
+}
@@ -10,7 +10,10 @@ import (
 
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/alert"
 	"github.com/hellodex/tradingbot/api"
+	"github.com/hellodex/tradingbot/entity"
+	"github.com/hellodex/tradingbot/i18n"
 	"github.com/hellodex/tradingbot/logger"
 	"github.com/hellodex/tradingbot/model"
 	"github.com/hellodex/tradingbot/store"
@@ -23,22 +26,27 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-var aiMonitorMenuTempl = `
-HelloDex: AI 监控
-
-可监控推特,钱包,代币等信息
-通过TG机器人,网页,APP提醒
-`
-
 // handle callback button ai monitor button
 func CallbackAIMonitorMenu(ctx context.Context, b *bot.Bot, u *models.Update) {
 	chatId := util.EffectId(u)
 
-	kb := util.NewAiMonitorKeyboard()
+	lang := i18n.UserLang(chatId)
+	kb := util.NewAiMonitorKeyboard(lang)
+	row := []models.InlineKeyboardButton{
+		entity.GetCallbackButtonLang(entity.ALERT_DELIVERY, lang),
+		entity.GetCallbackButtonLang(entity.ALERT_HISTORY, lang),
+	}
+	// 组合监控由本地引擎计算
+	if alert.Enabled() {
+		row = append([]models.InlineKeyboardButton{entity.GetCallbackButtonLang(entity.COMPOUND_ALERT, lang)}, row...)
+	}
+	kb.InlineKeyboard = append(kb.InlineKeyboard, row, []models.InlineKeyboardButton{
+		entity.GetCallbackButtonLang(entity.SMART_MONEY, lang),
+	})
 	store.BotMessageAdd()
 	_, err := b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID:      chatId,
-		Text:        aiMonitorMenuTempl,
+		Text:        i18n.L(chatId, "aimonitor.menu"),
 		ParseMode:   "HTML",
 		ReplyMarkup: kb,
 		LinkPreviewOptions: &models.LinkPreviewOptions{
@@ -50,14 +58,6 @@ func CallbackAIMonitorMenu(ctx context.Context, b *bot.Bot, u *models.Update) {
 	}
 }
 
-var aiMonitorTextTempl = `
-HelloDex: Ai监控
-
-%s
-当前价格: $%s
-目标价格: $%s
-`
-
 // handle ai monitor list
 func CallbackAIMonitorList(ctx context.Context, b *bot.Bot, u *models.Update) {
 	chatId := util.EffectId(u)
@@ -91,7 +91,11 @@ func CallbackAIMonitorList(ctx context.Context, b *bot.Bot, u *models.Update) {
 	}
 
 	listData := gjson.GetBytes(data.([]byte), "data.subscribeList").Raw
-	message, err := template.RanderListAimonitor([]byte(listData))
+	var compounds []model.CompoundAlert
+	if alert.Enabled() {
+		compounds = alert.UserCompoundAlerts(chatId)
+	}
+	message, err := template.RanderListAimonitor(i18n.UserLang(chatId), []byte(listData), compounds)
 	if err != nil {
 		log.Error().Err(err).Send()
 		return
@@ -116,26 +120,27 @@ func BuildAiMonitorSelectTypeKeyboard(chatId any) (models.InlineKeyboardMarkup,
 	var buttons [][]models.InlineKeyboardButton
 
 	prefix := fmt.Sprintf("monitor_select::%v", chatId)
+	lang := i18n.UserLang(cast.ToInt64(chatId))
 
 	// First row of buttons
 	priceMonitorButton := models.InlineKeyboardButton{
-		Text:         "价格监控",
+		Text:         i18n.T(lang, "aimonitor.type.price"),
 		CallbackData: prefix + "::price",
 	}
 
 	volMonitorButton := models.InlineKeyboardButton{
-		Text:         "波跌幅监控",
+		Text:         i18n.T(lang, "aimonitor.type.chg"),
 		CallbackData: prefix + "::chg",
 	}
 
 	// Second row of buttons
 	largeOrderBuyButton := models.InlineKeyboardButton{
-		Text:         "大单买入监控",
+		Text:         i18n.T(lang, "aimonitor.type.buy"),
 		CallbackData: prefix + "::buy",
 	}
 
 	largeOrderSellButton := models.InlineKeyboardButton{
-		Text:         "大单卖出监控",
+		Text:         i18n.T(lang, "aimonitor.type.sell"),
 		CallbackData: prefix + "::sell",
 	}
 
@@ -203,14 +208,14 @@ func CallbackUserConfirmChainForAimonitor(ctx context.Context, b *bot.Bot, u *mo
 
 	dateByte, has := store.UserGetAiMonitorInfo(chatId)
 	if !has {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 
 	reqData := &model.AISubscribeReqData{}
 	err := json.Unmarshal(dateByte, reqData)
 	if err != nil {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 	reqData.ChainCode = params[len(params)-1]
@@ -224,13 +229,13 @@ func CallbackUserConfirmChainForAimonitor(ctx context.Context, b *bot.Bot, u *mo
 	}
 	reply := models.ForceReply{
 		ForceReply:            true,
-		InputFieldPlaceholder: "请输入代币合约地址",
+		InputFieldPlaceholder: i18n.L(chatId, "aimonitor.input_token"),
 	}
 
 	store.BotMessageAdd()
 	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID:    chatId,
-		Text:      "请输入代币合约地址",
+		Text:      i18n.L(chatId, "aimonitor.input_token"),
 		ParseMode: "HTML",
 		LinkPreviewOptions: &models.LinkPreviewOptions{
 			IsDisabled: bot.True(),
@@ -253,11 +258,7 @@ func CallabckAiMonitorSelectType(ctx context.Context, b *bot.Bot, u *models.Upda
 		return
 	}
 
-	message := `
-HelloDex: AI监控
-
-请选择监控类型
-	`
+	message := i18n.L(chatId, "aimonitor.select_type")
 
 	store.BotMessageAdd()
 	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
@@ -311,11 +312,7 @@ func CallbackAiMonitorSelectChain(ctx context.Context, b *bot.Bot, u *models.Upd
 		return
 	}
 
-	message := `
-HelloDex: AI监控
-
-请选择需要监控的公链
-	`
+	message := i18n.L(chatId, "aimonitor.select_chain")
 
 	store.BotMessageAdd()
 	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
@@ -340,11 +337,12 @@ func CallbackAiMonitorTokenInfoSetting(ctx context.Context, b *bot.Bot, u *model
 		EnableWeb: false,
 		EnableApp: false,
 		Frequency: "one week",
+		Lang:      i18n.UserLang(chatId),
 	})
 	store.BotMessageAdd()
 	_, err := b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID:      chatId,
-		Text:        fmt.Sprintf(aiMonitorTextTempl, "Symbol", "100", "100"),
+		Text:        i18n.L(chatId, "aimonitor.token_setting", "Symbol", "100", "100"),
 		ParseMode:   "HTML",
 		ReplyMarkup: kb,
 		LinkPreviewOptions: &models.LinkPreviewOptions{
@@ -374,18 +372,19 @@ func Callback_add_token_alert(ctx context.Context, b *bot.Bot, u *models.Update)
 	if subReq.Vaild() {
 		userInfo, err := api.GetUserProfile(chatId)
 		if err != nil {
-			util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 			return
 		}
 		_, err = api.UpdateCommonSubscribe(subReq, userInfo)
 		if err != nil {
-			util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 			return
 		}
 
 		if err == nil {
 			// util.QuickMessage(ctx, b, chatId, "添加监听成功")
-			util.QuickMessageWithButton(ctx, b, chatId, "添加监听成功", util.BackToMainMenu())
+			util.QuickMessageWithButton(ctx, b, chatId, i18n.L(chatId, "aimonitor.add_success"), util.BackToMainMenu(i18n.UserLang(chatId)))
+			alert.Saved(chatId, subReq)
 			// set notify
 			// prefix: toggle_TG/APP/网页
 
@@ -397,7 +396,7 @@ func Callback_add_token_alert(ctx context.Context, b *bot.Bot, u *models.Update)
 			logger.StdLogger().Info().Interface("sub list", subList).Send()
 			_, err = api.UpdateUserSubscribeSetting(subList, userInfo)
 			if err != nil {
-				util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 				return
 			}
 			b.DeleteMessages(ctx, &bot.DeleteMessagesParams{
@@ -407,7 +406,7 @@ func Callback_add_token_alert(ctx context.Context, b *bot.Bot, u *models.Update)
 
 		}
 	} else {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 	store.UserDeleteAiMonitorInfo(chatId)
@@ -418,18 +417,18 @@ func Callback_cancel_settings(ctx context.Context, b *bot.Bot, u *models.Update)
 	chatId := util.EffectId(u)
 	datad, has := store.UserGetAiMonitorInfo(chatId)
 	if !has {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 
 	var subReq model.AISubscribeReqData
 	err := json.Unmarshal(datad, &subReq)
 	if err != nil {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 
-	util.QuickMessageWithButton(ctx, b, chatId, "已经取消监听设置", util.BackToMainMenu())
+	util.QuickMessageWithButton(ctx, b, chatId, i18n.L(chatId, "aimonitor.cancel_success"), util.BackToMainMenu(i18n.UserLang(chatId)))
 	b.DeleteMessages(ctx, &bot.DeleteMessagesParams{
 		ChatID:     chatId,
 		MessageIDs: []int{subReq.SessionMessageID},
@@ -480,15 +479,16 @@ func localUpdateUserProfileChannelList(chatId int64, list []string) {
 // status keyboard for aimonitor info
 func newKeyboardToggle(newStatus []string, u *models.Update) models.InlineKeyboardMarkup {
 	kb := u.CallbackQuery.Message.Message.ReplyMarkup
+	lang := i18n.UserLang(util.EffectId(u))
 	for i, buttons := range kb.InlineKeyboard {
 		for j, b := range buttons {
 			switch b.CallbackData {
 			case "toggle_TG":
-				kb.InlineKeyboard[i][j] = util.GetToggleButton("TG", lo.Contains(newStatus, "telegram"))
+				kb.InlineKeyboard[i][j] = util.GetToggleButton(lang, "TG", lo.Contains(newStatus, "telegram"))
 			case "toggle_网页":
-				kb.InlineKeyboard[i][j] = util.GetToggleButton("网页", lo.Contains(newStatus, "web"))
+				kb.InlineKeyboard[i][j] = util.GetToggleButton(lang, "网页", lo.Contains(newStatus, "web"))
 			case "toggle_APP":
-				kb.InlineKeyboard[i][j] = util.GetToggleButton("APP", lo.Contains(newStatus, "app"))
+				kb.InlineKeyboard[i][j] = util.GetToggleButton(lang, "APP", lo.Contains(newStatus, "app"))
 			}
 		}
 	}
@@ -615,7 +615,7 @@ func CallbackUpdateFreqSetting(ctx context.Context, b *bot.Bot, u *models.Update
 	subReq := &model.AISubscribeReqData{}
 	err := json.Unmarshal(monitoryInfoData, subReq)
 	if err != nil {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 
@@ -683,14 +683,15 @@ func CallbackHandleAimonitorPuserButton(ctx context.Context, b *bot.Bot, u *mode
 			log.Error().Err(err).Send()
 			return
 		}
-		util.QuickMessage(ctx, b, chatId, "暂停成功")
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.pause_success"))
 	case "delete":
 		_, err := api.DeleteUserTokenSubscribe(reqbodyMap, userInfo)
 		if err != nil {
 			log.Error().Err(err).Send()
 			return
 		}
-		util.QuickMessage(ctx, b, chatId, "删除成功")
+		alert.RemoveRuleAction(chatId, reqbodyMap["chainCode"], reqbodyMap["baseAddress"], reqbodyMap["type"])
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.delete_success"))
 	}
 }
 
@@ -720,15 +721,10 @@ func editPusherTokenInfo(ctx context.Context, b *bot.Bot, chatId int64, chainCod
 		EnableWeb: lo.Contains(userInfo.Data.SubscribeSetting, "web"),
 		EnableApp: lo.Contains(userInfo.Data.SubscribeSetting, "app"),
 		Frequency: freq_Map[subTokenInfo.NoticeType],
+		Lang:      i18n.UserLang(chatId),
 	}
 	kb := util.AiMonitorSettingsKeyBoard(keyBoardData)
 
-	tmplll := `
-HelloDex: AI监控
-当前价格: $%s
-目标价格: $%s
-		`
-
 	subReq := model.AISubscribeReqData{
 		UserId:       chatId,
 		CurrentPrice: subTokenInfo.StartPrice,
@@ -741,7 +737,7 @@ HelloDex: AI监控
 	}
 	newData, err := subReq.JsonB()
 	if err != nil {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 	store.UserSetAiMonitorInfo(chatId, newData)
@@ -750,25 +746,25 @@ HelloDex: AI监控
 	store.BotMessageAdd()
 	b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID:      chatId,
-		Text:        fmt.Sprintf(tmplll, currentPrice, targetPrice),
+		Text:        i18n.L(chatId, "aimonitor.price_info", currentPrice, targetPrice),
 		ReplyMarkup: kb,
 	})
 }
 
 // this will be replay by type, and set state in redis to with user to replay
-func replayByTypeAndInstate(monitorType string) (models.ForceReply, string) {
+func replayByTypeAndInstate(lang string, monitorType string) (models.ForceReply, string) {
 	placeholder := ""
 	switch monitorType {
 	case "price":
-		placeholder = "请输入目标价格"
+		placeholder = i18n.T(lang, "aimonitor.input.price")
 	case "chg":
-		placeholder = "请输入目标涨幅"
+		placeholder = i18n.T(lang, "aimonitor.input.chg")
 	case "buy":
-		placeholder = "请输入买入交易额"
+		placeholder = i18n.T(lang, "aimonitor.input.buy")
 	case "sell":
-		placeholder = "请输入卖出交易额"
+		placeholder = i18n.T(lang, "aimonitor.input.sell")
 	default:
-		placeholder = "请输入数额"
+		placeholder = i18n.T(lang, "aimonitor.input.amount")
 	}
 
 	return models.ForceReply{
@@ -784,7 +780,7 @@ func CallBackEditCurrentAimonitor(ctx context.Context, b *bot.Bot, u *models.Upd
 	chatId := util.EffectId(u)
 	callabckData := u.CallbackQuery.Data
 	monitorType := strings.TrimPrefix(callabckData, "edit_current_")
-	reply, text := replayByTypeAndInstate(monitorType)
+	reply, text := replayByTypeAndInstate(i18n.UserLang(chatId), monitorType)
 	store.BotMessageAdd()
 	_, err := b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID:      chatId,
@@ -792,7 +788,7 @@ func CallBackEditCurrentAimonitor(ctx context.Context, b *bot.Bot, u *models.Upd
 		ReplyMarkup: reply,
 	})
 	if err != nil {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s, 编辑监控", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.error.edit"))
 		log.Error().Err(err).Send()
 		return
 	}
@@ -809,7 +805,7 @@ func CallBackDeleteCurrentAimonitor(ctx context.Context, b *bot.Bot, u *models.U
 	}()
 	dataB, has := store.UserGetAiMonitorInfo(chatId)
 	if !has {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s, 删除监控", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.error.delete"))
 		log.Error().Msg("cant get user aimonitorinfo")
 		return
 	}
@@ -817,14 +813,14 @@ func CallBackDeleteCurrentAimonitor(ctx context.Context, b *bot.Bot, u *models.U
 	var reqDataRaw model.AISubscribeReqData
 	err := json.Unmarshal(dataB, &reqDataRaw)
 	if err != nil {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s, 删除监控", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.error.delete"))
 		log.Error().Err(err).Send()
 		return
 	}
 
 	userInfo, err := api.GetUserProfile(chatId)
 	if err != nil {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s, 删除监控", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.error.delete"))
 		log.Error().Err(err).Send()
 		return
 	}
@@ -836,12 +832,13 @@ func CallBackDeleteCurrentAimonitor(ctx context.Context, b *bot.Bot, u *models.U
 	}
 	_, err = api.DeleteUserTokenSubscribe(reqData, userInfo)
 	if err != nil {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s, 删除监控", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.error.delete"))
 		log.Error().Err(err).Send()
 		return
 	}
 
-	util.QuickMessageWithButton(ctx, b, chatId, "删除成功", util.BackToMainMenu())
+	alert.RemoveRuleAction(chatId, reqDataRaw.ChainCode, reqDataRaw.BaseAddress, reqDataRaw.MonitorType)
+	util.QuickMessageWithButton(ctx, b, chatId, i18n.L(chatId, "aimonitor.delete_success"), util.BackToMainMenu(i18n.UserLang(chatId)))
 	b.DeleteMessages(ctx, &bot.DeleteMessagesParams{
 		ChatID:     chatId,
 		MessageIDs: []int{reqDataRaw.SessionMessageID},
@@ -857,7 +854,7 @@ func CallBackPauseCurrentAimonitor(ctx context.Context, b *bot.Bot, u *models.Up
 	}()
 	dataB, has := store.UserGetAiMonitorInfo(chatId)
 	if !has {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s, 暂停监控", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.error.pause"))
 		log.Error().Msg("cant get user aimonitorinfo")
 		return
 	}
@@ -865,14 +862,14 @@ func CallBackPauseCurrentAimonitor(ctx context.Context, b *bot.Bot, u *models.Up
 	var reqDataRaw model.AISubscribeReqData
 	err := json.Unmarshal(dataB, &reqDataRaw)
 	if err != nil {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s, 暂停监控", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.error.pause"))
 		log.Error().Err(err).Send()
 		return
 	}
 
 	userInfo, err := api.GetUserProfile(chatId)
 	if err != nil {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s, 暂停监控", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.error.pause"))
 		log.Error().Err(err).Send()
 		return
 	}
@@ -884,12 +881,12 @@ func CallBackPauseCurrentAimonitor(ctx context.Context, b *bot.Bot, u *models.Up
 	}
 	_, err = api.PauseUserTokenSubscribe(reqData, userInfo)
 	if err != nil {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s, 暂停监控", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.error.pause"))
 		log.Error().Err(err).Send()
 		return
 	}
 
-	util.QuickMessageWithButton(ctx, b, chatId, "暂停成功", util.BackToMainMenu())
+	util.QuickMessageWithButton(ctx, b, chatId, i18n.L(chatId, "aimonitor.pause_success"), util.BackToMainMenu(i18n.UserLang(chatId)))
 	b.DeleteMessages(ctx, &bot.DeleteMessagesParams{
 		ChatID:     chatId,
 		MessageIDs: []int{reqDataRaw.SessionMessageID},
@@ -905,7 +902,7 @@ func CallbackSaveCurrentAimonitor(ctx context.Context, b *bot.Bot, u *models.Upd
 	}()
 	dataB, has := store.UserGetAiMonitorInfo(chatId)
 	if !has {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s, 保存监控", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.error.save"))
 		log.Error().Msg("cant get user aimonitorinfo")
 		return
 	}
@@ -913,26 +910,27 @@ func CallbackSaveCurrentAimonitor(ctx context.Context, b *bot.Bot, u *models.Upd
 	var reqData model.AISubscribeReqData
 	err := json.Unmarshal(dataB, &reqData)
 	if err != nil {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s, 保存监控", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.error.save"))
 		log.Error().Err(err).Send()
 		return
 	}
 
 	userInfo, err := api.GetUserProfile(chatId)
 	if err != nil {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s, 保存监控", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.error.save"))
 		log.Error().Err(err).Send()
 		return
 	}
 
 	_, err = api.UpdateCommonSubscribe(reqData, userInfo)
 	if err != nil {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s, 保存监控", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.error.save"))
 		log.Error().Err(err).Send()
 		return
 	}
 
-	util.QuickMessageWithButton(ctx, b, chatId, "保存成功", util.BackToMainMenu())
+	util.QuickMessageWithButton(ctx, b, chatId, i18n.L(chatId, "aimonitor.save_success"), util.BackToMainMenu(i18n.UserLang(chatId)))
+	alert.Saved(chatId, reqData)
 	b.DeleteMessages(ctx, &bot.DeleteMessagesParams{
 		ChatID:     chatId,
 		MessageIDs: []int{reqData.SessionMessageID},
@@ -1009,13 +1007,13 @@ func ListAimonitorWithButton(ctx context.Context, b *bot.Bot, u *models.Update)
 	getNotifyType := func(nt int64) string {
 		switch nt {
 		case 0:
-			return "关闭"
+			return i18n.L(chatId, "aimonitor.notice.off")
 		case 1:
-			return "1次"
+			return i18n.L(chatId, "aimonitor.notice.once")
 		case 2:
-			return "1日1次"
+			return i18n.L(chatId, "aimonitor.notice.daily")
 		case 3:
-			return "每次"
+			return i18n.L(chatId, "aimonitor.notice.every")
 		}
 		return ""
 	}
@@ -1023,13 +1021,13 @@ func ListAimonitorWithButton(ctx context.Context, b *bot.Bot, u *models.Update)
 	getMonitorType := func(mt string) string {
 		switch mt {
 		case "price":
-			return "价格"
+			return i18n.L(chatId, "aimonitor.monitor_type.price")
 		case "chg":
-			return "涨跌幅"
+			return i18n.L(chatId, "aimonitor.monitor_type.chg")
 		case "buy":
-			return "买入交易额"
+			return i18n.L(chatId, "aimonitor.monitor_type.buy")
 		case "sell":
-			return "卖出交易额"
+			return i18n.L(chatId, "aimonitor.monitor_type.sell")
 		}
 		return ""
 	}
@@ -1068,7 +1066,7 @@ func ListAimonitorWithButton(ctx context.Context, b *bot.Bot, u *models.Update)
 	store.BotMessageAdd()
 	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID:      chatId,
-		Text:        "点击监控即可编辑",
+		Text:        i18n.L(chatId, "aimonitor.click_to_edit"),
 		ReplyMarkup: kb,
 	})
 	if err != nil {
@@ -1080,12 +1078,12 @@ func ListAimonitorWithButton(ctx context.Context, b *bot.Bot, u *models.Update)
 
 func CallBackNoSublistAction(ctx context.Context, b *bot.Bot, update *models.Update) {
 	chatId := util.EffectId(update)
-	text := "请先添加监控"
+	text := i18n.L(chatId, "aimonitor.empty")
 	kb := models.InlineKeyboardMarkup{
 		InlineKeyboard: [][]models.InlineKeyboardButton{
 			{
-				models.InlineKeyboardButton{Text: "添加监控", CallbackData: "ai_add_monitor"},
-				util.BackToMainMenu(),
+				models.InlineKeyboardButton{Text: i18n.L(chatId, "code::addAiMonitor"), CallbackData: "ai_add_monitor"},
+				util.BackToMainMenu(i18n.UserLang(chatId)),
 			},
 		},
 	}
@@ -1144,34 +1142,9 @@ func CallbackHendlerSelectEditAimonitor(ctx context.Context, b *bot.Bot, update
 		EnableWeb: lo.Contains(userInfo.Data.SubscribeSetting, "web"),
 		EnableApp: lo.Contains(userInfo.Data.SubscribeSetting, "app"),
 		Frequency: freq_Map[subTokenInfo.NoticeType],
+		Lang:      i18n.UserLang(chatId),
 	}
 	kb := util.AiMonitor_EditSettingsKeyBoard(keyBoardData, monitorType, int(subTokenInfo.NoticeType))
-	tmplll := func() string {
-		switch monitorType {
-		case "price":
-			return `
-HelloDex: AI监控
-当前价格: $%s
-目标价格: $%s
-						`
-		case "chg":
-			return `
-HelloDex: AI监控
-目标涨跌幅: %s
-						`
-		case "buy":
-			return `
-HelloDex: AI监控
-买入交易额: %s
-						`
-		case "sell":
-			return `
-HelloDex: AI监控
-卖出交易额: %s
-						`
-		}
-		return ""
-	}()
 
 	var subReq model.AISubscribeReqData
 	subReq.MonitorType = monitorType
@@ -1188,49 +1161,49 @@ HelloDex: AI监控
 	if monitorType == "price" {
 		sendParams := &bot.SendMessageParams{
 			ChatID:      chatId,
-			Text:        fmt.Sprintf(tmplll, currentPrice, targetPrice),
+			Text:        i18n.L(chatId, "aimonitor.price_info", currentPrice, targetPrice),
 			ReplyMarkup: kb,
 		}
 		store.BotMessageAdd()
 		msg, err := b.SendMessage(ctx, sendParams)
 		if err != nil {
 			log.Error().Err(err).Send()
-			util.QuickMessage(ctx, b, chatId, "出错了,请联系客服")
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 			return
 		}
 		subReq.SessionMessageID = msg.ID
 		dataSendParams, err := json.Marshal(sendParams)
 		if err != nil {
 			log.Error().Err(err).Send()
-			util.QuickMessage(ctx, b, chatId, "出错了,请联系客服")
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 			return
 		}
 		store.RedisSetSendMessageParams(chatId, msg.ID, dataSendParams)
 	} else {
 		sendParams := &bot.SendMessageParams{
 			ChatID:      chatId,
-			Text:        fmt.Sprintf(tmplll, subReq.Data),
+			Text:        i18n.L(chatId, "aimonitor.data_info."+monitorType, subReq.Data),
 			ReplyMarkup: kb,
 		}
 		store.BotMessageAdd()
 		msg, err := b.SendMessage(ctx, sendParams)
 		if err != nil {
 			log.Error().Err(err).Send()
-			util.QuickMessage(ctx, b, chatId, "出错了,请联系客服")
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 			return
 		}
 		subReq.SessionMessageID = msg.ID
 		dataSendParams, err := json.Marshal(sendParams)
 		if err != nil {
 			log.Error().Err(err).Send()
-			util.QuickMessage(ctx, b, chatId, "出错了,请联系客服")
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 			return
 		}
 		store.RedisSetSendMessageParams(chatId, msg.ID, dataSendParams)
 	}
 	newData, err := subReq.JsonB()
 	if err != nil {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 	store.UserSetAiMonitorInfo(chatId, newData)
@@ -1244,7 +1217,7 @@ func CallbackHandlerEnableTokenAiMonitor(ctx context.Context, b *bot.Bot, update
 	}()
 	dataB, has := store.UserGetAiMonitorInfo(chatId)
 	if !has {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s, 启动监控", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.error.enable"))
 		log.Error().Msg("cant get user aimonitorinfo")
 		return
 	}
@@ -1252,14 +1225,14 @@ func CallbackHandlerEnableTokenAiMonitor(ctx context.Context, b *bot.Bot, update
 	var reqDataRaw model.AISubscribeReqData
 	err := json.Unmarshal(dataB, &reqDataRaw)
 	if err != nil {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s, 启动监控", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.error.enable"))
 		log.Error().Err(err).Send()
 		return
 	}
 
 	userInfo, err := api.GetUserProfile(chatId)
 	if err != nil {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s, 启动监控", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.error.enable"))
 		log.Error().Err(err).Send()
 		return
 	}
@@ -1268,12 +1241,12 @@ func CallbackHandlerEnableTokenAiMonitor(ctx context.Context, b *bot.Bot, update
 
 	_, err = api.UpdateCommonSubscribe(reqDataRaw, userInfo)
 	if err != nil {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s, 启动监控", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "aimonitor.error.enable"))
 		log.Error().Err(err).Send()
 		return
 	}
 
-	util.QuickMessageWithButton(ctx, b, chatId, "启动成功", util.BackToMainMenu())
+	util.QuickMessageWithButton(ctx, b, chatId, i18n.L(chatId, "aimonitor.enable_success"), util.BackToMainMenu(i18n.UserLang(chatId)))
 	b.DeleteMessages(ctx, &bot.DeleteMessagesParams{
 		ChatID:     chatId,
 		MessageIDs: []int{reqDataRaw.SessionMessageID},
@@ -0,0 +1,318 @@
+package callback
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/alert"
+	"github.com/hellodex/tradingbot/api"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/session"
+	"github.com/hellodex/tradingbot/store"
+	"github.com/hellodex/tradingbot/util"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cast"
+)
+
+type alertActionReply struct {
+	MessageID int
+	Id        string
+}
+
+// model err -> i18n key
+var alertActionErrKeys = map[error]string{
+	model.ErrAlertActionFull:     "alert.action_err.full",
+	model.ErrAlertActionNoWallet: "alert.action_err.no_wallet",
+	model.ErrAlertActionNumber:   "alert.action_err.number",
+	model.ErrAlertActionPercent:  "alert.action_err.percent",
+}
+
+func alertActionText(lang string, a model.AlertAction) string {
+	if !a.IsBuy() {
+		return i18n.T(lang, "alert.action_desc_sell", a.Amount)
+	}
+	native := a.ChainCode
+	if chains, err := api.GetChainConfigs(); err == nil {
+		if cfg, ok := chains.Get(a.ChainCode); ok {
+			native = cfg.Symbol
+		}
+	}
+	return i18n.T(lang, "alert.action_desc_buy", a.Amount, native)
+}
+
+func alertActionView(chatId int64, a model.AlertAction) (string, models.InlineKeyboardMarkup) {
+	lang := i18n.UserLang(chatId)
+
+	wallet := a.WalletId
+	if userInfo, err := api.GetUserProfile(chatId); err == nil {
+		for _, w := range api.ListUserChainWallets(userInfo, a.ChainCode) {
+			if w.WalletId == a.WalletId {
+				wallet = w.Wallet
+			}
+		}
+	}
+	symbol := a.Symbol
+	if symbol == "" {
+		symbol = util.ShortAddress(a.Token)
+	}
+	status := i18n.T(lang, "common.off")
+	if a.Enabled {
+		status = i18n.T(lang, "common.on")
+	}
+
+	text := i18n.T(lang, "alert.action",
+		symbol, api.GetChainNameFallbackCode(a.ChainCode), i18n.T(lang, "aimonitor.type."+a.Type),
+		wallet, alertActionText(lang, a), a.DailyCap, alert.ActionCount(chatId, a), status,
+	)
+
+	toggle := i18n.T(lang, "alert.action_enable")
+	if a.Enabled {
+		toggle = i18n.T(lang, "alert.action_disable")
+	}
+	side := i18n.T(lang, "alert.action_side_sell")
+	if !a.IsBuy() {
+		side = i18n.T(lang, "alert.action_side_buy")
+	}
+	kb := models.InlineKeyboardMarkup{InlineKeyboard: [][]models.InlineKeyboardButton{
+		{
+			util.NewCallbackDataButton(side, "aa::sd::"+a.Id),
+			util.NewCallbackDataButton(i18n.T(lang, "alert.action_set_amount"), "aa::a::"+a.Id),
+		},
+		{
+			util.NewCallbackDataButton(i18n.T(lang, "alert.action_set_cap", a.DailyCap), "aa::cap::"+a.Id),
+			util.NewCallbackDataButton(i18n.T(lang, "alert.action_set_wallet"), "aa::w::"+a.Id),
+		},
+		{
+			util.NewCallbackDataButton(toggle, "aa::on::"+a.Id),
+			util.NewCallbackDataButton(i18n.T(lang, "alert.action_remove"), "aa::rm::"+a.Id),
+		},
+	}}
+	return text, kb
+}
+
+// action of rule, a disabled one is created if not exists
+func ruleAlertAction(chatId int64, chainCode, token, symbol, monitorType string) (model.AlertAction, error) {
+	if a, ok := alert.RuleAction(chatId, chainCode, token, monitorType); ok {
+		return a, nil
+	}
+	if len(alert.UserActions(chatId)) >= model.MaxAlertActionLen {
+		return model.AlertAction{}, model.ErrAlertActionFull
+	}
+
+	userInfo, err := api.GetUserProfile(chatId)
+	if err != nil {
+		return model.AlertAction{}, err
+	}
+	dw, _, _ := UserDefaultWalletInfo(userInfo)
+	walletId := dw.WalletId
+	if dw.ChainCode != chainCode {
+		wallets := api.ListUserChainWallets(userInfo, chainCode)
+		if len(wallets) == 0 {
+			return model.AlertAction{}, model.ErrAlertActionNoWallet
+		}
+		walletId = wallets[0].WalletId
+	}
+
+	a := model.NewAlertAction(chainCode, token, symbol, monitorType, walletId)
+	return a, alert.SaveAction(chatId, a)
+}
+
+func alertActionErrText(chatId int64, err error) string {
+	switch err {
+	case model.ErrAlertActionFull:
+		return i18n.L(chatId, "alert.action_err.full", model.MaxAlertActionLen)
+	}
+	if key, ok := alertActionErrKeys[err]; ok {
+		return i18n.L(chatId, key)
+	}
+	log.Error().Err(err).Send()
+	return i18n.L(chatId, "common.error")
+}
+
+// rule of the alert push or the monitor in editing
+func alertActionRule(chatId int64, update *models.Update, from string) (req model.PusherHandlerReqData, symbol string, ok bool) {
+	switch from {
+	case "push":
+		data, err := store.GetMessageByMsgId(chatId, cast.ToString(update.CallbackQuery.Message.Message.ID))
+		if err != nil {
+			log.Error().Err(err).Send()
+			return req, "", false
+		}
+		if err := json.Unmarshal([]byte(data), &req); err != nil {
+			log.Error().Err(err).Send()
+			return req, "", false
+		}
+		// 推送里没有代币名称
+		symbol = api.SearchTokenInfo(req.BaseAddress).Symbol
+	case "edit":
+		data, has := store.UserGetAiMonitorInfo(chatId)
+		if !has {
+			return req, "", false
+		}
+		var sub model.AISubscribeReqData
+		if err := json.Unmarshal(data, &sub); err != nil {
+			log.Error().Err(err).Send()
+			return req, "", false
+		}
+		req = model.PusherHandlerReqData{ChainCode: sub.ChainCode, BaseAddress: sub.BaseAddress, MonitorType: sub.MonitorType}
+		symbol = sub.Symbol
+	default:
+		return req, "", false
+	}
+	return req, symbol, req.ChainCode != "" && req.BaseAddress != "" && req.MonitorType != ""
+}
+
+// prefix: aa::push, aa::edit open action of rule, aa::<action>::<id>
+func CallbackAlertAction(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatId := util.EffectId(update)
+	msg := update.CallbackQuery.Message.Message
+	params := strings.Split(update.CallbackQuery.Data, "::")
+	if msg == nil {
+		return
+	}
+
+	if len(params) == 2 {
+		req, symbol, ok := alertActionRule(chatId, update, params[1])
+		if !ok {
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "alert.action_err.rule"))
+			return
+		}
+		a, err := ruleAlertAction(chatId, req.ChainCode, req.BaseAddress, symbol, req.MonitorType)
+		if err != nil {
+			util.QuickMessage(ctx, b, chatId, "❌ "+alertActionErrText(chatId, err))
+			return
+		}
+		text, kb := alertActionView(chatId, a)
+		sendHTMLView(ctx, b, chatId, text, kb)
+		return
+	}
+	if len(params) != 3 {
+		return
+	}
+	action, id := params[1], params[2]
+
+	a, ok := alert.UserAction(chatId, id)
+	if !ok {
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "alert.action_err.not_found"))
+		return
+	}
+
+	switch action {
+	case "a":
+		key := "alert.action_input_buy"
+		if !a.IsBuy() {
+			key = "alert.action_input_sell"
+		}
+		store.BotMessageAdd()
+		message, err := b.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID:    chatId,
+			Text:      i18n.L(chatId, key),
+			ParseMode: models.ParseModeHTML,
+			ReplyMarkup: models.ForceReply{
+				ForceReply: true,
+			},
+		})
+		if err != nil {
+			log.Error().Err(err).Send()
+			return
+		}
+		session.GetSessionManager().Set(chatId, session.UserAlertActionReply, alertActionReply{
+			MessageID: message.ID,
+			Id:        id,
+		})
+		return
+	case "rm":
+		if err := store.UserDeleteAlertAction(chatId, id); err != nil {
+			log.Error().Err(err).Send()
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+			return
+		}
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "alert.action_removed"))
+		b.DeleteMessages(ctx, &bot.DeleteMessagesParams{
+			ChatID:     chatId,
+			MessageIDs: []int{msg.ID},
+		})
+		return
+	case "sd":
+		a.ToggleSide()
+	case "cap":
+		a.DailyCap = model.CycleNext(model.AlertActionCaps, a.DailyCap)
+	case "on":
+		a.Enabled = !a.Enabled
+	case "w":
+		userInfo, err := api.GetUserProfile(chatId)
+		if err != nil {
+			log.Error().Err(err).Send()
+			return
+		}
+		var ids []string
+		for _, w := range api.ListUserChainWallets(userInfo, a.ChainCode) {
+			ids = append(ids, w.WalletId)
+		}
+		if len(ids) == 0 {
+			util.QuickMessage(ctx, b, chatId, "❌ "+alertActionErrText(chatId, model.ErrAlertActionNoWallet))
+			return
+		}
+		a.WalletId = model.CycleNext(ids, a.WalletId)
+	default:
+		return
+	}
+
+	if err := alert.SaveAction(chatId, a); err != nil {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+		return
+	}
+	text, kb := alertActionView(chatId, a)
+	editHTMLView(ctx, b, chatId, msg.ID, text, kb)
+}
+
+// check if user replay to alert action amount input message
+func IsAlertActionReply(chatId int64, replyToID int) bool {
+	v, ok := session.GetSessionManager().Get(chatId, session.UserAlertActionReply)
+	if !ok {
+		return false
+	}
+	reply, ok := v.(alertActionReply)
+	return ok && reply.MessageID == replyToID
+}
+
+func HandleAlertActionReply(ctx context.Context, b *bot.Bot, update *models.Update) {
+	if update.Message == nil || update.Message.ReplyToMessage == nil {
+		return
+	}
+	chatId := update.Message.Chat.ID
+
+	v, ok := session.GetSessionManager().Get(chatId, session.UserAlertActionReply)
+	if !ok {
+		return
+	}
+	reply, ok := v.(alertActionReply)
+	if !ok || reply.MessageID != update.Message.ReplyToMessage.ID {
+		return
+	}
+
+	a, ok := alert.UserAction(chatId, reply.Id)
+	if !ok {
+		session.GetSessionManager().Delete(chatId, session.UserAlertActionReply)
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "alert.action_err.not_found"))
+		return
+	}
+	if err := a.SetAmount(update.Message.Text); err != nil {
+		util.QuickMessage(ctx, b, chatId, "❌ "+alertActionErrText(chatId, err))
+		return
+	}
+
+	if err := alert.SaveAction(chatId, a); err != nil {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+		return
+	}
+	session.GetSessionManager().Delete(chatId, session.UserAlertActionReply)
+	text, kb := alertActionView(chatId, a)
+	sendHTMLView(ctx, b, chatId, text, kb)
+}
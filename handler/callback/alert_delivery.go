@@ -0,0 +1,172 @@
+package callback
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/alert"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/session"
+	"github.com/hellodex/tradingbot/store"
+	"github.com/hellodex/tradingbot/util"
+	"github.com/rs/zerolog/log"
+)
+
+type alertDeliveryReply struct {
+	MessageID int
+	// quiet or tz
+	Field string
+}
+
+func minutesText(lang string, min int) string {
+	if min == 0 {
+		return i18n.T(lang, "common.off")
+	}
+	return i18n.T(lang, "alert.minutes", min)
+}
+
+func utcOffsetText(offset int) string {
+	return fmt.Sprintf("UTC%+d", offset)
+}
+
+func alertDeliveryView(chatId int64) (string, models.InlineKeyboardMarkup) {
+	lang := i18n.UserLang(chatId)
+	d := alert.UserDelivery(chatId)
+
+	quiet := i18n.T(lang, "common.off")
+	if d.HasQuietHours() {
+		quiet = fmt.Sprintf("%02d:00-%02d:00", d.QuietStart, d.QuietEnd)
+	}
+	text := i18n.T(lang, "alert.delivery",
+		minutesText(lang, d.CooldownMin), quiet, utcOffsetText(d.UtcOffset), minutesText(lang, d.DigestMin),
+	)
+
+	kb := models.InlineKeyboardMarkup{InlineKeyboard: [][]models.InlineKeyboardButton{
+		{
+			util.NewCallbackDataButton(i18n.T(lang, "alert.set_cooldown", minutesText(lang, d.CooldownMin)), "ad::cd"),
+			util.NewCallbackDataButton(i18n.T(lang, "alert.set_digest", minutesText(lang, d.DigestMin)), "ad::dg"),
+		},
+		{
+			util.NewCallbackDataButton(i18n.T(lang, "alert.set_quiet"), "ad::quiet"),
+			util.NewCallbackDataButton(i18n.T(lang, "alert.set_tz", utcOffsetText(d.UtcOffset)), "ad::tz"),
+		},
+	}}
+	return text, kb
+}
+
+// trigger by entity.ALERT_DELIVERY
+func CallbackAlertDelivery(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatID := util.EffectId(update)
+	text, kb := alertDeliveryView(chatID)
+	sendHTMLView(ctx, b, chatID, text, kb)
+}
+
+// prefix: ad::<action>
+func CallbackAlertDeliveryAction(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatId := util.EffectId(update)
+	msg := update.CallbackQuery.Message.Message
+	params := strings.Split(update.CallbackQuery.Data, "::")
+	if len(params) != 2 || msg == nil {
+		return
+	}
+
+	d := alert.UserDelivery(chatId)
+	switch params[1] {
+	case "cd":
+		d.CooldownMin = model.CycleNext(model.AlertCooldowns, d.CooldownMin)
+	case "dg":
+		d.DigestMin = model.CycleNext(model.AlertDigests, d.DigestMin)
+	case "quiet", "tz":
+		key := "alert.input_quiet"
+		if params[1] == "tz" {
+			key = "alert.input_tz"
+		}
+		store.BotMessageAdd()
+		message, err := b.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID:    chatId,
+			Text:      i18n.L(chatId, key),
+			ParseMode: models.ParseModeHTML,
+			ReplyMarkup: models.ForceReply{
+				ForceReply: true,
+			},
+		})
+		if err != nil {
+			log.Error().Err(err).Send()
+			return
+		}
+		session.GetSessionManager().Set(chatId, session.UserAlertDeliveryReply, alertDeliveryReply{
+			MessageID: message.ID,
+			Field:     params[1],
+		})
+		return
+	default:
+		return
+	}
+
+	if err := alert.SaveDelivery(chatId, d); err != nil {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+		return
+	}
+	text, kb := alertDeliveryView(chatId)
+	editHTMLView(ctx, b, chatId, msg.ID, text, kb)
+}
+
+// check if user replay to alert delivery input message
+func IsAlertDeliveryReply(chatId int64, replyToID int) bool {
+	v, ok := session.GetSessionManager().Get(chatId, session.UserAlertDeliveryReply)
+	if !ok {
+		return false
+	}
+	reply, ok := v.(alertDeliveryReply)
+	return ok && reply.MessageID == replyToID
+}
+
+func HandleAlertDeliveryReply(ctx context.Context, b *bot.Bot, update *models.Update) {
+	if update.Message == nil || update.Message.ReplyToMessage == nil {
+		return
+	}
+	chatId := update.Message.Chat.ID
+
+	v, ok := session.GetSessionManager().Get(chatId, session.UserAlertDeliveryReply)
+	if !ok {
+		return
+	}
+	reply, ok := v.(alertDeliveryReply)
+	if !ok || reply.MessageID != update.Message.ReplyToMessage.ID {
+		return
+	}
+
+	d := alert.UserDelivery(chatId)
+	switch reply.Field {
+	case "quiet":
+		start, end, err := model.ParseQuietHours(update.Message.Text)
+		if err != nil {
+			util.QuickMessage(ctx, b, chatId, "❌ "+i18n.L(chatId, "alert.err.quiet"))
+			return
+		}
+		d.QuietStart, d.QuietEnd = start, end
+	case "tz":
+		offset, err := model.ParseUtcOffset(update.Message.Text)
+		if err != nil {
+			util.QuickMessage(ctx, b, chatId, "❌ "+i18n.L(chatId, "alert.err.tz"))
+			return
+		}
+		d.UtcOffset = offset
+	default:
+		return
+	}
+
+	if err := alert.SaveDelivery(chatId, d); err != nil {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+		return
+	}
+	session.GetSessionManager().Delete(chatId, session.UserAlertDeliveryReply)
+	text, kb := alertDeliveryView(chatId)
+	sendHTMLView(ctx, b, chatId, text, kb)
+}
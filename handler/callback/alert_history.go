@@ -0,0 +1,128 @@
+package callback
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/alert"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/util"
+	"github.com/spf13/cast"
+)
+
+func alertTypeText(lang, t string) string {
+	switch t {
+	case model.AlertTypeCompound:
+		return i18n.T(lang, "alert.type_compound")
+	case model.PushKindSmartMoney, model.PushKindNewListing, model.PushKindOrderFilled:
+		return i18n.T(lang, "push.kind."+t)
+	}
+	return i18n.T(lang, "aimonitor.type."+t)
+}
+
+func alertStatusText(lang string, r model.AlertRecord) string {
+	if r.Status == model.AlertStatusFailed {
+		return i18n.T(lang, "alert.status.failed", html.EscapeString(r.Error))
+	}
+	return i18n.T(lang, "alert.status."+r.Status)
+}
+
+// time in user timezone of alert delivery
+func alertTimeText(chatId int64, at int64) string {
+	zone := time.FixedZone("", alert.UserDelivery(chatId).UtcOffset*3600)
+	return time.Unix(at, 0).In(zone).Format("01-02 15:04")
+}
+
+func alertHistoryView(chatId int64, page int) (string, models.InlineKeyboardMarkup) {
+	lang := i18n.UserLang(chatId)
+	records, total := alert.UserRecords(chatId, page)
+	pages := int((total + model.AlertHistoryPageSize - 1) / model.AlertHistoryPageSize)
+
+	text := i18n.T(lang, "alert.history", total, alert.QueuedLen(chatId))
+	if len(records) == 0 {
+		text += "\n\n" + i18n.T(lang, "alert.history_empty")
+	}
+	for _, r := range records {
+		text += fmt.Sprintf("\n\n🕒 %s <b>%s</b> · %s · %s\n%s",
+			alertTimeText(chatId, r.At), html.EscapeString(r.Symbol), alertTypeText(lang, r.Type),
+			util.FormatNumber(r.Value), alertStatusText(lang, r),
+		)
+	}
+
+	var nav []models.InlineKeyboardButton
+	if page > 0 {
+		nav = append(nav, util.NewCallbackDataButton("⬅️", fmt.Sprintf("ah::p::%d", page-1)))
+	}
+	if page+1 < pages {
+		nav = append(nav, util.NewCallbackDataButton("➡️", fmt.Sprintf("ah::p::%d", page+1)))
+	}
+	buttons := [][]models.InlineKeyboardButton{}
+	if len(nav) > 0 {
+		buttons = append(buttons, nav)
+	}
+	buttons = append(buttons, []models.InlineKeyboardButton{
+		util.NewCallbackDataButton(i18n.T(lang, "alert.stats_button"), "ah::st"),
+	})
+	return text, models.InlineKeyboardMarkup{InlineKeyboard: buttons}
+}
+
+func alertStatsView(chatId int64) (string, models.InlineKeyboardMarkup) {
+	lang := i18n.UserLang(chatId)
+	stats := alert.UserStats(chatId)
+
+	text := i18n.T(lang, "alert.stats", len(stats))
+	if len(stats) == 0 {
+		text += "\n\n" + i18n.T(lang, "alert.history_empty")
+	}
+	for i, s := range stats {
+		if i >= model.MaxAlertStatLen {
+			break
+		}
+		text += "\n\n" + i18n.T(lang, "alert.stat",
+			html.EscapeString(s.Last.Symbol), alertTypeText(lang, s.Last.Type), s.Count, alertTimeText(chatId, s.Last.At),
+		)
+	}
+
+	kb := models.InlineKeyboardMarkup{InlineKeyboard: [][]models.InlineKeyboardButton{{
+		util.NewCallbackDataButton(i18n.T(lang, "alert.history_button"), "ah::p::0"),
+	}}}
+	return text, kb
+}
+
+// command /alerts_history, trigger by entity.ALERT_HISTORY
+func CallbackAlertHistory(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatID := util.EffectId(update)
+	text, kb := alertHistoryView(chatID, 0)
+	sendHTMLView(ctx, b, chatID, text, kb)
+}
+
+// prefix: ah::p::<page>, ah::st
+func CallbackAlertHistoryAction(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatId := util.EffectId(update)
+	msg := update.CallbackQuery.Message.Message
+	params := strings.Split(update.CallbackQuery.Data, "::")
+	if len(params) < 2 || msg == nil {
+		return
+	}
+
+	var text string
+	var kb models.InlineKeyboardMarkup
+	switch params[1] {
+	case "p":
+		if len(params) != 3 {
+			return
+		}
+		text, kb = alertHistoryView(chatId, max(cast.ToInt(params[2]), 0))
+	case "st":
+		text, kb = alertStatsView(chatId)
+	default:
+		return
+	}
+	editHTMLView(ctx, b, chatId, msg.ID, text, kb)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/go-telegram/bot/models"
 	"github.com/hellodex/tradingbot/api"
 	"github.com/hellodex/tradingbot/entity"
+	"github.com/hellodex/tradingbot/i18n"
 	"github.com/hellodex/tradingbot/model"
 	"github.com/hellodex/tradingbot/session"
 	"github.com/hellodex/tradingbot/store"
@@ -19,13 +20,6 @@ import (
 	"github.com/spf13/cast"
 )
 
-const assetsText = `
-当前钱包：<code>%s</code>
-当前公链：%s
-
-选择 Token 进行快速交易！！
-`
-
 func AssetsSelectByAddressHandler(ctx context.Context, b *bot.Bot, update *models.Update, wallet model.Wallet) {
 	chatID := util.EffectId(update)
 
@@ -45,15 +39,15 @@ func AssetsSelectByAddressHandler(ctx context.Context, b *bot.Bot, update *model
 
 	kb := buildAssetsMenuSelect(assets, chatID)
 	switchWalletButton := models.InlineKeyboardButton{
-		Text:         "切换钱包",
+		Text:         i18n.L(chatID, "wallet.switch"),
 		CallbackData: entity.SWITCH_DEFAULT_WALLET,
 	}
 	menuButton := models.InlineKeyboardButton{
-		Text:         "主菜单",
+		Text:         i18n.L(chatID, "common.main_menu"),
 		CallbackData: "go_menu",
 	}
 	lastLineButton := []models.InlineKeyboardButton{
-		entity.GetCallbackButton(entity.SWITCH_PUBLIC_CHAIN),
+		entity.GetCallbackButtonLang(entity.SWITCH_PUBLIC_CHAIN, i18n.UserLang(chatID)),
 		// entity.GetCallbackButton(entity.SWITCH_DEFAULT_WALLET),
 		switchWalletButton,
 		menuButton,
@@ -65,7 +59,7 @@ func AssetsSelectByAddressHandler(ctx context.Context, b *bot.Bot, update *model
 	store.BotMessageAdd()
 	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID:      chatID,
-		Text:        fmt.Sprintf(assetsText, wallet.Wallet, chainName),
+		Text:        i18n.L(chatID, "assets.info", wallet.Wallet, chainName),
 		ParseMode:   "HTML",
 		ReplyMarkup: kb,
 		LinkPreviewOptions: &models.LinkPreviewOptions{
@@ -93,15 +87,15 @@ func AssetsHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 
 	kb := buildAssetsMenuSelect(assets, chatID)
 	switchWalletButton := models.InlineKeyboardButton{
-		Text:         "切换钱包",
+		Text:         i18n.L(chatID, "wallet.switch"),
 		CallbackData: entity.SWITCH_DEFAULT_WALLET,
 	}
 	menuButton := models.InlineKeyboardButton{
-		Text:         "主菜单",
+		Text:         i18n.L(chatID, "common.main_menu"),
 		CallbackData: "go_menu",
 	}
 	lastLineButton := []models.InlineKeyboardButton{
-		entity.GetCallbackButton(entity.SWITCH_PUBLIC_CHAIN),
+		entity.GetCallbackButtonLang(entity.SWITCH_PUBLIC_CHAIN, i18n.UserLang(chatID)),
 		// entity.GetCallbackButton(entity.SWITCH_DEFAULT_WALLET),
 		switchWalletButton,
 		menuButton,
@@ -113,7 +107,7 @@ func AssetsHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 	store.BotMessageAdd()
 	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID:      chatID,
-		Text:        fmt.Sprintf(assetsText, defaultW.Wallet, chainName),
+		Text:        i18n.L(chatID, "assets.info", defaultW.Wallet, chainName),
 		ParseMode:   "HTML",
 		ReplyMarkup: kb,
 		LinkPreviewOptions: &models.LinkPreviewOptions{
@@ -198,7 +192,7 @@ func AssetsSelectHandler(ctx context.Context, b *bot.Bot, update *models.Update)
 	userInfo, err := api.GetUserProfile(chatId)
 	if err != nil {
 		log.Error().Err(err).Send()
-		util.QuickMessage(ctx, b, chatId, "出错了，请联系客服")
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 
@@ -207,7 +201,7 @@ func AssetsSelectHandler(ctx context.Context, b *bot.Bot, update *models.Update)
 	log.Debug().Interface("tokeninfo", tokenInfo).Msg("test to find bug in bnb")
 	if err != nil {
 		log.Error().Err(err).Send()
-		util.QuickMessage(ctx, b, chatId, "出错了，请联系客服")
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 
@@ -217,6 +211,7 @@ func AssetsSelectHandler(ctx context.Context, b *bot.Bot, update *models.Update)
 	buyCallBackData := fmt.Sprintf(BUY_BUTTON, tokenInfo.Data.PairAddress, tokenInfo.Data.ChainCode)
 	sellCallBackData := fmt.Sprintf(SELL_BUTTON, tokenInfo.Data.PairAddress, tokenInfo.Data.ChainCode)
 
+	tradePreset := UserTradePreset(chatId, tokenInfo.Data.ChainCode)
 	kb := util.BuySellKeyBoard(util.BuySellKeyBoardData{
 		PoolAddress:         tokenInfo.Data.PairAddress,
 		BuyCallBackData:     buyCallBackData,
@@ -227,6 +222,9 @@ func AssetsSelectHandler(ctx context.Context, b *bot.Bot, update *models.Update)
 		QuoteToken:          tokenInfo.Data.QuoteToken.Symbol,
 		QuoteTokenChainCode: tokenInfo.Data.QuoteToken.ChainCode,
 		QuoteTokenAddress:   tokenInfo.Data.QuoteToken.Address,
+		BuyPresets:          tradePreset.Buy,
+		SellPresets:         tradePreset.Sell,
+		Lang:                i18n.UserLang(chatId),
 	})
 
 	// check PairAddress
@@ -235,10 +233,10 @@ func AssetsSelectHandler(ctx context.Context, b *bot.Bot, update *models.Update)
 		return
 	}
 
-	textTemplate, err := template.RanderTokenInfo(tokenInfo)
+	textTemplate, err := template.RanderTokenInfo(i18n.UserLang(chatId), tokenInfo)
 	if err != nil {
 		log.Error().Err(err).Send()
-		util.QuickMessage(ctx, b, chatId, "出错了，请联系客服")
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 	store.BotMessageAdd()
@@ -0,0 +1,163 @@
+package callback
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/api"
+	"github.com/hellodex/tradingbot/chart"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/store"
+	"github.com/hellodex/tradingbot/util"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cast"
+)
+
+const (
+	chartKlineLimit      = 60
+	ChartDefaultTimeType = "15m"
+)
+
+// entry price of user position and open limit orders of this token
+func chartPriceLines(chatId int64, info api.TokenInfo) (entry string, orders []string, lines []chart.Line) {
+	userInfo, err := api.GetUserProfile(chatId)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+	dw, _, _ := UserDefaultWalletInfo(userInfo)
+	if dw.ChainCode != info.ChainCode {
+		return
+	}
+
+	pos, err := api.GetPositionByWalletAddress(dw.Wallet, info.BaseAddress, dw.ChainCode, userInfo)
+	if err != nil {
+		log.Debug().Err(err).Msg("chart get position")
+	} else if cast.ToFloat64(pos.Data.Amount) > 0 && cast.ToFloat64(pos.Data.AveragePrice) > 0 {
+		entry = pos.Data.AveragePrice
+		lines = append(lines, chart.Line{Price: cast.ToFloat64(entry), Color: chart.ColorEntry})
+	}
+
+	openOrders, err := api.ListOpeningOrders(cast.ToFloat64(dw.WalletId), userInfo)
+	if err != nil {
+		log.Debug().Err(err).Msg("chart list opening orders")
+		return
+	}
+	for _, o := range openOrders.Data {
+		if o.BaseAddress != info.BaseAddress || cast.ToFloat64(o.Price) <= 0 {
+			continue
+		}
+		orders = append(orders, o.Price)
+		lines = append(lines, chart.Line{Price: cast.ToFloat64(o.Price), Color: chart.ColorOrder})
+	}
+	return
+}
+
+func chartTimeTypeKeyboard(timeType string, baseAddress string) models.InlineKeyboardMarkup {
+	var line []models.InlineKeyboardButton
+	for _, t := range model.KlineTimeTypes {
+		text := t
+		if t == timeType {
+			text = "✅" + t
+		}
+		line = append(line, util.NewCallbackDataButton(text, "chart::"+t+"::"+baseAddress))
+	}
+	return models.InlineKeyboardMarkup{InlineKeyboard: [][]models.InlineKeyboardButton{line}}
+}
+
+func renderChart(chatId int64, info api.TokenInfo, timeType string) ([]byte, string, error) {
+	lang := i18n.UserLang(chatId)
+	klines, err := api.GetKline(info.PairAddress, info.ChainCode, timeType, chartKlineLimit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	entry, orders, lines := chartPriceLines(chatId, info)
+	png, err := chart.RenderCandles(klines.Data, lines)
+	if err != nil {
+		return nil, "", err
+	}
+
+	caption := i18n.T(lang, "chart.caption", info.Symbol, api.GetChainNameFallbackCode(info.ChainCode), timeType, util.FormatNumber(info.Price))
+	if entry != "" {
+		caption += "\n" + i18n.T(lang, "chart.entry", util.FormatNumber(entry))
+	}
+	if len(orders) > 0 {
+		for i := range orders {
+			orders[i] = "$" + util.FormatNumber(orders[i])
+		}
+		caption += "\n" + i18n.T(lang, "chart.orders", strings.Join(orders, ", "))
+	}
+	return png, caption, nil
+}
+
+// prefix: chart::<timeType>::<baseAddress>
+// from token card send new photo, from chart photo edit media in place
+func CallbackChart(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatId := util.EffectId(update)
+	msg := update.CallbackQuery.Message.Message
+	params := strings.Split(update.CallbackQuery.Data, "::")
+	if len(params) != 3 || msg == nil {
+		return
+	}
+	timeType, address := params[1], params[2]
+	if !slices.Contains(model.KlineTimeTypes, timeType) {
+		timeType = ChartDefaultTimeType
+	}
+
+	info := api.SearchTokenInfoSwitch(address)
+	if info.PairAddress == "" {
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "group.token_not_found"))
+		return
+	}
+	if info.BaseAddress == "" {
+		info.BaseAddress = address
+	}
+
+	png, caption, err := renderChart(chatId, info, timeType)
+	if err != nil {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "chart.error"))
+		return
+	}
+	kb := chartTimeTypeKeyboard(timeType, address)
+
+	if len(msg.Photo) > 0 {
+		_, err = b.EditMessageMedia(ctx, &bot.EditMessageMediaParams{
+			ChatID:    chatId,
+			MessageID: msg.ID,
+			Media: &models.InputMediaPhoto{
+				Media:           "attach://chart.png",
+				Caption:         caption,
+				ParseMode:       models.ParseModeHTML,
+				MediaAttachment: bytes.NewReader(png),
+			},
+			ReplyMarkup: kb,
+		})
+		if err != nil {
+			log.Error().Err(err).Send()
+		}
+		return
+	}
+
+	store.BotMessageAdd()
+	_, err = b.SendPhoto(ctx, &bot.SendPhotoParams{
+		ChatID:      chatId,
+		Photo:       &models.InputFileUpload{Filename: "chart.png", Data: bytes.NewReader(png)},
+		Caption:     caption,
+		ParseMode:   models.ParseModeHTML,
+		ReplyMarkup: kb,
+		ReplyParameters: &models.ReplyParameters{
+			MessageID:                msg.ID,
+			AllowSendingWithoutReply: true,
+		},
+	})
+	if err != nil {
+		log.Error().Err(err).Send()
+	}
+}
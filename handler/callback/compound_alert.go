@@ -0,0 +1,340 @@
+package callback
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/alert"
+	"github.com/hellodex/tradingbot/api"
+	"github.com/hellodex/tradingbot/entity"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/session"
+	"github.com/hellodex/tradingbot/store"
+	"github.com/hellodex/tradingbot/template"
+	"github.com/hellodex/tradingbot/util"
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+)
+
+// compound alert being built
+type compoundDraft struct {
+	Alert model.CompoundAlert
+	// pending condition: logic to join, metric and op
+	Logic  string
+	Metric string
+	Op     string
+	// expr before each added condition, for undo
+	History []model.AlertExpr
+}
+
+type compoundReply struct {
+	MessageID int
+	// token or value
+	Field string
+}
+
+var compoundNoticeKeys = map[int64]string{
+	1: "aimonitor.notice.once",
+	2: "aimonitor.notice.daily",
+	3: "aimonitor.notice.every",
+}
+
+func compoundListView(chatId int64) (string, models.InlineKeyboardMarkup) {
+	lang := i18n.UserLang(chatId)
+	alerts := alert.UserCompoundAlerts(chatId)
+
+	text := i18n.T(lang, "alert.title", len(alerts), model.MaxCompoundAlertLen)
+	if len(alerts) == 0 {
+		text += "\n\n" + i18n.T(lang, "alert.empty")
+	}
+
+	var buttons [][]models.InlineKeyboardButton
+	for _, c := range alerts {
+		text += "\n\n🧩 <b>" + c.Symbol + "</b> (" + api.GetChainNameFallbackCode(c.ChainCode) + ")\n<code>" + c.Token + "</code>\n" +
+			template.RenderAlertExpr(lang, c.Expr) + "\n" + i18n.T(lang, compoundNoticeKeys[c.NoticeType])
+		buttons = append(buttons, []models.InlineKeyboardButton{
+			util.NewCallbackDataButton(i18n.T(lang, "alert.remove", c.Symbol), "ca::rm::"+c.Id),
+		})
+	}
+	buttons = append(buttons, []models.InlineKeyboardButton{
+		entity.GetCallbackButtonLang(entity.ADD_COMPOUND_ALERT, lang),
+	})
+	return text, models.InlineKeyboardMarkup{InlineKeyboard: buttons}
+}
+
+func compoundDraftView(chatId int64, d compoundDraft) (string, models.InlineKeyboardMarkup) {
+	lang := i18n.UserLang(chatId)
+	c := d.Alert
+
+	text := i18n.T(lang, "alert.draft",
+		c.Symbol, api.GetChainNameFallbackCode(c.ChainCode), c.Token,
+		template.RenderAlertExpr(lang, c.Expr), i18n.T(lang, compoundNoticeKeys[c.NoticeType]),
+	)
+
+	var buttons [][]models.InlineKeyboardButton
+	switch {
+	case d.Metric != "":
+		text += "\n\n" + i18n.T(lang, "alert.pick_op", i18n.T(lang, "alert.metric."+d.Metric))
+		var row []models.InlineKeyboardButton
+		for _, op := range model.AlertOps {
+			row = append(row, util.NewCallbackDataButton(i18n.T(lang, "alert.op."+op), "ca::o::"+op))
+		}
+		buttons = append(buttons, row)
+	case c.Expr.IsEmpty() || d.Logic != "":
+		text += "\n\n" + i18n.T(lang, "alert.pick_metric")
+		var row []models.InlineKeyboardButton
+		for _, m := range model.AlertMetrics {
+			row = append(row, util.NewCallbackDataButton(i18n.T(lang, "alert.metric."+m), "ca::m::"+m))
+			if len(row) == 2 {
+				buttons = append(buttons, row)
+				row = nil
+			}
+		}
+		if len(row) > 0 {
+			buttons = append(buttons, row)
+		}
+	default:
+		text += "\n\n" + i18n.T(lang, "alert.pick_next")
+		if c.Expr.Leaves() < model.MaxAlertConditions {
+			buttons = append(buttons, []models.InlineKeyboardButton{
+				util.NewCallbackDataButton(i18n.T(lang, "alert.add_and"), "ca::l::"+model.AlertAnd),
+				util.NewCallbackDataButton(i18n.T(lang, "alert.add_or"), "ca::l::"+model.AlertOr),
+			})
+		}
+		buttons = append(buttons, []models.InlineKeyboardButton{
+			util.NewCallbackDataButton(i18n.T(lang, "alert.notice", i18n.T(lang, compoundNoticeKeys[c.NoticeType])), "ca::n::"),
+			util.NewCallbackDataButton(i18n.T(lang, "alert.save"), "ca::s::"),
+		})
+	}
+
+	row := []models.InlineKeyboardButton{util.NewCallbackDataButton(i18n.T(lang, "alert.cancel"), "ca::x::")}
+	if !c.Expr.IsEmpty() || d.Metric != "" {
+		row = append([]models.InlineKeyboardButton{util.NewCallbackDataButton(i18n.T(lang, "alert.undo"), "ca::u::")}, row...)
+	}
+	buttons = append(buttons, row)
+	return text, models.InlineKeyboardMarkup{InlineKeyboard: buttons}
+}
+
+// trigger by entity.COMPOUND_ALERT
+func CallbackCompoundAlert(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatID := util.EffectId(update)
+	text, kb := compoundListView(chatID)
+	sendHTMLView(ctx, b, chatID, text, kb)
+}
+
+// trigger by entity.ADD_COMPOUND_ALERT
+func CallbackAddCompoundAlert(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatID := util.EffectId(update)
+	if len(alert.UserCompoundAlerts(chatID)) >= model.MaxCompoundAlertLen {
+		util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "alert.err.full", model.MaxCompoundAlertLen))
+		return
+	}
+	askCompoundInput(ctx, b, chatID, "token", i18n.L(chatID, "alert.input_token"))
+}
+
+func askCompoundInput(ctx context.Context, b *bot.Bot, chatId int64, field string, text string) {
+	store.BotMessageAdd()
+	message, err := b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:    chatId,
+		Text:      text,
+		ParseMode: models.ParseModeHTML,
+		ReplyMarkup: models.ForceReply{
+			ForceReply: true,
+		},
+	})
+	if err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+	session.GetSessionManager().Set(chatId, session.UserCompoundAlertReply, compoundReply{
+		MessageID: message.ID,
+		Field:     field,
+	})
+}
+
+func getCompoundDraft(chatId int64) (compoundDraft, bool) {
+	v, ok := session.GetSessionManager().Get(chatId, session.UserCompoundAlertDraft)
+	if !ok {
+		return compoundDraft{}, false
+	}
+	d, ok := v.(compoundDraft)
+	return d, ok
+}
+
+// prefix: ca::<action>::<arg>
+func CallbackCompoundAlertAction(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatId := util.EffectId(update)
+	msg := update.CallbackQuery.Message.Message
+	params := strings.Split(update.CallbackQuery.Data, "::")
+	if len(params) != 3 || msg == nil {
+		return
+	}
+	action, arg := params[1], params[2]
+
+	if action == "rm" {
+		if err := store.UserDeleteCompoundAlert(chatId, arg); err != nil {
+			log.Error().Err(err).Send()
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+			return
+		}
+		text, kb := compoundListView(chatId)
+		editHTMLView(ctx, b, chatId, msg.ID, text, kb)
+		return
+	}
+
+	d, ok := getCompoundDraft(chatId)
+	if !ok {
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "alert.not_found"))
+		return
+	}
+
+	switch action {
+	case "l":
+		if d.Alert.Expr.Leaves() >= model.MaxAlertConditions {
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "alert.err.conditions", model.MaxAlertConditions))
+			return
+		}
+		d.Logic = arg
+	case "m":
+		d.Metric = arg
+	case "o":
+		if d.Metric == "" {
+			return
+		}
+		d.Op = arg
+		session.GetSessionManager().Set(chatId, session.UserCompoundAlertDraft, d)
+		text := i18n.L(chatId, "alert.input_value", i18n.L(chatId, "alert.metric."+d.Metric))
+		if d.Metric == model.MetricChg1h {
+			text = i18n.L(chatId, "alert.input_chg")
+		}
+		askCompoundInput(ctx, b, chatId, "value", text)
+		return
+	case "n":
+		d.Alert.NoticeType = model.CycleNext(model.AlertNoticeTypes, d.Alert.NoticeType)
+	case "u":
+		// 先撤销未完成的条件, 再撤销上一个条件
+		if d.Metric != "" || d.Logic != "" {
+			d.Metric, d.Op, d.Logic = "", "", ""
+		} else if n := len(d.History); n > 0 {
+			d.Alert.Expr, d.History = d.History[n-1], d.History[:n-1]
+		}
+	case "s":
+		if !d.Alert.Vaild() {
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "alert.err.invalid"))
+			return
+		}
+		if err := alert.SaveCompoundAlert(chatId, d.Alert); err != nil {
+			log.Error().Err(err).Send()
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+			return
+		}
+		session.GetSessionManager().Delete(chatId, session.UserCompoundAlertDraft)
+		text, kb := compoundListView(chatId)
+		editHTMLView(ctx, b, chatId, msg.ID, text, kb)
+		return
+	case "x":
+		session.GetSessionManager().Delete(chatId, session.UserCompoundAlertDraft)
+		session.GetSessionManager().Delete(chatId, session.UserCompoundAlertReply)
+		text, kb := compoundListView(chatId)
+		editHTMLView(ctx, b, chatId, msg.ID, text, kb)
+		return
+	default:
+		return
+	}
+
+	session.GetSessionManager().Set(chatId, session.UserCompoundAlertDraft, d)
+	text, kb := compoundDraftView(chatId, d)
+	editHTMLView(ctx, b, chatId, msg.ID, text, kb)
+}
+
+// check if user replay to compound alert input message
+func IsCompoundAlertReply(chatId int64, replyToID int) bool {
+	v, ok := session.GetSessionManager().Get(chatId, session.UserCompoundAlertReply)
+	if !ok {
+		return false
+	}
+	reply, ok := v.(compoundReply)
+	return ok && reply.MessageID == replyToID
+}
+
+func HandleCompoundAlertReply(ctx context.Context, b *bot.Bot, update *models.Update) {
+	if update.Message == nil || update.Message.ReplyToMessage == nil {
+		return
+	}
+	chatId := update.Message.Chat.ID
+
+	v, ok := session.GetSessionManager().Get(chatId, session.UserCompoundAlertReply)
+	if !ok {
+		return
+	}
+	reply, ok := v.(compoundReply)
+	if !ok || reply.MessageID != update.Message.ReplyToMessage.ID {
+		return
+	}
+	input := strings.TrimSpace(update.Message.Text)
+
+	var d compoundDraft
+	switch reply.Field {
+	case "token":
+		c, err := newCompoundAlert(chatId, input)
+		if err != nil {
+			text := i18n.L(chatId, "common.error")
+			switch err {
+			case model.ErrCompoundAlertFull:
+				text = i18n.L(chatId, "alert.err.full", model.MaxCompoundAlertLen)
+			case model.ErrCompoundAlertToken:
+				text = i18n.L(chatId, "alert.err.token")
+			}
+			util.QuickMessage(ctx, b, chatId, "❌ "+text)
+			return
+		}
+		d = compoundDraft{Alert: c}
+	case "value":
+		d, ok = getCompoundDraft(chatId)
+		if !ok || d.Metric == "" || d.Op == "" {
+			session.GetSessionManager().Delete(chatId, session.UserCompoundAlertReply)
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "alert.not_found"))
+			return
+		}
+		v, err := decimal.NewFromString(input)
+		if err != nil || (v.IsNegative() && d.Metric != model.MetricChg1h) {
+			util.QuickMessage(ctx, b, chatId, "❌ "+i18n.L(chatId, "alert.err.number"))
+			return
+		}
+		logic := d.Logic
+		if logic == "" {
+			logic = model.AlertAnd
+		}
+		d.History = append(d.History, d.Alert.Expr)
+		d.Alert.Expr = d.Alert.Expr.Add(logic, model.AlertExpr{Metric: d.Metric, Op: d.Op, Value: v.String()})
+		d.Logic, d.Metric, d.Op = "", "", ""
+	default:
+		return
+	}
+
+	session.GetSessionManager().Delete(chatId, session.UserCompoundAlertReply)
+	session.GetSessionManager().Set(chatId, session.UserCompoundAlertDraft, d)
+	text, kb := compoundDraftView(chatId, d)
+	sendHTMLView(ctx, b, chatId, text, kb)
+}
+
+func newCompoundAlert(chatId int64, token string) (model.CompoundAlert, error) {
+	if len(alert.UserCompoundAlerts(chatId)) >= model.MaxCompoundAlertLen {
+		return model.CompoundAlert{}, model.ErrCompoundAlertFull
+	}
+	if _, err := util.CheckValidAddress(token); err != nil {
+		return model.CompoundAlert{}, model.ErrCompoundAlertToken
+	}
+	info := api.SearchTokenInfo(token)
+	if info.ChainCode == "" || info.Price == "" {
+		return model.CompoundAlert{}, model.ErrCompoundAlertToken
+	}
+	symbol := info.Symbol
+	if symbol == "" {
+		symbol = util.ShortAddress(token)
+	}
+	return model.NewCompoundAlert(info.ChainCode, token, symbol, info.PairAddress), nil
+}
@@ -0,0 +1,246 @@
+package callback
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/api"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/store"
+	"github.com/hellodex/tradingbot/util"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cast"
+)
+
+const (
+	exportCSV  = "csv"
+	exportJSON = "json"
+)
+
+// 0 means all history
+var exportRangeDays = []int{7, 30, 90, 0}
+
+// export button under history list
+func ExportButton(lang, kind string) models.InlineKeyboardButton {
+	return util.NewCallbackDataButton(i18n.T(lang, "export.button"), "exp::"+kind)
+}
+
+func exportRangeText(lang string, days int) string {
+	if days == 0 {
+		return i18n.T(lang, "export.range_all")
+	}
+	return i18n.T(lang, "export.range_days", days)
+}
+
+func exportRangeKeyboard(lang, kind string) models.InlineKeyboardMarkup {
+	var line []models.InlineKeyboardButton
+	for _, d := range exportRangeDays {
+		line = append(line, util.NewCallbackDataButton(exportRangeText(lang, d), fmt.Sprintf("exp::%s::%d", kind, d)))
+	}
+	return models.InlineKeyboardMarkup{InlineKeyboard: [][]models.InlineKeyboardButton{line}}
+}
+
+func exportFormatKeyboard(kind string, days int) models.InlineKeyboardMarkup {
+	return models.InlineKeyboardMarkup{InlineKeyboard: [][]models.InlineKeyboardButton{{
+		util.NewCallbackDataButton("CSV", fmt.Sprintf("exp::%s::%d::%s", kind, days, exportCSV)),
+		util.NewCallbackDataButton("JSON", fmt.Sprintf("exp::%s::%d::%s", kind, days, exportJSON)),
+	}}}
+}
+
+// timestamp in millisecond
+func inExportRange(ts int64, since int64) bool {
+	return since == 0 || ts >= since
+}
+
+// list is newest first, records before since mean no more pages needed
+func beforeExportRange(ts int64, since int64) bool {
+	return !inExportRange(ts, since)
+}
+
+// fetch all records of kind in range, return json data and csv rows,
+// truncated is true when the page limit is reached
+func exportRecords(kind string, since int64, w model.Wallet, userInfo model.GetUserResp) (any, [][]string, bool, error) {
+	switch kind {
+	case HistoryTrade:
+		walletID := cast.ToFloat64(w.WalletId)
+		data, truncated, err := api.ListAllPages(func(pageNum int) ([]model.TradeHistoryInner, error) {
+			r, err := api.ListTradeHistory(walletID, userInfo, api.Page{Num: pageNum, Size: api.ExportPageSize})
+			return r.Data, err
+		}, func(t model.TradeHistoryInner) string { return t.OrderNo + t.Tx }, func(t model.TradeHistoryInner) bool {
+			return beforeExportRange(cast.ToInt64(t.Timestamp), since)
+		})
+		if err != nil {
+			return nil, nil, false, err
+		}
+
+		records := []model.TradeHistoryInner{}
+		rows := [][]string{{"time", "chain", "base", "quote", "direction", "tradeType", "amount", "price", "volume", "marketCap", "status", "orderNo", "tx"}}
+		for _, t := range data {
+			if !inExportRange(cast.ToInt64(t.Timestamp), since) {
+				continue
+			}
+			records = append(records, t)
+			rows = append(rows, []string{util.FormatTime(t.Timestamp), t.ChainCode, t.BaseSymbol, t.QuoteSymbol, t.Direction, t.TradeType, t.Amount, t.Price, t.Volume, t.MarketCap, t.OrderStatusUI, t.OrderNo, t.Tx})
+		}
+		return records, rows, truncated, nil
+	case HistoryTransfer:
+		data, truncated, err := api.ListAllPages(func(pageNum int) ([]model.TransferHistoryInner, error) {
+			r, err := api.ListTransferHistory(cast.ToFloat64(w.WalletId), w.ChainCode, userInfo, api.Page{Num: pageNum, Size: api.ExportPageSize})
+			return r.Data, err
+		}, func(t model.TransferHistoryInner) string { return t.Hash + cast.ToString(t.Timestamp) }, func(t model.TransferHistoryInner) bool {
+			return beforeExportRange(t.Timestamp, since)
+		})
+		if err != nil {
+			return nil, nil, false, err
+		}
+
+		records := []model.TransferHistoryInner{}
+		rows := [][]string{{"time", "chain", "wallet", "token", "to", "amount", "fee", "status", "hash"}}
+		for _, t := range data {
+			if !inExportRange(t.Timestamp, since) {
+				continue
+			}
+			records = append(records, t)
+			rows = append(rows, []string{util.FormatTime(cast.ToString(t.Timestamp)), t.ChainCode, t.WalletAddress, t.TokenAddress, t.ToAddress, t.Amount, t.Fee, t.OrderStatusUI, t.Hash})
+		}
+		return records, rows, truncated, nil
+	case HistoryOrder:
+		data, truncated, err := api.ListAllPages(func(pageNum int) ([]model.OpenOrderInner, error) {
+			r, err := api.ListHistoryOrders(cast.ToFloat64(w.WalletId), userInfo, api.Page{Num: pageNum, Size: api.ExportPageSize})
+			return r.Data, err
+		}, func(o model.OpenOrderInner) string { return o.OrderNo }, func(o model.OpenOrderInner) bool {
+			return beforeExportRange(cast.ToInt64(o.Timestamp), since)
+		})
+		if err != nil {
+			return nil, nil, false, err
+		}
+
+		records := []model.OpenOrderInner{}
+		rows := [][]string{{"time", "chain", "from", "to", "orderType", "limitType", "price", "amount", "volume", "status", "orderNo", "tx"}}
+		for _, o := range data {
+			if !inExportRange(cast.ToInt64(o.Timestamp), since) {
+				continue
+			}
+			records = append(records, o)
+			rows = append(rows, []string{util.FormatTime(o.Timestamp), o.ChainCode, o.FromTokenSymbol, o.ToTokenSymbol, o.OrderType, o.LimitType, o.Price, o.Amount, o.Volume, o.OrderStatusUI, o.OrderNo, o.Tx})
+		}
+		return records, rows, truncated, nil
+	}
+	return nil, nil, false, fmt.Errorf("unknown export kind %s", kind)
+}
+
+func encodeExport(format string, records any, rows [][]string) ([]byte, error) {
+	var buf bytes.Buffer
+	if format == exportJSON {
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", "  ")
+		err := enc.Encode(records)
+		return buf.Bytes(), err
+	}
+
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func sendExport(ctx context.Context, b *bot.Bot, chatId int64, kind string, days int, format string) {
+	lang := i18n.UserLang(chatId)
+	util.QuickMessage(ctx, b, chatId, i18n.T(lang, "export.exporting"))
+
+	userInfo, err := api.GetUserProfile(chatId)
+	if err != nil {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, chatId, i18n.T(lang, "common.error"))
+		return
+	}
+
+	var since int64
+	if days > 0 {
+		since = time.Now().AddDate(0, 0, -days).UnixMilli()
+	}
+	records, rows, truncated, err := exportRecords(kind, since, historyWallet(userInfo, getHistoryFilter(chatId, kind).WalletId), userInfo)
+	if err != nil {
+		log.Error().Err(err).Str("kind", kind).Msg("export history")
+		util.QuickMessage(ctx, b, chatId, i18n.T(lang, "common.error"))
+		return
+	}
+	// rows include header
+	if len(rows) <= 1 {
+		util.QuickMessage(ctx, b, chatId, i18n.T(lang, "export.empty"))
+		return
+	}
+
+	data, err := encodeExport(format, records, rows)
+	if err != nil {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, chatId, i18n.T(lang, "common.error"))
+		return
+	}
+
+	caption := i18n.T(lang, "export.caption", len(rows)-1, exportRangeText(lang, days))
+	if truncated {
+		caption += "\n" + i18n.T(lang, "export.truncated", api.ExportMaxRecords)
+	}
+
+	filename := fmt.Sprintf("%s_history_%s.%s", kind, time.Now().Format("20060102"), format)
+	store.BotMessageAdd()
+	_, err = b.SendDocument(ctx, &bot.SendDocumentParams{
+		ChatID:   chatId,
+		Document: &models.InputFileUpload{Filename: filename, Data: bytes.NewReader(data)},
+		Caption:  caption,
+	})
+	if err != nil {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, chatId, i18n.T(lang, "common.error"))
+	}
+}
+
+// prefix: exp::<kind> / exp::<kind>::<days> / exp::<kind>::<days>::<csv|json>
+func CallbackExport(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatId := util.EffectId(update)
+	lang := i18n.UserLang(chatId)
+	params := strings.Split(update.CallbackQuery.Data, "::")
+	if len(params) < 2 || !slices.Contains([]string{HistoryTrade, HistoryTransfer, HistoryOrder}, params[1]) {
+		return
+	}
+	kind := params[1]
+
+	switch len(params) {
+	case 2:
+		store.BotMessageAdd()
+		b.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID:      chatId,
+			Text:        i18n.T(lang, "export.select_range"),
+			ReplyMarkup: exportRangeKeyboard(lang, kind),
+		})
+	case 3:
+		days := cast.ToInt(params[2])
+		msg := update.CallbackQuery.Message.Message
+		if msg == nil {
+			return
+		}
+		b.EditMessageText(ctx, &bot.EditMessageTextParams{
+			ChatID:      chatId,
+			MessageID:   msg.ID,
+			Text:        i18n.T(lang, "export.select_format", exportRangeText(lang, days)),
+			ReplyMarkup: exportFormatKeyboard(kind, days),
+		})
+	case 4:
+		format := params[3]
+		if format != exportJSON {
+			format = exportCSV
+		}
+		sendExport(ctx, b, chatId, kind, cast.ToInt(params[2]), format)
+	}
+}
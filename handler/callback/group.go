@@ -0,0 +1,237 @@
+package callback
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/api"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/store"
+	"github.com/hellodex/tradingbot/template"
+	"github.com/hellodex/tradingbot/util"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+)
+
+// 同一个群同一个token, 在这个时间内只回复一次
+const groupCardDedupe = 10 * time.Minute
+
+func GroupSettingOf(groupId int64) model.GroupSetting {
+	setting := model.GroupSetting{GroupId: groupId}
+	data, has := store.GroupGetSetting(groupId)
+	if !has {
+		return setting
+	}
+	if err := json.Unmarshal(data, &setting); err != nil {
+		log.Error().Err(err).Send()
+	}
+	return setting
+}
+
+func IsGroupAdmin(ctx context.Context, b *bot.Bot, groupId int64, userId int64) bool {
+	member, err := b.GetChatMember(ctx, &bot.GetChatMemberParams{
+		ChatID: groupId,
+		UserID: userId,
+	})
+	if err != nil {
+		log.Error().Err(err).Send()
+		return false
+	}
+	return member.Type == models.ChatMemberTypeOwner || member.Type == models.ChatMemberTypeAdministrator
+}
+
+// compact token card in group, never trade in group, only link to private chat
+func SendGroupTokenCard(ctx context.Context, b *bot.Bot, message *models.Message, address string, force bool) {
+	groupId := message.Chat.ID
+	setting := GroupSettingOf(groupId)
+	// 群里用触发者的语言
+	lang := i18n.Default
+	if message.From != nil {
+		lang = i18n.UserLang(message.From.ID)
+	}
+
+	dedupeKey := "groupCard:" + address
+	if !force {
+		if _, has := store.RedisGetState(groupId, dedupeKey); has {
+			return
+		}
+	}
+
+	info := api.SearchTokenInfoSwitch(address)
+	if info.Symbol == "" {
+		if force {
+			util.QuickMessage(ctx, b, groupId, i18n.T(lang, "group.token_not_found"))
+		}
+		return
+	}
+	if info.BaseAddress == "" {
+		info.BaseAddress = address
+	}
+	if !setting.ChainAllowed(info.ChainCode) {
+		return
+	}
+
+	botUserName := store.GetEnv(store.BOT_USERNAME)
+	startLink := util.TokenStartLink(botUserName, info.BaseAddress, "")
+	text, err := template.RanderInlineTokenCard(lang, info, startLink)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+
+	kb := models.InlineKeyboardMarkup{
+		InlineKeyboard: [][]models.InlineKeyboardButton{
+			{
+				util.UrlButton(i18n.T(lang, "group.private_trade"), startLink),
+				util.UrlButton(i18n.T(lang, "token.kline"), fmt.Sprintf("https://hellodex.io/k/%s?chainCode=%s&timeType=15m", info.PairAddress, info.ChainCode)),
+			},
+		},
+	}
+
+	store.BotMessageAdd()
+	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:              groupId,
+		Text:                text,
+		ParseMode:           models.ParseModeHTML,
+		ReplyMarkup:         kb,
+		DisableNotification: setting.Quiet,
+		ReplyParameters: &models.ReplyParameters{
+			MessageID:                message.ID,
+			AllowSendingWithoutReply: true,
+		},
+		LinkPreviewOptions: &models.LinkPreviewOptions{
+			IsDisabled: bot.True(),
+		},
+	})
+	if err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+
+	store.RedisSetState(groupId, dedupeKey, "1", groupCardDedupe)
+}
+
+func buildGroupSettingKeyboard(lang string, setting model.GroupSetting) (models.InlineKeyboardMarkup, error) {
+	chainCfgs, err := api.GetChainConfigs()
+	if err != nil {
+		return models.InlineKeyboardMarkup{}, err
+	}
+	slices.SortFunc(chainCfgs.Data, func(a, b model.ChainConfig) int {
+		return cmp.Compare(a.Sort, b.Sort)
+	})
+
+	var buttons [][]models.InlineKeyboardButton
+	for _, chainCfg := range chainCfgs.Data {
+		text := chainCfg.Chain
+		if setting.ChainAllowed(chainCfg.ChainCode) {
+			text = "✅" + text
+		}
+		buttons = append(buttons, []models.InlineKeyboardButton{
+			util.NewCallbackDataButton(text, "grp::chain::"+chainCfg.ChainCode),
+		})
+	}
+
+	quietText := i18n.T(lang, "group.quiet", i18n.T(lang, "common.off"))
+	if setting.Quiet {
+		quietText = i18n.T(lang, "group.quiet", i18n.T(lang, "common.on"))
+	}
+	buttons = append(buttons, []models.InlineKeyboardButton{
+		util.NewCallbackDataButton(quietText, "grp::quiet"),
+	})
+
+	return models.InlineKeyboardMarkup{InlineKeyboard: buttons}, nil
+}
+
+// command /groupsettings in group
+func GroupSettingHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	groupId := update.Message.Chat.ID
+	lang := i18n.UserLang(util.EffectId(update))
+	if !IsGroupAdmin(ctx, b, groupId, util.EffectId(update)) {
+		util.QuickMessage(ctx, b, groupId, i18n.T(lang, "group.admin_only"))
+		return
+	}
+
+	kb, err := buildGroupSettingKeyboard(lang, GroupSettingOf(groupId))
+	if err != nil {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, groupId, i18n.T(lang, "common.error"))
+		return
+	}
+
+	store.BotMessageAdd()
+	b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:      groupId,
+		Text:        i18n.T(lang, "group.setting"),
+		ReplyMarkup: kb,
+	})
+}
+
+// prefix: grp::chain::<chainCode> / grp::quiet
+func CallbackGroupSetting(ctx context.Context, b *bot.Bot, update *models.Update) {
+	msg := update.CallbackQuery.Message.Message
+	if msg == nil {
+		return
+	}
+	groupId := msg.Chat.ID
+	if !IsGroupAdmin(ctx, b, groupId, util.EffectId(update)) {
+		return
+	}
+
+	setting := GroupSettingOf(groupId)
+	params := strings.Split(update.CallbackQuery.Data, "::")
+	switch {
+	case len(params) == 3 && params[1] == "chain":
+		chainCode := params[2]
+		if len(setting.AllowedChains) == 0 {
+			// 全部允许时点击, 变成只取消该链
+			chainCfgs, err := api.GetChainConfigs()
+			if err != nil {
+				log.Error().Err(err).Send()
+				return
+			}
+			setting.AllowedChains = lo.FilterMap(chainCfgs.Data, func(c model.ChainConfig, _ int) (string, bool) {
+				return c.ChainCode, c.ChainCode != chainCode
+			})
+		} else if lo.Contains(setting.AllowedChains, chainCode) {
+			setting.AllowedChains = lo.Without(setting.AllowedChains, chainCode)
+		} else {
+			setting.AllowedChains = append(setting.AllowedChains, chainCode)
+		}
+	case len(params) == 2 && params[1] == "quiet":
+		setting.Quiet = !setting.Quiet
+	default:
+		return
+	}
+
+	data, err := setting.JsonB()
+	if err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+	if err := store.GroupSetSetting(groupId, data); err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+
+	kb, err := buildGroupSettingKeyboard(i18n.UserLang(util.EffectId(update)), setting)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+	_, err = b.EditMessageReplyMarkup(ctx, &bot.EditMessageReplyMarkupParams{
+		ChatID:      groupId,
+		MessageID:   msg.ID,
+		ReplyMarkup: kb,
+	})
+	if err != nil {
+		log.Error().Err(err).Send()
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/i18n"
 	"github.com/hellodex/tradingbot/store"
 	"github.com/hellodex/tradingbot/util"
 )
@@ -15,7 +16,7 @@ func HelpHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 	store.BotMessageAdd()
 	b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID:    chatID,
-		Text:      "这里是帮助信息",
+		Text:      i18n.L(chatID, "help.text"),
 		ParseMode: "HTML",
 		LinkPreviewOptions: &models.LinkPreviewOptions{
 			IsDisabled: bot.True(),
@@ -2,21 +2,34 @@ package callback
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/api"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/model"
 	"github.com/hellodex/tradingbot/store"
+	"github.com/hellodex/tradingbot/template"
 	"github.com/hellodex/tradingbot/util"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+	"github.com/spf13/cast"
 )
 
-func HistoryListKeyBoard() models.InlineKeyboardMarkup {
+func HistoryListKeyBoard(lang string) models.InlineKeyboardMarkup {
 	buttons := []models.InlineKeyboardButton{
 		{
-			Text:         "交易记录",
+			Text:         i18n.T(lang, "history.swap"),
 			CallbackData: "list_swap",
 		},
 		{
-			Text:         "转账记录",
+			Text:         i18n.T(lang, "history.transfer"),
 			CallbackData: "list_transfer",
 		},
 	}
@@ -26,14 +39,14 @@ func HistoryListKeyBoard() models.InlineKeyboardMarkup {
 	}
 }
 
-func OrdersListKeyBoard() models.InlineKeyboardMarkup {
+func OrdersListKeyBoard(lang string) models.InlineKeyboardMarkup {
 	buttons := []models.InlineKeyboardButton{
 		{
-			Text:         "当前委托",
+			Text:         i18n.T(lang, "history.orders"),
 			CallbackData: "list_orders",
 		},
 		{
-			Text:         "委托历史",
+			Text:         i18n.T(lang, "history.orders_history"),
 			CallbackData: "list_orders_history",
 		},
 	}
@@ -46,12 +59,12 @@ func OrdersListKeyBoard() models.InlineKeyboardMarkup {
 func OrdersList(ctx context.Context, b *bot.Bot, update *models.Update) {
 	chatId := util.EffectId(update)
 
-	text := "选择你要查看的记录"
+	text := i18n.L(chatId, "history.select")
 	store.BotMessageAdd()
 	b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID:      chatId,
 		Text:        text,
-		ReplyMarkup: OrdersListKeyBoard(),
+		ReplyMarkup: OrdersListKeyBoard(i18n.UserLang(chatId)),
 		ParseMode:   models.ParseModeHTML,
 		LinkPreviewOptions: &models.LinkPreviewOptions{
 			IsDisabled: bot.True(),
@@ -62,15 +75,359 @@ func OrdersList(ctx context.Context, b *bot.Bot, update *models.Update) {
 func HistoryList(ctx context.Context, b *bot.Bot, update *models.Update) {
 	chatId := util.EffectId(update)
 
-	text := "选择你要查看的记录"
+	text := i18n.L(chatId, "history.select")
 	store.BotMessageAdd()
 	b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID:      chatId,
 		Text:        text,
-		ReplyMarkup: HistoryListKeyBoard(),
+		ReplyMarkup: HistoryListKeyBoard(i18n.UserLang(chatId)),
 		ParseMode:   models.ParseModeHTML,
 		LinkPreviewOptions: &models.LinkPreviewOptions{
 			IsDisabled: bot.True(),
 		},
 	})
 }
+
+const (
+	HistoryTrade    = "trade"
+	HistoryTransfer = "transfer"
+	HistoryOrder    = "order"
+)
+
+const (
+	historyCacheTTL  = 2 * time.Minute
+	historyFilterTTL = 30 * time.Minute
+	// 跳页按钮最多显示的页数
+	historyJumpMax = 25
+)
+
+var historyPageSize = map[string]int{
+	HistoryTrade:    5,
+	HistoryTransfer: 5,
+	HistoryOrder:    8,
+}
+
+func historyFilterState(kind string) string {
+	return "historyFilter:" + kind
+}
+
+func getHistoryFilter(chatId int64, kind string) model.HistoryFilter {
+	f := model.HistoryFilter{Page: 1}
+	if data, ok := store.RedisGetState(chatId, historyFilterState(kind)); ok {
+		if err := json.Unmarshal([]byte(data), &f); err != nil {
+			log.Error().Err(err).Send()
+		}
+	}
+	return f
+}
+
+func setHistoryFilter(chatId int64, kind string, f model.HistoryFilter) {
+	data, err := f.JsonB()
+	if err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+	store.RedisSetState(chatId, historyFilterState(kind), string(data), historyFilterTTL)
+}
+
+// all wallets of user, sort by chain
+func historyWallets(userInfo model.GetUserResp) []model.Wallet {
+	chains := slices.Sorted(maps.Keys(userInfo.Data.Wallets))
+	var wallets []model.Wallet
+	for _, c := range chains {
+		wallets = append(wallets, userInfo.Data.Wallets[c]...)
+	}
+	return wallets
+}
+
+// wallet selected in history filter, fallback to default wallet
+func historyWallet(userInfo model.GetUserResp, walletId string) model.Wallet {
+	if w, ok := lo.Find(historyWallets(userInfo), func(w model.Wallet) bool { return w.WalletId == walletId }); ok {
+		return w
+	}
+	dw, _, _ := UserDefaultWalletInfo(userInfo)
+	return dw
+}
+
+func cachedHistory[T any](chatId int64, kind string, walletId string, reload bool, fetch func() ([]T, error)) ([]T, error) {
+	var list []T
+	if !reload {
+		if data, ok := store.UserGetHistory(chatId, kind, walletId); ok {
+			if err := json.Unmarshal(data, &list); err == nil {
+				return list, nil
+			}
+		}
+	}
+
+	list, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	if data, err := json.Marshal(list); err == nil {
+		store.UserSetHistory(chatId, kind, walletId, data, historyCacheTTL)
+	}
+	return list, nil
+}
+
+type historyPage struct {
+	text   string
+	tokens []string
+	total  int
+}
+
+func renderHistoryPage[T interface{ Token() string }](lang string, list []T, filter func([]T) []T, render func(string, []T, int, int) (string, error), page, pageSize int) (historyPage, error) {
+	filtered := filter(list)
+	p := historyPage{tokens: model.HistoryTokens(list), total: len(filtered)}
+	if len(filtered) == 0 {
+		p.text = i18n.T(lang, "history.no_match")
+		return p, nil
+	}
+	text, err := render(lang, filtered, page, pageSize)
+	p.text = text
+	return p, err
+}
+
+func historySideText(lang, side string) string {
+	switch side {
+	case model.HistorySideBuy:
+		return i18n.T(lang, "history.side_buy")
+	case model.HistorySideSell:
+		return i18n.T(lang, "history.side_sell")
+	}
+	return i18n.T(lang, "history.all")
+}
+
+func historyRangeText(lang string, days int) string {
+	if days == 0 {
+		return i18n.T(lang, "history.all")
+	}
+	return i18n.T(lang, "export.range_days", days)
+}
+
+// action: p(page) / j(jump) / t(token) / s(side) / w(wallet) / d(date range) / r(refresh), empty is new list
+func buildHistory(chatId int64, kind, action, arg string) (string, models.InlineKeyboardMarkup, error) {
+	lang := i18n.UserLang(chatId)
+	var kb models.InlineKeyboardMarkup
+
+	userInfo, err := api.GetUserProfile(chatId)
+	if err != nil {
+		return "", kb, err
+	}
+
+	f := model.HistoryFilter{Page: 1}
+	if action != "" {
+		f = getHistoryFilter(chatId, kind)
+	}
+
+	wallets := historyWallets(userInfo)
+	dw, _, _ := UserDefaultWalletInfo(userInfo)
+	switch action {
+	case "p":
+		f.Page = cast.ToInt(arg)
+	case "s":
+		f.Side, f.Page = model.CycleNext(model.HistorySides, f.Side), 1
+	case "d":
+		f.Days, f.Page = model.CycleNext(model.HistoryRangeDays, f.Days), 1
+	case "w":
+		ids := lo.Map(wallets, func(w model.Wallet, _ int) string { return w.WalletId })
+		cur := lo.Ternary(f.WalletId == "", dw.WalletId, f.WalletId)
+		f.WalletId, f.Token, f.Page = model.CycleNext(ids, cur), "", 1
+	}
+
+	w := historyWallet(userInfo, f.WalletId)
+	reload := action == "" || action == "r"
+	pageSize := historyPageSize[kind]
+
+	// token 需要拿到记录后才知道有哪些
+	var p historyPage
+	switch kind {
+	case HistoryTrade:
+		list, err := cachedHistory(chatId, kind, w.WalletId, reload, func() ([]model.TradeHistoryInner, error) {
+			r, err := api.ListTradeHistory(cast.ToFloat64(w.WalletId), userInfo)
+			return r.Data, err
+		})
+		if err != nil {
+			return "", kb, err
+		}
+		if action == "t" {
+			f.Token, f.Page = model.CycleNext(append([]string{""}, model.HistoryTokens(list)...), f.Token), 1
+		}
+		p, err = renderHistoryPage(lang, list, f.FilterTrades, template.RanderListTradeHistory, max(f.Page, 1), pageSize)
+		if err != nil {
+			return "", kb, err
+		}
+	case HistoryTransfer:
+		list, err := cachedHistory(chatId, kind, w.WalletId, reload, func() ([]model.TransferHistoryInner, error) {
+			r, err := api.ListTransferHistory(cast.ToFloat64(w.WalletId), w.ChainCode, userInfo)
+			return r.Data, err
+		})
+		if err != nil {
+			return "", kb, err
+		}
+		if action == "t" {
+			f.Token, f.Page = model.CycleNext(append([]string{""}, model.HistoryTokens(list)...), f.Token), 1
+		}
+		p, err = renderHistoryPage(lang, list, f.FilterTransfers, template.RanderListTransferHistory, max(f.Page, 1), pageSize)
+		if err != nil {
+			return "", kb, err
+		}
+	case HistoryOrder:
+		list, err := cachedHistory(chatId, kind, w.WalletId, reload, func() ([]model.OpenOrderInner, error) {
+			r, err := api.ListHistoryOrders(cast.ToFloat64(w.WalletId), userInfo)
+			return r.Data, err
+		})
+		if err != nil {
+			return "", kb, err
+		}
+		if action == "t" {
+			f.Token, f.Page = model.CycleNext(append([]string{""}, model.HistoryTokens(list)...), f.Token), 1
+		}
+		p, err = renderHistoryPage(lang, list, f.FilterOrders, template.RanderOpenOrdersHistory, max(f.Page, 1), pageSize)
+		if err != nil {
+			return "", kb, err
+		}
+	}
+
+	totalPage := model.TotalPage(p.total, pageSize)
+	if f.Page < 1 || f.Page > totalPage {
+		// 页码越界时回到合法范围重新渲染
+		f.Page = min(max(f.Page, 1), totalPage)
+		setHistoryFilter(chatId, kind, f)
+		return buildHistory(chatId, kind, "p", cast.ToString(f.Page))
+	}
+	setHistoryFilter(chatId, kind, f)
+
+	text := i18n.T(lang, "history.page_info", f.Page, totalPage, p.total) + "\n" + p.text
+	prefix := "hist::" + kind + "::"
+
+	if action == "j" {
+		var rows [][]models.InlineKeyboardButton
+		var line []models.InlineKeyboardButton
+		start := max(f.Page-historyJumpMax/2, 1)
+		end := min(start+historyJumpMax-1, totalPage)
+		for i := start; i <= end; i++ {
+			label := cast.ToString(i)
+			if i == f.Page {
+				label = "✅" + label
+			}
+			line = append(line, util.NewCallbackDataButton(label, prefix+"p::"+cast.ToString(i)))
+			if len(line) == 5 {
+				rows = append(rows, line)
+				line = nil
+			}
+		}
+		if len(line) > 0 {
+			rows = append(rows, line)
+		}
+		rows = append(rows, []models.InlineKeyboardButton{
+			util.NewCallbackDataButton(i18n.T(lang, "common.back"), prefix+"p::"+cast.ToString(f.Page)),
+		})
+		return text, models.InlineKeyboardMarkup{InlineKeyboard: rows}, nil
+	}
+
+	tokenText := lo.Ternary(f.Token == "", i18n.T(lang, "history.all"), f.Token)
+	if len(tokenText) > 12 {
+		tokenText = tokenText[:4] + ".." + tokenText[len(tokenText)-4:]
+	}
+	filterLine := []models.InlineKeyboardButton{
+		util.NewCallbackDataButton(i18n.T(lang, "history.filter_token", tokenText), prefix+"t"),
+	}
+	if kind != HistoryTransfer {
+		filterLine = append(filterLine, util.NewCallbackDataButton(i18n.T(lang, "history.filter_side", historySideText(lang, f.Side)), prefix+"s"))
+	}
+
+	addr := w.Wallet
+	if len(addr) > 8 {
+		addr = addr[:4] + ".." + addr[len(addr)-4:]
+	}
+	walletLine := []models.InlineKeyboardButton{
+		util.NewCallbackDataButton(i18n.T(lang, "history.filter_wallet", addr), prefix+"w"),
+		util.NewCallbackDataButton(i18n.T(lang, "history.filter_range", historyRangeText(lang, f.Days)), prefix+"d"),
+	}
+
+	var pageLine []models.InlineKeyboardButton
+	if f.Page > 1 {
+		pageLine = append(pageLine, util.NewCallbackDataButton(i18n.T(lang, "common.prev_page"), prefix+"p::"+cast.ToString(f.Page-1)))
+	}
+	pageLine = append(pageLine, util.NewCallbackDataButton(fmt.Sprintf("%d/%d", f.Page, totalPage), prefix+"j"))
+	if f.Page < totalPage {
+		pageLine = append(pageLine, util.NewCallbackDataButton(i18n.T(lang, "common.next_page"), prefix+"p::"+cast.ToString(f.Page+1)))
+	}
+
+	kb.InlineKeyboard = [][]models.InlineKeyboardButton{
+		filterLine,
+		walletLine,
+		pageLine,
+		{
+			util.NewCallbackDataButton(i18n.T(lang, "common.refresh"), prefix+"r"),
+			ExportButton(lang, kind),
+		},
+	}
+	return text, kb, nil
+}
+
+// new history list message, used by history commands
+func SendHistory(ctx context.Context, b *bot.Bot, chatId int64, kind string) {
+	util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "history.querying"))
+
+	text, kb, err := buildHistory(chatId, kind, "", "")
+	if err != nil {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+		return
+	}
+
+	store.BotMessageAdd()
+	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:      chatId,
+		Text:        text,
+		ParseMode:   models.ParseModeHTML,
+		ReplyMarkup: kb,
+		LinkPreviewOptions: &models.LinkPreviewOptions{
+			IsDisabled: bot.True(),
+		},
+	})
+	if err != nil {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+	}
+}
+
+// prefix: hist::<kind>::<action>[::<arg>]
+func CallbackHistory(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatId := util.EffectId(update)
+	msg := update.CallbackQuery.Message.Message
+	params := strings.Split(update.CallbackQuery.Data, "::")
+	if len(params) < 3 || msg == nil {
+		return
+	}
+	kind, action := params[1], params[2]
+	if _, ok := historyPageSize[kind]; !ok {
+		return
+	}
+	arg := ""
+	if len(params) > 3 {
+		arg = params[3]
+	}
+
+	text, kb, err := buildHistory(chatId, kind, action, arg)
+	if err != nil {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+		return
+	}
+
+	_, err = b.EditMessageText(ctx, &bot.EditMessageTextParams{
+		ChatID:      chatId,
+		MessageID:   msg.ID,
+		Text:        text,
+		ParseMode:   models.ParseModeHTML,
+		ReplyMarkup: kb,
+		LinkPreviewOptions: &models.LinkPreviewOptions{
+			IsDisabled: bot.True(),
+		},
+	})
+	if err != nil {
+		log.Debug().Err(err).Msg("edit history")
+	}
+}
@@ -3,12 +3,12 @@ package callback
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
 	"github.com/hellodex/tradingbot/api"
 	"github.com/hellodex/tradingbot/entity"
+	"github.com/hellodex/tradingbot/i18n"
 	"github.com/hellodex/tradingbot/store"
 	"github.com/hellodex/tradingbot/template"
 	"github.com/hellodex/tradingbot/util"
@@ -22,7 +22,7 @@ func InviteHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 			// line 1
 			{
 				// entity.GetCallbackButton(entity.INVITE_DETIAL),
-				entity.GetCallbackButton(entity.Withdrawal),
+				entity.GetCallbackButtonLang(entity.Withdrawal, i18n.UserLang(chatID)),
 			},
 		},
 	}
@@ -30,13 +30,13 @@ func InviteHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 	userInfo, err := api.GetUserProfile(chatID)
 	if err != nil {
 		log.Error().Err(err).Send()
-		util.QuickMessage(ctx, b, chatID, fmt.Sprintf("出错了,%s", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "common.error"))
 		return
 	}
 	data, err := api.GetMyCommissionSummary(userInfo)
 	if err != nil {
 		log.Error().Err(err).Send()
-		util.QuickMessage(ctx, b, chatID, fmt.Sprintf("出错了,%s", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "common.error"))
 		return
 	}
 
@@ -46,17 +46,17 @@ func InviteHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 	err = json.Unmarshal(data, &body)
 	if err != nil {
 		log.Error().Err(err).Send()
-		util.QuickMessage(ctx, b, chatID, fmt.Sprintf("出错了,%s", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "common.error"))
 		return
 	}
 
 	log.Debug().Interface("commission body", body).Send()
 	botUserName := store.GetEnv(store.BOT_USERNAME)
 
-	text, err := template.RanderMyCommissionSummary(userInfo.Data.InviteCode, botUserName, body)
+	text, err := template.RanderMyCommissionSummary(i18n.UserLang(chatID), userInfo.Data.InviteCode, botUserName, body)
 	if err != nil {
 		log.Error().Err(err).Send()
-		util.QuickMessage(ctx, b, chatID, fmt.Sprintf("出错了,%s", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "common.error"))
 		return
 	}
 	store.BotMessageAdd()
@@ -0,0 +1,56 @@
+package callback
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/entity"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/store"
+	"github.com/hellodex/tradingbot/util"
+	"github.com/rs/zerolog/log"
+)
+
+// trigger by entity.LANG
+func LangHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatID := util.EffectId(update)
+	lang := i18n.UserLang(chatID)
+
+	var buttons []models.InlineKeyboardButton
+	for _, l := range i18n.Langs {
+		text := i18n.LangNames[l]
+		if l == lang {
+			text = "✅" + text
+		}
+		buttons = append(buttons, util.NewCallbackDataButton(text, "lang::"+l))
+	}
+
+	store.BotMessageAdd()
+	b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID: chatID,
+		Text:   i18n.T(lang, "lang.select"),
+		ReplyMarkup: models.InlineKeyboardMarkup{
+			InlineKeyboard: [][]models.InlineKeyboardButton{
+				buttons,
+				{entity.GetCallbackButtonLang(entity.SETTING, lang)},
+			},
+		},
+	})
+}
+
+// prefix: lang::<lang>
+func CallbackSelectLang(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatID := util.EffectId(update)
+	lang := strings.TrimPrefix(update.CallbackQuery.Data, "lang::")
+
+	if err := i18n.SetUserLang(chatID, lang); err != nil {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "common.error"))
+		return
+	}
+
+	util.QuickMessage(ctx, b, chatID, i18n.T(lang, "lang.success", i18n.LangNames[lang]))
+	SettingHandler(ctx, b, update)
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/i18n"
 	"github.com/hellodex/tradingbot/logger"
 	"github.com/hellodex/tradingbot/store"
 	"github.com/hellodex/tradingbot/util"
@@ -21,7 +22,7 @@ func botButton(name string, url string) models.InlineKeyboardButton {
 
 func Alert(ctx context.Context, b *bot.Bot, update *models.Update) {
 	chatId := util.EffectId(update)
-	message := "当前bot 繁忙，回复延迟大请使用以下机器人"
+	message := i18n.L(chatId, "bot_busy.text")
 
 	botStatus, err := store.GetBotsStatus()
 	if err != nil {
@@ -33,7 +34,7 @@ func Alert(ctx context.Context, b *bot.Bot, update *models.Update) {
 		store.BotMessageAdd()
 		b.SendMessage(ctx, &bot.SendMessageParams{
 			ChatID: chatId,
-			Text:   message + "，但目前没有可用的替代机器人信息。",
+			Text:   message + i18n.L(chatId, "bot_busy.no_info"),
 		})
 		return
 	}
@@ -46,7 +47,7 @@ func Alert(ctx context.Context, b *bot.Bot, update *models.Update) {
 		url := fmt.Sprintf("https://t.me/%s", k)
 		logger.StdLogger().Info().Str("url", url).Msg("添加按钮")
 
-		status := getSimpleBotStatus(v)
+		status := getSimpleBotStatus(i18n.UserLang(chatId), v)
 		buttonText := fmt.Sprintf("@%s - %s", k, status)
 
 		buttonRow := []models.InlineKeyboardButton{
@@ -59,7 +60,7 @@ func Alert(ctx context.Context, b *bot.Bot, update *models.Update) {
 		store.BotMessageAdd()
 		b.SendMessage(ctx, &bot.SendMessageParams{
 			ChatID: chatId,
-			Text:   message + "，但目前没有可用的替代机器人。",
+			Text:   message + i18n.L(chatId, "bot_busy.no_bot"),
 		})
 		return
 	}
@@ -72,18 +73,18 @@ func Alert(ctx context.Context, b *bot.Bot, update *models.Update) {
 	})
 }
 
-func getSimpleBotStatus(count string) string {
+func getSimpleBotStatus(lang string, count string) string {
 	s, err := strconv.Atoi(count)
 	if err != nil {
-		return "状态未知"
+		return i18n.T(lang, "bot_busy.status.unknown")
 	}
 
 	switch {
 	case s > 55:
-		return "⚠️ 繁忙"
+		return i18n.T(lang, "bot_busy.status.busy")
 	case s > 30:
-		return "⚡ 拥挤"
+		return i18n.T(lang, "bot_busy.status.crowded")
 	default:
-		return "✅ 流畅"
+		return i18n.T(lang, "bot_busy.status.smooth")
 	}
 }
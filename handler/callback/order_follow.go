@@ -2,21 +2,195 @@ package callback
 
 import (
 	"context"
+	"slices"
+	"strings"
 
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/api"
+	"github.com/hellodex/tradingbot/copytrade"
+	"github.com/hellodex/tradingbot/entity"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/rpc"
+	"github.com/hellodex/tradingbot/session"
 	"github.com/hellodex/tradingbot/store"
 	"github.com/hellodex/tradingbot/util"
 	"github.com/rs/zerolog/log"
 )
 
-func CallbackOrderFollow(ctx context.Context, b *bot.Bot, update *models.Update) {
+type copyTradeReply struct {
+	MessageID int
+	// empty id means add new copy trade
+	Id    string
+	Field string
+}
+
+// model field -> input tip i18n key
+var copyTradeFieldKeys = map[string]string{
+	model.CopyFieldAmount:    "copytrade.input_amount",
+	model.CopyFieldRatio:     "copytrade.input_ratio",
+	model.CopyFieldMax:       "copytrade.input_max",
+	model.CopyFieldLiquidity: "copytrade.input_liquidity",
+	model.CopyFieldBlacklist: "copytrade.input_blacklist",
+}
+
+// model err -> i18n key
+var copyTradeErrKeys = map[error]string{
+	model.ErrCopyTradeNumber:    "copytrade.err.number",
+	model.ErrCopyTradeRatio:     "copytrade.err.ratio",
+	model.ErrCopyBlacklistFull:  "copytrade.err.blacklist_full",
+	model.ErrCopyBlacklistToken: "copytrade.err.token",
+	model.ErrCopyTradeExist:     "copytrade.err.exist",
+	model.ErrCopyTradeFull:      "copytrade.err.full",
+	model.ErrCopyTradeAddress:   "copytrade.err.address",
+	model.ErrCopyTradeNoWallet:  "copytrade.err.no_wallet",
+	rpc.ErrUnsupportedChain:     "copytrade.err.chain",
+}
+
+func copyTradeListView(chatId int64) (string, models.InlineKeyboardMarkup) {
+	lang := i18n.UserLang(chatId)
+	trades := copytrade.UserCopyTrades(chatId)
+
+	text := i18n.T(lang, "copytrade.title", len(trades), model.MaxCopyTradeLen)
+	if len(trades) == 0 {
+		text += "\n\n" + i18n.T(lang, "copytrade.empty")
+	}
+
+	var buttons [][]models.InlineKeyboardButton
+	for _, c := range trades {
+		status := "⏸"
+		if c.Enabled {
+			status = "▶️"
+		}
+		text += "\n\n" + status + " <code>" + c.Leader + "</code> (" + api.GetChainNameFallbackCode(c.ChainCode) + ")\n" + copyTradeModeText(lang, c)
+		buttons = append(buttons, []models.InlineKeyboardButton{
+			util.NewCallbackDataButton("⚙️"+util.ShortAddress(c.Leader), "cp::v::"+c.Id),
+		})
+	}
+	buttons = append(buttons, []models.InlineKeyboardButton{
+		entity.GetCallbackButtonLang(entity.ADD_ORDER_FOLLOW, lang),
+		entity.GetCallbackButtonLang(entity.ORDER_FOLLOW_ALL_STOP, lang),
+	})
+	return text, models.InlineKeyboardMarkup{InlineKeyboard: buttons}
+}
+
+func copyTradeModeText(lang string, c model.CopyTrade) string {
+	if c.Mode == model.CopyModeRatio {
+		return i18n.T(lang, "copytrade.mode_ratio", c.Ratio)
+	}
+	return i18n.T(lang, "copytrade.mode_fixed", c.Amount)
+}
+
+func copyTradeDetailView(chatId int64, c model.CopyTrade) (string, models.InlineKeyboardMarkup) {
+	lang := i18n.UserLang(chatId)
+	onOff := func(on bool) string {
+		if on {
+			return i18n.T(lang, "common.on")
+		}
+		return i18n.T(lang, "common.off")
+	}
+
+	status, toggle := i18n.T(lang, "copytrade.paused"), i18n.T(lang, "copytrade.resume")
+	if c.Enabled {
+		status, toggle = i18n.T(lang, "copytrade.running"), i18n.T(lang, "copytrade.pause")
+	}
+	maxText := c.MaxPerTrade
+	if maxText == "0" {
+		maxText = i18n.T(lang, "copytrade.no_limit")
+	}
+	blacklist := i18n.T(lang, "copytrade.none")
+	if len(c.Blacklist) > 0 {
+		short := make([]string, 0, len(c.Blacklist))
+		for _, t := range c.Blacklist {
+			short = append(short, util.ShortAddress(t))
+		}
+		blacklist = strings.Join(short, ", ")
+	}
+	wallet := c.WalletId
+	if userInfo, err := api.GetUserProfile(chatId); err == nil {
+		for _, w := range api.ListUserChainWallets(userInfo, c.ChainCode) {
+			if w.WalletId == c.WalletId {
+				wallet = w.Wallet
+			}
+		}
+	}
+
+	text := i18n.T(lang, "copytrade.detail",
+		c.Leader, api.GetChainNameFallbackCode(c.ChainCode),
+		status, wallet, copyTradeModeText(lang, c), maxText, c.MinLiquidity,
+		c.DelaySec, onOff(c.FollowSell), blacklist,
+	)
+
+	amountButton := util.NewCallbackDataButton(i18n.T(lang, "copytrade.set_amount"), "cp::"+model.CopyFieldAmount+"::"+c.Id)
+	if c.Mode == model.CopyModeRatio {
+		amountButton = util.NewCallbackDataButton(i18n.T(lang, "copytrade.set_ratio"), "cp::"+model.CopyFieldRatio+"::"+c.Id)
+	}
+	kb := models.InlineKeyboardMarkup{InlineKeyboard: [][]models.InlineKeyboardButton{
+		{
+			util.NewCallbackDataButton(toggle, "cp::on::"+c.Id),
+			util.NewCallbackDataButton(i18n.T(lang, "copytrade.switch_mode"), "cp::m::"+c.Id),
+		},
+		{
+			amountButton,
+			util.NewCallbackDataButton(i18n.T(lang, "copytrade.set_max"), "cp::"+model.CopyFieldMax+"::"+c.Id),
+		},
+		{
+			util.NewCallbackDataButton(i18n.T(lang, "copytrade.set_liquidity"), "cp::"+model.CopyFieldLiquidity+"::"+c.Id),
+			util.NewCallbackDataButton(i18n.T(lang, "copytrade.delay", c.DelaySec), "cp::d::"+c.Id),
+		},
+		{
+			util.NewCallbackDataButton(i18n.T(lang, "copytrade.follow_sell", onOff(c.FollowSell)), "cp::s::"+c.Id),
+			util.NewCallbackDataButton(i18n.T(lang, "copytrade.set_blacklist"), "cp::"+model.CopyFieldBlacklist+"::"+c.Id),
+		},
+		{
+			util.NewCallbackDataButton(i18n.T(lang, "copytrade.remove"), "cp::rm::"+c.Id),
+			entity.GetCallbackButtonLang(entity.ORDER_FOLLOW, lang),
+		},
+	}}
+	return text, kb
+}
+
+func sendHTMLView(ctx context.Context, b *bot.Bot, chatId int64, text string, kb models.InlineKeyboardMarkup) {
+	store.BotMessageAdd()
+	_, err := b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:      chatId,
+		Text:        text,
+		ParseMode:   models.ParseModeHTML,
+		ReplyMarkup: kb,
+		LinkPreviewOptions: &models.LinkPreviewOptions{
+			IsDisabled: bot.True(),
+		},
+	})
+	if err != nil {
+		log.Error().Err(err).Send()
+	}
+}
+
+func editHTMLView(ctx context.Context, b *bot.Bot, chatId int64, messageId int, text string, kb models.InlineKeyboardMarkup) {
+	_, err := b.EditMessageText(ctx, &bot.EditMessageTextParams{
+		ChatID:      chatId,
+		MessageID:   messageId,
+		Text:        text,
+		ParseMode:   models.ParseModeHTML,
+		ReplyMarkup: kb,
+		LinkPreviewOptions: &models.LinkPreviewOptions{
+			IsDisabled: bot.True(),
+		},
+	})
+	if err != nil {
+		log.Debug().Err(err).Msg("edit view")
+	}
+}
+
+// trigger by entity.BUY_SELL and entity.OrderTrade, user then sends token address to trade
+func CallbackInputContractAddress(ctx context.Context, b *bot.Bot, update *models.Update) {
 	chatID := util.EffectId(update)
 
 	store.BotMessageAdd()
 	_, err := b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID:    chatID,
-		Text:      "请输入合约地址",
+		Text:      i18n.L(chatID, "follow.input_address"),
 		ParseMode: "HTML",
 		LinkPreviewOptions: &models.LinkPreviewOptions{
 			IsDisabled: bot.True(),
@@ -26,3 +200,224 @@ func CallbackOrderFollow(ctx context.Context, b *bot.Bot, update *models.Update)
 		log.Error().Err(err).Send()
 	}
 }
+
+// trigger by entity.ORDER_FOLLOW
+func CallbackOrderFollow(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatID := util.EffectId(update)
+	text, kb := copyTradeListView(chatID)
+	sendHTMLView(ctx, b, chatID, text, kb)
+}
+
+// trigger by entity.ADD_ORDER_FOLLOW
+func CallbackAddOrderFollow(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatID := util.EffectId(update)
+	if len(copytrade.UserCopyTrades(chatID)) >= model.MaxCopyTradeLen {
+		util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "copytrade.err.full", model.MaxCopyTradeLen))
+		return
+	}
+	askCopyTradeInput(ctx, b, chatID, "", "", "copytrade.input_address")
+}
+
+// trigger by entity.ORDER_FOLLOW_ALL_STOP
+func CallbackOrderFollowAllStop(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatID := util.EffectId(update)
+	for _, c := range copytrade.UserCopyTrades(chatID) {
+		if !c.Enabled {
+			continue
+		}
+		c.Enabled = false
+		if err := copytrade.SaveCopyTrade(chatID, c); err != nil {
+			log.Error().Err(err).Send()
+			util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "common.error"))
+			return
+		}
+	}
+	util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "copytrade.all_paused"))
+	CallbackOrderFollow(ctx, b, update)
+}
+
+func askCopyTradeInput(ctx context.Context, b *bot.Bot, chatId int64, id string, field string, textKey string) {
+	store.BotMessageAdd()
+	message, err := b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:    chatId,
+		Text:      i18n.L(chatId, textKey),
+		ParseMode: models.ParseModeHTML,
+		ReplyMarkup: models.ForceReply{
+			ForceReply: true,
+		},
+	})
+	if err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+	session.GetSessionManager().Set(chatId, session.UserCopyTradeReply, copyTradeReply{
+		MessageID: message.ID,
+		Id:        id,
+		Field:     field,
+	})
+}
+
+// prefix: cp::<action>::<id>
+func CallbackCopyTrade(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatId := util.EffectId(update)
+	msg := update.CallbackQuery.Message.Message
+	params := strings.Split(update.CallbackQuery.Data, "::")
+	if len(params) != 3 || msg == nil {
+		return
+	}
+	action, id := params[1], params[2]
+
+	c, ok := copytrade.UserCopyTrade(chatId, id)
+	if !ok {
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "copytrade.not_found"))
+		return
+	}
+
+	if key, ok := copyTradeFieldKeys[action]; ok {
+		askCopyTradeInput(ctx, b, chatId, id, action, key)
+		return
+	}
+
+	switch action {
+	case "v":
+		text, kb := copyTradeDetailView(chatId, c)
+		editHTMLView(ctx, b, chatId, msg.ID, text, kb)
+		return
+	case "rm":
+		if err := store.UserDeleteCopyTrade(chatId, id); err != nil {
+			log.Error().Err(err).Send()
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+			return
+		}
+		text, kb := copyTradeListView(chatId)
+		editHTMLView(ctx, b, chatId, msg.ID, text, kb)
+		return
+	case "on":
+		c.Enabled = !c.Enabled
+	case "m":
+		c.Mode = model.CycleNext([]string{model.CopyModeFixed, model.CopyModeRatio}, c.Mode)
+	case "d":
+		c.DelaySec = model.CycleNext(model.CopyTradeDelays, c.DelaySec)
+	case "s":
+		c.FollowSell = !c.FollowSell
+	default:
+		return
+	}
+
+	if err := copytrade.SaveCopyTrade(chatId, c); err != nil {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+		return
+	}
+	text, kb := copyTradeDetailView(chatId, c)
+	editHTMLView(ctx, b, chatId, msg.ID, text, kb)
+}
+
+// check if user replay to copy trade input message
+func IsCopyTradeReply(chatId int64, replyToID int) bool {
+	v, ok := session.GetSessionManager().Get(chatId, session.UserCopyTradeReply)
+	if !ok {
+		return false
+	}
+	reply, ok := v.(copyTradeReply)
+	return ok && reply.MessageID == replyToID
+}
+
+func HandleCopyTradeReply(ctx context.Context, b *bot.Bot, update *models.Update) {
+	if update.Message == nil || update.Message.ReplyToMessage == nil {
+		return
+	}
+	chatId := update.Message.Chat.ID
+
+	v, ok := session.GetSessionManager().Get(chatId, session.UserCopyTradeReply)
+	if !ok {
+		return
+	}
+	reply, ok := v.(copyTradeReply)
+	if !ok || reply.MessageID != update.Message.ReplyToMessage.ID {
+		return
+	}
+
+	var c model.CopyTrade
+	var err error
+	if reply.Id == "" {
+		c, err = newCopyTrade(chatId, strings.TrimSpace(update.Message.Text))
+	} else {
+		c, ok = copytrade.UserCopyTrade(chatId, reply.Id)
+		if !ok {
+			session.GetSessionManager().Delete(chatId, session.UserCopyTradeReply)
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "copytrade.not_found"))
+			return
+		}
+		err = c.SetField(reply.Field, update.Message.Text)
+	}
+	if err != nil {
+		text := i18n.L(chatId, "common.error")
+		if key, ok := copyTradeErrKeys[err]; ok {
+			text = i18n.L(chatId, key)
+		}
+		switch err {
+		case model.ErrCopyBlacklistFull:
+			text = i18n.L(chatId, "copytrade.err.blacklist_full", model.MaxCopyBlacklistLen)
+		case model.ErrCopyTradeFull:
+			text = i18n.L(chatId, "copytrade.err.full", model.MaxCopyTradeLen)
+		case model.ErrCopyTradeRatio:
+			text = i18n.L(chatId, "copytrade.err.ratio", model.MaxCopyRatio)
+		}
+		util.QuickMessage(ctx, b, chatId, "❌ "+text)
+		return
+	}
+
+	if err := copytrade.SaveCopyTrade(chatId, c); err != nil {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+		return
+	}
+	session.GetSessionManager().Delete(chatId, session.UserCopyTradeReply)
+	text, kb := copyTradeDetailView(chatId, c)
+	sendHTMLView(ctx, b, chatId, text, kb)
+}
+
+// solana address follows on SOLANA, evm address follows on default chain if it's evm
+func newCopyTrade(chatId int64, address string) (model.CopyTrade, error) {
+	isSolana, err := util.CheckValidAddress(address)
+	if err != nil {
+		return model.CopyTrade{}, model.ErrCopyTradeAddress
+	}
+	userInfo, err := api.GetUserProfile(chatId)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return model.CopyTrade{}, err
+	}
+
+	dw, _, _ := UserDefaultWalletInfo(userInfo)
+	chainCode := "BSC"
+	switch {
+	case isSolana:
+		chainCode = "SOLANA"
+	case dw.ChainCode != "" && dw.ChainCode != "SOLANA":
+		chainCode = dw.ChainCode
+	}
+	if !rpc.SupportWalletSwaps(chainCode) {
+		return model.CopyTrade{}, rpc.ErrUnsupportedChain
+	}
+
+	trades := copytrade.UserCopyTrades(chatId)
+	if len(trades) >= model.MaxCopyTradeLen {
+		return model.CopyTrade{}, model.ErrCopyTradeFull
+	}
+	key := model.AddressKey(chainCode, address)
+	if slices.ContainsFunc(trades, func(c model.CopyTrade) bool { return c.LeaderKey() == key }) {
+		return model.CopyTrade{}, model.ErrCopyTradeExist
+	}
+
+	walletId := dw.WalletId
+	if dw.ChainCode != chainCode {
+		wallets := api.ListUserChainWallets(userInfo, chainCode)
+		if len(wallets) == 0 {
+			return model.CopyTrade{}, model.ErrCopyTradeNoWallet
+		}
+		walletId = wallets[0].WalletId
+	}
+	return model.NewCopyTrade(chainCode, address, walletId), nil
+}
@@ -0,0 +1,276 @@
+package callback
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/entity"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/session"
+	"github.com/hellodex/tradingbot/store"
+	"github.com/hellodex/tradingbot/util"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	pinStepVerify  = "verify"
+	pinStepNew     = "new"
+	pinStepConfirm = "confirm"
+)
+
+type pinReply struct {
+	MessageID int
+	Step      string
+	// 设置 PIN 时第一次输入的结果, 第二次输入用来校验
+	First model.PinRecord
+	Run   func(ctx context.Context)
+}
+
+// ok is false when user has no pin, any other failure is returned as err
+// so that callers refuse the action instead of skipping the pin
+func UserPin(chatId int64) (model.PinRecord, bool, error) {
+	data, has, err := store.UserGetPin(chatId)
+	if err != nil || !has {
+		return model.PinRecord{}, false, err
+	}
+
+	var p model.PinRecord
+	if err := json.Unmarshal(data, &p); err != nil {
+		return model.PinRecord{}, false, err
+	}
+	return p, true, nil
+}
+
+func saveUserPin(chatId int64, p model.PinRecord) error {
+	data, err := p.JsonB()
+	if err != nil {
+		return err
+	}
+	return store.UserSetPin(chatId, data)
+}
+
+func pinLockedText(chatId int64, until time.Time) string {
+	return i18n.L(chatId, "pin.locked", until.Format("15:04:05"))
+}
+
+func askPin(ctx context.Context, b *bot.Bot, chatId int64, textKey string, step string, first model.PinRecord, run func(ctx context.Context)) {
+	store.BotMessageAdd()
+	message, err := b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID: chatId,
+		Text:   i18n.L(chatId, textKey),
+		ReplyMarkup: models.ForceReply{
+			ForceReply:            true,
+			InputFieldPlaceholder: "****",
+		},
+	})
+	if err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+
+	session.GetSessionManager().Set(chatId, session.UserPinReply, pinReply{
+		MessageID: message.ID,
+		Step:      step,
+		First:     first,
+		Run:       run,
+	})
+}
+
+// RequirePin run action after user input the right pin, run directly if user has no pin
+func RequirePin(ctx context.Context, b *bot.Bot, chatId int64, run func(ctx context.Context)) {
+	_, ok, err := UserPin(chatId)
+	if err != nil {
+		log.Error().Err(err).Int64("chatId", chatId).Msg("get pin")
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+		return
+	}
+	if !ok {
+		run(ctx)
+		return
+	}
+	fails, ttl, err := store.UserGetPinFail(chatId)
+	if err != nil {
+		log.Error().Err(err).Int64("chatId", chatId).Msg("get pin fail")
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+		return
+	}
+	if model.PinLocked(fails) {
+		util.QuickMessage(ctx, b, chatId, pinLockedText(chatId, time.Now().Add(ttl)))
+		return
+	}
+	askPin(ctx, b, chatId, "pin.input", pinStepVerify, model.PinRecord{}, run)
+}
+
+// check if user replay to pin input message
+func IsPinReply(chatId int64, replyToID int) bool {
+	v, ok := session.GetSessionManager().Get(chatId, session.UserPinReply)
+	if !ok {
+		return false
+	}
+	reply, ok := v.(pinReply)
+	return ok && reply.MessageID == replyToID
+}
+
+func HandlePinReply(ctx context.Context, b *bot.Bot, update *models.Update) {
+	if update.Message == nil || update.Message.ReplyToMessage == nil {
+		return
+	}
+	chatId := update.Message.Chat.ID
+	sm := session.GetSessionManager()
+
+	v, ok := sm.Get(chatId, session.UserPinReply)
+	if !ok {
+		return
+	}
+	reply, ok := v.(pinReply)
+	if !ok || reply.MessageID != update.Message.ReplyToMessage.ID {
+		return
+	}
+	sm.Delete(chatId, session.UserPinReply)
+
+	// PIN 不能留在聊天记录里
+	for _, id := range []int{update.Message.ID, reply.MessageID} {
+		if _, err := b.DeleteMessage(ctx, &bot.DeleteMessageParams{ChatID: chatId, MessageID: id}); err != nil {
+			log.Debug().Err(err).Msg("delete pin message")
+		}
+	}
+
+	input := strings.TrimSpace(update.Message.Text)
+	now := time.Now()
+
+	switch reply.Step {
+	case pinStepVerify:
+		// 先 INCR 再校验, 并发输入也不会多出重试次数; 计数失败直接拒绝
+		fails, err := store.UserIncrPinFail(chatId, model.PinLockDuration)
+		if err != nil {
+			log.Error().Err(err).Int64("chatId", chatId).Msg("incr pin fail")
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+			return
+		}
+		// 这次输入之前已经锁定
+		if fails > model.PinMaxFail {
+			util.QuickMessage(ctx, b, chatId, pinLockedText(chatId, now.Add(model.PinLockDuration)))
+			return
+		}
+		// 校验期间 PIN 被删除或者读取失败都不执行
+		p, ok, err := UserPin(chatId)
+		if err != nil || !ok {
+			log.Error().Err(err).Int64("chatId", chatId).Msg("get pin")
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+			return
+		}
+		switch err := p.Verify(input); err {
+		case nil:
+			if err := store.UserResetPinFail(chatId); err != nil {
+				log.Error().Err(err).Int64("chatId", chatId).Msg("reset pin fail")
+			}
+			reply.Run(ctx)
+		case model.ErrPinWrong:
+			if model.PinLocked(fails) {
+				util.QuickMessage(ctx, b, chatId, pinLockedText(chatId, now.Add(model.PinLockDuration)))
+				return
+			}
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "pin.wrong", model.PinRemainTries(fails)))
+			askPin(ctx, b, chatId, "pin.input", pinStepVerify, model.PinRecord{}, reply.Run)
+		default:
+			log.Error().Err(err).Send()
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+		}
+	case pinStepNew:
+		first, err := model.NewPinRecord(input)
+		if err != nil {
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "pin.format", model.PinMinLen, model.PinMaxLen))
+			askPin(ctx, b, chatId, "pin.input_new", pinStepNew, model.PinRecord{}, nil)
+			return
+		}
+		askPin(ctx, b, chatId, "pin.input_confirm", pinStepConfirm, first, nil)
+	case pinStepConfirm:
+		first := reply.First
+		if err := first.Verify(input); err != nil {
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "pin.not_match"))
+			askPin(ctx, b, chatId, "pin.input_new", pinStepNew, model.PinRecord{}, nil)
+			return
+		}
+		if err := saveUserPin(chatId, first); err != nil {
+			log.Error().Err(err).Send()
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+			return
+		}
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "pin.set_success"))
+	}
+}
+
+// trigger by entity.SETTING_SECURITY
+func SecurityHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatId := util.EffectId(update)
+	lang := i18n.UserLang(chatId)
+
+	_, hasPin, err := UserPin(chatId)
+	if err != nil {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, chatId, i18n.T(lang, "common.error"))
+		return
+	}
+
+	var line []models.InlineKeyboardButton
+	status := i18n.T(lang, "common.off")
+	if hasPin {
+		status = i18n.T(lang, "common.on")
+		line = []models.InlineKeyboardButton{
+			util.NewCallbackDataButton(i18n.T(lang, "pin.change"), "pin::change"),
+			util.NewCallbackDataButton(i18n.T(lang, "pin.remove"), "pin::remove"),
+		}
+	} else {
+		line = []models.InlineKeyboardButton{
+			util.NewCallbackDataButton(i18n.T(lang, "pin.set"), "pin::set"),
+		}
+	}
+
+	w, err := UserWhitelist(chatId)
+	if err != nil {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, chatId, i18n.T(lang, "common.error"))
+		return
+	}
+	block := store.UserGetSafetyBlock(chatId)
+	store.BotMessageAdd()
+	b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:    chatId,
+		Text:      i18n.T(lang, "pin.info", status, model.PinMaxFail, int(model.PinLockDuration.Minutes())) + "\n\n" + whitelistInfo(lang, w) + "\n\n" + safetyInfo(lang, block),
+		ParseMode: models.ParseModeHTML,
+		ReplyMarkup: models.InlineKeyboardMarkup{InlineKeyboard: [][]models.InlineKeyboardButton{
+			line,
+			whitelistButtons(lang, w),
+			safetyButtons(lang, block),
+			{entity.GetCallbackButtonLang(entity.SETTING, lang)},
+		}},
+	})
+}
+
+// prefix: pin::set / pin::change / pin::remove
+func CallbackPin(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatId := util.EffectId(update)
+	action := strings.TrimPrefix(update.CallbackQuery.Data, "pin::")
+
+	switch action {
+	case "set", "change":
+		// 已经设置过 PIN 时需要先校验旧 PIN
+		RequirePin(ctx, b, chatId, func(ctx context.Context) {
+			askPin(ctx, b, chatId, "pin.input_new", pinStepNew, model.PinRecord{}, nil)
+		})
+	case "remove":
+		RequirePin(ctx, b, chatId, func(ctx context.Context) {
+			if err := store.UserDeletePin(chatId); err != nil {
+				log.Error().Err(err).Send()
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+				return
+			}
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "pin.removed"))
+		})
+	}
+}
@@ -0,0 +1,300 @@
+package callback
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/api"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/store"
+	"github.com/hellodex/tradingbot/template"
+	"github.com/hellodex/tradingbot/util"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cast"
+)
+
+const (
+	// 同时请求接口的数量
+	portfolioWorkers  = 5
+	portfolioCacheKey = "portfolio"
+	portfolioCacheTTL = 2 * time.Minute
+	portfolioAllChain = "all"
+	// 交易过的 token, 之后只翻到上次看到的最新成交
+	portfolioTradedKey = "portfolioTraded"
+	portfolioTradedTTL = 24 * time.Hour
+)
+
+// traded tokens of wallet and newest trade seen
+type tradedCache struct {
+	Latest string
+	Tokens []string
+}
+
+type walletToken struct {
+	wallet model.Wallet
+	token  model.DataTokenInfo
+}
+
+// fetch every wallet of every chain, then position of every token for pnl
+func fetchPortfolio(chatId int64, userInfo model.GetUserResp) model.Portfolio {
+	var wallets []model.Wallet
+	for _, ws := range userInfo.Data.Wallets {
+		wallets = append(wallets, ws...)
+	}
+
+	tokensOfWallet := make([][]model.DataTokenInfo, len(wallets))
+	util.ParallelLimit(portfolioWorkers, len(wallets), func(i int) {
+		tokens, err := api.GetTokensByWalletAddress(wallets[i].Wallet, wallets[i].ChainCode, userInfo)
+		if err != nil {
+			log.Error().Err(err).Str("wallet", wallets[i].Wallet).Msg("portfolio get tokens")
+			return
+		}
+		tokensOfWallet[i] = tokens.Data
+	})
+
+	var pairs []walletToken
+	held := make(map[string]struct{})
+	for i, tokens := range tokensOfWallet {
+		for _, t := range tokens {
+			if cast.ToFloat64(t.Amount) <= 0 {
+				continue
+			}
+			pairs = append(pairs, walletToken{wallet: wallets[i], token: t})
+			held[wallets[i].Wallet+":"+model.AddressKey(wallets[i].ChainCode, t.Address)] = struct{}{}
+		}
+	}
+
+	items := make([]model.PortfolioItem, len(pairs))
+	util.ParallelLimit(portfolioWorkers, len(pairs), func(i int) {
+		w, t := pairs[i].wallet, pairs[i].token
+		// 主币没有仓位信息, 只算价值
+		if util.IsNativeCoion(t.Address) {
+			items[i] = model.NewPortfolioItem(w, t, model.PositionDataInner{})
+			return
+		}
+		pos, err := api.GetPositionByWalletAddress(w.Wallet, t.Address, w.ChainCode, userInfo)
+		if err != nil {
+			log.Error().Err(err).Str("token", t.Address).Msg("portfolio get position")
+		}
+		items[i] = model.NewPortfolioItem(w, t, pos.Data)
+	})
+
+	// 已清仓的 token 不在持仓里, 从成交记录找出来单独算已实现盈亏
+	cached, _ := store.Get(chatId, portfolioTradedKey)
+	old, _ := cached.(map[string]tradedCache)
+	tradedOfWallet := make([]tradedCache, len(wallets))
+	util.ParallelLimit(portfolioWorkers, len(wallets), func(i int) {
+		tradedOfWallet[i] = tradedTokens(wallets[i], userInfo, old[wallets[i].WalletId])
+	})
+	traded := make(map[string]tradedCache, len(wallets))
+	for i, c := range tradedOfWallet {
+		traded[wallets[i].WalletId] = c
+	}
+	store.Set(chatId, portfolioTradedKey, traded, portfolioTradedTTL)
+
+	var closedPairs []walletToken
+	for i, c := range tradedOfWallet {
+		for _, addr := range c.Tokens {
+			if _, ok := held[wallets[i].Wallet+":"+model.AddressKey(wallets[i].ChainCode, addr)]; ok {
+				continue
+			}
+			closedPairs = append(closedPairs, walletToken{wallet: wallets[i], token: model.DataTokenInfo{Address: addr}})
+		}
+	}
+	closedItems := make([]model.PortfolioItem, len(closedPairs))
+	util.ParallelLimit(portfolioWorkers, len(closedPairs), func(i int) {
+		w, t := closedPairs[i].wallet, closedPairs[i].token
+		pos, err := api.GetPositionByWalletAddress(w.Wallet, t.Address, w.ChainCode, userInfo)
+		if err != nil {
+			log.Error().Err(err).Str("token", t.Address).Msg("portfolio get closed position")
+			return
+		}
+		closedItems[i] = model.NewPortfolioItem(w, t, pos.Data)
+	})
+	var closed []model.PortfolioItem
+	for _, item := range closedItems {
+		if !item.Realised.IsZero() {
+			closed = append(closed, item)
+		}
+	}
+
+	p := model.Portfolio{Items: items, Closed: closed}
+	p.Summarize()
+	return p
+}
+
+// base token of every trade in wallet, swaps and filled limit orders, native coin excluded.
+// only trades newer than old.Latest are listed
+func tradedTokens(w model.Wallet, userInfo model.GetUserResp, old tradedCache) tradedCache {
+	trades, _, err := api.ListAllPages(func(pageNum int) ([]model.TradeHistoryInner, error) {
+		r, err := api.ListTradeHistory(cast.ToFloat64(w.WalletId), userInfo, api.Page{Num: pageNum, Size: api.ExportPageSize})
+		return r.Data, err
+	}, func(t model.TradeHistoryInner) string { return t.OrderNo + t.Tx }, func(t model.TradeHistoryInner) bool {
+		return old.Latest != "" && t.OrderNo+t.Tx == old.Latest
+	})
+
+	c := tradedCache{Latest: old.Latest, Tokens: slices.Clone(old.Tokens)}
+	for _, t := range trades {
+		if t.BaseAddress == "" || util.IsNativeCoion(t.BaseAddress) || (t.ChainCode != "" && t.ChainCode != w.ChainCode) {
+			continue
+		}
+		if !slices.Contains(c.Tokens, t.BaseAddress) {
+			c.Tokens = append(c.Tokens, t.BaseAddress)
+		}
+	}
+	if err != nil {
+		// 不更新 Latest, 下次重新翻这些成交
+		log.Error().Err(err).Str("wallet", w.Wallet).Msg("portfolio list trade history")
+		return c
+	}
+	if len(trades) > 0 {
+		c.Latest = trades[0].OrderNo + trades[0].Tx
+	}
+	return c
+}
+
+func userPortfolio(chatId int64, reload bool) (model.Portfolio, error) {
+	if !reload {
+		if v, ok := store.Get(chatId, portfolioCacheKey); ok {
+			if p, ok := v.(model.Portfolio); ok {
+				return p, nil
+			}
+		}
+	}
+
+	userInfo, err := api.GetUserProfile(chatId)
+	if err != nil {
+		return model.Portfolio{}, err
+	}
+	p := fetchPortfolio(chatId, userInfo)
+	store.Set(chatId, portfolioCacheKey, p, portfolioCacheTTL)
+	return p, nil
+}
+
+func buildPortfolio(chatId int64, chain string, page int, reload bool) (string, models.InlineKeyboardMarkup, error) {
+	lang := i18n.UserLang(chatId)
+	all, err := userPortfolio(chatId, reload)
+	if err != nil {
+		return "", models.InlineKeyboardMarkup{}, err
+	}
+
+	chainCode := chain
+	if chain == portfolioAllChain {
+		chainCode = ""
+	}
+	p := all.Filter(chainCode)
+	_, totalPage := p.Page(page)
+	page = min(max(page, 1), totalPage)
+
+	text, err := template.RanderPortfolio(lang, p, chainCode, page)
+	if err != nil {
+		return "", models.InlineKeyboardMarkup{}, err
+	}
+
+	var buttons [][]models.InlineKeyboardButton
+
+	// chain filter
+	allText := i18n.T(lang, "portfolio.all_chain")
+	if chainCode == "" {
+		allText = "✅" + allText
+	}
+	chainLine := []models.InlineKeyboardButton{
+		util.NewCallbackDataButton(allText, "pf::"+portfolioAllChain+"::1"),
+	}
+	for _, c := range all.Chains {
+		text := api.GetChainNameFallbackCode(c)
+		if c == chainCode {
+			text = "✅" + text
+		}
+		chainLine = append(chainLine, util.NewCallbackDataButton(text, "pf::"+c+"::1"))
+	}
+	for len(chainLine) > 0 {
+		n := min(len(chainLine), 3)
+		buttons = append(buttons, chainLine[:n])
+		chainLine = chainLine[n:]
+	}
+
+	// pagination
+	var pageLine []models.InlineKeyboardButton
+	if page > 1 {
+		pageLine = append(pageLine, util.NewCallbackDataButton(i18n.T(lang, "common.prev_page"), "pf::"+chain+"::"+cast.ToString(page-1)))
+	}
+	if page < totalPage {
+		pageLine = append(pageLine, util.NewCallbackDataButton(i18n.T(lang, "common.next_page"), "pf::"+chain+"::"+cast.ToString(page+1)))
+	}
+	if len(pageLine) > 0 {
+		buttons = append(buttons, pageLine)
+	}
+
+	buttons = append(buttons, []models.InlineKeyboardButton{
+		util.NewCallbackDataButton(i18n.T(lang, "common.refresh"), "pf::r::"+chain),
+		util.BackToMainMenu(lang),
+	})
+
+	return text, models.InlineKeyboardMarkup{InlineKeyboard: buttons}, nil
+}
+
+// command /portfolio
+func PortfolioHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatId := util.EffectId(update)
+	util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "portfolio.loading"))
+
+	text, kb, err := buildPortfolio(chatId, portfolioAllChain, 1, true)
+	if err != nil {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+		return
+	}
+
+	store.BotMessageAdd()
+	b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:      chatId,
+		Text:        text,
+		ParseMode:   models.ParseModeHTML,
+		ReplyMarkup: kb,
+		LinkPreviewOptions: &models.LinkPreviewOptions{
+			IsDisabled: bot.True(),
+		},
+	})
+}
+
+// prefix: pf::<chainCode|all>::<page> / pf::r::<chainCode|all>
+func CallbackPortfolio(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatId := util.EffectId(update)
+	msg := update.CallbackQuery.Message.Message
+	params := strings.Split(update.CallbackQuery.Data, "::")
+	if len(params) != 3 || msg == nil {
+		return
+	}
+
+	chain, page, reload := params[1], cast.ToInt(params[2]), false
+	if params[1] == "r" {
+		chain, page, reload = params[2], 1, true
+	}
+
+	text, kb, err := buildPortfolio(chatId, chain, page, reload)
+	if err != nil {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+		return
+	}
+
+	_, err = b.EditMessageText(ctx, &bot.EditMessageTextParams{
+		ChatID:      chatId,
+		MessageID:   msg.ID,
+		Text:        text,
+		ParseMode:   models.ParseModeHTML,
+		ReplyMarkup: kb,
+		LinkPreviewOptions: &models.LinkPreviewOptions{
+			IsDisabled: bot.True(),
+		},
+	})
+	if err != nil {
+		log.Debug().Err(err).Msg("edit portfolio")
+	}
+}
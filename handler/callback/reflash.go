@@ -6,6 +6,7 @@ import (
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
 	"github.com/hellodex/tradingbot/api"
+	"github.com/hellodex/tradingbot/i18n"
 	"github.com/hellodex/tradingbot/model"
 	"github.com/hellodex/tradingbot/session"
 	"github.com/hellodex/tradingbot/template"
@@ -74,7 +75,7 @@ func ReflashTokenInfo(ctx context.Context, b *bot.Bot, update *models.Update) {
 		return nil
 	}()
 
-	text, err := template.RanderTokenInfo(tokenInfo)
+	text, err := template.RanderTokenInfo(i18n.UserLang(chatId), tokenInfo)
 	if err != nil {
 		log.Error().Err(err).Send()
 		return
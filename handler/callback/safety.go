@@ -0,0 +1,47 @@
+package callback
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/store"
+	"github.com/hellodex/tradingbot/util"
+	"github.com/rs/zerolog/log"
+)
+
+func safetyBlockText(lang string, score int) string {
+	if score <= 0 {
+		return i18n.T(lang, "common.off")
+	}
+	return i18n.T(lang, "safety.block_score", score)
+}
+
+func safetyInfo(lang string, score int) string {
+	return i18n.T(lang, "safety.block_info", safetyBlockText(lang, score))
+}
+
+func safetyButtons(lang string, score int) []models.InlineKeyboardButton {
+	return []models.InlineKeyboardButton{
+		util.NewCallbackDataButton(i18n.T(lang, "safety.block_button", safetyBlockText(lang, score)), "sf::block"),
+	}
+}
+
+// prefix: sf::block
+func CallbackSafety(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatId := util.EffectId(update)
+	if strings.TrimPrefix(update.CallbackQuery.Data, "sf::") != "block" {
+		return
+	}
+
+	score := model.CycleNext(model.SafetyBlockScores, store.UserGetSafetyBlock(chatId))
+	if err := store.UserSetSafetyBlock(chatId, score); err != nil {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+		return
+	}
+	SecurityHandler(ctx, b, update)
+}
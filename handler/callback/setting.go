@@ -8,6 +8,7 @@ import (
 	"github.com/go-telegram/bot/models"
 	"github.com/hellodex/tradingbot/api"
 	"github.com/hellodex/tradingbot/entity"
+	"github.com/hellodex/tradingbot/i18n"
 	"github.com/hellodex/tradingbot/store"
 	"github.com/hellodex/tradingbot/util"
 	"github.com/rs/zerolog/log"
@@ -18,35 +19,39 @@ func SettingHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 	isDisabled := true
 
 	chatID := util.EffectId(update)
+	lang := i18n.UserLang(chatID)
 	kb := &models.InlineKeyboardMarkup{
 		InlineKeyboard: [][]models.InlineKeyboardButton{
 			// line 1
 			{
-				entity.GetCallbackButton(entity.SETTING_SLIPPY),
+				entity.GetCallbackButtonLang(entity.SETTING_SLIPPY, lang),
+				entity.GetCallbackButtonLang(entity.SETTING_TRADE_PRESET, lang),
 			},
 
 			// line2
-			{},
+			{
+				entity.GetCallbackButtonLang(entity.SETTING_ADDRESS_BOOK, lang),
+				entity.GetCallbackButtonLang(entity.LANG, lang),
+			},
+
+			// line3
+			{
+				entity.GetCallbackButtonLang(entity.SETTING_SECURITY, lang),
+			},
 		},
 	}
 
 	userInfo, err := api.GetUserProfile(chatID)
 	if err != nil {
 		log.Error().Err(err).Send()
-		util.QuickMessage(ctx, b, chatID, "出问题了，请联系管理员")
+		util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "common.error"))
 		return
 	}
-	textTempl := `
-请选择你要设置的内容：
-当前设置：
-滑点：%s%%
-<code>UUID: %s</code>
-	`
 
 	store.BotMessageAdd()
 	b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID:      chatID,
-		Text:        fmt.Sprintf(textTempl, userInfo.FromPercentage(userInfo.Data.Slippage), userInfo.Data.UUID),
+		Text:        i18n.T(lang, "setting.info", userInfo.FromPercentage(userInfo.Data.Slippage), i18n.LangNames[lang], userInfo.Data.UUID),
 		ParseMode:   "HTML",
 		ReplyMarkup: kb,
 		LinkPreviewOptions: &models.LinkPreviewOptions{
@@ -71,7 +76,7 @@ func SlippyHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 	store.BotMessageAdd()
 	message, err := b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID:      chatID,
-		Text:        "请输入数字设置滑点,例如 20 就是设置为20%",
+		Text:        i18n.L(chatID, "setting.slippage_input"),
 		ReplyMarkup: reply,
 	})
 	if err != nil {
@@ -101,7 +106,7 @@ func HandleSlippyReply(ctx context.Context, b *bot.Bot, update *models.Update) {
 		store.BotMessageAdd()
 		b.SendMessage(ctx, &bot.SendMessageParams{
 			ChatID: chatID,
-			Text:   "❌ 请输入有效的数字",
+			Text:   i18n.L(chatID, "setting.invalid_number"),
 		})
 		return
 	}
@@ -111,7 +116,7 @@ func HandleSlippyReply(ctx context.Context, b *bot.Bot, update *models.Update) {
 		store.BotMessageAdd()
 		b.SendMessage(ctx, &bot.SendMessageParams{
 			ChatID: chatID,
-			Text:   "❌ 滑点值必须在 0-100 之间",
+			Text:   i18n.L(chatID, "setting.slippage_range"),
 		})
 		return
 	}
@@ -122,7 +127,7 @@ func HandleSlippyReply(ctx context.Context, b *bot.Bot, update *models.Update) {
 	userInfo, err := api.GetUserProfile(chatID)
 	if err != nil {
 		log.Error().Err(err).Send()
-		util.QuickMessage(ctx, b, chatID, "出问题了，请联系管理员")
+		util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "common.error"))
 		return
 	}
 	userInfo.Data.Slippage = userInfo.ToPercentage(slippageNum)
@@ -130,7 +135,7 @@ func HandleSlippyReply(ctx context.Context, b *bot.Bot, update *models.Update) {
 	err = api.UpdateUserProfile(chatID, userInfo)
 	if err != nil {
 		log.Error().Err(err).Send()
-		util.QuickMessage(ctx, b, chatID, err.Error())
+		util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "common.error"))
 		return
 	}
 
@@ -138,6 +143,6 @@ func HandleSlippyReply(ctx context.Context, b *bot.Bot, update *models.Update) {
 	store.BotMessageAdd()
 	b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID: chatID,
-		Text:   fmt.Sprintf("✅ 滑点设置成功：%.2f%%", slippageNum),
+		Text:   i18n.L(chatID, "setting.slippage_success", slippageNum),
 	})
 }
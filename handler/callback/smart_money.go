@@ -0,0 +1,356 @@
+package callback
+
+import (
+	"context"
+	"encoding/json"
+	"html"
+	"slices"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/api"
+	"github.com/hellodex/tradingbot/entity"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/rpc"
+	"github.com/hellodex/tradingbot/session"
+	"github.com/hellodex/tradingbot/smartmoney"
+	"github.com/hellodex/tradingbot/store"
+	"github.com/hellodex/tradingbot/util"
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cast"
+)
+
+type smartWalletReply struct {
+	MessageID int
+	// empty id means add new wallet, otherwise set label
+	Id string
+}
+
+// model err -> i18n key
+var smartWalletErrKeys = map[error]string{
+	model.ErrSmartWalletExist:   "smartmoney.err.exist",
+	model.ErrSmartWalletAddress: "copytrade.err.address",
+	model.ErrSmartWalletLabel:   "smartmoney.err.label",
+	rpc.ErrUnsupportedChain:     "smartmoney.err.chain",
+}
+
+func smartWalletErrText(chatId int64, err error) string {
+	if err == model.ErrSmartWalletFull {
+		return i18n.L(chatId, "smartmoney.err.full", model.MaxSmartWalletLen)
+	}
+	if key, ok := smartWalletErrKeys[err]; ok {
+		return i18n.L(chatId, key)
+	}
+	log.Error().Err(err).Send()
+	return i18n.L(chatId, "common.error")
+}
+
+func smartWalletLabel(w model.SmartWallet) string {
+	if w.Label == "" {
+		return util.ShortAddress(w.Address)
+	}
+	return w.Label
+}
+
+func smartWalletSideText(lang string, w model.SmartWallet) string {
+	switch {
+	case w.Buy && w.Sell:
+		return i18n.T(lang, "smartmoney.side_both")
+	case w.Buy:
+		return i18n.T(lang, "smartmoney.side_buy")
+	case w.Sell:
+		return i18n.T(lang, "smartmoney.side_sell")
+	}
+	return i18n.T(lang, "smartmoney.side_none")
+}
+
+func smartMoneyListView(chatId int64) (string, models.InlineKeyboardMarkup) {
+	lang := i18n.UserLang(chatId)
+	wallets := smartmoney.UserWallets(chatId)
+
+	text := i18n.T(lang, "smartmoney.title", len(wallets), model.MaxSmartWalletLen)
+	if len(wallets) == 0 {
+		text += "\n\n" + i18n.T(lang, "smartmoney.empty")
+	}
+
+	var buttons [][]models.InlineKeyboardButton
+	for _, w := range wallets {
+		status := "⏸"
+		if w.Enabled {
+			status = "▶️"
+		}
+		text += "\n\n" + status + " <b>" + html.EscapeString(smartWalletLabel(w)) + "</b> (" + api.GetChainNameFallbackCode(w.ChainCode) + ")\n<code>" + w.Address + "</code>\n" +
+			i18n.T(lang, "smartmoney.rule", smartWalletSideText(lang, w), w.MinUsd)
+		buttons = append(buttons, []models.InlineKeyboardButton{
+			util.NewCallbackDataButton("⚙️"+smartWalletLabel(w), "sm::v::"+w.Id),
+		})
+	}
+	// 推荐钱包中还没有关注的
+	for i, c := range smartmoney.CuratedWallets() {
+		key := c.WalletKey()
+		if slices.ContainsFunc(wallets, func(w model.SmartWallet) bool { return w.WalletKey() == key }) {
+			continue
+		}
+		buttons = append(buttons, []models.InlineKeyboardButton{
+			util.NewCallbackDataButton(i18n.T(lang, "smartmoney.curated", smartWalletLabel(c), api.GetChainNameFallbackCode(c.ChainCode)), "sm::c::"+cast.ToString(i)),
+		})
+	}
+	buttons = append(buttons, []models.InlineKeyboardButton{
+		entity.GetCallbackButtonLang(entity.ADD_SMART_WALLET, lang),
+	})
+	return text, models.InlineKeyboardMarkup{InlineKeyboard: buttons}
+}
+
+func smartWalletDetailView(chatId int64, w model.SmartWallet) (string, models.InlineKeyboardMarkup) {
+	lang := i18n.UserLang(chatId)
+	onOff := func(on bool) string {
+		if on {
+			return i18n.T(lang, "common.on")
+		}
+		return i18n.T(lang, "common.off")
+	}
+
+	status, toggle := i18n.T(lang, "copytrade.paused"), i18n.T(lang, "copytrade.resume")
+	if w.Enabled {
+		status, toggle = i18n.T(lang, "copytrade.running"), i18n.T(lang, "copytrade.pause")
+	}
+	text := i18n.T(lang, "smartmoney.detail",
+		html.EscapeString(smartWalletLabel(w)), w.Address, api.GetChainNameFallbackCode(w.ChainCode),
+		status, onOff(w.Buy), onOff(w.Sell), w.MinUsd,
+	)
+
+	kb := models.InlineKeyboardMarkup{InlineKeyboard: [][]models.InlineKeyboardButton{
+		{
+			util.NewCallbackDataButton(toggle, "sm::on::"+w.Id),
+			util.NewCallbackDataButton(i18n.T(lang, "smartmoney.set_label"), "sm::lb::"+w.Id),
+		},
+		{
+			util.NewCallbackDataButton(i18n.T(lang, "smartmoney.alert_buy", onOff(w.Buy)), "sm::b::"+w.Id),
+			util.NewCallbackDataButton(i18n.T(lang, "smartmoney.alert_sell", onOff(w.Sell)), "sm::s::"+w.Id),
+		},
+		{
+			util.NewCallbackDataButton(i18n.T(lang, "smartmoney.min_usd", w.MinUsd), "sm::u::"+w.Id),
+		},
+		{
+			util.NewCallbackDataButton(i18n.T(lang, "smartmoney.remove"), "sm::rm::"+w.Id),
+			entity.GetCallbackButtonLang(entity.SMART_MONEY, lang),
+		},
+	}}
+	return text, kb
+}
+
+// trigger by entity.SMART_MONEY
+func CallbackSmartMoney(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatID := util.EffectId(update)
+	text, kb := smartMoneyListView(chatID)
+	sendHTMLView(ctx, b, chatID, text, kb)
+}
+
+// trigger by entity.ADD_SMART_WALLET
+func CallbackAddSmartWallet(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatID := util.EffectId(update)
+	if len(smartmoney.UserWallets(chatID)) >= model.MaxSmartWalletLen {
+		util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "smartmoney.err.full", model.MaxSmartWalletLen))
+		return
+	}
+	askSmartWalletInput(ctx, b, chatID, "", "smartmoney.input_address")
+}
+
+func askSmartWalletInput(ctx context.Context, b *bot.Bot, chatId int64, id string, textKey string) {
+	store.BotMessageAdd()
+	message, err := b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:    chatId,
+		Text:      i18n.L(chatId, textKey),
+		ParseMode: models.ParseModeHTML,
+		ReplyMarkup: models.ForceReply{
+			ForceReply: true,
+		},
+	})
+	if err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+	session.GetSessionManager().Set(chatId, session.UserSmartWalletReply, smartWalletReply{
+		MessageID: message.ID,
+		Id:        id,
+	})
+}
+
+// prefix: sm::<action>::<id>, sm::c::<index of curated wallet>
+func CallbackSmartWallet(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatId := util.EffectId(update)
+	msg := update.CallbackQuery.Message.Message
+	params := strings.Split(update.CallbackQuery.Data, "::")
+	if len(params) != 3 || msg == nil {
+		return
+	}
+	action, id := params[1], params[2]
+
+	if action == "c" {
+		curated := smartmoney.CuratedWallets()
+		i := cast.ToInt(id)
+		if i < 0 || i >= len(curated) {
+			return
+		}
+		w := curated[i]
+		if err := smartmoney.AddWallet(chatId, w); err != nil {
+			util.QuickMessage(ctx, b, chatId, "❌ "+smartWalletErrText(chatId, err))
+			return
+		}
+		text, kb := smartWalletDetailView(chatId, w)
+		editHTMLView(ctx, b, chatId, msg.ID, text, kb)
+		return
+	}
+
+	w, ok := smartmoney.UserWallet(chatId, id)
+	if !ok {
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "smartmoney.not_found"))
+		return
+	}
+
+	switch action {
+	case "v":
+		text, kb := smartWalletDetailView(chatId, w)
+		editHTMLView(ctx, b, chatId, msg.ID, text, kb)
+		return
+	case "lb":
+		askSmartWalletInput(ctx, b, chatId, id, "smartmoney.input_label")
+		return
+	case "rm":
+		if err := store.UserDeleteSmartWallet(chatId, id); err != nil {
+			log.Error().Err(err).Send()
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+			return
+		}
+		text, kb := smartMoneyListView(chatId)
+		editHTMLView(ctx, b, chatId, msg.ID, text, kb)
+		return
+	case "on":
+		w.Enabled = !w.Enabled
+	case "b":
+		w.Buy = !w.Buy
+	case "s":
+		w.Sell = !w.Sell
+	case "u":
+		w.MinUsd = model.CycleNext(model.SmartMoneyMinUsds, w.MinUsd)
+	default:
+		return
+	}
+
+	if err := smartmoney.SaveWallet(chatId, w); err != nil {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+		return
+	}
+	text, kb := smartWalletDetailView(chatId, w)
+	editHTMLView(ctx, b, chatId, msg.ID, text, kb)
+}
+
+// prefix: smq::<amount>, quick buy token of smart money alert
+func CallbackSmartMoneyBuy(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatId := util.EffectId(update)
+	msg := update.CallbackQuery.Message.Message
+	if msg == nil {
+		return
+	}
+	amount, err := decimal.NewFromString(strings.TrimPrefix(update.CallbackQuery.Data, "smq::"))
+	if err != nil || !amount.IsPositive() {
+		return
+	}
+
+	data, err := store.GetMessageByMsgId(chatId, cast.ToString(msg.ID))
+	if err != nil {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+		return
+	}
+	var req model.PusherHandlerReqData
+	if err := json.Unmarshal([]byte(data), &req); err != nil || req.BaseAddress == "" {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+		return
+	}
+	smartmoney.Buy(ctx, b, chatId, req.ChainCode, req.BaseAddress, amount)
+}
+
+// check if user replay to smart wallet input message
+func IsSmartWalletReply(chatId int64, replyToID int) bool {
+	v, ok := session.GetSessionManager().Get(chatId, session.UserSmartWalletReply)
+	if !ok {
+		return false
+	}
+	reply, ok := v.(smartWalletReply)
+	return ok && reply.MessageID == replyToID
+}
+
+func HandleSmartWalletReply(ctx context.Context, b *bot.Bot, update *models.Update) {
+	if update.Message == nil || update.Message.ReplyToMessage == nil {
+		return
+	}
+	chatId := update.Message.Chat.ID
+
+	v, ok := session.GetSessionManager().Get(chatId, session.UserSmartWalletReply)
+	if !ok {
+		return
+	}
+	reply, ok := v.(smartWalletReply)
+	if !ok || reply.MessageID != update.Message.ReplyToMessage.ID {
+		return
+	}
+
+	var w model.SmartWallet
+	var err error
+	if reply.Id == "" {
+		w, err = newSmartWallet(chatId, update.Message.Text)
+		if err == nil {
+			err = smartmoney.AddWallet(chatId, w)
+		}
+	} else {
+		w, ok = smartmoney.UserWallet(chatId, reply.Id)
+		if !ok {
+			session.GetSessionManager().Delete(chatId, session.UserSmartWalletReply)
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "smartmoney.not_found"))
+			return
+		}
+		err = w.SetLabel(strings.TrimSpace(update.Message.Text))
+		if err == nil {
+			err = smartmoney.SaveWallet(chatId, w)
+		}
+	}
+	if err != nil {
+		util.QuickMessage(ctx, b, chatId, "❌ "+smartWalletErrText(chatId, err))
+		return
+	}
+
+	session.GetSessionManager().Delete(chatId, session.UserSmartWalletReply)
+	text, kb := smartWalletDetailView(chatId, w)
+	sendHTMLView(ctx, b, chatId, text, kb)
+}
+
+// input: address [label], chain is detected same as copy trade
+func newSmartWallet(chatId int64, text string) (model.SmartWallet, error) {
+	address, label, _ := strings.Cut(strings.TrimSpace(text), " ")
+	isSolana, err := util.CheckValidAddress(address)
+	if err != nil {
+		return model.SmartWallet{}, model.ErrSmartWalletAddress
+	}
+	chainCode := "BSC"
+	if isSolana {
+		chainCode = "SOLANA"
+	} else if userInfo, err := api.GetUserProfile(chatId); err == nil {
+		if dw, _, _ := UserDefaultWalletInfo(userInfo); dw.ChainCode != "" && dw.ChainCode != "SOLANA" {
+			chainCode = dw.ChainCode
+		}
+	}
+
+	w := model.NewSmartWallet(chainCode, address, "", false)
+	if label = strings.TrimSpace(label); label != "" {
+		if err := w.SetLabel(label); err != nil {
+			return model.SmartWallet{}, err
+		}
+	}
+	return w, nil
+}
@@ -0,0 +1,325 @@
+package callback
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/api"
+	"github.com/hellodex/tradingbot/entity"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/rpc"
+	"github.com/hellodex/tradingbot/session"
+	"github.com/hellodex/tradingbot/sniper"
+	"github.com/hellodex/tradingbot/store"
+	"github.com/hellodex/tradingbot/util"
+	"github.com/rs/zerolog/log"
+)
+
+type snipeReply struct {
+	MessageID int
+	// empty id means add new snipe
+	Id    string
+	Field string
+}
+
+// model field -> input tip i18n key
+var snipeFieldKeys = map[string]string{
+	model.SnipeFieldAmount:    "sniper.input_amount",
+	model.SnipeFieldSlippage:  "sniper.input_slippage",
+	model.SnipeFieldFee:       "sniper.input_fee",
+	model.SnipeFieldLiquidity: "sniper.input_liquidity",
+}
+
+// model err -> i18n key
+var snipeErrKeys = map[error]string{
+	model.ErrSnipeFull:      "sniper.err.full",
+	model.ErrSnipeExist:     "sniper.err.exist",
+	model.ErrSnipeToken:     "sniper.err.token",
+	model.ErrSnipeNoWallet:  "sniper.err.no_wallet",
+	model.ErrSnipeNumber:    "sniper.err.number",
+	model.ErrSnipeSlippage:  "sniper.err.slippage",
+	rpc.ErrUnsupportedChain: "sniper.err.chain",
+}
+
+func snipeSlippageText(lang string, s model.Snipe) string {
+	if s.Slippage == "0" {
+		return i18n.T(lang, "sniper.default")
+	}
+	return s.Slippage + "%"
+}
+
+func snipeFeeText(lang string, s model.Snipe) string {
+	if s.PriorityFee == "0" {
+		return i18n.T(lang, "sniper.default")
+	}
+	return s.PriorityFee
+}
+
+func snipeListView(chatId int64) (string, models.InlineKeyboardMarkup) {
+	lang := i18n.UserLang(chatId)
+	snipes := sniper.UserSnipes(chatId)
+
+	text := i18n.T(lang, "sniper.title", len(snipes), model.MaxSnipeLen)
+	if len(snipes) == 0 {
+		text += "\n\n" + i18n.T(lang, "sniper.empty")
+	}
+
+	var buttons [][]models.InlineKeyboardButton
+	for _, s := range snipes {
+		text += "\n\n🎯 <code>" + s.Token + "</code> (" + api.GetChainNameFallbackCode(s.ChainCode) + ")\n" + i18n.T(lang, "sniper.amount", s.Amount)
+		buttons = append(buttons, []models.InlineKeyboardButton{
+			util.NewCallbackDataButton("⚙️"+util.ShortAddress(s.Token), "sn::v::"+s.Id),
+		})
+	}
+	buttons = append(buttons, []models.InlineKeyboardButton{
+		entity.GetCallbackButtonLang(entity.ADD_SNIPE, lang),
+	})
+	return text, models.InlineKeyboardMarkup{InlineKeyboard: buttons}
+}
+
+func snipeDetailView(chatId int64, s model.Snipe) (string, models.InlineKeyboardMarkup) {
+	lang := i18n.UserLang(chatId)
+
+	wallet := s.WalletId
+	if userInfo, err := api.GetUserProfile(chatId); err == nil {
+		for _, w := range api.ListUserChainWallets(userInfo, s.ChainCode) {
+			if w.WalletId == s.WalletId {
+				wallet = w.Wallet
+			}
+		}
+	}
+
+	text := i18n.T(lang, "sniper.detail",
+		s.Token, api.GetChainNameFallbackCode(s.ChainCode),
+		wallet, s.Amount, snipeSlippageText(lang, s), snipeFeeText(lang, s), s.MinLiquidity,
+	)
+
+	buttons := [][]models.InlineKeyboardButton{
+		{
+			util.NewCallbackDataButton(i18n.T(lang, "sniper.set_amount"), "sn::"+model.SnipeFieldAmount+"::"+s.Id),
+			util.NewCallbackDataButton(i18n.T(lang, "sniper.set_slippage"), "sn::"+model.SnipeFieldSlippage+"::"+s.Id),
+		},
+		{
+			util.NewCallbackDataButton(i18n.T(lang, "sniper.set_fee"), "sn::"+model.SnipeFieldFee+"::"+s.Id),
+			util.NewCallbackDataButton(i18n.T(lang, "sniper.set_liquidity"), "sn::"+model.SnipeFieldLiquidity+"::"+s.Id),
+		},
+	}
+	// 只有 pump.fun 代币可以选择等待迁移
+	if s.IsPumpToken() {
+		text += "\n" + i18n.T(lang, "sniper.migration_tip")
+		onOff := i18n.T(lang, "common.off")
+		if s.Migration {
+			onOff = i18n.T(lang, "common.on")
+		}
+		buttons = append(buttons, []models.InlineKeyboardButton{
+			util.NewCallbackDataButton(i18n.T(lang, "sniper.migration", onOff), "sn::mg::"+s.Id),
+		})
+	}
+	buttons = append(buttons, []models.InlineKeyboardButton{
+		util.NewCallbackDataButton(i18n.T(lang, "sniper.remove"), "sn::rm::"+s.Id),
+		entity.GetCallbackButtonLang(entity.SNIPER, lang),
+	})
+	return text, models.InlineKeyboardMarkup{InlineKeyboard: buttons}
+}
+
+// trigger by entity.SNIPER
+func CallbackSniper(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatID := util.EffectId(update)
+	text, kb := snipeListView(chatID)
+	sendHTMLView(ctx, b, chatID, text, kb)
+}
+
+// trigger by entity.ADD_SNIPE
+func CallbackAddSnipe(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatID := util.EffectId(update)
+	if len(sniper.UserSnipes(chatID)) >= model.MaxSnipeLen {
+		util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "sniper.err.full", model.MaxSnipeLen))
+		return
+	}
+	askSnipeInput(ctx, b, chatID, "", "", "sniper.input_token")
+}
+
+func askSnipeInput(ctx context.Context, b *bot.Bot, chatId int64, id string, field string, textKey string) {
+	store.BotMessageAdd()
+	message, err := b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:    chatId,
+		Text:      i18n.L(chatId, textKey),
+		ParseMode: models.ParseModeHTML,
+		ReplyMarkup: models.ForceReply{
+			ForceReply: true,
+		},
+	})
+	if err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+	session.GetSessionManager().Set(chatId, session.UserSnipeReply, snipeReply{
+		MessageID: message.ID,
+		Id:        id,
+		Field:     field,
+	})
+}
+
+// prefix: sn::<action>::<id>
+func CallbackSnipe(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatId := util.EffectId(update)
+	msg := update.CallbackQuery.Message.Message
+	params := strings.Split(update.CallbackQuery.Data, "::")
+	if len(params) != 3 || msg == nil {
+		return
+	}
+	action, id := params[1], params[2]
+
+	s, ok := sniper.UserSnipe(chatId, id)
+	if !ok {
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "sniper.not_found"))
+		return
+	}
+
+	if key, ok := snipeFieldKeys[action]; ok {
+		askSnipeInput(ctx, b, chatId, id, action, key)
+		return
+	}
+
+	switch action {
+	case "v":
+		text, kb := snipeDetailView(chatId, s)
+		editHTMLView(ctx, b, chatId, msg.ID, text, kb)
+		return
+	case "rm":
+		if _, err := sniper.DeleteSnipe(chatId, id); err != nil {
+			log.Error().Err(err).Send()
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+			return
+		}
+		text, kb := snipeListView(chatId)
+		editHTMLView(ctx, b, chatId, msg.ID, text, kb)
+		return
+	case "mg":
+		s.Migration = !s.Migration
+	default:
+		return
+	}
+
+	if err := sniper.SaveSnipe(chatId, s); err != nil {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+		return
+	}
+	text, kb := snipeDetailView(chatId, s)
+	editHTMLView(ctx, b, chatId, msg.ID, text, kb)
+}
+
+// check if user replay to snipe input message
+func IsSnipeReply(chatId int64, replyToID int) bool {
+	v, ok := session.GetSessionManager().Get(chatId, session.UserSnipeReply)
+	if !ok {
+		return false
+	}
+	reply, ok := v.(snipeReply)
+	return ok && reply.MessageID == replyToID
+}
+
+func HandleSnipeReply(ctx context.Context, b *bot.Bot, update *models.Update) {
+	if update.Message == nil || update.Message.ReplyToMessage == nil {
+		return
+	}
+	chatId := update.Message.Chat.ID
+
+	v, ok := session.GetSessionManager().Get(chatId, session.UserSnipeReply)
+	if !ok {
+		return
+	}
+	reply, ok := v.(snipeReply)
+	if !ok || reply.MessageID != update.Message.ReplyToMessage.ID {
+		return
+	}
+
+	var s model.Snipe
+	var err error
+	if reply.Id == "" {
+		s, err = newSnipe(chatId, strings.TrimSpace(update.Message.Text))
+	} else {
+		s, ok = sniper.UserSnipe(chatId, reply.Id)
+		if !ok {
+			session.GetSessionManager().Delete(chatId, session.UserSnipeReply)
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "sniper.not_found"))
+			return
+		}
+		err = s.SetField(reply.Field, update.Message.Text)
+	}
+	if err != nil {
+		text := i18n.L(chatId, "common.error")
+		if key, ok := snipeErrKeys[err]; ok {
+			text = i18n.L(chatId, key)
+		}
+		switch err {
+		case model.ErrSnipeFull:
+			text = i18n.L(chatId, "sniper.err.full", model.MaxSnipeLen)
+		case model.ErrSnipeSlippage:
+			text = i18n.L(chatId, "sniper.err.slippage", model.MaxSnipeSlippage)
+		}
+		util.QuickMessage(ctx, b, chatId, "❌ "+text)
+		return
+	}
+
+	if err := sniper.SaveSnipe(chatId, s); err != nil {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+		return
+	}
+	session.GetSessionManager().Delete(chatId, session.UserSnipeReply)
+	text, kb := snipeDetailView(chatId, s)
+	sendHTMLView(ctx, b, chatId, text, kb)
+}
+
+// solana token snipes on SOLANA, evm token snipes on default chain if it's evm
+func newSnipe(chatId int64, token string) (model.Snipe, error) {
+	isSolana, err := util.CheckValidAddress(token)
+	if err != nil || util.IsNativeCoion(token) {
+		return model.Snipe{}, model.ErrSnipeToken
+	}
+	userInfo, err := api.GetUserProfile(chatId)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return model.Snipe{}, err
+	}
+
+	dw, _, _ := UserDefaultWalletInfo(userInfo)
+	chainCode := "BSC"
+	switch {
+	case isSolana:
+		chainCode = "SOLANA"
+	case dw.ChainCode != "" && dw.ChainCode != "SOLANA":
+		chainCode = dw.ChainCode
+	}
+	if !rpc.SupportSnipe(chainCode) {
+		return model.Snipe{}, rpc.ErrUnsupportedChain
+	}
+
+	snipes := sniper.UserSnipes(chatId)
+	if len(snipes) >= model.MaxSnipeLen {
+		return model.Snipe{}, model.ErrSnipeFull
+	}
+	key := model.AddressKey(chainCode, token)
+	if slices.ContainsFunc(snipes, func(s model.Snipe) bool { return s.TokenKey() == key }) {
+		return model.Snipe{}, model.ErrSnipeExist
+	}
+
+	walletId := dw.WalletId
+	if dw.ChainCode != chainCode {
+		wallets := api.ListUserChainWallets(userInfo, chainCode)
+		if len(wallets) == 0 {
+			return model.Snipe{}, model.ErrSnipeNoWallet
+		}
+		walletId = wallets[0].WalletId
+	}
+	s := model.NewSnipe(chainCode, token, walletId)
+	// pump.fun 代币默认等待迁移后再买入
+	s.Migration = s.IsPumpToken()
+	return s, nil
+}
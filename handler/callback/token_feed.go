@@ -0,0 +1,138 @@
+package callback
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/api"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/tokenfeed"
+	"github.com/hellodex/tradingbot/util"
+	"github.com/rs/zerolog/log"
+)
+
+func tokenFeedLimitText(lang string, v int, format string) string {
+	if v == 0 {
+		return i18n.T(lang, "copytrade.no_limit")
+	}
+	return i18n.T(lang, format, v)
+}
+
+func tokenFeedView(chatId int64) (string, models.InlineKeyboardMarkup) {
+	lang := i18n.UserLang(chatId)
+	f := tokenfeed.UserFeed(chatId)
+	onOff := func(on bool) string {
+		if on {
+			return i18n.T(lang, "common.on")
+		}
+		return i18n.T(lang, "common.off")
+	}
+
+	status, toggle := i18n.T(lang, "copytrade.paused"), i18n.T(lang, "tokenfeed.enable")
+	if f.Enabled {
+		status, toggle = i18n.T(lang, "copytrade.running"), i18n.T(lang, "copytrade.pause")
+	}
+	chains := make([]string, 0, len(f.Chains))
+	for _, c := range f.Chains {
+		chains = append(chains, api.GetChainNameFallbackCode(c))
+	}
+	chainText := strings.Join(chains, ", ")
+	if chainText == "" {
+		chainText = i18n.T(lang, "copytrade.none")
+	}
+	liquidity := tokenFeedLimitText(lang, f.MinLiquidity, "tokenfeed.usd")
+	devHolding := tokenFeedLimitText(lang, f.MaxDevHolding, "tokenfeed.percent")
+	risk := tokenFeedLimitText(lang, f.MaxRisk, "tokenfeed.risk_below")
+
+	text := i18n.T(lang, "tokenfeed.detail",
+		status, chainText, onOff(f.Launch), onOff(f.Pair),
+		liquidity, devHolding, onOff(f.Socials), risk, f.HourLimit,
+	)
+
+	var chainRow []models.InlineKeyboardButton
+	for _, c := range model.TokenFeedChains {
+		mark := "⬜"
+		if slices.Contains(f.Chains, c) {
+			mark = "✅"
+		}
+		chainRow = append(chainRow, util.NewCallbackDataButton(mark+api.GetChainNameFallbackCode(c), "tf::ch::"+c))
+	}
+	kb := models.InlineKeyboardMarkup{InlineKeyboard: [][]models.InlineKeyboardButton{
+		{
+			util.NewCallbackDataButton(toggle, "tf::on"),
+		},
+		chainRow,
+		{
+			util.NewCallbackDataButton(i18n.T(lang, "tokenfeed.set_launch", onOff(f.Launch)), "tf::launch"),
+			util.NewCallbackDataButton(i18n.T(lang, "tokenfeed.set_pair", onOff(f.Pair)), "tf::pair"),
+		},
+		{
+			util.NewCallbackDataButton(i18n.T(lang, "tokenfeed.set_liquidity", liquidity), "tf::liq"),
+			util.NewCallbackDataButton(i18n.T(lang, "tokenfeed.set_dev", devHolding), "tf::dev"),
+		},
+		{
+			util.NewCallbackDataButton(i18n.T(lang, "tokenfeed.set_socials", onOff(f.Socials)), "tf::soc"),
+			util.NewCallbackDataButton(i18n.T(lang, "tokenfeed.set_risk", risk), "tf::risk"),
+		},
+		{
+			util.NewCallbackDataButton(i18n.T(lang, "tokenfeed.set_limit", f.HourLimit), "tf::limit"),
+		},
+	}}
+	return text, kb
+}
+
+// trigger by entity.TOKEN_FEED
+func CallbackTokenFeed(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatID := util.EffectId(update)
+	text, kb := tokenFeedView(chatID)
+	sendHTMLView(ctx, b, chatID, text, kb)
+}
+
+// prefix: tf::<action>, tf::ch::<chainCode>
+func CallbackTokenFeedAction(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatId := util.EffectId(update)
+	msg := update.CallbackQuery.Message.Message
+	params := strings.Split(update.CallbackQuery.Data, "::")
+	if len(params) < 2 || msg == nil {
+		return
+	}
+
+	f := tokenfeed.UserFeed(chatId)
+	switch params[1] {
+	case "on":
+		f.Enabled = !f.Enabled
+	case "ch":
+		if len(params) != 3 || !slices.Contains(model.TokenFeedChains, params[2]) {
+			return
+		}
+		f.ToggleChain(params[2])
+	case "launch":
+		f.Launch = !f.Launch
+	case "pair":
+		f.Pair = !f.Pair
+	case "liq":
+		f.MinLiquidity = model.CycleNext(model.TokenFeedLiquidities, f.MinLiquidity)
+	case "dev":
+		f.MaxDevHolding = model.CycleNext(model.TokenFeedDevHoldings, f.MaxDevHolding)
+	case "soc":
+		f.Socials = !f.Socials
+	case "risk":
+		f.MaxRisk = model.CycleNext(model.SafetyBlockScores, f.MaxRisk)
+	case "limit":
+		f.HourLimit = model.CycleNext(model.TokenFeedHourlyLimits, f.HourLimit)
+	default:
+		return
+	}
+
+	if err := tokenfeed.SaveFeed(chatId, f); err != nil {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+		return
+	}
+	text, kb := tokenFeedView(chatId)
+	editHTMLView(ctx, b, chatId, msg.ID, text, kb)
+}
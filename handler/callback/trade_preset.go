@@ -0,0 +1,237 @@
+package callback
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/api"
+	"github.com/hellodex/tradingbot/entity"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/session"
+	"github.com/hellodex/tradingbot/store"
+	"github.com/hellodex/tradingbot/util"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+)
+
+type tradePresetReply struct {
+	MessageID int
+	Side      string
+	ChainCode string
+}
+
+// get user quick trade preset of chain, fallback to default
+func UserTradePreset(chatId int64, chainCode string) model.TradePreset {
+	data, has := store.UserGetTradePreset(chatId, chainCode)
+	if !has {
+		return model.DefaultTradePreset(chainCode)
+	}
+
+	var preset model.TradePreset
+	if err := json.Unmarshal(data, &preset); err != nil {
+		log.Error().Err(err).Send()
+		return model.DefaultTradePreset(chainCode)
+	}
+
+	if err := preset.Vaild(); err != nil {
+		log.Error().Err(err).Int64("chatId", chatId).Msg("trade preset not vaild")
+		return model.DefaultTradePreset(chainCode)
+	}
+	return preset
+}
+
+// trigger by entity.SETTING_TRADE_PRESET
+func TradePresetHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatID := util.EffectId(update)
+
+	chainCfgs, err := api.GetChainConfigs()
+	if err != nil {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "common.error"))
+		return
+	}
+	slices.SortFunc(chainCfgs.Data, func(a, b model.ChainConfig) int {
+		return cmp.Compare(a.Sort, b.Sort)
+	})
+
+	var buttons [][]models.InlineKeyboardButton
+	for _, chainCfg := range chainCfgs.Data {
+		buttons = append(buttons, []models.InlineKeyboardButton{
+			util.NewCallbackDataButton(chainCfg.Chain, "tpChain::"+chainCfg.ChainCode),
+		})
+	}
+	buttons = append(buttons, []models.InlineKeyboardButton{
+		entity.GetCallbackButtonLang(entity.SETTING, i18n.UserLang(chatID)),
+	})
+
+	store.BotMessageAdd()
+	b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:      chatID,
+		Text:        i18n.L(chatID, "preset.select_chain"),
+		ReplyMarkup: models.InlineKeyboardMarkup{InlineKeyboard: buttons},
+	})
+}
+
+// model err -> i18n key
+var tradePresetErrKeys = map[error]string{
+	model.ErrTradePresetEmpty:    "preset.err.empty",
+	model.ErrTradePresetTooMany:  "preset.err.too_many",
+	model.ErrTradePresetNotNum:   "preset.err.not_num",
+	model.ErrTradePresetTooLong:  "preset.err.too_long",
+	model.ErrTradePresetNotRange: "preset.err.not_range",
+}
+
+func tradePresetErrText(chatID int64, err error) string {
+	if key, ok := tradePresetErrKeys[err]; ok {
+		return i18n.L(chatID, key)
+	}
+	return err.Error()
+}
+
+// prefix: tpChain::<chainCode>
+func CallbackTradePresetChain(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatID := util.EffectId(update)
+	chainCode := strings.TrimPrefix(update.CallbackQuery.Data, "tpChain::")
+
+	sendTradePresetInfo(ctx, b, chatID, chainCode)
+}
+
+func sendTradePresetInfo(ctx context.Context, b *bot.Bot, chatID int64, chainCode string) {
+	preset := UserTradePreset(chatID, chainCode)
+	sellText := lo.Map(preset.Sell, func(s string, _ int) string { return s + "%" })
+	lang := i18n.UserLang(chatID)
+
+	kb := models.InlineKeyboardMarkup{
+		InlineKeyboard: [][]models.InlineKeyboardButton{
+			{
+				util.NewCallbackDataButton(i18n.T(lang, "preset.set_buy"), fmt.Sprintf("tpSet::buy::%s", chainCode)),
+				util.NewCallbackDataButton(i18n.T(lang, "preset.set_sell"), fmt.Sprintf("tpSet::sell::%s", chainCode)),
+			},
+			{
+				util.NewCallbackDataButton(i18n.T(lang, "preset.reset"), fmt.Sprintf("tpSet::reset::%s", chainCode)),
+				entity.GetCallbackButtonLang(entity.SETTING_TRADE_PRESET, lang),
+			},
+		},
+	}
+
+	store.BotMessageAdd()
+	b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID: chatID,
+		Text: i18n.T(lang, "preset.info",
+			api.GetChainNameFallbackCode(chainCode),
+			strings.Join(preset.Buy, " / "),
+			strings.Join(sellText, " / "),
+		),
+		ReplyMarkup: kb,
+	})
+}
+
+// prefix: tpSet::buy/sell/reset::<chainCode>
+func CallbackTradePresetSet(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatID := util.EffectId(update)
+	params := strings.Split(update.CallbackQuery.Data, "::")
+	if len(params) < 3 {
+		log.Error().Str("callbackData", update.CallbackQuery.Data).Msg("trade preset callback data err")
+		return
+	}
+	side, chainCode := params[1], params[2]
+
+	var text, placeholder string
+	switch side {
+	case model.TradePresetSideBuy:
+		text = i18n.L(chatID, "preset.input_buy", model.MaxBuyPresetCount)
+		placeholder = "0.1 0.5 1 5 10"
+	case model.TradePresetSideSell:
+		text = i18n.L(chatID, "preset.input_sell", model.MaxSellPresetCount)
+		placeholder = "25 50 100"
+	case "reset":
+		store.UserDeleteTradePreset(chatID, chainCode)
+		util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "preset.reset_success"))
+		sendTradePresetInfo(ctx, b, chatID, chainCode)
+		return
+	default:
+		return
+	}
+
+	store.BotMessageAdd()
+	message, err := b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID: chatID,
+		Text:   text,
+		ReplyMarkup: models.ForceReply{
+			ForceReply:            true,
+			InputFieldPlaceholder: placeholder,
+		},
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("发送快捷买卖设置消息失败")
+		return
+	}
+
+	session.GetSessionManager().Set(chatID, session.UserTradePresetReply, tradePresetReply{
+		MessageID: message.ID,
+		Side:      side,
+		ChainCode: chainCode,
+	})
+}
+
+// check if user replay to trade preset setting message
+func IsTradePresetReply(chatID int64, replyToID int) bool {
+	v, ok := session.GetSessionManager().Get(chatID, session.UserTradePresetReply)
+	if !ok {
+		return false
+	}
+	reply, ok := v.(tradePresetReply)
+	return ok && reply.MessageID == replyToID
+}
+
+func HandleTradePresetReply(ctx context.Context, b *bot.Bot, update *models.Update) {
+	if update.Message == nil || update.Message.ReplyToMessage == nil {
+		return
+	}
+	chatID := update.Message.Chat.ID
+
+	v, ok := session.GetSessionManager().Get(chatID, session.UserTradePresetReply)
+	if !ok {
+		return
+	}
+	reply, ok := v.(tradePresetReply)
+	if !ok || reply.MessageID != update.Message.ReplyToMessage.ID {
+		return
+	}
+
+	values, err := model.ParseTradePresetInput(reply.Side, update.Message.Text)
+	if err != nil {
+		util.QuickMessage(ctx, b, chatID, "❌ "+tradePresetErrText(chatID, err))
+		return
+	}
+
+	preset := UserTradePreset(chatID, reply.ChainCode)
+	if reply.Side == model.TradePresetSideBuy {
+		preset.Buy = values
+	} else {
+		preset.Sell = values
+	}
+
+	data, err := preset.JsonB()
+	if err != nil {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "common.error"))
+		return
+	}
+	if err := store.UserSetTradePreset(chatID, reply.ChainCode, data); err != nil {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "common.error"))
+		return
+	}
+
+	session.GetSessionManager().Delete(chatID, session.UserTradePresetReply)
+	util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "preset.set_success"))
+	sendTradePresetInfo(ctx, b, chatID, reply.ChainCode)
+}
@@ -10,10 +10,12 @@ import (
 	"github.com/go-telegram/bot/models"
 	"github.com/hellodex/tradingbot/api"
 	"github.com/hellodex/tradingbot/entity"
+	"github.com/hellodex/tradingbot/i18n"
 	"github.com/hellodex/tradingbot/model"
 	"github.com/hellodex/tradingbot/store"
 	"github.com/hellodex/tradingbot/util"
 	"github.com/rs/zerolog/log"
+	"github.com/spf13/cast"
 )
 
 // trigger by entity.WALLET
@@ -23,7 +25,7 @@ func WalletMenuHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 	userInfo, err := api.GetUserProfile(chatID)
 	if err != nil {
 		log.Error().Err(err).Send()
-		util.QuickMessage(ctx, b, chatID, "你的账户信息不正常，请联系管理员！")
+		util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "common.error"))
 		return
 	}
 
@@ -34,11 +36,11 @@ func WalletMenuHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 
 	kb := buildWalletMenuSelect(chainWallets, chatID, userInfo)
 	switchWalletButton := models.InlineKeyboardButton{
-		Text:         "切换钱包",
+		Text:         i18n.L(chatID, "wallet.switch"),
 		CallbackData: entity.SWITCH_DEFAULT_WALLET,
 	}
 	lastLineButton := []models.InlineKeyboardButton{
-		entity.GetCallbackButton(entity.SWITCH_PUBLIC_CHAIN),
+		entity.GetCallbackButtonLang(entity.SWITCH_PUBLIC_CHAIN, i18n.UserLang(chatID)),
 		// entity.GetCallbackButton(entity.SWITCH_DEFAULT_WALLET),
 		switchWalletButton,
 	}
@@ -47,7 +49,7 @@ func WalletMenuHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 	store.BotMessageAdd()
 	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID:      chatID,
-		Text:        walletInfo(defaultW, chanName),
+		Text:        walletInfo(i18n.UserLang(chatID), defaultW, chanName),
 		ParseMode:   "HTML",
 		ReplyMarkup: kb,
 		LinkPreviewOptions: &models.LinkPreviewOptions{
@@ -59,15 +61,8 @@ func WalletMenuHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 	}
 }
 
-func walletInfo(w model.Wallet, chain string) string {
-	textTemplate := `
-当前钱包：（点击地址切换默认钱包）
-<code>%s</code> (点击复制)
-
-当前公链：%s
-`
-	textTemplate = strings.TrimSpace(textTemplate)
-	return fmt.Sprintf(textTemplate, w.Wallet, chain)
+func walletInfo(lang string, w model.Wallet, chain string) string {
+	return strings.TrimSpace(i18n.T(lang, "wallet.info", w.Wallet, chain))
 }
 
 // trigger by SWITCH_DEFAULLT_CHAIN_WALLETS
@@ -84,7 +79,7 @@ func CallbackSelectDefaultChainWallet(ctx context.Context, b *bot.Bot, update *m
 	kb := buildWalletMenuSelect(chainWallets, chatID, userInfo)
 	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID:      update.CallbackQuery.Message.Message.Chat.ID,
-		Text:        fmt.Sprintf("当前 %s 链的钱包有如下：\n", chanName),
+		Text:        i18n.L(chatID, "wallet.list", chanName),
 		ParseMode:   "HTML",
 		ReplyMarkup: kb,
 		LinkPreviewOptions: &models.LinkPreviewOptions{
@@ -104,20 +99,20 @@ func CallbackSelectChainHandler(ctx context.Context, b *bot.Bot, update *models.
 	userInfo, err := api.GetUserProfile(chatID)
 	if err != nil {
 		log.Error().Err(err).Send()
-		util.QuickMessage(ctx, b, chatID, "请联系管理员！")
+		util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "common.error"))
 		return
 	}
 
 	kb, err := BuildChainsMenuSelect(chatID, userInfo)
 	if err != nil {
 		log.Error().Err(err).Send()
-		util.QuickMessage(ctx, b, chatID, "请联系管理员！")
+		util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "common.error"))
 		return
 	}
 
 	b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID:      update.CallbackQuery.Message.Message.Chat.ID,
-		Text:        "选择你要设置的链",
+		Text:        i18n.L(chatID, "wallet.select_chain"),
 		ParseMode:   "HTML",
 		ReplyMarkup: kb,
 		LinkPreviewOptions: &models.LinkPreviewOptions{
@@ -185,7 +180,7 @@ func CallbackListSelectWalletHandler(ctx context.Context, b *bot.Bot, update *mo
 	}
 	wallets := api.ListUserDefaultWalletsSwitch(userInfo, selectChain())
 	if wallets == nil {
-		util.QuickMessage(ctx, b, chatID, "没有钱包")
+		util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "wallet.empty"))
 		return
 	}
 
@@ -193,7 +188,7 @@ func CallbackListSelectWalletHandler(ctx context.Context, b *bot.Bot, update *mo
 
 	chainName := api.GetChainNameFallbackCode(selectChain())
 
-	text := fmt.Sprintf("当前选择链：%s\n\n现在选择你的钱包", chainName)
+	text := i18n.L(chatID, "wallet.select_wallet", chainName)
 
 	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID:      update.CallbackQuery.Message.Message.Chat.ID,
@@ -214,11 +209,12 @@ func CallbackListSelectWalletHandler(ctx context.Context, b *bot.Bot, update *mo
 // text: testA....ess1
 func buildWalletMenuSelect(wallets []model.Wallet, chatID any, userInfo model.GetUserResp) models.InlineKeyboardMarkup {
 	var buttons [][]models.InlineKeyboardButton
+	lang := i18n.UserLang(cast.ToInt64(chatID))
 
 	for index, wallet := range wallets {
 		addr := wallet.Wallet
 		if len(addr) > 10 {
-			displayText := fmt.Sprintf("钱包%d %s....%s", index+1, addr[:4], addr[len(addr)-4:])
+			displayText := i18n.T(lang, "wallet.button", index+1, addr[:4], addr[len(addr)-4:])
 			if wallet.WalletId == userInfo.Data.TgDefaultWalletId {
 				displayText = "✅" + displayText
 			}
@@ -250,7 +246,7 @@ func CallbackConfirmSelectWallet(ctx context.Context, b *bot.Bot, update *models
 	userInfo, err := api.GetUserProfile(chatID)
 	if err != nil {
 		log.Error().Err(err).Send()
-		util.QuickMessage(ctx, b, chatID, "出错了，请联系管理员！")
+		util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "common.error"))
 		return
 	}
 
@@ -260,7 +256,7 @@ func CallbackConfirmSelectWallet(ctx context.Context, b *bot.Bot, update *models
 	err = api.UpdateUserProfile(chatID, userInfo)
 	if err != nil {
 		log.Error().Err(err).Send()
-		util.QuickMessage(ctx, b, chatID, "更新出错，请联系客服")
+		util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "common.error"))
 		return
 	}
 
@@ -0,0 +1,236 @@
+package callback
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/api"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/store"
+	"github.com/hellodex/tradingbot/template"
+	"github.com/hellodex/tradingbot/util"
+	"github.com/rs/zerolog/log"
+)
+
+// user watch tokens, sort by added time
+func UserWatchlist(chatId int64) []model.WatchToken {
+	data, err := store.UserGetWatchlist(chatId)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return nil
+	}
+
+	tokens := make([]model.WatchToken, 0, len(data))
+	for _, v := range data {
+		var w model.WatchToken
+		if err := json.Unmarshal([]byte(v), &w); err != nil {
+			log.Error().Err(err).Send()
+			continue
+		}
+		tokens = append(tokens, w)
+	}
+	slices.SortFunc(tokens, func(a, b model.WatchToken) int {
+		return cmp.Compare(a.AddedAt, b.AddedAt)
+	})
+	return tokens
+}
+
+func AddWatchToken(chatId int64, info api.TokenInfo) error {
+	if store.UserHasWatchToken(chatId, info.BaseAddress) {
+		return model.ErrWatchlistExist
+	}
+	if len(UserWatchlist(chatId)) >= model.MaxWatchlistLen {
+		return model.ErrWatchlistFull
+	}
+
+	w := model.WatchToken{
+		ChainCode:   info.ChainCode,
+		BaseAddress: info.BaseAddress,
+		Symbol:      info.Symbol,
+		AddedAt:     time.Now().Unix(),
+	}
+	data, err := w.JsonB()
+	if err != nil {
+		return err
+	}
+	return store.UserAddWatchToken(chatId, info.BaseAddress, data)
+}
+
+// 同时查询代币信息的数量
+const watchlistFetchLimit = 5
+
+func buildWatchlist(chatId int64) (string, models.InlineKeyboardMarkup, error) {
+	lang := i18n.UserLang(chatId)
+	tokens := UserWatchlist(chatId)
+
+	// 并发查询, 保持列表顺序
+	infos := make([]api.TokenInfo, len(tokens))
+	util.ParallelLimit(watchlistFetchLimit, len(tokens), func(i int) {
+		infos[i] = api.SearchTokenInfoSwitch(tokens[i].BaseAddress)
+	})
+
+	var buttons [][]models.InlineKeyboardButton
+	for i, w := range tokens {
+		info := infos[i]
+		if info.Symbol == "" {
+			// 查询失败时用保存的信息占位
+			info = api.TokenInfo{
+				BaseAddress: w.BaseAddress,
+				ChainCode:   w.ChainCode,
+				Symbol:      w.Symbol,
+			}
+		}
+		if info.BaseAddress == "" {
+			info.BaseAddress = w.BaseAddress
+		}
+		infos[i] = info
+		buttons = append(buttons, []models.InlineKeyboardButton{
+			util.NewCallbackDataButton("👉"+info.Symbol, "watch::open::"+w.BaseAddress),
+			util.NewCallbackDataButton(i18n.T(lang, "watchlist.remove"), "watch::rm::"+w.BaseAddress),
+		})
+	}
+
+	buttons = append(buttons, []models.InlineKeyboardButton{
+		util.NewCallbackDataButton(i18n.T(lang, "common.refresh"), "watch::refresh"),
+		util.BackToMainMenu(lang),
+	})
+	kb := models.InlineKeyboardMarkup{InlineKeyboard: buttons}
+
+	if len(infos) == 0 {
+		return i18n.T(lang, "watchlist.empty"), kb, nil
+	}
+
+	text, err := template.RanderWatchlist(lang, infos, time.Now().Format("15:04:05"))
+	if err != nil {
+		return "", kb, err
+	}
+	return text, kb, nil
+}
+
+// command /watchlist
+func WatchlistHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatId := util.EffectId(update)
+
+	text, kb, err := buildWatchlist(chatId)
+	if err != nil {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+		return
+	}
+
+	store.BotMessageAdd()
+	b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:      chatId,
+		Text:        text,
+		ParseMode:   models.ParseModeHTML,
+		ReplyMarkup: kb,
+		LinkPreviewOptions: &models.LinkPreviewOptions{
+			IsDisabled: bot.True(),
+		},
+	})
+}
+
+func editWatchlist(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatId := util.EffectId(update)
+	msg := update.CallbackQuery.Message.Message
+	if msg == nil {
+		WatchlistHandler(ctx, b, update)
+		return
+	}
+
+	text, kb, err := buildWatchlist(chatId)
+	if err != nil {
+		log.Error().Err(err).Send()
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+		return
+	}
+
+	_, err = b.EditMessageText(ctx, &bot.EditMessageTextParams{
+		ChatID:      chatId,
+		MessageID:   msg.ID,
+		Text:        text,
+		ParseMode:   models.ParseModeHTML,
+		ReplyMarkup: kb,
+		LinkPreviewOptions: &models.LinkPreviewOptions{
+			IsDisabled: bot.True(),
+		},
+	})
+	if err != nil {
+		log.Debug().Err(err).Msg("edit watchlist")
+	}
+}
+
+// prefix: watch::add::<address> / watch::rm::<address> / watch::open::<address> / watch::refresh
+func CallbackWatchlist(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatId := util.EffectId(update)
+	params := strings.Split(update.CallbackQuery.Data, "::")
+	if len(params) < 2 {
+		return
+	}
+
+	switch params[1] {
+	case "refresh":
+		editWatchlist(ctx, b, update)
+	case "add":
+		if len(params) != 3 {
+			return
+		}
+		info := api.SearchTokenInfoSwitch(params[2])
+		if info.Symbol == "" {
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "group.token_not_found"))
+			return
+		}
+		if info.BaseAddress == "" {
+			info.BaseAddress = params[2]
+		}
+
+		switch err := AddWatchToken(chatId, info); err {
+		case nil:
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "watchlist.added", info.Symbol))
+		case model.ErrWatchlistExist:
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "watchlist.exist", info.Symbol))
+		case model.ErrWatchlistFull:
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "watchlist.full", model.MaxWatchlistLen))
+		default:
+			log.Error().Err(err).Send()
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+		}
+	case "rm":
+		if len(params) != 3 {
+			return
+		}
+		if err := store.UserDeleteWatchToken(chatId, params[2]); err != nil {
+			log.Error().Err(err).Send()
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+			return
+		}
+		editWatchlist(ctx, b, update)
+	case "open":
+		if len(params) != 3 {
+			return
+		}
+		msg := update.CallbackQuery.Message.Message
+		if msg == nil {
+			return
+		}
+		// 交给 TokenInfoHandler 处理, 和用户直接发送地址一样
+		newUpdate := &models.Update{
+			Message: &models.Message{
+				Text: params[2],
+				From: &update.CallbackQuery.From,
+				Chat: msg.Chat,
+				ID:   msg.ID,
+				Date: int(time.Now().Unix()),
+			},
+			ID: update.ID,
+		}
+		b.ProcessUpdate(ctx, newUpdate)
+	}
+}
@@ -0,0 +1,132 @@
+package callback
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/store"
+	"github.com/hellodex/tradingbot/util"
+	"github.com/rs/zerolog/log"
+)
+
+// UserWhitelist with pending change applied, err is returned instead of
+// falling back to the disabled default so transfers are not left unguarded
+func UserWhitelist(chatId int64) (model.WhitelistSetting, error) {
+	w := model.DefaultWhitelistSetting()
+	data, has, err := store.UserGetWhitelist(chatId)
+	if err != nil || !has {
+		return w, err
+	}
+
+	if err := json.Unmarshal(data, &w); err != nil {
+		return model.WhitelistSetting{}, err
+	}
+	w.Apply(time.Now())
+	return w, nil
+}
+
+func saveUserWhitelist(chatId int64, w model.WhitelistSetting) error {
+	data, err := w.JsonB()
+	if err != nil {
+		return err
+	}
+	return store.UserSetWhitelist(chatId, data)
+}
+
+// CheckTransferWhitelist send reason to user and return false if address can not be used for transfer
+func CheckTransferWhitelist(ctx context.Context, b *bot.Bot, chatId int64, chainCode, address string) bool {
+	w, err := UserWhitelist(chatId)
+	if err != nil {
+		log.Error().Err(err).Int64("chatId", chatId).Msg("get whitelist")
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+		return false
+	}
+	entry, err := w.CheckAddress(UserAddressBook(chatId), chainCode, address, time.Now())
+	switch err {
+	case nil:
+		return true
+	case model.ErrWhitelistCoolDown:
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "whitelist.cool_down", entry.Label, w.UsableAt(entry).Format("2006-01-02 15:04")))
+	default:
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "whitelist.not_allowed"))
+	}
+	return false
+}
+
+// 地址簿添加地址后的提示
+func whitelistAddedTip(chatId int64, w model.WhitelistSetting, entry model.AddressEntry) string {
+	if !w.Enabled {
+		return ""
+	}
+	return i18n.L(chatId, "whitelist.added_tip", w.UsableAt(entry).Format("2006-01-02 15:04"))
+}
+
+func whitelistInfo(lang string, w model.WhitelistSetting) string {
+	status := i18n.T(lang, "common.off")
+	if w.Enabled {
+		status = i18n.T(lang, "common.on")
+	}
+	text := i18n.T(lang, "whitelist.info", status, w.CoolDownHours)
+	if w.Pending != nil {
+		pending := i18n.T(lang, "common.off")
+		if w.Pending.Enabled {
+			pending = i18n.T(lang, "common.on")
+		}
+		text += "\n" + i18n.T(lang, "whitelist.pending", time.Unix(w.Pending.EffectiveAt, 0).Format("2006-01-02 15:04"), pending, w.Pending.CoolDownHours)
+	}
+	return text
+}
+
+// buttons change the target setting, pending change included
+func whitelistButtons(lang string, w model.WhitelistSetting) []models.InlineKeyboardButton {
+	enabled, coolDownHours := w.Target()
+	toggle := i18n.T(lang, "whitelist.enable")
+	if enabled {
+		toggle = i18n.T(lang, "whitelist.disable")
+	}
+	return []models.InlineKeyboardButton{
+		util.NewCallbackDataButton(toggle, "wl::toggle"),
+		util.NewCallbackDataButton(i18n.T(lang, "whitelist.cool_down_button", coolDownHours), "wl::cd"),
+	}
+}
+
+// prefix: wl::toggle / wl::cd
+func CallbackWhitelist(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatId := util.EffectId(update)
+	action := strings.TrimPrefix(update.CallbackQuery.Data, "wl::")
+	if action != "toggle" && action != "cd" {
+		return
+	}
+
+	RequirePin(ctx, b, chatId, func(ctx context.Context) {
+		w, err := UserWhitelist(chatId)
+		if err != nil {
+			log.Error().Err(err).Send()
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+			return
+		}
+		enabled, coolDownHours := w.Target()
+		switch action {
+		case "toggle":
+			enabled = !enabled
+		case "cd":
+			coolDownHours = model.CycleNext(model.WhitelistCoolDownHours, coolDownHours)
+		}
+		w.Change(enabled, coolDownHours, time.Now())
+		if err := saveUserWhitelist(chatId, w); err != nil {
+			log.Error().Err(err).Send()
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+			return
+		}
+		if w.Pending != nil {
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "whitelist.delayed", time.Unix(w.Pending.EffectiveAt, 0).Format("2006-01-02 15:04")))
+		}
+		SecurityHandler(ctx, b, update)
+	})
+}
@@ -3,12 +3,12 @@ package callback
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"strings"
 
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
 	"github.com/hellodex/tradingbot/api"
+	"github.com/hellodex/tradingbot/i18n"
 	"github.com/hellodex/tradingbot/store"
 	"github.com/hellodex/tradingbot/util"
 	"github.com/rs/zerolog/log"
@@ -16,10 +16,6 @@ import (
 	"github.com/tidwall/gjson"
 )
 
-var withdrawlTxt = `
-可提现金额: $%s, 请输入提现金额
-`
-
 func CallbackWithdrawl(ctx context.Context, b *bot.Bot, u *models.Update) {
 	chatId := util.EffectId(u)
 
@@ -29,12 +25,12 @@ func CallbackWithdrawl(ctx context.Context, b *bot.Bot, u *models.Update) {
 	}
 	userInfo, err := api.GetUserProfile(chatId)
 	if err != nil {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s, 错误代码：getuser", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error_code", "getuser"))
 		return
 	}
 	cmInfo, err := api.GetMyCommissionSummary(userInfo)
 	if err != nil {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 	dw, _, _ := UserDefaultWalletInfo(userInfo)
@@ -46,7 +42,7 @@ func CallbackWithdrawl(ctx context.Context, b *bot.Bot, u *models.Update) {
 
 	dd, err := json.Marshal(reqData)
 	if err != nil {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 
@@ -58,7 +54,7 @@ func CallbackWithdrawl(ctx context.Context, b *bot.Bot, u *models.Update) {
 	b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID:      chatId,
 		ReplyMarkup: reply,
-		Text:        fmt.Sprintf(withdrawlTxt, withdrawableCommissionAmount),
+		Text:        i18n.L(chatId, "withdrawal.input", withdrawableCommissionAmount),
 	})
 }
 
@@ -74,14 +70,14 @@ func CallBackWithdrawlButton(ctx context.Context, b *bot.Bot, u *models.Update)
 		data, has := store.UserGetCommissionInfo(chatId)
 		if !has {
 			log.Debug().Msg("user has not commission info")
-			util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 			return
 		}
 
 		var sq map[string]string
 		err := json.Unmarshal(data, &sq)
 		if err != nil {
-			util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 			return
 		}
 
@@ -89,30 +85,83 @@ func CallBackWithdrawlButton(ctx context.Context, b *bot.Bot, u *models.Update)
 		chainCode := sq["chainCode"]
 		walletAddress := sq["walletAddress"]
 
-		userInfo, err := api.GetUserProfile(chatId)
-		if err != nil {
-			util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s, 错误代码：getuser", util.AdminUrl))
-			return
-		}
-		_, err = api.SubmitWithdraw(chainCode, walletAddress, amount, userInfo)
-		if err != nil {
-			util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
-			return
-		}
-		store.BotMessageAdd()
-		b.SendMessage(ctx, &bot.SendMessageParams{
-			Text:      "提现已提交，审核通过后立即发放",
-			ChatID:    chatId,
-			ParseMode: "HTML",
-		})
+		// 防止重复点击
 		store.UserDeleteCommissionInfo(chatId)
+		RequirePin(ctx, b, chatId, func(ctx context.Context) {
+			userInfo, err := api.GetUserProfile(chatId)
+			if err != nil {
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error_code", "getuser"))
+				return
+			}
+			_, err = api.SubmitWithdraw(chainCode, walletAddress, amount, userInfo)
+			if err != nil {
+				util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+				return
+			}
+			MarkAddressUsed(chatId, chainCode, walletAddress)
+			store.BotMessageAdd()
+			b.SendMessage(ctx, &bot.SendMessageParams{
+				Text:      i18n.L(chatId, "withdrawal.submitted"),
+				ChatID:    chatId,
+				ParseMode: "HTML",
+			})
+		})
 	case "no":
 		store.UserDeleteCommissionInfo(chatId)
 		store.BotMessageAdd()
 		b.SendMessage(ctx, &bot.SendMessageParams{
-			Text:      "提现已取消",
+			Text:      i18n.L(chatId, "withdrawal.cancelled"),
 			ChatID:    chatId,
 			ParseMode: "HTML",
 		})
 	}
 }
+
+// save withdrawal address and send confirm message, warn if address never used
+func SendWithdrawalConfirm(ctx context.Context, b *bot.Bot, chatId int64, subReq map[string]string) {
+	ddn, err := json.Marshal(subReq)
+	if err != nil {
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
+		store.UserDeleteCommissionInfo(chatId)
+		return
+	}
+	store.UserSetCommissionInfo(chatId, ddn)
+
+	text := i18n.L(chatId, "withdrawal.confirm_info", subReq["amount"], api.GetChainNameFallbackCode(subReq["chainCode"]), subReq["walletAddress"])
+	if !IsKnownAddress(chatId, subReq["chainCode"], subReq["walletAddress"]) {
+		text += "\n\n" + i18n.L(chatId, "addressbook.new_address_tip")
+	}
+
+	store.BotMessageAdd()
+	b.SendMessage(ctx, &bot.SendMessageParams{
+		Text:        text,
+		ChatID:      chatId,
+		ReplyMarkup: util.WithdrawalKeyBoard(i18n.UserLang(chatId)),
+		ParseMode:   "HTML",
+	})
+}
+
+// prefix: abWd::<address book id>
+func CallbackAddressBookWithdrawal(ctx context.Context, b *bot.Bot, u *models.Update) {
+	chatId := util.EffectId(u)
+	id := strings.TrimPrefix(u.CallbackQuery.Data, "abWd::")
+
+	data, has := store.UserGetCommissionInfo(chatId)
+	if !has {
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "addressbook.transfer_expired"))
+		return
+	}
+	var subReq map[string]string
+	if err := json.Unmarshal(data, &subReq); err != nil || subReq["amount"] == "" {
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "addressbook.transfer_expired"))
+		return
+	}
+
+	entry, ok := UserAddressEntry(chatId, id)
+	if !ok || entry.ChainCode != subReq["chainCode"] {
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "addressbook.err.chain"))
+		return
+	}
+	subReq["walletAddress"] = entry.Address
+	SendWithdrawalConfirm(ctx, b, chatId, subReq)
+}
@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"context"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/alert"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/pusher"
+	"github.com/hellodex/tradingbot/util"
+)
+
+// 死信列表最多展示的数量
+const maxPushDeadShow = 10
+
+var adminTimeLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04", time.DateOnly}
+
+// parseAdminTime parse time in server timezone, date of end means the end of day
+func parseAdminTime(text string, end bool) (time.Time, bool) {
+	if sec, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return time.Unix(sec, 0), true
+	}
+	for _, layout := range adminTimeLayouts {
+		t, err := time.ParseInLocation(layout, text, time.Local)
+		if err != nil {
+			continue
+		}
+		if end && layout == time.DateOnly {
+			t = t.AddDate(0, 0, 1).Add(-time.Millisecond)
+		}
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// /replay_push <from> <to>, push archived pushes again
+func ReplayPushHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatId := util.EffectId(update)
+	fields := strings.Fields(update.Message.Text)
+	if len(fields) != 3 {
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "admin.replay_usage"))
+		return
+	}
+	from, ok1 := parseAdminTime(fields[1], false)
+	to, ok2 := parseAdminTime(fields[2], true)
+	if !ok1 || !ok2 || to.Before(from) {
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "admin.replay_usage"))
+		return
+	}
+
+	n, err := pusher.Replay(from, to)
+	if err != nil {
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "admin.replay_failed", n, html.EscapeString(err.Error())))
+		return
+	}
+	util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "admin.replay_done", n, from.Format(time.DateTime), to.Format(time.DateTime)))
+}
+
+// /push_dead, recent pushes failed permanently
+func PushDeadHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatId := util.EffectId(update)
+	lang := i18n.UserLang(chatId)
+	list, total := alert.DeadPushes(maxPushDeadShow)
+	if len(list) == 0 {
+		util.QuickMessage(ctx, b, chatId, i18n.T(lang, "admin.push_dead_empty"))
+		return
+	}
+
+	text := i18n.T(lang, "admin.push_dead", total, len(list))
+	for _, d := range list {
+		text += "\n\n" + i18n.T(lang, "admin.push_dead_item",
+			time.Unix(d.At, 0).Format(time.DateTime), d.Push.ChatId,
+			html.EscapeString(d.Push.Symbol), html.EscapeString(d.Push.Type),
+			d.Push.Attempt, html.EscapeString(d.Error),
+		)
+	}
+	util.QuickMessage(ctx, b, chatId, text)
+}
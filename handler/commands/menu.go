@@ -2,50 +2,44 @@ package commands
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
 	"github.com/hellodex/tradingbot/api"
 	"github.com/hellodex/tradingbot/entity"
 	"github.com/hellodex/tradingbot/handler/callback"
+	"github.com/hellodex/tradingbot/i18n"
 	"github.com/hellodex/tradingbot/store"
 	"github.com/hellodex/tradingbot/util"
 	"github.com/rs/zerolog/log"
 )
 
-const menuTemplate = `
-钱包地址：
-<code>%s</code> (点击复制)
-
-钱包余额：%s SOL ($ %s)
-`
-
 func MenuHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 	chatID := util.EffectId(update)
+	lang := i18n.UserLang(chatID)
 	kb := &models.InlineKeyboardMarkup{
 		InlineKeyboard: [][]models.InlineKeyboardButton{
 			{
-				entity.GetCallbackButton(entity.BUY_SELL),
-				entity.GetCallbackButton(entity.OrderTrade),
+				entity.GetCallbackButtonLang(entity.BUY_SELL, lang),
+				entity.GetCallbackButtonLang(entity.OrderTrade, lang),
 			},
 			{
-				entity.GetCallbackButton(entity.WALLET),
-				entity.GetCallbackButton(entity.ASSETS),
+				entity.GetCallbackButtonLang(entity.WALLET, lang),
+				entity.GetCallbackButtonLang(entity.ASSETS, lang),
 			},
 			{
-				entity.GetCallbackButton(entity.HistoryOrder),
-				entity.GetCallbackButton(entity.HistoryTransfer),
+				entity.GetCallbackButtonLang(entity.HistoryOrder, lang),
+				entity.GetCallbackButtonLang(entity.HistoryTransfer, lang),
 			},
 			{
-				entity.GetCallbackButton(entity.AppDownload),
+				entity.GetCallbackButtonLang(entity.AppDownload, lang),
 			},
 			{
-				entity.GetCallbackButton(entity.SETTING),
-				entity.GetCallbackButton(entity.AdminUrl),
+				entity.GetCallbackButtonLang(entity.SETTING, lang),
+				entity.GetCallbackButtonLang(entity.AdminUrl, lang),
 			},
 			{
-				entity.GetCallbackButton(entity.Other),
+				entity.GetCallbackButtonLang(entity.Other, lang),
 			},
 		},
 	}
@@ -55,7 +49,7 @@ func MenuHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 	userInfo, err := api.GetUserProfile(chatId)
 	if err != nil {
 		log.Error().Err(err).Send()
-		util.QuickMessage(ctx, b, chatId, "出错了，请联系客服")
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 	badUserDWInfo := true
@@ -71,7 +65,7 @@ func MenuHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 			if util.IsNativeCoion(t.Address) {
 				nativeCoinBalance := util.FormatNumber(util.ShiftLeftStr(t.Amount, t.Decimals))
 				usdTotalAmount := util.FormatNumber(t.TotalAmount)
-				text = fmt.Sprintf(menuTemplate, dW.Wallet, nativeCoinBalance, usdTotalAmount)
+				text = i18n.T(lang, "menu.info", dW.Wallet, nativeCoinBalance, usdTotalAmount)
 				badUserDWInfo = false
 				return
 			}
@@ -79,7 +73,7 @@ func MenuHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 	}()
 
 	if badUserDWInfo {
-		util.QuickMessage(ctx, b, chatId, "出错了，请联系客服")
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 
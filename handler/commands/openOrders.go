@@ -8,6 +8,7 @@ import (
 	"github.com/go-telegram/bot/models"
 	"github.com/hellodex/tradingbot/api"
 	"github.com/hellodex/tradingbot/handler/callback"
+	"github.com/hellodex/tradingbot/i18n"
 	"github.com/hellodex/tradingbot/store"
 	"github.com/hellodex/tradingbot/template"
 	"github.com/hellodex/tradingbot/util"
@@ -17,7 +18,7 @@ import (
 
 func OpenOrdersHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 	chatID := util.EffectId(update)
-	util.QuickMessage(ctx, b, chatID, "正在查询......")
+	util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "history.querying"))
 	userInfo, err := api.GetUserProfile(chatID)
 	if err != nil {
 		log.Error().Err(err).Send()
@@ -30,12 +31,12 @@ func OpenOrdersHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 	history, err := api.ListOpeningOrders(cast.ToFloat64(dw.WalletId), userInfo)
 	if err != nil {
 		log.Error().Err(err).Send()
-		util.QuickMessage(ctx, b, chatID, "出错了，请联系客服")
+		util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "common.error"))
 		return
 	}
 
 	if len(history.Data) == 0 {
-		util.QuickMessage(ctx, b, chatID, "没有最近委托记录！")
+		util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "history.no_orders"))
 		return
 	}
 
@@ -49,7 +50,7 @@ func OpenOrdersHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 	historyByte, err := json.Marshal(history.Data)
 	if err != nil {
 		log.Error().Err(err).Send()
-		util.QuickMessage(ctx, b, chatID, "没有最近委托记录！")
+		util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "history.no_orders"))
 		return
 	}
 
@@ -63,7 +64,7 @@ func OpenOrdersHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 	store.BotMessageAdd()
 	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID:    chatID,
-		Text:      msg + "当前委托列表",
+		Text:      msg + i18n.L(chatID, "history.orders_list"),
 		ParseMode: models.ParseModeHTML,
 		LinkPreviewOptions: &models.LinkPreviewOptions{
 			IsDisabled: bot.True(),
@@ -72,61 +73,10 @@ func OpenOrdersHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 	})
 	if err != nil {
 		log.Error().Err(err).Send()
-		util.QuickMessage(ctx, b, chatID, "出错了，请联系客服！")
+		util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "common.error"))
 	}
 }
 
 func OpenOrdersHistoryHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
-	chatID := util.EffectId(update)
-	util.QuickMessage(ctx, b, chatID, "正在查询......")
-	userInfo, err := api.GetUserProfile(chatID)
-	if err != nil {
-		log.Error().Err(err).Send()
-		return
-	}
-
-	// list TgDefaultWallet openOrders history
-	dw, _, _ := callback.UserDefaultWalletInfo(userInfo)
-
-	history, err := api.ListHistoryOrders(cast.ToFloat64(dw.WalletId), userInfo)
-	if err != nil {
-		log.Error().Err(err).Send()
-		util.QuickMessage(ctx, b, chatID, "出错了，请联系客服")
-		return
-	}
-
-	if len(history.Data) == 0 {
-		util.QuickMessage(ctx, b, chatID, "没有最近委托记录！")
-		return
-	}
-
-	var msg string
-	msg, err = template.RanderOpenOrdersHistory(history.Data, 1, 8)
-	if err != nil {
-		log.Error().Err(err).Send()
-		msg = err.Error()
-	}
-
-	historyByte, err := json.Marshal(history.Data)
-	if err != nil {
-		log.Error().Err(err).Send()
-		util.QuickMessage(ctx, b, chatID, "没有最近委托记录！")
-		return
-	}
-
-	store.UserSetOrderHistory(chatID, historyByte)
-
-	store.BotMessageAdd()
-	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
-		ChatID:    chatID,
-		Text:      msg,
-		ParseMode: models.ParseModeHTML,
-		LinkPreviewOptions: &models.LinkPreviewOptions{
-			IsDisabled: bot.True(),
-		},
-	})
-	if err != nil {
-		log.Error().Err(err).Send()
-		util.QuickMessage(ctx, b, chatID, "出错了，请联系客服！")
-	}
+	callback.SendHistory(ctx, b, util.EffectId(update), callback.HistoryOrder)
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/hellodex/tradingbot/api"
 	"github.com/hellodex/tradingbot/entity"
 	"github.com/hellodex/tradingbot/handler/callback"
+	"github.com/hellodex/tradingbot/i18n"
 	"github.com/hellodex/tradingbot/logger"
 	"github.com/hellodex/tradingbot/queue"
 	"github.com/hellodex/tradingbot/session"
@@ -37,6 +38,7 @@ func UserStartReflashGet(userID int64) int {
 
 func StartHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 	chatId := util.EffectId(update)
+	lang := i18n.UserLang(chatId)
 	// delete cache key make it hard update
 	// store.Delete(chatId, api.UserProfilePrefix)
 	// delete redis profile cache
@@ -66,6 +68,7 @@ func StartHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 
 				botUserName := store.GetEnv(store.BOT_USERNAME)
 				t, err := template.RanderStart(
+					lang,
 					t.Symbol,
 					dW.Wallet,
 					nativeCoinBalance,
@@ -86,38 +89,43 @@ func StartHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 
 	if badUserDWInfo {
 		log.Debug().Str("err by", "badUserDWInfo").Msg("err in StartHandler")
-		util.QuickMessage(ctx, b, chatId, "出错了，请联系客服")
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 
 	kb := &models.InlineKeyboardMarkup{
 		InlineKeyboard: [][]models.InlineKeyboardButton{
 			{
-				entity.GetCallbackButton(entity.BUY_SELL),
-				entity.GetCallbackButton(entity.OrderTrade),
+				entity.GetCallbackButtonLang(entity.BUY_SELL, lang),
+				entity.GetCallbackButtonLang(entity.OrderTrade, lang),
 			},
 			{
-				entity.GetCallbackButton(entity.WALLET),
-				entity.GetCallbackButton(entity.ASSETS),
+				entity.GetCallbackButtonLang(entity.WALLET, lang),
+				entity.GetCallbackButtonLang(entity.ASSETS, lang),
 			},
 			{
-				entity.GetCallbackButton(entity.HistoryOrder),
-				entity.GetCallbackButton(entity.HistoryTransfer),
+				entity.GetCallbackButtonLang(entity.HistoryOrder, lang),
+				entity.GetCallbackButtonLang(entity.HistoryTransfer, lang),
 			},
 			{
-				util.UrlButton(entity.CallbackTextMap[entity.AppDownload], "https://hellodex.io/Download"),
-				entity.GetCallbackButton(entity.RefalshStartBalacne),
+				util.UrlButton(entity.CallbackTextLang(entity.AppDownload, lang), "https://hellodex.io/Download"),
+				entity.GetCallbackButtonLang(entity.RefalshStartBalacne, lang),
 			},
 			{
-				entity.GetCallbackButton(entity.SETTING),
-				util.UrlButton(entity.CallbackTextMap[entity.AdminUrl], "https://t.me/HelloDex_cn"),
+				entity.GetCallbackButtonLang(entity.SETTING, lang),
+				util.UrlButton(entity.CallbackTextLang(entity.AdminUrl, lang), "https://t.me/HelloDex_cn"),
 			},
 			{
-				entity.GetCallbackButton(entity.InviteButton),
-				entity.GetCallbackButton(entity.AIMonitorButton),
+				entity.GetCallbackButtonLang(entity.InviteButton, lang),
+				entity.GetCallbackButtonLang(entity.AIMonitorButton, lang),
+				entity.GetCallbackButtonLang(entity.TOKEN_FEED, lang),
 			},
 			{
-				util.UrlButton(entity.CallbackTextMap[entity.Other], "https://t.me/HelloDex_cn"),
+				entity.GetCallbackButtonLang(entity.ORDER_FOLLOW, lang),
+				entity.GetCallbackButtonLang(entity.SNIPER, lang),
+			},
+			{
+				util.UrlButton(entity.CallbackTextLang(entity.Other, lang), "https://t.me/HelloDex_cn"),
 			},
 		},
 	}
@@ -149,6 +157,7 @@ func StartHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
 
 func StartReflashInfo(ctx context.Context, b *bot.Bot, update *models.Update) {
 	chatId := util.EffectId(update)
+	lang := i18n.UserLang(chatId)
 	// delete cache key make it hard update
 	store.Delete(chatId, api.UserProfilePrefix)
 
@@ -175,6 +184,7 @@ func StartReflashInfo(ctx context.Context, b *bot.Bot, update *models.Update) {
 				usdTotalAmount := util.FormatNumber(t.TotalAmount)
 				botUserName := store.GetEnv(store.BOT_USERNAME)
 				t, err := template.RanderStart(
+					lang,
 					t.Symbol,
 					dW.Wallet,
 					nativeCoinBalance,
@@ -195,34 +205,38 @@ func StartReflashInfo(ctx context.Context, b *bot.Bot, update *models.Update) {
 
 	if badUserDWInfo {
 		log.Debug().Str("err by", "badUserDWInfo").Msg("err in StartHandler")
-		util.QuickMessage(ctx, b, chatId, "出错了，请联系客服")
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 
 	kb := &models.InlineKeyboardMarkup{
 		InlineKeyboard: [][]models.InlineKeyboardButton{
 			{
-				entity.GetCallbackButton(entity.BUY_SELL),
-				entity.GetCallbackButton(entity.OrderTrade),
+				entity.GetCallbackButtonLang(entity.BUY_SELL, lang),
+				entity.GetCallbackButtonLang(entity.OrderTrade, lang),
+			},
+			{
+				entity.GetCallbackButtonLang(entity.WALLET, lang),
+				entity.GetCallbackButtonLang(entity.ASSETS, lang),
 			},
 			{
-				entity.GetCallbackButton(entity.WALLET),
-				entity.GetCallbackButton(entity.ASSETS),
+				entity.GetCallbackButtonLang(entity.HistoryOrder, lang),
+				entity.GetCallbackButtonLang(entity.HistoryTransfer, lang),
 			},
 			{
-				entity.GetCallbackButton(entity.HistoryOrder),
-				entity.GetCallbackButton(entity.HistoryTransfer),
+				util.UrlButton(entity.CallbackTextLang(entity.AppDownload, lang), "https://hellodex.io/Download"),
+				entity.GetCallbackButtonLang(entity.RefalshStartBalacne, lang),
 			},
 			{
-				util.UrlButton(entity.CallbackTextMap[entity.AppDownload], "https://hellodex.io/Download"),
-				entity.GetCallbackButton(entity.RefalshStartBalacne),
+				entity.GetCallbackButtonLang(entity.SETTING, lang),
+				util.UrlButton(entity.CallbackTextLang(entity.AdminUrl, lang), "https://t.me/HelloDex_cn"),
 			},
 			{
-				entity.GetCallbackButton(entity.SETTING),
-				util.UrlButton(entity.CallbackTextMap[entity.AdminUrl], "https://t.me/HelloDex_cn"),
+				entity.GetCallbackButtonLang(entity.ORDER_FOLLOW, lang),
+				entity.GetCallbackButtonLang(entity.SNIPER, lang),
 			},
 			{
-				util.UrlButton(entity.CallbackTextMap[entity.Other], "https://t.me/HelloDex_cn"),
+				util.UrlButton(entity.CallbackTextLang(entity.Other, lang), "https://t.me/HelloDex_cn"),
 			},
 		},
 	}
@@ -298,13 +312,13 @@ func StartWrapMiddlewares(next bot.HandlerFunc) bot.HandlerFunc {
 								platform := args[len(args)-1]
 								userInfo, err := api.GetUserProfile(chatID)
 								if err != nil {
-									util.QuickMessage(ctx, b, chatID, fmt.Sprintf("出错了,%s", util.AdminUrl))
+									util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "common.error"))
 									log.Error().Err(err).Send()
 									return
 								}
-								templ, err := template.RanderStartLogin(userInfo, "https://example.com")
+								templ, err := template.RanderStartLogin(i18n.UserLang(chatID), userInfo, "https://example.com")
 								if err != nil {
-									util.QuickMessage(ctx, b, chatID, fmt.Sprintf("出错了,%s", util.AdminUrl))
+									util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "common.error"))
 									log.Error().Err(err).Send()
 									return
 								}
@@ -313,20 +327,20 @@ func StartWrapMiddlewares(next bot.HandlerFunc) bot.HandlerFunc {
 
 								matchPlatform := strings.Split(platform, "_")
 								if len(matchPlatform) == 0 {
-									util.QuickMessage(ctx, b, chatID, fmt.Sprintf("出错了,%s", util.AdminUrl))
+									util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "common.error"))
 									log.Error().Err(err).Send()
 									return
 								}
 
 								result, err := api.GetTg2WebLoginToken(chatID, matchPlatform[len(matchPlatform)-1])
 								if err != nil {
-									util.QuickMessage(ctx, b, chatID, fmt.Sprintf("出错了,%s", util.AdminUrl))
+									util.QuickMessage(ctx, b, chatID, i18n.L(chatID, "common.error"))
 									log.Error().Err(err).Send()
 									return
 								}
 								url := gjson.GetBytes(result.([]byte), "data.url").String()
 
-								button := util.UrlButton("登陆网站", url)
+								button := util.UrlButton(i18n.L(chatID, "start.login_web"), url)
 								MessageWithButton(ctx, b, chatID, templ, button)
 								log.Debug().MsgFunc(func() string {
 									return fmt.Sprintf("user: %d %s params: %s", chatID, "正在使用deeplink", update.Message.Text)
@@ -420,7 +434,7 @@ func MatchCodeAndToken(link string) (map[string]any, bool, error) {
 		iIndex := strings.Index(data, "I_")
 
 		result["type"] = 1
-		result["B"] = data[bIndex:iIndex]
+		result["B"] = strings.TrimSuffix(data[bIndex:iIndex], "_")
 		result["I"] = data[iIndex+2:]
 
 	} else if strings.Contains(data, "B_") {
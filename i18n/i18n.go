@@ -0,0 +1,159 @@
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/store"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	ZH = "zh"
+	EN = "en"
+
+	Default = ZH
+)
+
+// supported languages, first is default
+var Langs = []string{ZH, EN}
+
+// display name in language select
+var LangNames = map[string]string{
+	ZH: "🇨🇳 中文",
+	EN: "🇺🇸 English",
+}
+
+//go:embed locales/*.yml
+var localeFS embed.FS
+
+// lang -> key -> text
+var catalog = map[string]map[string]string{}
+
+// load locales/<lang>.yml
+var _ = func() any {
+	for _, lang := range Langs {
+		data, err := localeFS.ReadFile(path.Join("locales", lang+".yml"))
+		if err != nil {
+			panic(fmt.Sprintf("i18n: locale %s not found: %v", lang, err))
+		}
+		messages := map[string]string{}
+		if err := yaml.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: locale %s parse err: %v", lang, err))
+		}
+		catalog[lang] = messages
+	}
+	return nil
+}()
+
+func Supported(lang string) bool {
+	return slices.Contains(Langs, lang)
+}
+
+// telegram language_code like "en-US" to "en", unsupported return ""
+func Normalize(code string) string {
+	code = strings.ToLower(code)
+	if i := strings.IndexAny(code, "-_"); i > 0 {
+		code = code[:i]
+	}
+	if Supported(code) {
+		return code
+	}
+	return ""
+}
+
+// Has check key in lang catalog, without fallback
+func Has(lang string, key string) bool {
+	_, ok := catalog[lang][key]
+	return ok
+}
+
+// T translate key, fallback: lang -> Default -> key, args for fmt.Sprintf
+func T(lang string, key string, args ...any) string {
+	text, ok := catalog[lang][key]
+	if !ok {
+		text, ok = catalog[Default][key]
+	}
+	if !ok {
+		log.Debug().Str("lang", lang).Str("key", key).Msg("i18n key not found")
+		text = key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
+
+// L translate by user language
+func L(userID int64, key string, args ...any) string {
+	return T(UserLang(userID), key, args...)
+}
+
+const (
+	langCacheKey = "lang"
+	tgLangState  = "tgLang"
+	tgLangExpire = 30 * 24 * time.Hour
+)
+
+// UserLang: user setting > telegram language_code > Default
+func UserLang(userID int64) string {
+	if v, ok := store.Get(userID, langCacheKey); ok {
+		if lang, ok := v.(string); ok {
+			return lang
+		}
+	}
+
+	lang := Default
+	if l, ok := store.UserGetLang(userID); ok && Supported(l) {
+		lang = l
+	} else if tg, ok := store.RedisGetState(userID, tgLangState); ok && Normalize(tg) != "" {
+		lang = Normalize(tg)
+	}
+
+	store.Set(userID, langCacheKey, lang, 5*time.Minute)
+	return lang
+}
+
+func SetUserLang(userID int64, lang string) error {
+	if !Supported(lang) {
+		return fmt.Errorf("i18n: unsupported lang %s", lang)
+	}
+	if err := store.UserSetLang(userID, lang); err != nil {
+		return err
+	}
+	store.Delete(userID, langCacheKey)
+	return nil
+}
+
+// RememberTgLang save telegram language_code of user for fallback
+func RememberTgLang(update *models.Update) {
+	var from *models.User
+	switch {
+	case update.Message != nil:
+		from = update.Message.From
+	case update.CallbackQuery != nil:
+		from = &update.CallbackQuery.From
+	case update.InlineQuery != nil:
+		from = update.InlineQuery.From
+	}
+	if from == nil || from.LanguageCode == "" {
+		return
+	}
+
+	// only write redis when changed
+	if v, ok := store.Get(from.ID, tgLangState); ok && v == from.LanguageCode {
+		return
+	}
+	if err := store.RedisSetState(from.ID, tgLangState, from.LanguageCode, tgLangExpire); err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+	store.Set(from.ID, tgLangState, from.LanguageCode, 24*time.Hour)
+	store.Delete(from.ID, langCacheKey)
+}
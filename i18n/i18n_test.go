@@ -0,0 +1,26 @@
+package i18n
+
+import "testing"
+
+// every key must be in all languages, missing key shows the raw key to users of default language
+func TestCatalogKeys(t *testing.T) {
+	for _, lang := range Langs {
+		for key := range catalog[lang] {
+			for _, other := range Langs {
+				if !Has(other, key) {
+					t.Errorf("%s: key %q of %s is missing", other, key, lang)
+				}
+			}
+		}
+	}
+}
+
+func TestCallbackCodeText(t *testing.T) {
+	for _, key := range []string{"code::aiMonitorList", "code::addAiMonitor"} {
+		for _, lang := range Langs {
+			if got := T(lang, key); got == key {
+				t.Errorf("T(%s, %s) returns the key", lang, key)
+			}
+		}
+	}
+}
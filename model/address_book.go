@@ -0,0 +1,75 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hellodex/tradingbot/util"
+)
+
+const (
+	MaxAddressBookLen    = 20
+	MaxAddressLabelRunes = 20
+)
+
+var (
+	ErrAddressBookFull     = errors.New("address book is full")
+	ErrAddressBookExist    = errors.New("address already in address book")
+	ErrAddressBookFormat   = errors.New("address book input format err")
+	ErrAddressLabelTooLong = errors.New("address label too long")
+)
+
+type AddressEntry struct {
+	Id        string `json:"id"`
+	Label     string `json:"label"`
+	ChainCode string `json:"chainCode"`
+	Address   string `json:"address"`
+	AddedAt   int64  `json:"addedAt"`
+	UsableAt  int64  `json:"usableAt"` // 白名单冷却结束时间, 添加时按当时的冷却时间计算
+}
+
+func (a *AddressEntry) JsonB() ([]byte, error) {
+	return json.Marshal(a)
+}
+
+func (a *AddressEntry) Key() string {
+	return AddressKey(a.ChainCode, a.Address)
+}
+
+// chainCode:address, evm address is case insensitive
+func AddressKey(chainCode, address string) string {
+	if chainCode != "SOLANA" {
+		address = strings.ToLower(address)
+	}
+	return chainCode + ":" + address
+}
+
+// input: <label> <address>, label can contain space
+func ParseAddressEntryInput(chainCode, text string) (AddressEntry, error) {
+	fields := strings.Fields(text)
+	if len(fields) < 2 {
+		return AddressEntry{}, ErrAddressBookFormat
+	}
+
+	address := fields[len(fields)-1]
+	label := strings.Join(fields[:len(fields)-1], " ")
+	if utf8.RuneCountInString(label) > MaxAddressLabelRunes {
+		return AddressEntry{}, ErrAddressLabelTooLong
+	}
+	if err := util.CheckChainAddress(chainCode, address); err != nil {
+		return AddressEntry{}, err
+	}
+
+	now := time.Now()
+	return AddressEntry{
+		Id:        strconv.FormatInt(now.UnixMilli(), 36),
+		Label:     label,
+		ChainCode: chainCode,
+		Address:   address,
+		AddedAt:   now.Unix(),
+	}, nil
+}
@@ -13,6 +13,7 @@ type AISubscribeReqData struct {
 	MonitorType      string
 	TargetPrice      string
 	Data             string // for chg,buy,sell
+	Op               string // price op of local alert, empty is unchanged
 }
 
 func (sq *AISubscribeReqData) JsonB() ([]byte, error) {
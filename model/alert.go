@@ -0,0 +1,196 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// alert compare op
+const (
+	AlertAbove = "above"
+	AlertBelow = "below"
+	AlertCross = "cross"
+	// decided by start price, clear op override
+	AlertAuto = "auto"
+)
+
+// AlertRule a monitor rule evaluated by bot
+type AlertRule struct {
+	ChatId     int64
+	ChainCode  string
+	Token      string
+	Symbol     string
+	Type       string // price, chg, buy, sell
+	Op         string
+	Value      decimal.Decimal
+	StartPrice decimal.Decimal
+	NoticeType int64
+}
+
+// AlertTick price or trade of token
+type AlertTick struct {
+	ChainCode string
+	Token     string
+	Symbol    string
+	Price     decimal.Decimal
+	Chg       decimal.Decimal // percent, used when start price unknown
+	Trade     bool
+	IsBuy     bool
+	Amount    decimal.Decimal
+	Volume    decimal.Decimal // trade volume in usd
+	// market stats, only polled tick has them
+	Polled    bool
+	MarketCap decimal.Decimal
+	Holders   decimal.Decimal
+	Volume1h  decimal.Decimal
+	Chg1h     decimal.Decimal
+	Time      time.Time
+}
+
+func (t AlertTick) TokenKey() string {
+	return AddressKey(t.ChainCode, t.Token)
+}
+
+// Stat value of compound alert metric
+func (t AlertTick) Stat(metric string) (decimal.Decimal, bool) {
+	if !t.Polled {
+		return decimal.Zero, false
+	}
+	switch metric {
+	case MetricPrice:
+		return t.Price, t.Price.IsPositive()
+	case MetricMcap:
+		return t.MarketCap, true
+	case MetricHolders:
+		return t.Holders, true
+	case MetricVolume1h:
+		return t.Volume1h, true
+	case MetricChg1h:
+		return t.Chg1h, true
+	}
+	return decimal.Zero, false
+}
+
+func (r AlertRule) TokenKey() string {
+	return AddressKey(r.ChainCode, r.Token)
+}
+
+// Key same token and type with different value is a different rule
+func (r AlertRule) Key() string {
+	return fmt.Sprintf("%s:%s:%s", r.TokenKey(), r.Type, r.Value.String())
+}
+
+// AlertOpKey field of op override, one rule per token and type in backend
+func AlertOpKey(chainCode, token, monitorType string) string {
+	return fmt.Sprintf("%s:%s", AddressKey(chainCode, token), monitorType)
+}
+
+// RuleFromSubscribe convert backend subscribe to rule, op overrides price rule op
+func RuleFromSubscribe(chatId int64, s TokenSubscribeInfo, op string) (AlertRule, bool) {
+	r := AlertRule{
+		ChatId:     chatId,
+		ChainCode:  s.ChainCode,
+		Token:      s.BaseAddress,
+		Symbol:     s.Symbol,
+		Type:       s.Type,
+		Op:         AlertAbove,
+		NoticeType: s.NoticeType,
+	}
+	r.StartPrice, _ = decimal.NewFromString(s.StartPrice)
+
+	var err error
+	switch s.Type {
+	case "price":
+		r.Value, err = decimal.NewFromString(s.TargetPrice)
+		// 目标价低于设置时的价格, 跌破提醒
+		if r.StartPrice.GreaterThan(r.Value) {
+			r.Op = AlertBelow
+		}
+		if op != "" && op != AlertAuto {
+			r.Op = op
+		}
+	case "chg", "buy", "sell":
+		r.Value, err = decimal.NewFromString(s.Data)
+	default:
+		return r, false
+	}
+	if err != nil || r.Value.IsNegative() {
+		return r, false
+	}
+	return r, true
+}
+
+// Metric value of tick the rule compares, false if tick is not for this rule
+func (r AlertRule) Metric(t AlertTick) (decimal.Decimal, bool) {
+	switch r.Type {
+	case "price":
+		return t.Price, !t.Trade && t.Price.IsPositive()
+	case "chg":
+		if t.Trade {
+			return decimal.Zero, false
+		}
+		// 相对设置时的价格, 涨跌都算
+		if r.StartPrice.IsPositive() && t.Price.IsPositive() {
+			return t.Price.Sub(r.StartPrice).Div(r.StartPrice).Mul(decimal.NewFromInt(100)).Abs(), true
+		}
+		return t.Chg.Abs(), true
+	case "buy":
+		return t.Volume, t.Trade && t.IsBuy
+	case "sell":
+		return t.Volume, t.Trade && !t.IsBuy
+	}
+	return decimal.Zero, false
+}
+
+// Eval above/below trigger when the condition becomes true, cross trigger on every side change
+func (r AlertRule) Eval(prev, cur decimal.Decimal, hasPrev bool) bool {
+	switch r.Op {
+	case AlertAbove:
+		return cur.GreaterThanOrEqual(r.Value) && (!hasPrev || prev.LessThan(r.Value))
+	case AlertBelow:
+		return cur.LessThanOrEqual(r.Value) && (!hasPrev || prev.GreaterThan(r.Value))
+	case AlertCross:
+		if !hasPrev {
+			return false
+		}
+		return (prev.LessThan(r.Value) && cur.GreaterThanOrEqual(r.Value)) ||
+			(prev.GreaterThan(r.Value) && cur.LessThanOrEqual(r.Value))
+	}
+	return false
+}
+
+func (r AlertRule) CanNotify(now, last time.Time) bool {
+	return CanNotice(r.NoticeType, now, last)
+}
+
+// CanNotice check notice frequency, last is zero if never notified
+func CanNotice(noticeType int64, now, last time.Time) bool {
+	switch noticeType {
+	case 1: // once
+		return last.IsZero()
+	case 2: // daily
+		return last.IsZero() || now.Sub(last) >= 24*time.Hour
+	case 3: // every time
+		return true
+	}
+	return false
+}
+
+// ParseAlertTarget parse price input with optional op prefix, ">1.2" above, "<1.2" below, "~1.2" cross
+func ParseAlertTarget(text string) (op string, value string) {
+	text = strings.TrimSpace(text)
+	switch {
+	case strings.HasPrefix(text, ">"):
+		op = AlertAbove
+	case strings.HasPrefix(text, "<"):
+		op = AlertBelow
+	case strings.HasPrefix(text, "~"):
+		op = AlertCross
+	default:
+		return AlertAuto, text
+	}
+	return op, strings.TrimSpace(text[1:])
+}
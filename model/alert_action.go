@@ -0,0 +1,97 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	AlertActionBuy  = "buy"
+	AlertActionSell = "sell"
+
+	// 每个用户的自动交易数量上限
+	MaxAlertActionLen = 20
+)
+
+// 每条规则每天最多执行的次数
+var AlertActionCaps = []int{1, 2, 3, 5, 10}
+
+var (
+	ErrAlertActionFull     = errors.New("too many alert actions")
+	ErrAlertActionNoWallet = errors.New("no wallet on chain")
+	ErrAlertActionNumber   = errors.New("invalid number")
+	ErrAlertActionPercent  = errors.New("percent out of range")
+)
+
+// AlertAction auto-trade executed when alert rule of token and type triggers
+type AlertAction struct {
+	Id        string `json:"id"`
+	ChainCode string `json:"chainCode"`
+	Token     string `json:"token"`
+	Symbol    string `json:"symbol"`
+	// monitor type of rule: price, chg, buy, sell
+	Type     string `json:"type"`
+	WalletId string `json:"walletId"`
+	Side     string `json:"side"`
+	// native coin amount for buy, percent of holding for sell
+	Amount   string `json:"amount"`
+	DailyCap int    `json:"dailyCap"`
+	Enabled  bool   `json:"enabled"`
+
+	CreatedAt int64 `json:"createdAt"`
+}
+
+func NewAlertAction(chainCode, token, symbol, monitorType, walletId string) AlertAction {
+	now := time.Now()
+	return AlertAction{
+		Id:        strconv.FormatInt(now.UnixMilli(), 36),
+		ChainCode: chainCode,
+		Token:     token,
+		Symbol:    symbol,
+		Type:      monitorType,
+		WalletId:  walletId,
+		Side:      AlertActionBuy,
+		Amount:    "0.1",
+		DailyCap:  AlertActionCaps[0],
+		CreatedAt: now.Unix(),
+	}
+}
+
+func (a *AlertAction) JsonB() ([]byte, error) {
+	return json.Marshal(a)
+}
+
+// RuleKey same as AlertOpKey, one rule per token and type
+func (a *AlertAction) RuleKey() string {
+	return AlertOpKey(a.ChainCode, a.Token, a.Type)
+}
+
+func (a *AlertAction) IsBuy() bool {
+	return a.Side == AlertActionBuy
+}
+
+// ToggleSide switch buy and sell, amount is reset to default of side
+func (a *AlertAction) ToggleSide() {
+	if a.IsBuy() {
+		a.Side, a.Amount = AlertActionSell, "50"
+		return
+	}
+	a.Side, a.Amount = AlertActionBuy, "0.1"
+}
+
+func (a *AlertAction) SetAmount(input string) error {
+	v, err := decimal.NewFromString(strings.TrimSuffix(strings.TrimSpace(input), "%"))
+	if err != nil || !v.IsPositive() {
+		return ErrAlertActionNumber
+	}
+	if !a.IsBuy() && v.GreaterThan(decimal.NewFromInt(100)) {
+		return ErrAlertActionPercent
+	}
+	a.Amount = v.String()
+	return nil
+}
@@ -0,0 +1,143 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot/models"
+)
+
+// options of alert delivery, in minute
+var (
+	AlertCooldowns = []int{0, 5, 15, 30, 60}
+	AlertDigests   = []int{0, 30, 60, 240}
+)
+
+var (
+	ErrAlertQuietHours = errors.New("invalid quiet hours")
+	ErrAlertTimezone   = errors.New("invalid timezone")
+)
+
+// AlertDelivery how alerts are delivered to user
+type AlertDelivery struct {
+	// same token and rule is not pushed again in cooldown
+	CooldownMin int `json:"cooldownMin"`
+	// local hour [start, end), off if start equals end
+	QuietStart int `json:"quietStart"`
+	QuietEnd   int `json:"quietEnd"`
+	// utc offset in hour
+	UtcOffset int `json:"utcOffset"`
+	// batch alerts into one message every DigestMin, 0 is off
+	DigestMin int `json:"digestMin"`
+}
+
+func (d *AlertDelivery) JsonB() ([]byte, error) {
+	return json.Marshal(d)
+}
+
+func (d AlertDelivery) HasQuietHours() bool {
+	return d.QuietStart != d.QuietEnd
+}
+
+// Quiet whether now is in quiet hours of user
+func (d AlertDelivery) Quiet(now time.Time) bool {
+	if !d.HasQuietHours() {
+		return false
+	}
+	h := now.UTC().Add(time.Duration(d.UtcOffset) * time.Hour).Hour()
+	if d.QuietStart < d.QuietEnd {
+		return h >= d.QuietStart && h < d.QuietEnd
+	}
+	// 跨零点, 例如 22-8
+	return h >= d.QuietStart || h < d.QuietEnd
+}
+
+// Queued alerts are not sent immediately
+func (d AlertDelivery) Queued(now time.Time) bool {
+	return d.DigestMin > 0 || d.Quiet(now)
+}
+
+// Day date of now in user timezone, daily cap is counted by it
+func (d AlertDelivery) Day(now time.Time) string {
+	return now.UTC().Add(time.Duration(d.UtcOffset) * time.Hour).Format(time.DateOnly)
+}
+
+// ParseQuietHours parse "22-8", "0" or "off" turns quiet hours off
+func ParseQuietHours(text string) (start, end int, err error) {
+	text = strings.TrimSpace(strings.ToLower(text))
+	if text == "0" || text == "off" {
+		return 0, 0, nil
+	}
+	from, to, ok := strings.Cut(text, "-")
+	if !ok {
+		return 0, 0, ErrAlertQuietHours
+	}
+	start, err1 := strconv.Atoi(strings.TrimSpace(from))
+	end, err2 := strconv.Atoi(strings.TrimSpace(to))
+	if err1 != nil || err2 != nil || start < 0 || start > 23 || end < 0 || end > 23 || start == end {
+		return 0, 0, ErrAlertQuietHours
+	}
+	return start, end, nil
+}
+
+// ParseUtcOffset parse "UTC+8", "+8" or "-5"
+func ParseUtcOffset(text string) (int, error) {
+	text = strings.TrimSpace(strings.ToUpper(text))
+	text = strings.TrimPrefix(strings.TrimPrefix(text, "UTC"), "GMT")
+	offset, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(text), "+"))
+	if err != nil || offset < -12 || offset > 14 {
+		return 0, ErrAlertTimezone
+	}
+	return offset, nil
+}
+
+// AlertPush alert waiting for delivery
+type AlertPush struct {
+	ChatId int64 `json:"chatId"`
+	// cooldown key, token and rule
+	Key  string `json:"key"`
+	Text string `json:"text"`
+	// backend monitor of push buttons, nil if not from backend monitor
+	Req *PusherHandlerReqData `json:"req,omitempty"`
+	// keyboard of backend push kind, nil is keyboard of AI monitor
+	Markup *models.InlineKeyboardMarkup `json:"markup,omitempty"`
+	At     int64                        `json:"at"`
+	// telegram id of bot, 0 uses push bot of user
+	BotId int64 `json:"botId,omitempty"`
+	// used by alert history
+	Symbol string `json:"symbol"`
+	Type   string `json:"type"`
+	Value  string `json:"value"`
+	// failed sends, retried with backoff
+	Attempt int `json:"attempt,omitempty"`
+}
+
+// Record alert record of push with delivery status
+func (p AlertPush) Record(status string, err error) AlertRecord {
+	r := AlertRecord{
+		Key:    p.Key,
+		Symbol: p.Symbol,
+		Type:   p.Type,
+		Value:  p.Value,
+		At:     p.At,
+		Status: status,
+	}
+	if err != nil {
+		r.Error = err.Error()
+	}
+	return r
+}
+
+// PushDead push failed permanently, e.g. user blocked the bot
+type PushDead struct {
+	Push  AlertPush `json:"push"`
+	Error string    `json:"error"`
+	At    int64     `json:"at"`
+}
+
+func (d *PushDead) JsonB() ([]byte, error) {
+	return json.Marshal(d)
+}
@@ -0,0 +1,235 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// 每个用户的组合监控数量上限
+	MaxCompoundAlertLen = 10
+	// 一个组合监控最多的条件数量
+	MaxAlertConditions = 5
+	// 括号嵌套层数
+	maxAlertExprDepth = 3
+)
+
+// compound alert metrics
+const (
+	MetricPrice    = "price"
+	MetricMcap     = "mcap"
+	MetricHolders  = "holders"
+	MetricVolume1h = "vol1h"
+	MetricChg1h    = "chg1h"
+)
+
+var AlertMetrics = []string{MetricPrice, MetricMcap, MetricHolders, MetricVolume1h, MetricChg1h}
+
+// compound alert logic
+const (
+	AlertAnd = "and"
+	AlertOr  = "or"
+)
+
+var AlertOps = []string{AlertAbove, AlertBelow, AlertCross}
+
+// notice type same as backend: 0 off, 1 once, 2 daily, 3 every time
+var AlertNoticeTypes = []int64{1, 2, 3}
+
+var (
+	ErrCompoundAlertFull  = errors.New("too many compound alerts")
+	ErrCompoundAlertToken = errors.New("token not found")
+)
+
+// AlertExpr expression of compound alert, leaf if Logic is empty
+type AlertExpr struct {
+	Logic    string      `json:"logic,omitempty"`
+	Children []AlertExpr `json:"children,omitempty"`
+	Metric   string      `json:"metric,omitempty"`
+	Op       string      `json:"op,omitempty"`
+	Value    string      `json:"value,omitempty"`
+}
+
+func (x AlertExpr) IsLeaf() bool {
+	return x.Logic == ""
+}
+
+func (x AlertExpr) IsEmpty() bool {
+	return x.IsLeaf() && x.Metric == ""
+}
+
+// Leaves count of conditions
+func (x AlertExpr) Leaves() int {
+	if x.IsLeaf() {
+		if x.IsEmpty() {
+			return 0
+		}
+		return 1
+	}
+	n := 0
+	for _, c := range x.Children {
+		n += c.Leaves()
+	}
+	return n
+}
+
+// Add join condition with logic, same logic is appended to current group
+func (x AlertExpr) Add(logic string, leaf AlertExpr) AlertExpr {
+	if x.IsEmpty() {
+		return leaf
+	}
+	if x.Logic == logic {
+		x.Children = append(slices.Clone(x.Children), leaf)
+		return x
+	}
+	return AlertExpr{Logic: logic, Children: []AlertExpr{x, leaf}}
+}
+
+// Uses whether any condition is on metric
+func (x AlertExpr) Uses(metric string) bool {
+	if x.IsLeaf() {
+		return x.Metric == metric
+	}
+	return slices.ContainsFunc(x.Children, func(c AlertExpr) bool { return c.Uses(metric) })
+}
+
+func (x AlertExpr) Vaild() bool {
+	return x.vaild(1)
+}
+
+func (x AlertExpr) vaild(depth int) bool {
+	if depth > maxAlertExprDepth {
+		return false
+	}
+	if x.IsLeaf() {
+		if !slices.Contains(AlertMetrics, x.Metric) || !slices.Contains(AlertOps, x.Op) {
+			return false
+		}
+		v, err := decimal.NewFromString(x.Value)
+		// 涨跌幅可以是负数
+		if err != nil || (v.IsNegative() && x.Metric != MetricChg1h) {
+			return false
+		}
+		return true
+	}
+	if x.Logic != AlertAnd && x.Logic != AlertOr {
+		return false
+	}
+	if len(x.Children) < 2 {
+		return false
+	}
+	for _, c := range x.Children {
+		if !c.vaild(depth + 1) {
+			return false
+		}
+	}
+	return x.Leaves() <= MaxAlertConditions
+}
+
+// Eval whether the expression holds on cur, prev is used by cross
+func (x AlertExpr) Eval(prev, cur AlertTick, hasPrev bool) bool {
+	if x.IsLeaf() {
+		v, _ := decimal.NewFromString(x.Value)
+		c, ok := cur.Stat(x.Metric)
+		if !ok {
+			return false
+		}
+		switch x.Op {
+		case AlertAbove:
+			return c.GreaterThanOrEqual(v)
+		case AlertBelow:
+			return c.LessThanOrEqual(v)
+		case AlertCross:
+			p, ok := prev.Stat(x.Metric)
+			if !hasPrev || !ok {
+				return false
+			}
+			return AlertRule{Op: AlertCross, Value: v}.Eval(p, c, true)
+		}
+		return false
+	}
+	for _, c := range x.Children {
+		hold := c.Eval(prev, cur, hasPrev)
+		if x.Logic == AlertAnd && !hold {
+			return false
+		}
+		if x.Logic == AlertOr && hold {
+			return true
+		}
+	}
+	return x.Logic == AlertAnd
+}
+
+// Format readable expression, leaf is formatted by fn
+func (x AlertExpr) Format(fn func(leaf AlertExpr) string, and, or string) string {
+	if x.IsLeaf() {
+		return fn(x)
+	}
+	sep := " " + and + " "
+	if x.Logic == AlertOr {
+		sep = " " + or + " "
+	}
+	parts := make([]string, 0, len(x.Children))
+	for _, c := range x.Children {
+		s := c.Format(fn, and, or)
+		if !c.IsLeaf() {
+			s = "(" + s + ")"
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, sep)
+}
+
+// CompoundAlert alert with compound conditions, evaluated by local alert engine
+type CompoundAlert struct {
+	Id          string    `json:"id"`
+	ChainCode   string    `json:"chainCode"`
+	Token       string    `json:"token"`
+	Symbol      string    `json:"symbol"`
+	PairAddress string    `json:"pairAddress"`
+	Expr        AlertExpr `json:"expr"`
+	NoticeType  int64     `json:"noticeType"`
+	CreatedAt   int64     `json:"createdAt"`
+}
+
+func NewCompoundAlert(chainCode, token, symbol, pair string) CompoundAlert {
+	now := time.Now()
+	return CompoundAlert{
+		Id:          strconv.FormatInt(now.UnixMilli(), 36),
+		ChainCode:   chainCode,
+		Token:       token,
+		Symbol:      symbol,
+		PairAddress: pair,
+		NoticeType:  1,
+		CreatedAt:   now.Unix(),
+	}
+}
+
+func (c *CompoundAlert) JsonB() ([]byte, error) {
+	return json.Marshal(c)
+}
+
+func (c *CompoundAlert) TokenKey() string {
+	return AddressKey(c.ChainCode, c.Token)
+}
+
+// NoticeKey field of last notice time
+func (c *CompoundAlert) NoticeKey() string {
+	return "c:" + c.Id
+}
+
+func (c *CompoundAlert) Vaild() bool {
+	if c.Id == "" || c.ChainCode == "" || c.Token == "" || c.Symbol == "" {
+		return false
+	}
+	if !slices.Contains(AlertNoticeTypes, c.NoticeType) {
+		return false
+	}
+	return c.Expr.Vaild()
+}
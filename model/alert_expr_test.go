@@ -0,0 +1,84 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func leaf(metric, op, value string) AlertExpr {
+	return AlertExpr{Metric: metric, Op: op, Value: value}
+}
+
+func TestAlertExprVaild(t *testing.T) {
+	price := leaf(MetricPrice, AlertAbove, "1.5")
+	tests := []struct {
+		name string
+		x    AlertExpr
+		ok   bool
+	}{
+		{"leaf", price, true},
+		{"unknown metric", leaf("tvl", AlertAbove, "1"), false},
+		{"unknown op", leaf(MetricPrice, "eq", "1"), false},
+		{"bad value", leaf(MetricPrice, AlertAbove, "x"), false},
+		{"negative price", leaf(MetricPrice, AlertBelow, "-1"), false},
+		{"negative change", leaf(MetricChg1h, AlertBelow, "-10"), true},
+		{"and", AlertExpr{Logic: AlertAnd, Children: []AlertExpr{price, leaf(MetricHolders, AlertAbove, "100")}}, true},
+		{"one child", AlertExpr{Logic: AlertAnd, Children: []AlertExpr{price}}, false},
+		{"unknown logic", AlertExpr{Logic: "xor", Children: []AlertExpr{price, price}}, false},
+		{"invalid child", AlertExpr{Logic: AlertOr, Children: []AlertExpr{price, leaf("tvl", AlertAbove, "1")}}, false},
+		{"too many conditions", AlertExpr{Logic: AlertOr, Children: []AlertExpr{price, price, price, price, price, price}}, false},
+		{"too deep", AlertExpr{Logic: AlertAnd, Children: []AlertExpr{
+			price,
+			{Logic: AlertOr, Children: []AlertExpr{
+				price,
+				{Logic: AlertAnd, Children: []AlertExpr{
+					price,
+					{Logic: AlertOr, Children: []AlertExpr{price, price}},
+				}},
+			}},
+		}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.x.Vaild(); got != tt.ok {
+				t.Errorf("Vaild() = %v, want %v", got, tt.ok)
+			}
+		})
+	}
+}
+
+func TestAlertExprEval(t *testing.T) {
+	tick := func(price, holders int64) AlertTick {
+		return AlertTick{Polled: true, Price: decimal.NewFromInt(price), Holders: decimal.NewFromInt(holders)}
+	}
+	priceAbove := leaf(MetricPrice, AlertAbove, "2")
+	holdersBelow := leaf(MetricHolders, AlertBelow, "100")
+	tests := []struct {
+		name    string
+		x       AlertExpr
+		prev    AlertTick
+		cur     AlertTick
+		hasPrev bool
+		want    bool
+	}{
+		{"above", priceAbove, AlertTick{}, tick(2, 50), false, true},
+		{"not above", priceAbove, AlertTick{}, tick(1, 50), false, false},
+		{"not polled", priceAbove, AlertTick{}, AlertTick{Price: decimal.NewFromInt(3)}, false, false},
+		{"and holds", AlertExpr{Logic: AlertAnd, Children: []AlertExpr{priceAbove, holdersBelow}}, AlertTick{}, tick(3, 50), false, true},
+		{"and fails", AlertExpr{Logic: AlertAnd, Children: []AlertExpr{priceAbove, holdersBelow}}, AlertTick{}, tick(3, 500), false, false},
+		{"or holds", AlertExpr{Logic: AlertOr, Children: []AlertExpr{priceAbove, holdersBelow}}, AlertTick{}, tick(1, 50), false, true},
+		{"or fails", AlertExpr{Logic: AlertOr, Children: []AlertExpr{priceAbove, holdersBelow}}, AlertTick{}, tick(1, 500), false, false},
+		{"cross up", leaf(MetricPrice, AlertCross, "2"), tick(1, 0), tick(3, 0), true, true},
+		{"cross down", leaf(MetricPrice, AlertCross, "2"), tick(3, 0), tick(1, 0), true, true},
+		{"no cross", leaf(MetricPrice, AlertCross, "2"), tick(3, 0), tick(4, 0), true, false},
+		{"cross without prev", leaf(MetricPrice, AlertCross, "2"), AlertTick{}, tick(3, 0), false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.x.Eval(tt.prev, tt.cur, tt.hasPrev); got != tt.want {
+				t.Errorf("Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
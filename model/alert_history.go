@@ -0,0 +1,49 @@
+package model
+
+import "encoding/json"
+
+// delivery status of alert record
+const (
+	AlertStatusSent       = "sent"
+	AlertStatusFailed     = "failed"
+	AlertStatusSuppressed = "suppressed"
+	AlertStatusDigest     = "digest"
+)
+
+// type of compound alert record
+const AlertTypeCompound = "compound"
+
+const (
+	// 每个用户保留的提醒记录数量
+	MaxAlertHistoryLen   = 200
+	AlertHistoryPageSize = 10
+	// 规则统计最多展示的数量
+	MaxAlertStatLen = 20
+)
+
+// AlertRecord a triggered alert and how it was delivered
+type AlertRecord struct {
+	// rule key, same as cooldown key
+	Key    string `json:"key"`
+	Symbol string `json:"symbol"`
+	// monitor type or compound
+	Type string `json:"type"`
+	// metric value when triggered
+	Value string `json:"value"`
+	// trigger time
+	At     int64  `json:"at"`
+	Status string `json:"status"`
+	// telegram error of failed delivery
+	Error string `json:"error,omitempty"`
+}
+
+func (r *AlertRecord) JsonB() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+// AlertStat trigger stats of rule
+type AlertStat struct {
+	Count int64
+	// last record of rule
+	Last AlertRecord
+}
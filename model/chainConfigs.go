@@ -1,6 +1,11 @@
 package model
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/hellodex/tradingbot/util"
+)
 
 func UnmarshalChainConfigs(data []byte) (ChainConfigs, error) {
 	var r ChainConfigs
@@ -43,3 +48,17 @@ type ChainConfig struct {
 	Wrapped        string `json:"wrapped"`
 	WssRPC         string `json:"wssRpc"`
 }
+
+func (r *ChainConfigs) Get(chainCode string) (ChainConfig, bool) {
+	for _, c := range r.Data {
+		if c.ChainCode == chainCode {
+			return c, true
+		}
+	}
+	return ChainConfig{}, false
+}
+
+// native coin or wrapped native token of chain
+func (c *ChainConfig) IsNative(address string) bool {
+	return strings.EqualFold(address, c.Wrapped) || strings.EqualFold(address, c.SymbolAddress) || util.IsNativeCoion(address)
+}
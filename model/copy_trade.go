@@ -0,0 +1,204 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hellodex/tradingbot/util"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// 固定金额 / 按带单钱包买入金额的比例
+	CopyModeFixed = "fixed"
+	CopyModeRatio = "ratio"
+
+	MaxCopyTradeLen     = 10
+	MaxCopyBlacklistLen = 20
+	MaxCopyRatio        = 1000
+)
+
+// 可编辑的字段, 用在 callback data 中, 越短越好
+const (
+	CopyFieldAmount    = "a"
+	CopyFieldRatio     = "r"
+	CopyFieldMax       = "x"
+	CopyFieldLiquidity = "l"
+	CopyFieldBlacklist = "b"
+)
+
+// 可选的跟单延迟(秒)
+var CopyTradeDelays = []int{0, 5, 15, 30, 60}
+
+var (
+	ErrCopyTradeFull      = errors.New("copy trade list is full")
+	ErrCopyTradeExist     = errors.New("already following this wallet")
+	ErrCopyTradeAddress   = errors.New("invalid wallet address")
+	ErrCopyTradeNoWallet  = errors.New("no wallet on chain")
+	ErrCopyTradeNumber    = errors.New("invalid number")
+	ErrCopyTradeRatio     = errors.New("ratio out of range")
+	ErrCopyBlacklistFull  = errors.New("copy trade blacklist is full")
+	ErrCopyBlacklistToken = errors.New("invalid token address")
+)
+
+// follow a wallet and mirror its swaps
+type CopyTrade struct {
+	Id        string `json:"id"`
+	ChainCode string `json:"chainCode"`
+	Leader    string `json:"leader"`
+	// 用来跟单的钱包
+	WalletId string `json:"walletId"`
+
+	Mode string `json:"mode"`
+	// quote token amount for fixed mode
+	Amount string `json:"amount"`
+	// percent of leader's buy amount for ratio mode
+	Ratio string `json:"ratio"`
+	// 单笔最大买入, 0 表示不限制
+	MaxPerTrade string `json:"maxPerTrade"`
+	// min tvl in usd
+	MinLiquidity string   `json:"minLiquidity"`
+	Blacklist    []string `json:"blacklist"`
+	DelaySec     int      `json:"delaySec"`
+	FollowSell   bool     `json:"followSell"`
+	Enabled      bool     `json:"enabled"`
+	CreatedAt    int64    `json:"createdAt"`
+}
+
+func NewCopyTrade(chainCode, leader, walletId string) CopyTrade {
+	now := time.Now()
+	return CopyTrade{
+		Id:           strconv.FormatInt(now.UnixMilli(), 36),
+		ChainCode:    chainCode,
+		Leader:       leader,
+		WalletId:     walletId,
+		Mode:         CopyModeFixed,
+		Amount:       "0.1",
+		Ratio:        "100",
+		MaxPerTrade:  "1",
+		MinLiquidity: "0",
+		FollowSell:   true,
+		Enabled:      true,
+		CreatedAt:    now.Unix(),
+	}
+}
+
+func (c *CopyTrade) JsonB() ([]byte, error) {
+	return json.Marshal(c)
+}
+
+func (c *CopyTrade) LeaderKey() string {
+	return AddressKey(c.ChainCode, c.Leader)
+}
+
+func (c *CopyTrade) Blacklisted(token string) bool {
+	key := AddressKey(c.ChainCode, token)
+	for _, t := range c.Blacklist {
+		if AddressKey(c.ChainCode, t) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// BuyAmount quote amount to buy when leader spent leaderQuote, zero means skip
+func (c *CopyTrade) BuyAmount(leaderQuote decimal.Decimal) decimal.Decimal {
+	var amount decimal.Decimal
+	switch c.Mode {
+	case CopyModeRatio:
+		ratio, _ := decimal.NewFromString(c.Ratio)
+		amount = leaderQuote.Mul(ratio).Div(decimal.NewFromInt(100))
+	default:
+		amount, _ = decimal.NewFromString(c.Amount)
+	}
+
+	max, _ := decimal.NewFromString(c.MaxPerTrade)
+	if max.IsPositive() && amount.GreaterThan(max) {
+		amount = max
+	}
+	if !amount.IsPositive() {
+		return decimal.Zero
+	}
+	return amount
+}
+
+func (c *CopyTrade) LiquidityOK(tvl string) bool {
+	return liquidityOK(c.MinLiquidity, tvl)
+}
+
+// min <= 0 means no limit
+func liquidityOK(min, tvl string) bool {
+	m, _ := decimal.NewFromString(min)
+	if !m.IsPositive() {
+		return true
+	}
+	v, err := decimal.NewFromString(tvl)
+	return err == nil && v.GreaterThanOrEqual(m)
+}
+
+// SetField update field by user input, blacklist input toggles the tokens
+func (c *CopyTrade) SetField(field, input string) error {
+	input = strings.TrimSpace(input)
+	if field == CopyFieldBlacklist {
+		return c.toggleBlacklist(input)
+	}
+
+	v, err := decimal.NewFromString(input)
+	if err != nil || v.IsNegative() {
+		return ErrCopyTradeNumber
+	}
+	switch field {
+	case CopyFieldAmount:
+		if !v.IsPositive() {
+			return ErrCopyTradeNumber
+		}
+		c.Amount = v.String()
+	case CopyFieldRatio:
+		if !v.IsPositive() || v.GreaterThan(decimal.NewFromInt(MaxCopyRatio)) {
+			return ErrCopyTradeRatio
+		}
+		c.Ratio = v.String()
+	case CopyFieldMax:
+		c.MaxPerTrade = v.String()
+	case CopyFieldLiquidity:
+		c.MinLiquidity = v.String()
+	default:
+		return ErrCopyTradeNumber
+	}
+	return nil
+}
+
+func (c *CopyTrade) toggleBlacklist(input string) error {
+	tokens := strings.FieldsFunc(input, func(r rune) bool {
+		return r == ' ' || r == ',' || r == '\n'
+	})
+	if len(tokens) == 0 {
+		return ErrCopyBlacklistToken
+	}
+
+	for _, t := range tokens {
+		if err := util.CheckChainAddress(c.ChainCode, t); err != nil {
+			return ErrCopyBlacklistToken
+		}
+		key := AddressKey(c.ChainCode, t)
+		idx := -1
+		for i, b := range c.Blacklist {
+			if AddressKey(c.ChainCode, b) == key {
+				idx = i
+				break
+			}
+		}
+		if idx >= 0 {
+			c.Blacklist = append(c.Blacklist[:idx], c.Blacklist[idx+1:]...)
+			continue
+		}
+		if len(c.Blacklist) >= MaxCopyBlacklistLen {
+			return ErrCopyBlacklistFull
+		}
+		c.Blacklist = append(c.Blacklist, t)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+// group chat settings, only admin can change
+type GroupSetting struct {
+	GroupId       int64    `json:"groupId"`
+	AllowedChains []string `json:"allowedChains"` // empty is all chains
+	Quiet         bool     `json:"quiet"`         // 安静模式: 只在 @机器人 或 /p 命令时回复
+}
+
+func (g *GroupSetting) JsonB() ([]byte, error) {
+	return json.Marshal(g)
+}
+
+func (g *GroupSetting) ChainAllowed(chainCode string) bool {
+	if len(g.AllowedChains) == 0 {
+		return true
+	}
+	return slices.Contains(g.AllowedChains, chainCode)
+}
@@ -0,0 +1,133 @@
+package model
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+const (
+	HistorySideAll  = ""
+	HistorySideBuy  = "buy"
+	HistorySideSell = "sell"
+
+	HistoryNativeToken = "native"
+)
+
+var (
+	HistorySides     = []string{HistorySideAll, HistorySideBuy, HistorySideSell}
+	HistoryRangeDays = []int{0, 1, 7, 30}
+)
+
+// filter and page of history list message
+type HistoryFilter struct {
+	Page     int    `json:"page"`
+	WalletId string `json:"walletId"` // empty is default wallet
+	Token    string `json:"token"`    // symbol or address, empty is all
+	Side     string `json:"side"`
+	Days     int    `json:"days"` // 0 is all
+}
+
+func (f HistoryFilter) JsonB() ([]byte, error) {
+	return json.Marshal(f)
+}
+
+// next value of list, back to first one at the end
+func CycleNext[T comparable](list []T, cur T) T {
+	if len(list) == 0 {
+		return cur
+	}
+	i := slices.Index(list, cur)
+	return list[(i+1)%len(list)]
+}
+
+// timestamp in millisecond
+func (f HistoryFilter) matchTime(ts int64) bool {
+	return f.Days == 0 || ts >= time.Now().AddDate(0, 0, -f.Days).UnixMilli()
+}
+
+func (f HistoryFilter) matchSide(isBuy bool) bool {
+	switch f.Side {
+	case HistorySideBuy:
+		return isBuy
+	case HistorySideSell:
+		return !isBuy
+	}
+	return true
+}
+
+func (f HistoryFilter) matchToken(token string) bool {
+	return f.Token == "" || f.Token == token
+}
+
+func (t TradeHistoryInner) Token() string {
+	return t.BaseSymbol
+}
+
+func (t TradeHistoryInner) IsBuy() bool {
+	return t.Direction != "1"
+}
+
+// 主币转账没有 token 地址
+func (t TransferHistoryInner) Token() string {
+	if t.TokenAddress == "" {
+		return HistoryNativeToken
+	}
+	return t.TokenAddress
+}
+
+func (o OpenOrderInner) Token() string {
+	return o.BaseSymbol
+}
+
+func (o OpenOrderInner) IsBuy() bool {
+	return o.ToTokenAddress == o.BaseAddress
+}
+
+func (f HistoryFilter) FilterTrades(list []TradeHistoryInner) []TradeHistoryInner {
+	var out []TradeHistoryInner
+	for _, t := range list {
+		if f.matchToken(t.Token()) && f.matchSide(t.IsBuy()) && f.matchTime(cast.ToInt64(t.Timestamp)) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// transfer has no side
+func (f HistoryFilter) FilterTransfers(list []TransferHistoryInner) []TransferHistoryInner {
+	var out []TransferHistoryInner
+	for _, t := range list {
+		if f.matchToken(t.Token()) && f.matchTime(t.Timestamp) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func (f HistoryFilter) FilterOrders(list []OpenOrderInner) []OpenOrderInner {
+	var out []OpenOrderInner
+	for _, o := range list {
+		if f.matchToken(o.Token()) && f.matchSide(o.IsBuy()) && f.matchTime(cast.ToInt64(o.Timestamp)) {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+// distinct tokens by first appearance
+func HistoryTokens[T interface{ Token() string }](list []T) []string {
+	var tokens []string
+	for _, t := range list {
+		if !slices.Contains(tokens, t.Token()) {
+			tokens = append(tokens, t.Token())
+		}
+	}
+	return tokens
+}
+
+func TotalPage(n, pageSize int) int {
+	return max((n+pageSize-1)/pageSize, 1)
+}
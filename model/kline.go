@@ -0,0 +1,26 @@
+package model
+
+import "github.com/spf13/cast"
+
+// supported chart time type
+var KlineTimeTypes = []string{"5m", "15m", "1h", "4h"}
+
+type KlineResp struct {
+	Code int     `json:"code"`
+	Msg  string  `json:"msg"`
+	Data []Kline `json:"data"`
+}
+
+// one candle, time is unix second
+type Kline struct {
+	Time   int64  `json:"time"`
+	Open   string `json:"open"`
+	High   string `json:"high"`
+	Low    string `json:"low"`
+	Close  string `json:"close"`
+	Volume string `json:"volume"`
+}
+
+func (k Kline) OHLC() (open, high, low, close float64) {
+	return cast.ToFloat64(k.Open), cast.ToFloat64(k.High), cast.ToFloat64(k.Low), cast.ToFloat64(k.Close)
+}
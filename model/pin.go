@@ -0,0 +1,104 @@
+package model
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	PinMinLen = 4
+	PinMaxLen = 8
+	// 连续输错次数达到后锁定
+	PinMaxFail      = 5
+	PinLockDuration = 30 * time.Minute
+)
+
+var (
+	ErrPinFormat = errors.New("pin must be 4-8 digits")
+	ErrPinWrong  = errors.New("wrong pin")
+)
+
+// argon2id params
+const (
+	pinKdfTime    = 1
+	pinKdfMemory  = 64 * 1024
+	pinKdfThreads = 4
+	pinKdfKeyLen  = 32
+	pinSaltLen    = 16
+)
+
+// only salt and hash of pin are stored
+type PinRecord struct {
+	Salt      string `json:"salt"`
+	Hash      string `json:"hash"`
+	UpdatedAt int64  `json:"updatedAt"`
+}
+
+func (p *PinRecord) JsonB() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+func ValidPinFormat(pin string) error {
+	if len(pin) < PinMinLen || len(pin) > PinMaxLen {
+		return ErrPinFormat
+	}
+	for _, c := range pin {
+		if c < '0' || c > '9' {
+			return ErrPinFormat
+		}
+	}
+	return nil
+}
+
+func pinHash(pin string, salt []byte) []byte {
+	return argon2.IDKey([]byte(pin), salt, pinKdfTime, pinKdfMemory, pinKdfThreads, pinKdfKeyLen)
+}
+
+func NewPinRecord(pin string) (PinRecord, error) {
+	if err := ValidPinFormat(pin); err != nil {
+		return PinRecord{}, err
+	}
+	salt := make([]byte, pinSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return PinRecord{}, err
+	}
+	return PinRecord{
+		Salt:      base64.StdEncoding.EncodeToString(salt),
+		Hash:      base64.StdEncoding.EncodeToString(pinHash(pin, salt)),
+		UpdatedAt: time.Now().Unix(),
+	}, nil
+}
+
+// Verify only check pin, fail count is kept in redis by caller
+func (p *PinRecord) Verify(pin string) error {
+	salt, err := base64.StdEncoding.DecodeString(p.Salt)
+	if err != nil {
+		return err
+	}
+	hash, err := base64.StdEncoding.DecodeString(p.Hash)
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare(pinHash(pin, salt), hash) != 1 {
+		return ErrPinWrong
+	}
+	return nil
+}
+
+// fails include current attempt
+func PinLocked(fails int64) bool {
+	return fails >= PinMaxFail
+}
+
+func PinRemainTries(fails int64) int {
+	if fails >= PinMaxFail {
+		return 0
+	}
+	return PinMaxFail - int(fails)
+}
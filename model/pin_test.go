@@ -0,0 +1,56 @@
+package model
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidPinFormat(t *testing.T) {
+	tests := []struct {
+		pin string
+		ok  bool
+	}{
+		{"1234", true},
+		{"12345678", true},
+		{"123", false},
+		{"123456789", false},
+		{"12a4", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if err := ValidPinFormat(tt.pin); (err == nil) != tt.ok {
+			t.Errorf("ValidPinFormat(%q) = %v, want ok %v", tt.pin, err, tt.ok)
+		}
+	}
+}
+
+func TestPinRecordVerify(t *testing.T) {
+	p, err := NewPinRecord("1234")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.Verify("1234"); err != nil {
+		t.Fatalf("right pin: %v", err)
+	}
+	if err := p.Verify("4321"); !errors.Is(err, ErrPinWrong) {
+		t.Fatalf("wrong pin: %v", err)
+	}
+}
+
+func TestPinLockout(t *testing.T) {
+	for fails := int64(1); fails < PinMaxFail; fails++ {
+		if PinLocked(fails) {
+			t.Fatalf("locked after %d fails", fails)
+		}
+		if got := PinRemainTries(fails); got != PinMaxFail-int(fails) {
+			t.Errorf("remain tries after %d fails = %d", fails, got)
+		}
+	}
+	// 达到次数锁定, 之后继续计数也保持锁定
+	for _, fails := range []int64{PinMaxFail, PinMaxFail + 1} {
+		if !PinLocked(fails) || PinRemainTries(fails) != 0 {
+			t.Fatalf("not locked after %d fails", fails)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package model
+
+import (
+	"slices"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	PortfolioPageSize = 8
+	PortfolioTopCount = 3
+)
+
+// one token position in one wallet
+type PortfolioItem struct {
+	ChainCode   string
+	Wallet      string
+	Address     string
+	PairAddress string
+	Symbol      string
+	Amount      string
+	Value       decimal.Decimal // 当前持仓价值 $
+	Unrealised  decimal.Decimal // 未实现盈亏: 持仓价值 - 持仓成本
+	Realised    decimal.Decimal // 已实现盈亏: 卖出金额 - 卖出成本
+	Percent     decimal.Decimal // 占总价值百分比, Summarize 时计算
+}
+
+func (p PortfolioItem) PnL() decimal.Decimal {
+	return p.Unrealised.Add(p.Realised)
+}
+
+// unrealised = value - amount * avgPrice
+// realised = sellVolume - sellAmount * avgPrice
+func NewPortfolioItem(w Wallet, token DataTokenInfo, pos PositionDataInner) PortfolioItem {
+	item := PortfolioItem{
+		ChainCode:   w.ChainCode,
+		Wallet:      w.Wallet,
+		Address:     token.Address,
+		PairAddress: token.PairAddress,
+		Symbol:      token.Symbol,
+		Amount:      token.Amount,
+		Value:       toDecimal(token.TotalAmount),
+	}
+	if item.PairAddress == "" {
+		item.PairAddress = pos.PairAddress
+	}
+	if item.Symbol == "" {
+		item.Symbol = pos.Symbol
+	}
+
+	avgPrice := toDecimal(pos.AveragePrice)
+	if avgPrice.IsZero() {
+		// 没有买入记录(转入等), 无法计算盈亏
+		return item
+	}
+	item.Unrealised = item.Value.Sub(toDecimal(token.Amount).Mul(avgPrice))
+	item.Realised = toDecimal(pos.TotalSellVolume).Sub(toDecimal(pos.TotalSellAmount).Mul(avgPrice))
+	return item
+}
+
+type Portfolio struct {
+	Items      []PortfolioItem
+	Closed     []PortfolioItem // 已清仓, 只有已实现盈亏
+	TotalValue decimal.Decimal
+	Unrealised decimal.Decimal
+	Realised   decimal.Decimal
+	Chains     []string // chainCode has position
+}
+
+// Summarize sort items by value and calc total and allocation
+func (p *Portfolio) Summarize() {
+	p.TotalValue, p.Unrealised, p.Realised = decimal.Zero, decimal.Zero, decimal.Zero
+	p.Chains = p.Chains[:0]
+	for _, item := range slices.Concat(p.Items, p.Closed) {
+		p.TotalValue = p.TotalValue.Add(item.Value)
+		p.Unrealised = p.Unrealised.Add(item.Unrealised)
+		p.Realised = p.Realised.Add(item.Realised)
+		if !slices.Contains(p.Chains, item.ChainCode) {
+			p.Chains = append(p.Chains, item.ChainCode)
+		}
+	}
+	for i := range p.Items {
+		if p.TotalValue.IsPositive() {
+			p.Items[i].Percent = p.Items[i].Value.Div(p.TotalValue).Mul(decimal.NewFromInt(100)).Round(2)
+		}
+	}
+	slices.SortFunc(p.Items, func(a, b PortfolioItem) int {
+		return b.Value.Cmp(a.Value)
+	})
+	slices.Sort(p.Chains)
+}
+
+// Filter by chainCode, empty is all
+func (p Portfolio) Filter(chainCode string) Portfolio {
+	if chainCode == "" {
+		return p
+	}
+	result := Portfolio{}
+	for _, item := range p.Items {
+		if item.ChainCode == chainCode {
+			result.Items = append(result.Items, item)
+		}
+	}
+	for _, item := range p.Closed {
+		if item.ChainCode == chainCode {
+			result.Closed = append(result.Closed, item)
+		}
+	}
+	result.Summarize()
+	result.Chains = p.Chains
+	return result
+}
+
+// Top winners and losers by total pnl, closed positions included
+func (p Portfolio) Top(n int) (winners []PortfolioItem, losers []PortfolioItem) {
+	sorted := slices.Concat(p.Items, p.Closed)
+	slices.SortFunc(sorted, func(a, b PortfolioItem) int {
+		return b.PnL().Cmp(a.PnL())
+	})
+	for _, item := range sorted {
+		if len(winners) < n && item.PnL().IsPositive() {
+			winners = append(winners, item)
+		}
+	}
+	slices.Reverse(sorted)
+	for _, item := range sorted {
+		if len(losers) < n && item.PnL().IsNegative() {
+			losers = append(losers, item)
+		}
+	}
+	return winners, losers
+}
+
+// Page start from 1, return items and total page
+func (p Portfolio) Page(page int) ([]PortfolioItem, int) {
+	totalPage := max(1, (len(p.Items)+PortfolioPageSize-1)/PortfolioPageSize)
+	page = min(max(page, 1), totalPage)
+	start := (page - 1) * PortfolioPageSize
+	end := min(start+PortfolioPageSize, len(p.Items))
+	return p.Items[start:end], totalPage
+}
+
+func toDecimal(s string) decimal.Decimal {
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Zero
+	}
+	return d
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestNewPortfolioItem(t *testing.T) {
+	w := Wallet{ChainCode: "SOLANA", Wallet: "w1"}
+	tests := []struct {
+		name       string
+		token      DataTokenInfo
+		pos        PositionDataInner
+		symbol     string
+		unrealised string
+		realised   string
+	}{
+		{
+			name:       "no buy record",
+			token:      DataTokenInfo{Address: "t1", Symbol: "AAA", Amount: "10", TotalAmount: "30"},
+			pos:        PositionDataInner{},
+			symbol:     "AAA",
+			unrealised: "0",
+			realised:   "0",
+		},
+		{
+			name:       "holding and sold",
+			token:      DataTokenInfo{Address: "t1", Symbol: "AAA", Amount: "10", TotalAmount: "30"},
+			pos:        PositionDataInner{AveragePrice: "2", TotalSellAmount: "20", TotalSellVolume: "50"},
+			symbol:     "AAA",
+			unrealised: "10",
+			realised:   "10",
+		},
+		{
+			name:       "closed position",
+			token:      DataTokenInfo{Address: "t1"},
+			pos:        PositionDataInner{Symbol: "BBB", AveragePrice: "2", TotalSellAmount: "20", TotalSellVolume: "30"},
+			symbol:     "BBB",
+			unrealised: "0",
+			realised:   "-10",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := NewPortfolioItem(w, tt.token, tt.pos)
+			if item.Symbol != tt.symbol {
+				t.Errorf("symbol = %s, want %s", item.Symbol, tt.symbol)
+			}
+			if !item.Unrealised.Equal(decimal.RequireFromString(tt.unrealised)) {
+				t.Errorf("unrealised = %s, want %s", item.Unrealised, tt.unrealised)
+			}
+			if !item.Realised.Equal(decimal.RequireFromString(tt.realised)) {
+				t.Errorf("realised = %s, want %s", item.Realised, tt.realised)
+			}
+		})
+	}
+}
+
+func TestPortfolioSummarizeClosed(t *testing.T) {
+	p := Portfolio{
+		Items:  []PortfolioItem{{ChainCode: "SOLANA", Value: decimal.NewFromInt(100), Unrealised: decimal.NewFromInt(20), Realised: decimal.NewFromInt(5)}},
+		Closed: []PortfolioItem{{ChainCode: "BSC", Realised: decimal.NewFromInt(-8)}},
+	}
+	p.Summarize()
+	if !p.TotalValue.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("total value = %s", p.TotalValue)
+	}
+	if !p.Realised.Equal(decimal.NewFromInt(-3)) {
+		t.Errorf("realised = %s, want -3", p.Realised)
+	}
+	if len(p.Chains) != 2 {
+		t.Errorf("chains = %v", p.Chains)
+	}
+}
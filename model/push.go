@@ -0,0 +1,278 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/tidwall/gjson"
+)
+
+// PushVersion current version of backend push, push without version is v1 of AI monitor.
+// v1 push of unknown topic or without fields of its kind is kept as legacy push:
+//
+//	v1: {"payload": {"uuid", "chainCode", "baseAddress", "symbol", "price", "amount", "volume", "flag", "topic"}}
+//	v2: {"version": 2, "kind": "smart_money", "id": "...", "timestamp": 1700000000000, "payload": {...}}
+//
+// id is unique message id of backend, timestamp is unix millisecond time the push is created.
+// both are optional, old backend may only send timestamp in payload
+const PushVersion = 2
+
+// kinds of push event
+const (
+	PushKindPriceAlert  = "price_alert"
+	PushKindLargeTrade  = "large_trade"
+	PushKindSmartMoney  = "smart_money"
+	PushKindNewListing  = "new_listing"
+	PushKindOrderFilled = "order_filled"
+	// v1 push only has uuid for sure, shown by generic template
+	PushKindLegacy = "legacy"
+)
+
+var (
+	ErrPushJson    = errors.New("invalid push json")
+	ErrPushVersion = errors.New("unsupported push version")
+	ErrPushKind    = errors.New("unknown push kind")
+	ErrPushField   = errors.New("missing push field")
+)
+
+// required payload fields of kind, uuid and chainCode are required by all kinds except new listing.
+// new listing without uuid is sent to all users of token feed
+var pushRequired = map[string][]string{
+	PushKindPriceAlert:  {"baseAddress", "price", "topic"},
+	PushKindLargeTrade:  {"baseAddress", "volume", "flag", "topic"},
+	PushKindSmartMoney:  {"baseAddress", "wallet", "volume", "flag"},
+	PushKindNewListing:  {"baseAddress", "pairAddress"},
+	PushKindOrderFilled: {"baseAddress", "txHash", "flag"},
+	PushKindLegacy:      {},
+}
+
+// PushEvent typed backend push, fields not used by kind are zero
+type PushEvent struct {
+	Version int    `json:"version"`
+	Kind    string `json:"kind"`
+	// message id of backend
+	Id string `json:"id"`
+	// unix millisecond time the push is created by backend
+	Timestamp int64 `json:"timestamp"`
+	// user binding of bot
+	Uuid        string `json:"uuid"`
+	ChainCode   string `json:"chainCode"`
+	BaseAddress string `json:"baseAddress"`
+	Symbol      string `json:"symbol"`
+	// monitor type of AI monitor: price, chg, buy, sell
+	Topic string          `json:"topic"`
+	Price decimal.Decimal `json:"price"`
+
+	// trade of large trade, smart money and order filled
+	Amount decimal.Decimal `json:"amount"`
+	Volume decimal.Decimal `json:"volume"`
+	IsBuy  bool            `json:"isBuy"`
+
+	// smart money, swaps merged into the alert
+	Wallet      string `json:"wallet"`
+	WalletLabel string `json:"walletLabel"`
+	Txs         int    `json:"txs"`
+
+	// new listing
+	PairAddress string          `json:"pairAddress"`
+	Dex         string          `json:"dex"`
+	Liquidity   decimal.Decimal `json:"liquidity"`
+	MarketCap   decimal.Decimal `json:"marketCap"`
+	// percent of supply held by creator
+	DevHolding decimal.Decimal `json:"devHolding"`
+	Twitter    string          `json:"twitter"`
+	Telegram   string          `json:"telegram"`
+	Website    string          `json:"website"`
+
+	// order filled
+	OrderNo   string `json:"orderNo"`
+	OrderType string `json:"orderType"`
+	TxHash    string `json:"txHash"`
+}
+
+// legacy push of AI monitor, kind by monitor type
+func legacyPushKind(topic string) string {
+	switch topic {
+	case "price", "chg":
+		return PushKindPriceAlert
+	case "buy", "sell":
+		return PushKindLargeTrade
+	}
+	return ""
+}
+
+func pushDecimal(r gjson.Result) decimal.Decimal {
+	d, _ := decimal.NewFromString(r.String())
+	return d
+}
+
+// links are shown in message, others are dropped
+func pushUrl(r gjson.Result) string {
+	s := r.String()
+	if !strings.HasPrefix(s, "https://") && !strings.HasPrefix(s, "http://") {
+		return ""
+	}
+	return s
+}
+
+func checkPushFields(p gjson.Result, kind string) error {
+	required, ok := pushRequired[kind]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrPushKind, kind)
+	}
+	common := []string{"uuid", "chainCode"}
+	switch kind {
+	case PushKindNewListing:
+		common = common[1:]
+	case PushKindLegacy:
+		common = common[:1]
+	}
+	for _, field := range append(common, required...) {
+		if v := p.Get(field); !v.Exists() || v.String() == "" {
+			return fmt.Errorf("%w: %s", ErrPushField, field)
+		}
+	}
+	return nil
+}
+
+// DecodePush decode and validate backend push
+func DecodePush(data []byte) (PushEvent, error) {
+	var e PushEvent
+	if !gjson.ValidBytes(data) {
+		return e, ErrPushJson
+	}
+	root := gjson.ParseBytes(data)
+	p := root.Get("payload")
+	if !p.IsObject() {
+		return e, fmt.Errorf("%w: payload", ErrPushField)
+	}
+
+	e.Version = int(root.Get("version").Int())
+	if e.Version == 0 {
+		e.Version = 1
+	}
+	switch e.Version {
+	case 1:
+		e.Kind = legacyPushKind(p.Get("topic").String())
+		if e.Kind == "" || checkPushFields(p, e.Kind) != nil {
+			e.Kind = PushKindLegacy
+		}
+	case PushVersion:
+		e.Kind = root.Get("kind").String()
+	default:
+		return e, fmt.Errorf("%w: %d", ErrPushVersion, e.Version)
+	}
+	if err := checkPushFields(p, e.Kind); err != nil {
+		return e, err
+	}
+
+	e.Id = root.Get("id").String()
+	if e.Id == "" {
+		e.Id = p.Get("msgId").String()
+	}
+	e.Timestamp = root.Get("timestamp").Int()
+	if e.Timestamp == 0 {
+		e.Timestamp = p.Get("timestamp").Int()
+	}
+	e.Uuid = p.Get("uuid").String()
+	e.ChainCode = p.Get("chainCode").String()
+	e.BaseAddress = p.Get("baseAddress").String()
+	e.Symbol = p.Get("symbol").String()
+	e.Topic = p.Get("topic").String()
+	e.Price = pushDecimal(p.Get("price"))
+	e.Amount = pushDecimal(p.Get("amount"))
+	e.Volume = pushDecimal(p.Get("volume"))
+	// flag: 0 buy, 1 sell
+	e.IsBuy = p.Get("flag").Int() == 0
+	e.Wallet = p.Get("wallet").String()
+	e.WalletLabel = p.Get("walletLabel").String()
+	e.Txs = int(p.Get("txs").Int())
+	e.PairAddress = p.Get("pairAddress").String()
+	e.Dex = p.Get("dex").String()
+	e.Liquidity = pushDecimal(p.Get("liquidity"))
+	e.MarketCap = pushDecimal(p.Get("marketCap"))
+	e.DevHolding = pushDecimal(p.Get("devHolding"))
+	e.Twitter = pushUrl(p.Get("twitter"))
+	e.Telegram = pushUrl(p.Get("telegram"))
+	e.Website = pushUrl(p.Get("website"))
+	e.OrderNo = p.Get("orderNo").String()
+	e.OrderType = p.Get("orderType").String()
+	e.TxHash = p.Get("txHash").String()
+	return e, nil
+}
+
+// DedupeKey identify the same push received by several instances, empty if backend sent neither id nor timestamp.
+// same content sent twice by backend is two pushes, so content is not used
+func (e PushEvent) DedupeKey() string {
+	if e.Id != "" {
+		return e.Id
+	}
+	if e.Timestamp == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s:%s:%s:%s:%d", e.Kind, e.Uuid, e.ChainCode, e.BaseAddress, e.Timestamp)
+}
+
+// Monitor push of AI monitor rule of user
+func (e PushEvent) Monitor() bool {
+	return e.Kind == PushKindPriceAlert || e.Kind == PushKindLargeTrade
+}
+
+// FromMonitor push of AI monitor rule, legacy push included.
+// legacy push may have no price, so it is not a tick of local alert engine
+func (e PushEvent) FromMonitor() bool {
+	return e.Monitor() || e.Kind == PushKindLegacy
+}
+
+// Feed new listing of token feed, not bound to a user
+func (e PushEvent) Feed() bool {
+	return e.Kind == PushKindNewListing && e.Uuid == ""
+}
+
+// dex name of launchpads in lower case
+var launchDexes = []string{"pumpfun", "pump.fun", "fourmeme", "four.meme"}
+
+// Launch token launched on launchpad such as pump.fun, otherwise a new amm pair
+func (e PushEvent) Launch() bool {
+	return slices.Contains(launchDexes, strings.ToLower(e.Dex))
+}
+
+func (e PushEvent) HasSocials() bool {
+	return e.Twitter != "" || e.Telegram != "" || e.Website != ""
+}
+
+// Type monitor type of AI monitor push, kind of others
+func (e PushEvent) Type() string {
+	if e.FromMonitor() && e.Topic != "" {
+		return e.Topic
+	}
+	return e.Kind
+}
+
+// Value triggered value shown in alert history
+func (e PushEvent) Value() decimal.Decimal {
+	switch e.Kind {
+	case PushKindPriceAlert, PushKindNewListing, PushKindLegacy:
+		return e.Price
+	}
+	return e.Volume
+}
+
+// Tick tick of local alert engine
+func (e PushEvent) Tick() AlertTick {
+	return AlertTick{
+		ChainCode: e.ChainCode,
+		Token:     e.BaseAddress,
+		Symbol:    e.Symbol,
+		Price:     e.Price,
+		Amount:    e.Amount,
+		Volume:    e.Volume,
+		Trade:     e.Topic == "buy" || e.Topic == "sell",
+		IsBuy:     e.IsBuy,
+		Time:      time.Now(),
+	}
+}
@@ -0,0 +1,115 @@
+package model
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDecodePush(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		kind  string
+		err   error
+		dedup string
+	}{
+		{
+			name: "v1 price",
+			data: `{"payload": {"uuid": "u1", "chainCode": "BSC", "baseAddress": "0xa", "price": "1.2", "topic": "price"}}`,
+			kind: PushKindPriceAlert,
+		},
+		{
+			name: "v1 large trade",
+			data: `{"payload": {"uuid": "u1", "chainCode": "BSC", "baseAddress": "0xa", "volume": "5000", "flag": 1, "topic": "sell", "timestamp": 1700000000000}}`,
+			kind: PushKindLargeTrade,
+			// 没有消息 id 时用 uuid 和时间
+			dedup: "large_trade:u1:BSC:0xa:1700000000000",
+		},
+		{
+			name: "v1 unknown topic",
+			data: `{"payload": {"uuid": "u1", "chainCode": "BSC", "baseAddress": "0xa", "topic": "holders"}}`,
+			kind: PushKindLegacy,
+		},
+		{
+			name: "v1 without price",
+			data: `{"payload": {"uuid": "u1", "baseAddress": "0xa", "topic": "price"}}`,
+			kind: PushKindLegacy,
+		},
+		{
+			name: "v1 without uuid",
+			data: `{"payload": {"chainCode": "BSC", "baseAddress": "0xa", "price": "1.2", "topic": "price"}}`,
+			err:  ErrPushField,
+		},
+		{
+			name:  "v2 smart money",
+			data:  `{"version": 2, "kind": "smart_money", "id": "m1", "payload": {"uuid": "u1", "chainCode": "SOLANA", "baseAddress": "a", "wallet": "w", "volume": "100", "flag": 0}}`,
+			kind:  PushKindSmartMoney,
+			dedup: "m1",
+		},
+		{
+			name: "v2 new listing without uuid",
+			data: `{"version": 2, "kind": "new_listing", "payload": {"chainCode": "SOLANA", "baseAddress": "a", "pairAddress": "p"}}`,
+			kind: PushKindNewListing,
+		},
+		{
+			name: "v2 missing field",
+			data: `{"version": 2, "kind": "order_filled", "payload": {"uuid": "u1", "chainCode": "BSC", "baseAddress": "0xa", "flag": 0}}`,
+			err:  ErrPushField,
+		},
+		{
+			name: "v2 unknown kind",
+			data: `{"version": 2, "kind": "airdrop", "payload": {"uuid": "u1", "chainCode": "BSC"}}`,
+			err:  ErrPushKind,
+		},
+		{
+			name: "unsupported version",
+			data: `{"version": 3, "kind": "smart_money", "payload": {}}`,
+			err:  ErrPushVersion,
+		},
+		{
+			name: "no payload",
+			data: `{"version": 2, "kind": "smart_money"}`,
+			err:  ErrPushField,
+		},
+		{
+			name: "invalid json",
+			data: `{"payload": `,
+			err:  ErrPushJson,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := DecodePush([]byte(tt.data))
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("err = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if e.Kind != tt.kind {
+				t.Errorf("kind = %s, want %s", e.Kind, tt.kind)
+			}
+			if got := e.DedupeKey(); got != tt.dedup {
+				t.Errorf("dedupe key = %q, want %q", got, tt.dedup)
+			}
+		})
+	}
+}
+
+func TestPushEventFlags(t *testing.T) {
+	legacy := PushEvent{Kind: PushKindLegacy, Topic: "holders"}
+	if legacy.Monitor() || !legacy.FromMonitor() || legacy.Type() != "holders" {
+		t.Errorf("legacy push: monitor %v, from monitor %v, type %s", legacy.Monitor(), legacy.FromMonitor(), legacy.Type())
+	}
+	feed := PushEvent{Kind: PushKindNewListing}
+	if !feed.Feed() {
+		t.Error("new listing without uuid is not feed")
+	}
+	feed.Uuid = "u1"
+	if feed.Feed() {
+		t.Error("new listing of user is feed")
+	}
+}
@@ -0,0 +1,100 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const MaxSmartWalletLen = 20
+
+// 可选的提醒门槛(USD)
+var SmartMoneyMinUsds = []int{100, 500, 1000, 5000, 10000, 50000}
+
+var (
+	ErrSmartWalletFull    = errors.New("smart wallet list is full")
+	ErrSmartWalletExist   = errors.New("already watching this wallet")
+	ErrSmartWalletAddress = errors.New("invalid wallet address")
+	ErrSmartWalletLabel   = errors.New("invalid wallet label")
+)
+
+// SmartWallet wallet watched by user, alert when it buys or sells over MinUsd
+type SmartWallet struct {
+	Id        string `json:"id"`
+	ChainCode string `json:"chainCode"`
+	Address   string `json:"address"`
+	Label     string `json:"label"`
+	MinUsd    int    `json:"minUsd"`
+	Buy       bool   `json:"buy"`
+	Sell      bool   `json:"sell"`
+	Enabled   bool   `json:"enabled"`
+	// added from curated list
+	Curated   bool  `json:"curated"`
+	CreatedAt int64 `json:"createdAt"`
+}
+
+func NewSmartWallet(chainCode, address, label string, curated bool) SmartWallet {
+	now := time.Now()
+	return SmartWallet{
+		Id:        strconv.FormatInt(now.UnixMilli(), 36),
+		ChainCode: chainCode,
+		Address:   address,
+		Label:     label,
+		MinUsd:    SmartMoneyMinUsds[2],
+		Buy:       true,
+		Sell:      true,
+		Enabled:   true,
+		Curated:   curated,
+		CreatedAt: now.Unix(),
+	}
+}
+
+func (w *SmartWallet) JsonB() ([]byte, error) {
+	return json.Marshal(w)
+}
+
+func (w *SmartWallet) WalletKey() string {
+	return AddressKey(w.ChainCode, w.Address)
+}
+
+// Want whether trade of wallet should be alerted
+func (w *SmartWallet) Want(t SmartTrade) bool {
+	if !w.Enabled || (t.IsBuy && !w.Buy) || (!t.IsBuy && !w.Sell) {
+		return false
+	}
+	return t.Volume.GreaterThanOrEqual(decimal.NewFromInt(int64(w.MinUsd)))
+}
+
+// SetLabel label shown in alerts, at most 20 characters
+func (w *SmartWallet) SetLabel(label string) error {
+	if label == "" || len([]rune(label)) > 20 {
+		return ErrSmartWalletLabel
+	}
+	w.Label = label
+	return nil
+}
+
+// SmartTrade swaps of a wallet on a token in one direction, merged in a short window
+type SmartTrade struct {
+	ChainCode string
+	Wallet    string
+	Token     string
+	IsBuy     bool
+	// raw token amount
+	Amount decimal.Decimal
+	// usd value
+	Volume decimal.Decimal
+	Txs    []string
+	First  time.Time
+}
+
+func (t *SmartTrade) Key() string {
+	side := "s"
+	if t.IsBuy {
+		side = "b"
+	}
+	return AddressKey(t.ChainCode, t.Wallet) + ":" + AddressKey(t.ChainCode, t.Token) + ":" + side
+}
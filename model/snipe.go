@@ -0,0 +1,112 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// 每个用户同时等待中的狙击数量上限
+	MaxSnipeLen = 5
+	// slippage in percent
+	MaxSnipeSlippage = 100
+)
+
+// 可编辑的字段, 用在 callback data 中
+const (
+	SnipeFieldAmount    = "a"
+	SnipeFieldSlippage  = "s"
+	SnipeFieldFee       = "f"
+	SnipeFieldLiquidity = "l"
+)
+
+var (
+	ErrSnipeFull     = errors.New("too many armed snipes")
+	ErrSnipeExist    = errors.New("token already armed")
+	ErrSnipeToken    = errors.New("invalid token address")
+	ErrSnipeNoWallet = errors.New("no wallet on chain")
+	ErrSnipeNumber   = errors.New("invalid number")
+	ErrSnipeSlippage = errors.New("slippage out of range")
+)
+
+// Snipe buy token once it becomes tradable
+type Snipe struct {
+	Id        string `json:"id"`
+	ChainCode string `json:"chainCode"`
+	Token     string `json:"token"`
+	WalletId  string `json:"walletId"`
+	// native coin amount
+	Amount string `json:"amount"`
+	// percent, 0 uses user setting
+	Slippage string `json:"slippage"`
+	// native coin, 0 uses default
+	PriorityFee string `json:"priorityFee"`
+	// min tvl in usd
+	MinLiquidity string `json:"minLiquidity"`
+	// pump.fun token: buy after bonding curve migrated instead of on the curve
+	Migration bool  `json:"migration"`
+	CreatedAt int64 `json:"createdAt"`
+}
+
+func NewSnipe(chainCode, token, walletId string) Snipe {
+	now := time.Now()
+	return Snipe{
+		Id:           strconv.FormatInt(now.UnixMilli(), 36),
+		ChainCode:    chainCode,
+		Token:        token,
+		WalletId:     walletId,
+		Amount:       "0.1",
+		Slippage:     "0",
+		PriorityFee:  "0",
+		MinLiquidity: "0",
+		CreatedAt:    now.Unix(),
+	}
+}
+
+func (s *Snipe) JsonB() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+func (s *Snipe) TokenKey() string {
+	return AddressKey(s.ChainCode, s.Token)
+}
+
+func (s *Snipe) LiquidityOK(tvl string) bool {
+	return liquidityOK(s.MinLiquidity, tvl)
+}
+
+// pump.fun mint address ends with pump
+func (s *Snipe) IsPumpToken() bool {
+	return s.ChainCode == "SOLANA" && strings.HasSuffix(s.Token, "pump")
+}
+
+func (s *Snipe) SetField(field, input string) error {
+	v, err := decimal.NewFromString(strings.TrimSpace(input))
+	if err != nil || v.IsNegative() {
+		return ErrSnipeNumber
+	}
+	switch field {
+	case SnipeFieldAmount:
+		if !v.IsPositive() {
+			return ErrSnipeNumber
+		}
+		s.Amount = v.String()
+	case SnipeFieldSlippage:
+		if v.GreaterThan(decimal.NewFromInt(MaxSnipeSlippage)) {
+			return ErrSnipeSlippage
+		}
+		s.Slippage = v.String()
+	case SnipeFieldFee:
+		s.PriorityFee = v.String()
+	case SnipeFieldLiquidity:
+		s.MinLiquidity = v.String()
+	default:
+		return ErrSnipeNumber
+	}
+	return nil
+}
@@ -13,4 +13,6 @@ type Swap struct {
 	TradeType         string  `json:"tradeType"`
 	Price             string  `json:"price"`
 	ProfitFlag        float64 `json:"profitFlag"` //先阶段设置为 0
+	// 优先费(原生币), 为空时使用默认值
+	PriorityFee string `json:"priorityFee,omitempty"`
 }
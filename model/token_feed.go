@@ -0,0 +1,78 @@
+package model
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/shopspring/decimal"
+)
+
+// options of token feed
+var (
+	TokenFeedChains       = []string{"SOLANA", "BSC"}
+	TokenFeedLiquidities  = []int{0, 1000, 5000, 10000, 50000}
+	TokenFeedDevHoldings  = []int{0, 5, 10, 20, 50}
+	TokenFeedHourlyLimits = []int{5, 10, 20, 30, 60}
+)
+
+// TokenFeed filters of new listing feed of user
+type TokenFeed struct {
+	Enabled bool     `json:"enabled"`
+	Chains  []string `json:"chains"`
+	// launchpad tokens and new amm pairs
+	Launch bool `json:"launch"`
+	Pair   bool `json:"pair"`
+	// usd, 0 is no limit
+	MinLiquidity int `json:"minLiquidity"`
+	// max percent held by creator, 0 is no limit
+	MaxDevHolding int  `json:"maxDevHolding"`
+	Socials       bool `json:"socials"`
+	// skip token with risk score >= MaxRisk, 0 is no limit
+	MaxRisk   int `json:"maxRisk"`
+	HourLimit int `json:"hourLimit"`
+}
+
+func DefaultTokenFeed() TokenFeed {
+	return TokenFeed{
+		Chains:        slices.Clone(TokenFeedChains),
+		Launch:        true,
+		Pair:          true,
+		MinLiquidity:  TokenFeedLiquidities[2],
+		MaxDevHolding: TokenFeedDevHoldings[3],
+		HourLimit:     TokenFeedHourlyLimits[1],
+	}
+}
+
+func (f *TokenFeed) JsonB() ([]byte, error) {
+	return json.Marshal(f)
+}
+
+func (f *TokenFeed) ToggleChain(chainCode string) {
+	if i := slices.Index(f.Chains, chainCode); i >= 0 {
+		f.Chains = slices.Delete(f.Chains, i, i+1)
+		return
+	}
+	f.Chains = append(f.Chains, chainCode)
+}
+
+// Match whether new listing passes filters, risk score is checked by caller
+func (f *TokenFeed) Match(e PushEvent) bool {
+	if !f.Enabled || !slices.Contains(f.Chains, e.ChainCode) {
+		return false
+	}
+	if (e.Launch() && !f.Launch) || (!e.Launch() && !f.Pair) {
+		return false
+	}
+	if f.MinLiquidity > 0 && e.Liquidity.LessThan(decimal.NewFromInt(int64(f.MinLiquidity))) {
+		return false
+	}
+	if f.MaxDevHolding > 0 && e.DevHolding.GreaterThan(decimal.NewFromInt(int64(f.MaxDevHolding))) {
+		return false
+	}
+	return !f.Socials || e.HasSocials()
+}
+
+// RiskOK token without scan result is skipped if risk filter is on
+func (f *TokenFeed) RiskOK(s TokenSafety) bool {
+	return f.MaxRisk <= 0 || (!s.Empty() && s.Score < f.MaxRisk)
+}
@@ -0,0 +1,80 @@
+package model
+
+import "encoding/json"
+
+const (
+	SafetyOk = iota
+	SafetyWarn
+	SafetyDanger
+	// 无法检测, 只做提示, 不计分
+	SafetyInfo
+)
+
+// 风险分数, 越高越危险
+const (
+	safetyWarnScore   = 15
+	safetyDangerScore = 40
+	SafetyMaxScore    = 100
+	// >= medium 为中风险, >= high 为高风险
+	SafetyMediumScore = 30
+	SafetyHighScore   = 60
+)
+
+// 可选的买入拦截阈值, 0 表示不拦截
+var SafetyBlockScores = []int{0, 80, 60, 40}
+
+// SafetyCheck one check result, Key is i18n key and Args are its params
+type SafetyCheck struct {
+	Level int      `json:"level"`
+	Key   string   `json:"key"`
+	Args  []string `json:"args,omitempty"`
+}
+
+// FmtArgs args for i18n.T
+func (c SafetyCheck) FmtArgs() []any {
+	args := make([]any, 0, len(c.Args))
+	for _, a := range c.Args {
+		args = append(args, a)
+	}
+	return args
+}
+
+type TokenSafety struct {
+	ChainCode string        `json:"chainCode"`
+	Token     string        `json:"token"`
+	Score     int           `json:"score"`
+	Checks    []SafetyCheck `json:"checks"`
+	CheckedAt int64         `json:"checkedAt"`
+}
+
+func (s *TokenSafety) JsonB() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+func (s *TokenSafety) Add(level int, key string, args ...string) {
+	s.Checks = append(s.Checks, SafetyCheck{Level: level, Key: key, Args: args})
+	switch level {
+	case SafetyWarn:
+		s.Score += safetyWarnScore
+	case SafetyDanger:
+		s.Score += safetyDangerScore
+	}
+	s.Score = min(s.Score, SafetyMaxScore)
+}
+
+// i18n key of risk level
+func (s *TokenSafety) LevelKey() string {
+	switch {
+	case s.Score >= SafetyHighScore:
+		return "safety.level_high"
+	case s.Score >= SafetyMediumScore:
+		return "safety.level_medium"
+	default:
+		return "safety.level_low"
+	}
+}
+
+// 没有任何检测结果时不展示
+func (s *TokenSafety) Empty() bool {
+	return len(s.Checks) == 0
+}
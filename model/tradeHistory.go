@@ -8,6 +8,7 @@ type TradeHistory struct {
 
 type TradeHistoryInner struct {
 	BaseSymbol    string `json:"baseSymbol"`
+	BaseAddress   string `json:"baseAddress"`
 	ChainCode     string `json:"chainCode"`
 	QuoteSymbol   string `json:"quoteSymbol"`
 	MarketCap     string `json:"marketCap"`
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// callback data 最长 64 字节, buy_<pair>_<chain>_ 已经占了大部分, 预设值必须足够短
+	MaxTradePresetLen   = 6
+	MaxBuyPresetCount   = 6
+	MaxSellPresetCount  = 3
+	TradePresetSideBuy  = "buy"
+	TradePresetSideSell = "sell"
+)
+
+var (
+	ErrTradePresetEmpty    = errors.New("至少需要设置一个数值")
+	ErrTradePresetTooMany  = errors.New("预设数量过多")
+	ErrTradePresetNotNum   = errors.New("请输入有效的数字")
+	ErrTradePresetTooLong  = errors.New("数值过长, 最多 6 个字符")
+	ErrTradePresetNotRange = errors.New("数值超出范围")
+)
+
+// quick buy amounts (quote token) and quick sell percents, per chain
+type TradePreset struct {
+	ChainCode string   `json:"chainCode"`
+	Buy       []string `json:"buy"`
+	Sell      []string `json:"sell"`
+}
+
+// DefaultTradePreset presets of user who has not set them, same as buttons before presets can be set
+func DefaultTradePreset(chainCode string) TradePreset {
+	return TradePreset{
+		ChainCode: chainCode,
+		Buy:       []string{"0.1", "0.5", "1", "5", "10"},
+		Sell:      []string{"50", "100"},
+	}
+}
+
+func (p *TradePreset) JsonB() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+func (p *TradePreset) Vaild() error {
+	if _, err := ParseTradePresetInput(TradePresetSideBuy, strings.Join(p.Buy, " ")); err != nil {
+		return err
+	}
+	if _, err := ParseTradePresetInput(TradePresetSideSell, strings.Join(p.Sell, " ")); err != nil {
+		return err
+	}
+	return nil
+}
+
+// parse user input like "0.1 0.5 1" or "0.1,0.5,1"
+// buy amount must > 0, sell percent must in (0, 100]
+func ParseTradePresetInput(side string, input string) ([]string, error) {
+	fields := strings.FieldsFunc(input, func(r rune) bool {
+		return r == ' ' || r == ',' || r == '，' || r == '\n'
+	})
+	if len(fields) == 0 {
+		return nil, ErrTradePresetEmpty
+	}
+
+	maxCount := MaxBuyPresetCount
+	if side == TradePresetSideSell {
+		maxCount = MaxSellPresetCount
+	}
+	if len(fields) > maxCount {
+		return nil, ErrTradePresetTooMany
+	}
+
+	result := make([]string, 0, len(fields))
+	for _, f := range fields {
+		num, err := decimal.NewFromString(strings.TrimSuffix(f, "%"))
+		if err != nil {
+			return nil, ErrTradePresetNotNum
+		}
+		if !num.IsPositive() {
+			return nil, ErrTradePresetNotRange
+		}
+		if side == TradePresetSideSell && num.GreaterThan(decimal.NewFromInt(100)) {
+			return nil, ErrTradePresetNotRange
+		}
+
+		s := num.String()
+		if len(s) > MaxTradePresetLen {
+			return nil, ErrTradePresetTooLong
+		}
+		result = append(result, s)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// 每个用户最多关注的代币数量, 渲染时每个代币都要请求一次价格
+const MaxWatchlistLen = 20
+
+var (
+	ErrWatchlistFull  = errors.New("watchlist is full")
+	ErrWatchlistExist = errors.New("token already in watchlist")
+)
+
+type WatchToken struct {
+	ChainCode   string `json:"chainCode"`
+	BaseAddress string `json:"baseAddress"`
+	Symbol      string `json:"symbol"`
+	AddedAt     int64  `json:"addedAt"`
+}
+
+func (w *WatchToken) JsonB() ([]byte, error) {
+	return json.Marshal(w)
+}
@@ -0,0 +1,102 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// 可选的新地址冷却时间(小时)
+var WhitelistCoolDownHours = []int{1, 24, 48, 72}
+
+const DefaultWhitelistCoolDownHours = 24
+
+var (
+	ErrNotWhitelisted    = errors.New("address not in whitelist")
+	ErrWhitelistCoolDown = errors.New("address in cool-down")
+)
+
+// 开启后只能转账到地址簿中的地址, 新添加的地址冷却后才能使用
+type WhitelistSetting struct {
+	Enabled       bool `json:"enabled"`
+	CoolDownHours int  `json:"coolDownHours"`
+	// 关闭白名单或缩短冷却时间要等旧的冷却时间过后才生效
+	Pending *WhitelistChange `json:"pending,omitempty"`
+}
+
+type WhitelistChange struct {
+	Enabled       bool  `json:"enabled"`
+	CoolDownHours int   `json:"coolDownHours"`
+	EffectiveAt   int64 `json:"effectiveAt"`
+}
+
+func DefaultWhitelistSetting() WhitelistSetting {
+	return WhitelistSetting{CoolDownHours: DefaultWhitelistCoolDownHours}
+}
+
+func (w *WhitelistSetting) JsonB() ([]byte, error) {
+	return json.Marshal(w)
+}
+
+func (w *WhitelistSetting) CoolDown() time.Duration {
+	return time.Duration(w.CoolDownHours) * time.Hour
+}
+
+// Apply pending change if it is effective at now
+func (w *WhitelistSetting) Apply(now time.Time) {
+	if w.Pending != nil && now.Unix() >= w.Pending.EffectiveAt {
+		w.Enabled, w.CoolDownHours = w.Pending.Enabled, w.Pending.CoolDownHours
+		w.Pending = nil
+	}
+}
+
+// Target setting after pending change
+func (w *WhitelistSetting) Target() (enabled bool, coolDownHours int) {
+	if w.Pending != nil {
+		return w.Pending.Enabled, w.Pending.CoolDownHours
+	}
+	return w.Enabled, w.CoolDownHours
+}
+
+// Change take effect now if it makes whitelist stricter,
+// otherwise it is pending until current cool-down passed
+func (w *WhitelistSetting) Change(enabled bool, coolDownHours int, now time.Time) {
+	w.Apply(now)
+	if !w.Enabled || (enabled && coolDownHours >= w.CoolDownHours) {
+		w.Enabled, w.CoolDownHours, w.Pending = enabled, coolDownHours, nil
+		return
+	}
+	w.Pending = &WhitelistChange{
+		Enabled:       enabled,
+		CoolDownHours: coolDownHours,
+		EffectiveAt:   now.Add(w.CoolDown()).Unix(),
+	}
+}
+
+// UsableAt unlock time stored when address added,
+// entries added before that use added time and current cool-down
+func (w *WhitelistSetting) UsableAt(e AddressEntry) time.Time {
+	if e.UsableAt > 0 {
+		return time.Unix(e.UsableAt, 0)
+	}
+	return time.Unix(e.AddedAt, 0).Add(w.CoolDown())
+}
+
+// CheckAddress find address in entries and check cool-down, always pass if whitelist disabled
+func (w *WhitelistSetting) CheckAddress(entries []AddressEntry, chainCode, address string, now time.Time) (AddressEntry, error) {
+	if !w.Enabled {
+		return AddressEntry{}, nil
+	}
+
+	key := AddressKey(chainCode, address)
+	for _, e := range entries {
+		if e.Key() != key {
+			continue
+		}
+		if now.Before(w.UsableAt(e)) {
+			return e, ErrWhitelistCoolDown
+		}
+		return e, nil
+	}
+	return AddressEntry{}, ErrNotWhitelisted
+}
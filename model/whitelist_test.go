@@ -0,0 +1,93 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWhitelistUsableAt(t *testing.T) {
+	w := WhitelistSetting{Enabled: true, CoolDownHours: 24}
+	added := time.Unix(1700000000, 0)
+
+	// 添加时保存的解锁时间不受之后冷却时间修改影响
+	stored := AddressEntry{AddedAt: added.Unix(), UsableAt: added.Add(72 * time.Hour).Unix()}
+	if got := w.UsableAt(stored); !got.Equal(added.Add(72 * time.Hour)) {
+		t.Errorf("stored usable at = %v", got)
+	}
+	legacy := AddressEntry{AddedAt: added.Unix()}
+	if got := w.UsableAt(legacy); !got.Equal(added.Add(24 * time.Hour)) {
+		t.Errorf("legacy usable at = %v", got)
+	}
+}
+
+func TestWhitelistChange(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tests := []struct {
+		name     string
+		from     WhitelistSetting
+		enabled  bool
+		coolDown int
+		pending  bool
+	}{
+		{"enable", WhitelistSetting{CoolDownHours: 24}, true, 24, false},
+		{"longer cool-down", WhitelistSetting{Enabled: true, CoolDownHours: 24}, true, 72, false},
+		{"shorter cool-down", WhitelistSetting{Enabled: true, CoolDownHours: 24}, true, 1, true},
+		{"disable", WhitelistSetting{Enabled: true, CoolDownHours: 48}, false, 48, true},
+		{"change while disabled", WhitelistSetting{CoolDownHours: 72}, false, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := tt.from
+			w.Change(tt.enabled, tt.coolDown, now)
+			if enabled, cd := w.Target(); enabled != tt.enabled || cd != tt.coolDown {
+				t.Fatalf("target = %v %d", enabled, cd)
+			}
+			if (w.Pending != nil) != tt.pending {
+				t.Fatalf("pending = %+v, want %v", w.Pending, tt.pending)
+			}
+			if !tt.pending {
+				return
+			}
+			// 旧的冷却时间过后才生效
+			if w.Enabled != tt.from.Enabled || w.CoolDownHours != tt.from.CoolDownHours {
+				t.Fatalf("changed before effective: %+v", w)
+			}
+			w.Apply(now.Add(tt.from.CoolDown() - time.Second))
+			if w.Pending == nil {
+				t.Fatal("applied too early")
+			}
+			w.Apply(now.Add(tt.from.CoolDown()))
+			if w.Pending != nil || w.Enabled != tt.enabled || w.CoolDownHours != tt.coolDown {
+				t.Fatalf("not applied: %+v", w)
+			}
+		})
+	}
+}
+
+func TestWhitelistCheckAddress(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	entries := []AddressEntry{
+		{ChainCode: "BSC", Address: "0xAbC", AddedAt: now.Add(-48 * time.Hour).Unix()},
+		{ChainCode: "SOLANA", Address: "New", AddedAt: now.Unix(), UsableAt: now.Add(time.Hour).Unix()},
+	}
+	w := WhitelistSetting{Enabled: true, CoolDownHours: 24}
+	tests := []struct {
+		chainCode, address string
+		err                error
+	}{
+		{"BSC", "0xabc", nil},
+		{"SOLANA", "New", ErrWhitelistCoolDown},
+		{"SOLANA", "new", ErrNotWhitelisted},
+		{"BSC", "0xdef", ErrNotWhitelisted},
+	}
+	for _, tt := range tests {
+		if _, err := w.CheckAddress(entries, tt.chainCode, tt.address, now); !errors.Is(err, tt.err) {
+			t.Errorf("CheckAddress(%s, %s) = %v, want %v", tt.chainCode, tt.address, err, tt.err)
+		}
+	}
+	disabled := WhitelistSetting{CoolDownHours: 24}
+	if _, err := disabled.CheckAddress(entries, "BSC", "0xdef", now); err != nil {
+		t.Errorf("disabled whitelist: %v", err)
+	}
+}
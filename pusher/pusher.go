@@ -0,0 +1,274 @@
+package pusher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hellodex/tradingbot/alert"
+	"github.com/hellodex/tradingbot/config"
+	"github.com/hellodex/tradingbot/entity"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/store"
+	"github.com/hellodex/tradingbot/template"
+	"github.com/hellodex/tradingbot/tokenfeed"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// same push is ignored by other instances in ttl
+	pushClaimTTL = 10 * time.Minute
+	// 保留最近的推送用于补发
+	maxArchiveLen = 100000
+	// 一次最多补发的推送数量
+	MaxReplayLen = 2000
+	// 已发送标记保留时间, 更早的推送补发时不再检查
+	pushSentTTL  = 7 * 24 * time.Hour
+	defaultGroup = "tradingbot"
+)
+
+// push backend push received from redis
+type push struct {
+	data []byte
+	// only handle push of this bot, 0 is any bot of this instance
+	botId int64
+	// entry id of stream, empty from pub/sub
+	id string
+	// archive entry id of push replayed by admin, not archived again
+	replay string
+}
+
+// Run consume backend pushes until ctx done from stream.
+// old config without stream falls back to pub/sub channel, pushes are lost while no instance is running
+func Run(ctx context.Context) error {
+	cfg := config.Get().RedisPush
+	if cfg.Stream == "" {
+		if cfg.MessageCh == "" {
+			return errors.New("redis_push.message_stream is not set")
+		}
+		log.Warn().Str("channel", cfg.MessageCh).Msg("redis_push.message_stream is not set, pushes from pub/sub channel are lost while no instance is running")
+		ch, err := store.SubChannel(cfg.MessageCh)
+		if err != nil {
+			return err
+		}
+		go func() {
+			for msg := range ch {
+				handle(push{data: []byte(msg.Payload)})
+			}
+		}()
+		return nil
+	}
+
+	group := cfg.Group
+	if group == "" {
+		group = defaultGroup
+	}
+	consumer, err := os.Hostname()
+	if err != nil || consumer == "" {
+		consumer = strconv.Itoa(os.Getpid())
+	}
+	// 每个 bot 一个消费组, 运行同一个 bot 的实例共同消费
+	for id := range entity.BotMap {
+		ch, err := store.SubStream(ctx, cfg.Stream, fmt.Sprintf("%s:%d", group, id), consumer)
+		if err != nil {
+			return err
+		}
+		go func(botId int64) {
+			for msg := range ch {
+				handle(push{data: []byte(msg.Payload), botId: botId, id: msg.ID, replay: msg.Replay})
+				msg.Ack()
+			}
+		}(id)
+	}
+	return nil
+}
+
+// Replay push again archived pushes received in [from, to] which are not sent, return count of pushes.
+// without stream only users of bots in this instance receive them
+func Replay(from, to time.Time) (int, error) {
+	list, err := store.ArchivedPushes(from, to, MaxReplayLen)
+	if err != nil || len(list) == 0 {
+		return 0, err
+	}
+	ids := make([]string, 0, len(list))
+	for _, v := range list {
+		ids = append(ids, v.ID)
+	}
+	sent, err := store.PushesSent(ids)
+	if err != nil {
+		return 0, err
+	}
+
+	stream := config.Get().RedisPush.Stream
+	n := 0
+	for i, v := range list {
+		if sent[i] {
+			continue
+		}
+		if stream == "" {
+			handle(push{data: []byte(v.Data), replay: v.ID})
+		} else if err := store.PushStreamAdd(stream, v.Data, v.ID); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// pushKey message id of backend, otherwise entry id of stream
+func pushKey(e model.PushEvent, p push) string {
+	if key := e.DedupeKey(); key != "" {
+		return key
+	}
+	if p.id != "" {
+		return "stream:" + p.id
+	}
+	return ""
+}
+
+func handle(p push) {
+	msgData := p.data
+	log.Debug().RawJSON("push", msgData).Send()
+	e, err := model.DecodePush(msgData)
+	if err != nil {
+		log.Error().Err(err).RawJSON("push", msgData).Msg("Invalid push payload")
+		return
+	}
+	// 新币推送发给所有开启的用户, 补发时已经过时
+	if e.Feed() {
+		if p.replay == "" {
+			tokenfeed.Enqueue(e)
+		}
+		return
+	}
+	uuid := e.Uuid
+
+	value, err := store.UserInBot(uuid)
+	if err != nil {
+		if err == redis.Nil {
+			log.Error().Err(err).Str("uuid", uuid).Msg("Failed to get user in bot reids nil")
+			return
+		}
+		log.Error().Err(err).Str("uuid", uuid).Msg("Failed to get user in bot")
+		return
+	}
+
+	//  "botId::userId"
+	parts := strings.Split(value, "::")
+	if len(parts) != 2 {
+		log.Error().Str("value", value).Msg("Invalid format: expected botId::userId, user not in bot")
+		return
+	}
+
+	botId := parts[0]
+	userIdStr := parts[1]
+
+	userId, err := strconv.ParseInt(userIdStr, 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("user_id_str", userIdStr).Msg("Failed to parse user ID")
+		return
+	}
+
+	id, err := strconv.ParseInt(botId, 10, 64)
+	if err != nil {
+		log.Error().Err(err).Str("bot_id", botId).Msg("Failed to parse bot ID")
+		return
+	}
+	// 用户的 bot 由其他实例或者其他消费组处理
+	if _, ok := entity.BotMap[id]; !ok || (p.botId != 0 && p.botId != id) {
+		log.Debug().Str("bot_id", botId).Msg("Bot not in this instance")
+		return
+	}
+	// 多个实例订阅同一个频道或者消息被其他消费者接管时, 每条推送只处理一次.
+	// 补发按存档 id 处理一次, 避免重复补发
+	if p.replay != "" {
+		claimed, err := store.ClaimOnce("replay:"+p.replay, pushClaimTTL)
+		if err != nil || !claimed {
+			if err != nil {
+				log.Error().Err(err).Send()
+			}
+			return
+		}
+	} else if key := pushKey(e, p); key != "" {
+		claimed, err := store.ClaimOnce("push:"+key, pushClaimTTL)
+		if err != nil {
+			log.Error().Err(err).Send()
+		} else if !claimed {
+			return
+		}
+	} else {
+		log.Warn().RawJSON("push", msgData).Msg("push without id or timestamp is not deduped")
+	}
+	archive := p.replay
+	if archive == "" {
+		archive, err = store.ArchivePush(string(msgData), maxArchiveLen)
+		if err != nil {
+			log.Error().Err(err).Msg("archive push")
+		}
+	}
+	alert.SetPushBot(userId, id)
+
+	// 本地计算提醒时 AI 监控推送只作为行情数据
+	if alert.Enabled() && e.Monitor() {
+		alert.Track(userId)
+		alert.Feed(e.Tick())
+		setSent(archive)
+		return
+	}
+
+	messageTmpl, kb, err := template.RenderPush(i18n.UserLang(userId), e)
+	if err != nil {
+		log.Error().Err(err).Str("bot_id", botId).Int64("user_id", userId).Msg("Failed to render message template")
+		return
+	}
+
+	// in redis
+	puserhandlerReq := model.PusherHandlerReqData{
+		ChainCode:   e.ChainCode,
+		BaseAddress: e.BaseAddress,
+		MonitorType: e.Type(),
+	}
+	// 成交通知每条都要发送, 不受冷却影响
+	var key string
+	if e.Kind != model.PushKindOrderFilled {
+		key = model.AddressKey(e.ChainCode, e.BaseAddress) + ":" + e.Type()
+	}
+
+	// 发送失败时由 alert 重试或者进入死信
+	err = alert.Deliver(model.AlertPush{
+		ChatId: userId,
+		Key:    key,
+		Text:   messageTmpl,
+		Req:    &puserhandlerReq,
+		Markup: &kb,
+		BotId:  id,
+		Symbol: e.Symbol,
+		Type:   e.Type(),
+		Value:  e.Value().String(),
+	})
+	if err != nil {
+		log.Error().Err(err).Str("bot_id", botId).Int64("user_id", userId).Msg("Failed to send message")
+	} else {
+		setSent(archive)
+	}
+	// 补发的推送不再自动交易
+	if p.replay == "" && e.FromMonitor() {
+		alert.Act(userId, e.ChainCode, e.BaseAddress, e.Topic)
+	}
+}
+
+// setSent archived push is sent or queued for retry, replay skips it
+func setSent(archive string) {
+	if archive == "" {
+		return
+	}
+	if err := store.SetPushSent(archive, pushSentTTL); err != nil {
+		log.Error().Err(err).Str("archive", archive).Msg("set push sent")
+	}
+}
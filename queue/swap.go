@@ -10,6 +10,7 @@ import (
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
 	"github.com/hellodex/tradingbot/api"
+	"github.com/hellodex/tradingbot/i18n"
 	"github.com/hellodex/tradingbot/model"
 	"github.com/hellodex/tradingbot/rpc"
 	"github.com/hellodex/tradingbot/session"
@@ -35,6 +36,8 @@ type SwapPayload struct {
 	Status          Event
 	Tx              string
 	UserInputAmount string
+	// 跟单/狙击等自动交易成功后只发通知, 不刷新代币卡片
+	Auto bool
 }
 
 // AddProcessingSwapQueue 添加交易到队列，带有重试机制
@@ -171,13 +174,16 @@ func successSwap(sp *SwapPayload) {
 	msgqq := func() string {
 		// is buy
 		if sp.SwapBody.Type == "0" {
-			return fmt.Sprintf("✅ %s 买 %s %s，交易成功，", baseToken.Symbol, amount, quoteToken.Symbol)
+			return i18n.L(sp.UserID, "swap.buy_success", baseToken.Symbol, amount, quoteToken.Symbol)
 		}
-		return fmt.Sprintf("✅ %s 卖 %s %s，交易成功，", baseToken.Symbol, amount, baseToken.Symbol)
+		return i18n.L(sp.UserID, "swap.sell_success", baseToken.Symbol, amount, baseToken.Symbol)
 	}()
 	scanUrl := util.GetChainScanUrl(sp.HandleWallet.ChainCode, sp.Tx)
-	viewUrl := fmt.Sprintf(`<a href="%s">%s</a>`, scanUrl, " 点击查看区块浏览器")
+	viewUrl := fmt.Sprintf(`<a href="%s">%s</a>`, scanUrl, i18n.L(sp.UserID, "swap.view_scan"))
 	util.QuickMessage(ctx, sp.B, sp.UserID, msgqq+viewUrl)
+	if sp.Auto {
+		return
+	}
 
 	time.Sleep(3 * time.Second)
 
@@ -209,7 +215,7 @@ func successSwap(sp *SwapPayload) {
 
 	log.Debug().Interface("lastSwapMessaage", lastSwapMessaage).Send()
 
-	text, err := template.RanderTokenInfo(tokenInfo)
+	text, err := template.RanderTokenInfo(i18n.UserLang(sp.UserID), tokenInfo)
 	if err != nil {
 		log.Error().Err(err).Send()
 		return
@@ -268,11 +274,11 @@ func failedSwap(sp *SwapPayload) {
 	msgqq := func() string {
 		// is buy
 		if sp.SwapBody.Type == "0" {
-			return fmt.Sprintf("❌ %s 买 %s %s，交易失败，", baseToken.Symbol, amount, quoteToken.Symbol)
+			return i18n.L(sp.UserID, "swap.buy_failed", baseToken.Symbol, amount, quoteToken.Symbol)
 		}
-		return fmt.Sprintf("❌ %s 卖 %s %s，交易失败，", baseToken.Symbol, amount, baseToken.Symbol)
+		return i18n.L(sp.UserID, "swap.sell_failed", baseToken.Symbol, amount, baseToken.Symbol)
 	}()
-	util.QuickMessage(ctx, sp.B, sp.UserID, msgqq+util.AdminUrl)
+	util.QuickMessage(ctx, sp.B, sp.UserID, msgqq+i18n.L(sp.UserID, "common.admin_url"))
 }
 
 func processingSwap(sp *SwapPayload) {
@@ -310,19 +316,19 @@ func processingSwap(sp *SwapPayload) {
 		return ""
 	}()
 	scanUrl := util.GetChainScanUrl(chainCode, tx)
-	viewUrl := fmt.Sprintf(`<a href="%s">%s</a>`, scanUrl, "点击查看区块浏览器")
+	viewUrl := fmt.Sprintf(`<a href="%s">%s</a>`, scanUrl, i18n.L(chatId, "swap.view_scan"))
 
-	util.QuickMessage(ctx, b, chatId, fmt.Sprintf("⏳链上确认中 %s", viewUrl))
+	util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "swap.confirming", viewUrl))
 	// err := rpc.SOL_PollTransactionStatus(tx)
 	if chainCode == "" {
 		log.Error().Err(errors.New("get user wallet chainCode err in swap")).Send()
-		util.QuickMessage(ctx, b, chatId, "出错了，请联系客服")
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 	err = rpc.PollTransactionStatus(chainCode, tx)
 	if err != nil {
 		if errors.Is(err, rpc.ErrPollTxMaxRetry) {
-			util.QuickMessage(ctx, b, chatId, err.Error())
+			util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "transfer.poll_timeout"))
 		}
 		return
 	}
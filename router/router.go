@@ -12,11 +12,11 @@ import (
 func SetWebhook(ctx context.Context, b *bot.Bot) {
 	b.SetWebhook(ctx, &bot.SetWebhookParams{
 		DropPendingUpdates: true,
-		URL:                config.YmlConfig.Env.TgHook,
-		SecretToken:        config.YmlConfig.Env.TgHookToken,
+		URL:                config.Get().Env.TgHook,
+		SecretToken:        config.Get().Env.TgHookToken,
 	})
 	go func() {
-		http.ListenAndServe(config.YmlConfig.Env.LocalHost, wrapWebhookHandler(b))
+		http.ListenAndServe(config.Get().Env.LocalHost, wrapWebhookHandler(b))
 	}()
 	b.StartWebhook(ctx)
 }
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/hellodex/tradingbot/config"
 	"github.com/hellodex/tradingbot/logger"
@@ -17,12 +18,27 @@ func pollTxStatusLog() *zerolog.Event {
 	return log.Debug().Func(logger.WithCategory("PollTransactionStatus"))
 }
 
-var sol_rpc = func() string {
-	return config.YmlConfig.Env.SolRpc
-}()
-var bsc_rpc = func() string {
-	return config.YmlConfig.Env.BscRpc
-}()
+func sol_rpc() string {
+	return config.Get().Env.SolRpc
+}
+func bsc_rpc() string {
+	return config.Get().Env.BscRpc
+}
+
+// websocket endpoint, fallback to rpc url with ws scheme
+func sol_ws() string {
+	return wsUrl(config.Get().Env.SolWs, sol_rpc())
+}
+func bsc_ws() string {
+	return wsUrl(config.Get().Env.BscWs, bsc_rpc())
+}
+
+func wsUrl(ws, rpc string) string {
+	if ws != "" {
+		return ws
+	}
+	return strings.Replace(rpc, "http", "ws", 1)
+}
 
 type ChainChecker struct{}
 
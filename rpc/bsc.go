@@ -26,7 +26,7 @@ func BSC_GetSignatureStatuses(tx string) (string, error) {
 		return "", err
 	}
 
-	resp, err := http.Post(bsc_rpc, "application/json", bytes.NewBuffer(reqData))
+	resp, err := http.Post(bsc_rpc(), "application/json", bytes.NewBuffer(reqData))
 	if err != nil {
 		log.Error().Err(err).Send()
 		return "", err
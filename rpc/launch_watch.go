@@ -0,0 +1,64 @@
+package rpc
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/tidwall/gjson"
+)
+
+const (
+	pumpProgram = "6EF8rrecthR5Dkzon8Nwu78hRvfCKubJ14M5uBEwF6P"
+	// bonding curve account: discriminator + 5 u64 reserves, then complete flag
+	pumpCurveCompleteOffset = 48
+)
+
+func SupportSnipe(chainCode string) bool {
+	return chainCode == "SOLANA" || chainCode == "BSC"
+}
+
+// SubscribeTokenActivity notify on any on chain activity of token, such as pool creation or liquidity add
+func SubscribeTokenActivity(ctx context.Context, chainCode, token string) (<-chan gjson.Result, error) {
+	switch chainCode {
+	case "SOLANA":
+		return Subscribe(ctx, sol_ws(), "logsSubscribe", []any{
+			map[string]any{"mentions": []string{token}},
+			map[string]any{"commitment": "confirmed"},
+		})
+	case "BSC":
+		// 添加流动性时 token 会转入 pair, 产生 Transfer 日志
+		return Subscribe(ctx, bsc_ws(), "eth_subscribe", []any{"logs", map[string]any{"address": token}})
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedChain, chainCode)
+	}
+}
+
+// SOL_PumpCurve state of pump.fun bonding curve of mint, complete means migrated to amm
+func SOL_PumpCurve(mint string) (found bool, complete bool, err error) {
+	mintKey, err := solana.PublicKeyFromBase58(mint)
+	if err != nil {
+		return false, false, err
+	}
+	curve, _, err := solana.FindProgramAddress([][]byte{[]byte("bonding-curve"), mintKey.Bytes()}, solana.MustPublicKeyFromBase58(pumpProgram))
+	if err != nil {
+		return false, false, err
+	}
+
+	acc, err := jsonRpcCall(sol_rpc(), "getAccountInfo", []any{curve.String(), map[string]any{"encoding": "base64"}})
+	if err != nil {
+		return false, false, err
+	}
+	if acc.Get("value").Type == gjson.Null {
+		return false, false, nil
+	}
+	data, err := base64.StdEncoding.DecodeString(acc.Get("value.data.0").String())
+	if err != nil {
+		return false, false, err
+	}
+	if len(data) <= pumpCurveCompleteOffset {
+		return true, false, nil
+	}
+	return true, data[pumpCurveCompleteOffset] == 1, nil
+}
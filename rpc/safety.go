@@ -0,0 +1,242 @@
+package rpc
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hellodex/tradingbot/model"
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+	"github.com/tidwall/gjson"
+	"golang.org/x/crypto/sha3"
+)
+
+var ErrNotToken = errors.New("not a token")
+
+const safetyTopHolders = 10
+
+// owner 可以调用这些方法时认为有特权
+var bscPrivilegedSigs = []string{
+	"mint(address,uint256)",
+	"mint(uint256)",
+	"pause()",
+	"blacklist(address)",
+	"addToBlacklist(address)",
+	"setBlacklist(address,bool)",
+	"setTaxFee(uint256)",
+	"setFee(uint256)",
+	"setFees(uint256,uint256)",
+	"setMaxTxAmount(uint256)",
+	"setTrading(bool)",
+}
+
+// 常见的买卖税查询方法, 按顺序尝试
+var bscTaxGetters = [][2]string{
+	{"buyTax()", "sellTax()"},
+	{"buyFee()", "sellFee()"},
+	{"_buyTax()", "_sellTax()"},
+	{"totalBuyFee()", "totalSellFee()"},
+}
+
+// 4 bytes selector in hex, without 0x
+func selector(sig string) string {
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte(sig))
+	return hex.EncodeToString(h.Sum(nil)[:4])
+}
+
+func padAddress(address string) string {
+	return strings.Repeat("0", 24) + strings.TrimPrefix(strings.ToLower(address), "0x")
+}
+
+// TokenSafety run safety checks of token, pair is used to simulate sell on BSC
+func TokenSafety(chainCode, token, pair string) (model.TokenSafety, error) {
+	switch chainCode {
+	case "SOLANA":
+		return SOL_TokenSafety(token)
+	case "BSC":
+		return BSC_TokenSafety(token, pair)
+	default:
+		return model.TokenSafety{}, fmt.Errorf("%w: %s", ErrUnsupportedChain, chainCode)
+	}
+}
+
+func taxLevel(percent decimal.Decimal) int {
+	switch {
+	case percent.GreaterThanOrEqual(decimal.NewFromInt(10)):
+		return model.SafetyDanger
+	case percent.IsPositive():
+		return model.SafetyWarn
+	default:
+		return model.SafetyOk
+	}
+}
+
+func SOL_TokenSafety(mint string) (model.TokenSafety, error) {
+	s := model.TokenSafety{ChainCode: "SOLANA", Token: mint}
+
+	acc, err := jsonRpcCall(sol_rpc(), "getAccountInfo", []any{mint, map[string]any{"encoding": "jsonParsed"}})
+	if err != nil {
+		return s, err
+	}
+	info := acc.Get("value.data.parsed.info")
+	if acc.Get("value.data.parsed.type").String() != "mint" {
+		return s, ErrNotToken
+	}
+
+	if info.Get("mintAuthority").Type == gjson.Null {
+		s.Add(model.SafetyOk, "safety.sol.mint_renounced")
+	} else {
+		s.Add(model.SafetyDanger, "safety.sol.mint_authority")
+	}
+	if info.Get("freezeAuthority").Type == gjson.Null {
+		s.Add(model.SafetyOk, "safety.sol.freeze_renounced")
+	} else {
+		s.Add(model.SafetyDanger, "safety.sol.freeze_authority")
+	}
+
+	// token-2022 extensions
+	for _, e := range info.Get("extensions").Array() {
+		switch e.Get("extension").String() {
+		case "transferFeeConfig":
+			fee := decimal.NewFromInt(e.Get("state.newerTransferFee.transferFeeBasisPoints").Int()).Div(decimal.NewFromInt(100))
+			if fee.IsPositive() {
+				s.Add(taxLevel(fee), "safety.sol.transfer_fee", fee.String())
+			}
+		case "permanentDelegate":
+			s.Add(model.SafetyDanger, "safety.sol.permanent_delegate")
+		}
+	}
+
+	supply := parseDecimal(info.Get("supply").String())
+	largest, err := jsonRpcCall(sol_rpc(), "getTokenLargestAccounts", []any{mint})
+	if err != nil || !supply.IsPositive() {
+		// 大币种可能查询失败, 不影响其他检测
+		log.Debug().Err(err).Str("mint", mint).Msg("get token largest accounts")
+		return s, nil
+	}
+	top := decimal.Zero
+	for i, a := range largest.Get("value").Array() {
+		if i >= safetyTopHolders {
+			break
+		}
+		top = top.Add(parseDecimal(a.Get("amount").String()))
+	}
+	percent := top.Div(supply).Mul(decimal.NewFromInt(100)).Round(1)
+	switch {
+	case percent.GreaterThanOrEqual(decimal.NewFromInt(80)):
+		s.Add(model.SafetyDanger, "safety.top_holders", percent.String())
+	case percent.GreaterThanOrEqual(decimal.NewFromInt(50)):
+		s.Add(model.SafetyWarn, "safety.top_holders", percent.String())
+	default:
+		s.Add(model.SafetyOk, "safety.top_holders", percent.String())
+	}
+	return s, nil
+}
+
+func bscCall(from, to, data string) (string, error) {
+	tx := map[string]any{"to": to, "data": data}
+	if from != "" {
+		tx["from"] = from
+	}
+	result, err := jsonRpcCall(bsc_rpc(), "eth_call", []any{tx, "latest"})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(result.String(), "0x"), nil
+}
+
+// call a view method returns uint256
+func bscCallUint(token, sig string) (decimal.Decimal, bool) {
+	out, err := bscCall("", token, "0x"+selector(sig))
+	if err != nil || len(out) != 64 {
+		return decimal.Zero, false
+	}
+	return hexToDecimal(out), true
+}
+
+// tax getter may return percent or basis points
+func bscTaxPercent(v decimal.Decimal) (decimal.Decimal, bool) {
+	switch {
+	case v.LessThanOrEqual(decimal.NewFromInt(100)):
+		return v, true
+	case v.LessThanOrEqual(decimal.NewFromInt(10000)):
+		return v.Div(decimal.NewFromInt(100)), true
+	}
+	return decimal.Zero, false
+}
+
+func BSC_TokenSafety(token, pair string) (model.TokenSafety, error) {
+	s := model.TokenSafety{ChainCode: "BSC", Token: token}
+
+	codeResult, err := jsonRpcCall(bsc_rpc(), "eth_getCode", []any{token, "latest"})
+	if err != nil {
+		return s, err
+	}
+	code := strings.TrimPrefix(codeResult.String(), "0x")
+	if code == "" {
+		return s, ErrNotToken
+	}
+
+	// owner
+	out, err := bscCall("", token, "0x"+selector("owner()"))
+	switch {
+	case err != nil || len(out) < 64:
+		s.Add(model.SafetyOk, "safety.bsc.no_owner")
+	case strings.Trim(out[24:64], "0") == "" || strings.HasSuffix(out[24:64], "dead"):
+		s.Add(model.SafetyOk, "safety.bsc.owner_renounced")
+	default:
+		s.Add(model.SafetyWarn, "safety.bsc.owner_active", "0x"+out[24:64])
+
+		var names []string
+		for _, sig := range bscPrivilegedSigs {
+			// PUSH4 <selector> in dispatcher
+			if !strings.Contains(code, "63"+selector(sig)) {
+				continue
+			}
+			name := sig[:strings.Index(sig, "(")]
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+		if len(names) > 0 {
+			s.Add(model.SafetyDanger, "safety.bsc.privileges", strings.Join(names, ", "))
+		}
+	}
+
+	// simulate sell: transfer token to pair
+	if pair != "" {
+		balance, err := BSC_BalanceOf(token, pair)
+		amount := balance.Div(decimal.NewFromInt(1000)).Truncate(0)
+		if err == nil && amount.IsPositive() {
+			data := "0x" + selector("transfer(address,uint256)") + padAddress(pair) + fmt.Sprintf("%064x", amount.BigInt())
+			out, err := bscCall(pair, token, data)
+			// 没有返回值的 token 也算成功
+			if err != nil || (len(out) == 64 && hexToDecimal(out).IsZero()) {
+				s.Add(model.SafetyDanger, "safety.bsc.honeypot")
+			} else {
+				s.Add(model.SafetyOk, "safety.bsc.sell_ok")
+			}
+		}
+	}
+
+	for _, getters := range bscTaxGetters {
+		buyRaw, okBuy := bscCallUint(token, getters[0])
+		sellRaw, okSell := bscCallUint(token, getters[1])
+		if !okBuy || !okSell {
+			continue
+		}
+		buy, okBuy := bscTaxPercent(buyRaw)
+		sell, okSell := bscTaxPercent(sellRaw)
+		if !okBuy || !okSell {
+			continue
+		}
+		s.Add(taxLevel(decimal.Max(buy, sell)), "safety.bsc.tax", buy.String(), sell.String())
+		return s, nil
+	}
+	s.Add(model.SafetyInfo, "safety.bsc.tax_unknown")
+	return s, nil
+}
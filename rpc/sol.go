@@ -24,7 +24,7 @@ func SOL_GetSignatureStatuses(tx string) (string, error) {
         ]
     }`, tx)
 
-	resp, err := http.Post(sol_rpc,
+	resp, err := http.Post(sol_rpc(),
 		"application/json",
 		strings.NewReader(reqBody))
 	if err != nil {
@@ -0,0 +1,382 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+	"github.com/tidwall/gjson"
+)
+
+var ErrUnsupportedChain = errors.New("unsupported chain")
+
+const (
+	solSignatureLimit = 20
+	// 一次最多往前翻的页数, 超过后更早的签名不再处理
+	solMaxSignaturePages = 10
+	// eth_getLogs 单次最多查询的区块数
+	bscMaxBlockRange = 500
+	// keccak256("Transfer(address,address,uint256)")
+	erc20TransferTopic = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+)
+
+// WalletSwap a swap between native coin and a token made by watched wallet
+type WalletSwap struct {
+	Tx        string
+	ChainCode string
+	Token     string
+	IsBuy     bool
+	// native coin spent for buy, received for sell if known
+	QuoteAmount decimal.Decimal
+	// sold amount / balance before sell, only for sell
+	SellRatio decimal.Decimal
+	// raw amount of token bought or sold
+	TokenAmount decimal.Decimal
+}
+
+func SupportWalletSwaps(chainCode string) bool {
+	return chainCode == "SOLANA" || chainCode == "BSC"
+}
+
+// PollWalletSwaps return swaps of address after cursor and the new cursor, swaps before err are still returned.
+// first poll with empty cursor only returns current cursor, history swaps are ignored.
+// wrapped is wrapped native token address of chain, it's treated as native coin
+func PollWalletSwaps(chainCode, address, wrapped, cursor string) ([]WalletSwap, string, error) {
+	switch chainCode {
+	case "SOLANA":
+		return SOL_WalletSwaps(address, wrapped, cursor)
+	case "BSC":
+		return BSC_WalletSwaps(address, wrapped, cursor)
+	default:
+		return nil, cursor, fmt.Errorf("%w: %s", ErrUnsupportedChain, chainCode)
+	}
+}
+
+func jsonRpcCall(url string, method string, params []any) (gjson.Result, error) {
+	reqData, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return gjson.Result{}, err
+	}
+
+	resp, err := http.Post(url, "application/json", bytes.NewBuffer(reqData))
+	if err != nil {
+		return gjson.Result{}, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return gjson.Result{}, fmt.Errorf("failed to read response: %w", err)
+	}
+	if errMsg := gjson.GetBytes(data, "error.message").String(); errMsg != "" {
+		return gjson.Result{}, fmt.Errorf("rpc error: %s", errMsg)
+	}
+	return gjson.GetBytes(data, "result"), nil
+}
+
+func SOL_WalletSwaps(address, wrapped, cursor string) ([]WalletSwap, string, error) {
+	if cursor == "" {
+		result, err := jsonRpcCall(sol_rpc(), "getSignaturesForAddress", []any{address, map[string]any{"limit": 1}})
+		if err != nil {
+			return nil, cursor, err
+		}
+		if sigs := result.Array(); len(sigs) > 0 {
+			return nil, sigs[0].Get("signature").String(), nil
+		}
+		return nil, cursor, nil
+	}
+
+	sigs, err := solSignaturesUntil(address, cursor)
+	if err != nil {
+		return nil, cursor, err
+	}
+
+	var swaps []WalletSwap
+	// newest first, mirror in order
+	for i := len(sigs) - 1; i >= 0; i-- {
+		sig := sigs[i].Get("signature").String()
+		if sigs[i].Get("err").Type != gjson.Null {
+			cursor = sig
+			continue
+		}
+		tx, err := jsonRpcCall(sol_rpc(), "getTransaction", []any{sig, map[string]any{
+			"encoding":                       "jsonParsed",
+			"maxSupportedTransactionVersion": 0,
+		}})
+		if err != nil {
+			// 返回已处理的部分, 下次从失败的签名继续
+			return swaps, cursor, err
+		}
+		if s, ok := solWalletSwap(tx, address, wrapped); ok {
+			s.Tx = sig
+			swaps = append(swaps, s)
+		}
+		cursor = sig
+	}
+	return swaps, cursor, nil
+}
+
+// solSignaturesUntil signatures after cursor, newest first.
+// one page only has solSignatureLimit, page backwards with before until the cursor is reached
+func solSignaturesUntil(address, cursor string) ([]gjson.Result, error) {
+	var sigs []gjson.Result
+	opts := map[string]any{"limit": solSignatureLimit, "until": cursor}
+	for page := 0; page < solMaxSignaturePages; page++ {
+		result, err := jsonRpcCall(sol_rpc(), "getSignaturesForAddress", []any{address, opts})
+		if err != nil {
+			return nil, err
+		}
+		list := result.Array()
+		sigs = append(sigs, list...)
+		if len(list) < solSignatureLimit {
+			return sigs, nil
+		}
+		opts["before"] = list[len(list)-1].Get("signature").String()
+	}
+	log.Warn().Str("address", address).Int("count", len(sigs)).Msg("too many signatures since cursor, older ones are skipped")
+	return sigs, nil
+}
+
+// balance change of owner: native coin (include wrapped) down and one token up is buy, reverse is sell
+func solWalletSwap(tx gjson.Result, owner, wrapped string) (WalletSwap, bool) {
+	meta := tx.Get("meta")
+	if !meta.Exists() || meta.Get("err").Type != gjson.Null {
+		return WalletSwap{}, false
+	}
+
+	idx := -1
+	for i, k := range tx.Get("transaction.message.accountKeys.#.pubkey").Array() {
+		if k.String() == owner {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return WalletSwap{}, false
+	}
+
+	lamports := meta.Get(fmt.Sprintf("postBalances.%d", idx)).Int() - meta.Get(fmt.Sprintf("preBalances.%d", idx)).Int()
+	// 手续费不算在交易金额里
+	if idx == 0 {
+		lamports += meta.Get("fee").Int()
+	}
+	native := decimal.New(lamports, -9)
+
+	pre := map[string]decimal.Decimal{}
+	delta := map[string]decimal.Decimal{}
+	decimals := map[string]int32{}
+	for _, b := range meta.Get("preTokenBalances").Array() {
+		if b.Get("owner").String() != owner {
+			continue
+		}
+		mint := b.Get("mint").String()
+		decimals[mint] = int32(b.Get("uiTokenAmount.decimals").Int())
+		v := parseDecimal(b.Get("uiTokenAmount.amount").String()).Shift(-decimals[mint])
+		pre[mint] = pre[mint].Add(v)
+		delta[mint] = delta[mint].Sub(v)
+	}
+	for _, b := range meta.Get("postTokenBalances").Array() {
+		if b.Get("owner").String() != owner {
+			continue
+		}
+		mint := b.Get("mint").String()
+		decimals[mint] = int32(b.Get("uiTokenAmount.decimals").Int())
+		v := parseDecimal(b.Get("uiTokenAmount.amount").String()).Shift(-decimals[mint])
+		delta[mint] = delta[mint].Add(v)
+	}
+	native = native.Add(delta[wrapped])
+	delete(delta, wrapped)
+
+	var token string
+	for mint, d := range delta {
+		if d.IsZero() {
+			continue
+		}
+		// token to token swap is ignored
+		if token != "" {
+			return WalletSwap{}, false
+		}
+		token = mint
+	}
+	if token == "" {
+		return WalletSwap{}, false
+	}
+
+	d := delta[token]
+	raw := d.Abs().Shift(decimals[token])
+	switch {
+	case d.IsPositive() && native.IsNegative():
+		return WalletSwap{ChainCode: "SOLANA", Token: token, IsBuy: true, QuoteAmount: native.Neg(), TokenAmount: raw}, true
+	case d.IsNegative() && native.IsPositive() && pre[token].IsPositive():
+		return WalletSwap{ChainCode: "SOLANA", Token: token, QuoteAmount: native, SellRatio: d.Neg().Div(pre[token]), TokenAmount: raw}, true
+	}
+	return WalletSwap{}, false
+}
+
+func parseDecimal(s string) decimal.Decimal {
+	v, _ := decimal.NewFromString(s)
+	return v
+}
+
+func hexToDecimal(s string) decimal.Decimal {
+	n, ok := new(big.Int).SetString(strings.TrimPrefix(s, "0x"), 16)
+	if !ok {
+		return decimal.Zero
+	}
+	return decimal.NewFromBigInt(n, 0)
+}
+
+func topicToAddress(topic string) string {
+	if len(topic) < 40 {
+		return ""
+	}
+	return "0x" + strings.ToLower(topic[len(topic)-40:])
+}
+
+func BSC_BlockNumber() (int64, error) {
+	result, err := jsonRpcCall(bsc_rpc(), "eth_blockNumber", []any{})
+	if err != nil {
+		return 0, err
+	}
+	return hexToDecimal(result.String()).IntPart(), nil
+}
+
+func BSC_BalanceOf(token, owner string) (decimal.Decimal, error) {
+	data := "0x70a08231" + strings.Repeat("0", 24) + strings.TrimPrefix(strings.ToLower(owner), "0x")
+	result, err := jsonRpcCall(bsc_rpc(), "eth_call", []any{map[string]any{"to": token, "data": data}, "latest"})
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return hexToDecimal(result.String()), nil
+}
+
+type bscTxTransfers struct {
+	in  map[string]decimal.Decimal
+	out map[string]decimal.Decimal
+}
+
+func BSC_WalletSwaps(address, wrapped, cursor string) ([]WalletSwap, string, error) {
+	latest, err := BSC_BlockNumber()
+	if err != nil {
+		return nil, cursor, err
+	}
+	if cursor == "" {
+		return nil, fmt.Sprint(latest), nil
+	}
+	last, err := strconv.ParseInt(cursor, 10, 64)
+	if err != nil {
+		return nil, cursor, err
+	}
+	from := last + 1
+	if from > latest {
+		return nil, cursor, nil
+	}
+	to := min(latest, from+bscMaxBlockRange-1)
+
+	owner := strings.ToLower(address)
+	wrapped = strings.ToLower(wrapped)
+	padded := "0x" + strings.Repeat("0", 24) + strings.TrimPrefix(owner, "0x")
+
+	var order []string
+	txs := map[string]*bscTxTransfers{}
+	for _, topics := range [][]any{
+		{erc20TransferTopic, padded},
+		{erc20TransferTopic, nil, padded},
+	} {
+		logs, err := jsonRpcCall(bsc_rpc(), "eth_getLogs", []any{map[string]any{
+			"fromBlock": fmt.Sprintf("0x%x", from),
+			"toBlock":   fmt.Sprintf("0x%x", to),
+			"topics":    topics,
+		}})
+		if err != nil {
+			return nil, cursor, err
+		}
+		for _, l := range logs.Array() {
+			hash := l.Get("transactionHash").String()
+			t, ok := txs[hash]
+			if !ok {
+				t = &bscTxTransfers{in: map[string]decimal.Decimal{}, out: map[string]decimal.Decimal{}}
+				txs[hash] = t
+				order = append(order, hash)
+			}
+			token := strings.ToLower(l.Get("address").String())
+			amount := hexToDecimal(l.Get("data").String())
+			if topicToAddress(l.Get("topics.1").String()) == owner {
+				t.out[token] = t.out[token].Add(amount)
+			}
+			if topicToAddress(l.Get("topics.2").String()) == owner {
+				t.in[token] = t.in[token].Add(amount)
+			}
+		}
+	}
+
+	next := fmt.Sprint(to)
+	var swaps []WalletSwap
+	for _, hash := range order {
+		s, ok, err := bscWalletSwap(hash, owner, wrapped, txs[hash])
+		if err != nil {
+			return nil, cursor, err
+		}
+		if ok {
+			swaps = append(swaps, s)
+		}
+	}
+	return swaps, next, nil
+}
+
+// only tx sent by owner to a router is treated as swap, plain token transfer is ignored
+func bscWalletSwap(hash, owner, wrapped string, t *bscTxTransfers) (WalletSwap, bool, error) {
+	tx, err := jsonRpcCall(bsc_rpc(), "eth_getTransactionByHash", []any{hash})
+	if err != nil {
+		return WalletSwap{}, false, err
+	}
+	if strings.ToLower(tx.Get("from").String()) != owner {
+		return WalletSwap{}, false, nil
+	}
+	txTo := strings.ToLower(tx.Get("to").String())
+
+	pick := func(m map[string]decimal.Decimal) (string, int) {
+		var token string
+		n := 0
+		for k, v := range m {
+			if k != wrapped && v.IsPositive() {
+				token = k
+				n++
+			}
+		}
+		return token, n
+	}
+	inToken, inN := pick(t.in)
+	outToken, outN := pick(t.out)
+
+	switch {
+	case inN == 1 && outN == 0 && inToken != txTo:
+		quote := t.out[wrapped].Add(hexToDecimal(tx.Get("value").String())).Shift(-18)
+		if !quote.IsPositive() {
+			return WalletSwap{}, false, nil
+		}
+		return WalletSwap{Tx: hash, ChainCode: "BSC", Token: inToken, IsBuy: true, QuoteAmount: quote, TokenAmount: t.in[inToken]}, true, nil
+	case outN == 1 && inN == 0 && outToken != txTo:
+		sold := t.out[outToken]
+		balance, err := BSC_BalanceOf(outToken, owner)
+		if err != nil {
+			return WalletSwap{}, false, err
+		}
+		// 卖出得到的 BNB 由路由合约转出, 日志里没有
+		return WalletSwap{Tx: hash, ChainCode: "BSC", Token: outToken, SellRatio: sold.Div(sold.Add(balance)), TokenAmount: sold}, true, nil
+	}
+	return WalletSwap{}, false, nil
+}
@@ -0,0 +1,79 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+	"github.com/tidwall/gjson"
+)
+
+// notifications buffered before dropped, subscribers only care about activity
+const subscribeBuffer = 16
+
+// Subscribe json-rpc subscription over websocket, notification results are sent to returned chan,
+// chan is closed when ctx done or connection lost
+func Subscribe(ctx context.Context, url string, method string, params []any) (<-chan gjson.Result, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s: %w", method, err)
+	}
+
+	reqData, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := conn.WriteMessage(websocket.TextMessage, reqData); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to send %s: %w", method, err)
+	}
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read %s: %w", method, err)
+	}
+	if errMsg := gjson.GetBytes(data, "error.message").String(); errMsg != "" {
+		conn.Close()
+		return nil, fmt.Errorf("rpc error: %s", errMsg)
+	}
+
+	ch := make(chan gjson.Result, subscribeBuffer)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		conn.Close()
+	}()
+	go func() {
+		defer close(ch)
+		defer close(done)
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Debug().Err(err).Str("method", method).Msg("subscription closed")
+				}
+				return
+			}
+			result := gjson.GetBytes(data, "params.result")
+			if !result.Exists() {
+				continue
+			}
+			select {
+			case ch <- result:
+			default:
+			}
+		}
+	}()
+	return ch, nil
+}
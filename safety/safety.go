@@ -0,0 +1,55 @@
+package safety
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/rpc"
+	"github.com/hellodex/tradingbot/store"
+	"github.com/hellodex/tradingbot/util"
+	"github.com/rs/zerolog/log"
+)
+
+var cacheTTL = 10 * time.Minute
+
+// Scan return cached safety result of token, scan it if not cached.
+// failed scan is cached as empty result too, so card rendering won't be slowed down repeatedly
+func Scan(chainCode, token, pair string) model.TokenSafety {
+	if token == "" || util.IsNativeCoion(token) {
+		return model.TokenSafety{}
+	}
+	if data, ok := store.GetTokenSafety(chainCode, token); ok {
+		var s model.TokenSafety
+		if err := json.Unmarshal(data, &s); err == nil {
+			return s
+		}
+	}
+
+	s, err := rpc.TokenSafety(chainCode, token, pair)
+	if err != nil {
+		if !errors.Is(err, rpc.ErrUnsupportedChain) {
+			log.Error().Err(err).Str("chainCode", chainCode).Str("token", token).Msg("token safety scan")
+		}
+		s = model.TokenSafety{ChainCode: chainCode, Token: token}
+	}
+	s.CheckedAt = time.Now().Unix()
+
+	if data, err := s.JsonB(); err == nil {
+		if err := store.SetTokenSafety(chainCode, token, data, cacheTTL); err != nil {
+			log.Error().Err(err).Send()
+		}
+	}
+	return s
+}
+
+// BuyBlocked check token risk with user block setting
+func BuyBlocked(chatId int64, chainCode, token, pair string) (model.TokenSafety, bool) {
+	score := store.UserGetSafetyBlock(chatId)
+	if score <= 0 {
+		return model.TokenSafety{}, false
+	}
+	s := Scan(chainCode, token, pair)
+	return s, !s.Empty() && s.Score >= score
+}
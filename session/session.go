@@ -20,6 +20,16 @@ var UserInLimitOrderCache string = "user_limitOrder_cache"
 var TransferToState = "transferTo"
 var UserInTransferToCache string = "user_transferTo_cache"
 var UserStartMessaageIDkey = "user_start_reflash"
+var UserTradePresetReply = "user_tradePreset_reply"
+var UserAddressBookReply = "user_addressBook_reply"
+var UserPinReply = "user_pin_reply"
+var UserCopyTradeReply = "user_copyTrade_reply"
+var UserSnipeReply = "user_snipe_reply"
+var UserCompoundAlertReply = "user_compoundAlert_reply"
+var UserCompoundAlertDraft = "user_compoundAlert_draft"
+var UserAlertDeliveryReply = "user_alertDelivery_reply"
+var UserAlertActionReply = "user_alertAction_reply"
+var UserSmartWalletReply = "user_smartWallet_reply"
 
 var SessionType = struct{}{}
 
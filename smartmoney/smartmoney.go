@@ -0,0 +1,406 @@
+package smartmoney
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/alert"
+	"github.com/hellodex/tradingbot/api"
+	"github.com/hellodex/tradingbot/config"
+	"github.com/hellodex/tradingbot/entity"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/queue"
+	"github.com/hellodex/tradingbot/rpc"
+	"github.com/hellodex/tradingbot/store"
+	"github.com/hellodex/tradingbot/template"
+	"github.com/hellodex/tradingbot/trade"
+	"github.com/hellodex/tradingbot/util"
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cast"
+)
+
+var (
+	pollInterval = 15 * time.Second
+	// 同一钱包对同一代币同方向的交易在窗口内合并成一条提醒
+	mergeWindow = time.Minute
+	// 多个实例关注同一钱包时每笔交易只提醒一次
+	alertClaimTTL = 10 * time.Minute
+	// 提醒里快捷买入按钮的数量
+	quickBuyLen = 3
+)
+
+type watcher struct {
+	ChatId int64
+	Wallet model.SmartWallet
+}
+
+// CuratedWallets recommended smart wallets in config
+func CuratedWallets() []model.SmartWallet {
+	list := make([]model.SmartWallet, 0, len(config.Get().SmartWallets))
+	for _, w := range config.Get().SmartWallets {
+		list = append(list, model.NewSmartWallet(w.ChainCode, w.Address, w.Label, true))
+	}
+	return list
+}
+
+// user smart wallets, sort by created time
+func UserWallets(chatId int64) []model.SmartWallet {
+	data, err := store.UserGetSmartWallets(chatId)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return nil
+	}
+
+	wallets := make([]model.SmartWallet, 0, len(data))
+	for _, v := range data {
+		var w model.SmartWallet
+		if err := json.Unmarshal([]byte(v), &w); err != nil {
+			log.Error().Err(err).Send()
+			continue
+		}
+		wallets = append(wallets, w)
+	}
+	slices.SortFunc(wallets, func(a, b model.SmartWallet) int {
+		return cmp.Compare(a.CreatedAt, b.CreatedAt)
+	})
+	return wallets
+}
+
+func UserWallet(chatId int64, id string) (model.SmartWallet, bool) {
+	for _, w := range UserWallets(chatId) {
+		if w.Id == id {
+			return w, true
+		}
+	}
+	return model.SmartWallet{}, false
+}
+
+func SaveWallet(chatId int64, w model.SmartWallet) error {
+	data, err := w.JsonB()
+	if err != nil {
+		return err
+	}
+	return store.UserAddSmartWallet(chatId, w.Id, data)
+}
+
+// AddWallet watch new wallet of user
+func AddWallet(chatId int64, w model.SmartWallet) error {
+	if !rpc.SupportWalletSwaps(w.ChainCode) {
+		return rpc.ErrUnsupportedChain
+	}
+	wallets := UserWallets(chatId)
+	if len(wallets) >= model.MaxSmartWalletLen {
+		return model.ErrSmartWalletFull
+	}
+	key := w.WalletKey()
+	if slices.ContainsFunc(wallets, func(v model.SmartWallet) bool { return v.WalletKey() == key }) {
+		return model.ErrSmartWalletExist
+	}
+	return SaveWallet(chatId, w)
+}
+
+type monitor struct {
+	// 游标只保存在本实例, 每个实例只关注自己托管用户的钱包
+	cursors map[string]string
+	pending map[string]*model.SmartTrade
+}
+
+// Run poll watched wallets and alert their large trades until ctx done
+func Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	m := &monitor{cursors: map[string]string{}, pending: map[string]*model.SmartTrade{}}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.poll()
+		}
+	}
+}
+
+// watchers of enabled wallets group by wallet key, only users hosted by this instance
+func watchers() map[string][]watcher {
+	users, err := store.SmartMoneyUsers()
+	if err != nil {
+		log.Error().Err(err).Send()
+		return nil
+	}
+
+	groups := map[string][]watcher{}
+	for _, chatId := range users {
+		if !alert.Hosted(chatId) {
+			continue
+		}
+		for _, w := range UserWallets(chatId) {
+			if !w.Enabled {
+				continue
+			}
+			groups[w.WalletKey()] = append(groups[w.WalletKey()], watcher{ChatId: chatId, Wallet: w})
+		}
+	}
+	return groups
+}
+
+func (m *monitor) poll() {
+	groups := watchers()
+	for key := range m.cursors {
+		if _, ok := groups[key]; !ok {
+			delete(m.cursors, key)
+		}
+	}
+	if len(groups) == 0 && len(m.pending) == 0 {
+		return
+	}
+	chains, err := api.GetChainConfigs()
+	if err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+
+	for key, ws := range groups {
+		w := ws[0].Wallet
+		cfg, ok := chains.Get(w.ChainCode)
+		if !ok {
+			continue
+		}
+		cursor := m.cursors[key]
+		swaps, next, err := rpc.PollWalletSwaps(w.ChainCode, w.Address, cfg.Wrapped, cursor)
+		if err != nil {
+			log.Error().Err(err).Str("wallet", key).Msg("poll smart wallet")
+		}
+		m.cursors[key] = next
+		for _, s := range swaps {
+			m.merge(w.Address, s)
+		}
+	}
+
+	now := time.Now()
+	for k, t := range m.pending {
+		if now.Sub(t.First) < mergeWindow {
+			continue
+		}
+		delete(m.pending, k)
+		go notify(*t, groups[model.AddressKey(t.ChainCode, t.Wallet)])
+	}
+}
+
+func (m *monitor) merge(wallet string, s rpc.WalletSwap) {
+	t := &model.SmartTrade{ChainCode: s.ChainCode, Wallet: wallet, Token: s.Token, IsBuy: s.IsBuy, First: time.Now()}
+	if v, ok := m.pending[t.Key()]; ok {
+		t = v
+	} else {
+		m.pending[t.Key()] = t
+	}
+	t.Amount = t.Amount.Add(s.TokenAmount)
+	t.Txs = append(t.Txs, s.Tx)
+}
+
+func notify(t model.SmartTrade, ws []watcher) {
+	if len(ws) == 0 || len(t.Txs) == 0 {
+		return
+	}
+	info := api.SearchTokenInfo(t.Token)
+	price, _ := decimal.NewFromString(info.Price)
+	if !price.IsPositive() {
+		log.Debug().Str("token", t.Token).Msg("smart money token without price")
+		return
+	}
+	amount := t.Amount.Shift(-int32(info.BaseToken.Decimals))
+	t.Volume = amount.Mul(price)
+	marketCap, _ := decimal.NewFromString(info.MarketCap)
+	symbol := info.Symbol
+	if symbol == "" {
+		symbol = util.ShortAddress(t.Token)
+	}
+
+	for _, w := range ws {
+		if !w.Wallet.Want(t) {
+			continue
+		}
+		claimed, err := store.ClaimOnce("smart:"+cast.ToString(w.ChatId)+":"+t.Txs[0], alertClaimTTL)
+		if err != nil {
+			log.Error().Err(err).Send()
+		} else if !claimed {
+			continue
+		}
+
+		label := w.Wallet.Label
+		if label == "" {
+			label = util.ShortAddress(w.Wallet.Address)
+		}
+		lang := i18n.UserLang(w.ChatId)
+		text, _, err := template.RenderPush(lang, model.PushEvent{
+			Version:     model.PushVersion,
+			Kind:        model.PushKindSmartMoney,
+			ChainCode:   t.ChainCode,
+			BaseAddress: t.Token,
+			Symbol:      symbol,
+			Price:       price,
+			Amount:      amount,
+			Volume:      t.Volume,
+			IsBuy:       t.IsBuy,
+			Wallet:      w.Wallet.Address,
+			WalletLabel: label,
+			Txs:         len(t.Txs),
+			MarketCap:   marketCap,
+		})
+		if err != nil {
+			log.Error().Err(err).Int64("chatId", w.ChatId).Msg("render smart money alert")
+			continue
+		}
+		kb := keyboard(w.ChatId, lang, t.ChainCode)
+
+		err = alert.Deliver(model.AlertPush{
+			ChatId: w.ChatId,
+			Key:    t.Key(),
+			Text:   text,
+			Req: &model.PusherHandlerReqData{
+				ChainCode:   t.ChainCode,
+				BaseAddress: t.Token,
+				MonitorType: model.PushKindSmartMoney,
+			},
+			Markup: &kb,
+			Symbol: symbol,
+			Type:   model.PushKindSmartMoney,
+			Value:  t.Volume.Round(2).String(),
+		})
+		if err != nil {
+			log.Error().Err(err).Int64("chatId", w.ChatId).Msg("deliver smart money alert")
+		}
+	}
+}
+
+// quick buy amounts of user trade preset
+func buyAmounts(chatId int64, chainCode string) []string {
+	preset := model.DefaultTradePreset(chainCode)
+	if data, has := store.UserGetTradePreset(chatId, chainCode); has {
+		var p model.TradePreset
+		if err := json.Unmarshal(data, &p); err == nil && p.Vaild() == nil {
+			preset = p
+		}
+	}
+	return preset.Buy[:min(quickBuyLen, len(preset.Buy))]
+}
+
+// QuickBuyRow quick buy buttons of push, token is read from the push by smq:: callback
+func QuickBuyRow(chatId int64, lang string, chainCode string) []models.InlineKeyboardButton {
+	symbol := chainCode
+	if chains, err := api.GetChainConfigs(); err == nil {
+		if cfg, ok := chains.Get(chainCode); ok {
+			symbol = cfg.Symbol
+		}
+	}
+	var buys []models.InlineKeyboardButton
+	for _, amount := range buyAmounts(chatId, chainCode) {
+		buys = append(buys, util.NewCallbackDataButton(i18n.T(lang, "smartmoney.quick_buy", amount, symbol), "smq::"+amount))
+	}
+	return buys
+}
+
+func keyboard(chatId int64, lang string, chainCode string) models.InlineKeyboardMarkup {
+	return models.InlineKeyboardMarkup{InlineKeyboard: [][]models.InlineKeyboardButton{
+		QuickBuyRow(chatId, lang, chainCode),
+		{
+			util.NewCallbackDataButton(i18n.T(lang, "aimonitor.pusher.trade"), "ai_sendNewupdate"),
+			entity.GetCallbackButtonLang(entity.SMART_MONEY, lang),
+		},
+	}}
+}
+
+func notifyBuy(ctx context.Context, b *bot.Bot, chatId int64, key string, args ...any) {
+	util.QuickMessage(ctx, b, chatId, i18n.L(chatId, key, args...))
+}
+
+// Buy token with native coin from default wallet, or first wallet of chain
+func Buy(ctx context.Context, b *bot.Bot, chatId int64, chainCode, token string, amount decimal.Decimal) {
+	symbol := util.ShortAddress(token)
+	skip := func(reason string) {
+		notifyBuy(ctx, b, chatId, "smartmoney.buy_skip", symbol, reason)
+	}
+
+	userInfo, err := api.GetUserProfile(chatId)
+	if err != nil {
+		log.Error().Err(err).Send()
+		skip(i18n.L(chatId, "common.error"))
+		return
+	}
+	wallet, ok := trade.DefaultWallet(userInfo, chainCode)
+	if !ok {
+		skip(i18n.L(chatId, "alert.action_reason_wallet"))
+		return
+	}
+
+	pos, err := api.GetPositionByWalletAddress(wallet.Wallet, token, chainCode, userInfo)
+	if err != nil || pos.Data.BaseToken.Address == "" {
+		log.Error().Err(err).Str("token", token).Msg("smart money buy get pair")
+		skip(i18n.L(chatId, "common.error"))
+		return
+	}
+	baseToken, quoteToken := pos.Data.BaseToken, pos.Data.QuoteToken
+	symbol = baseToken.Symbol
+
+	chains, err := api.GetChainConfigs()
+	if err != nil {
+		log.Error().Err(err).Send()
+		skip(i18n.L(chatId, "common.error"))
+		return
+	}
+	// 买入金额按原生币计算
+	if cfg, ok := chains.Get(chainCode); !ok || !cfg.IsNative(quoteToken.Address) {
+		skip(i18n.L(chatId, "alert.action_reason_quote"))
+		return
+	}
+
+	swap := model.Swap{
+		WalletId:   wallet.WalletId,
+		WalletKey:  wallet.WalletKey,
+		Slippage:   userInfo.Data.Slippage,
+		Price:      pos.Data.Price,
+		TradeType:  trade.SwapTradeType,
+		ProfitFlag: 0,
+		Type:       trade.SwapBuy,
+	}
+	swap.FromTokenAddress, swap.FromTokenDecimals = quoteToken.Address, cast.ToInt(quoteToken.Decimals)
+	swap.ToTokenAddress, swap.ToTokenDecimals = baseToken.Address, cast.ToInt(baseToken.Decimals)
+	swap.Amount = amount.Shift(int32(swap.FromTokenDecimals)).Truncate(0).String()
+
+	raw, _ := decimal.NewFromString(swap.Amount)
+	if sf, err := trade.Precheck(chatId, userInfo, wallet, pos.Data, true, raw); err != nil {
+		if errors.Is(err, trade.ErrSafetyBlocked) {
+			notifyBuy(ctx, b, chatId, "safety.blocked", baseToken.Symbol, sf.Score)
+			return
+		}
+		log.Debug().Err(err).Int64("chatId", chatId).Msg("smart money buy precheck")
+		skip(trade.Reason(chatId, err, baseToken.Symbol, sf))
+		return
+	}
+
+	notifyBuy(ctx, b, chatId, "smartmoney.buy", baseToken.Symbol, amount.String(), quoteToken.Symbol)
+	// 成交后由交易队列发送带交易链接的确认消息
+	err = queue.AddProcessingSwapQueue(&queue.SwapPayload{
+		B:               b,
+		SwapBody:        swap,
+		BaseToken:       baseToken,
+		QuoteToken:      quoteToken,
+		UserInfo:        userInfo,
+		UserID:          chatId,
+		HandleWallet:    wallet,
+		UserInputAmount: amount.String(),
+		Auto:            true,
+	})
+	if err != nil {
+		log.Error().Err(err).Int64("chatId", chatId).Msg("add smart money swap")
+		skip(i18n.L(chatId, "common.error"))
+	}
+}
@@ -0,0 +1,360 @@
+package sniper
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/hellodex/tradingbot/alert"
+	"github.com/hellodex/tradingbot/api"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/queue"
+	"github.com/hellodex/tradingbot/rpc"
+	"github.com/hellodex/tradingbot/store"
+	"github.com/hellodex/tradingbot/trade"
+	"github.com/hellodex/tradingbot/util"
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cast"
+	"github.com/tidwall/gjson"
+)
+
+var (
+	// 重新加载狙击列表, 启动/停止 token 监听
+	reloadInterval = 10 * time.Second
+	// 订阅断开或者一直没有事件时的兜底检查
+	checkInterval = 15 * time.Second
+	// 链上事件可能很密集, 两次检查的最小间隔
+	minCheckGap = 2 * time.Second
+)
+
+// 上线时出现这些风险直接取消狙击
+var cancelChecks = map[string]bool{
+	"safety.sol.mint_authority":   true,
+	"safety.sol.freeze_authority": true,
+	"safety.bsc.honeypot":         true,
+}
+
+type armed struct {
+	ChatId int64
+	Snipe  model.Snipe
+	B      *bot.Bot
+}
+
+// 内存中的狙击列表, 定时或保存/删除狙击后重新加载, 链上事件触发检查时不读 redis
+var (
+	armedMu     sync.RWMutex
+	armedGroups = map[string][]armed{}
+	reloadNow   = make(chan struct{}, 1)
+)
+
+// user snipes, sort by created time
+func UserSnipes(chatId int64) []model.Snipe {
+	data, err := store.UserGetSnipes(chatId)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return nil
+	}
+
+	snipes := make([]model.Snipe, 0, len(data))
+	for _, v := range data {
+		var s model.Snipe
+		if err := json.Unmarshal([]byte(v), &s); err != nil {
+			log.Error().Err(err).Send()
+			continue
+		}
+		snipes = append(snipes, s)
+	}
+	slices.SortFunc(snipes, func(a, b model.Snipe) int {
+		return cmp.Compare(a.CreatedAt, b.CreatedAt)
+	})
+	return snipes
+}
+
+func UserSnipe(chatId int64, id string) (model.Snipe, bool) {
+	for _, s := range UserSnipes(chatId) {
+		if s.Id == id {
+			return s, true
+		}
+	}
+	return model.Snipe{}, false
+}
+
+func SaveSnipe(chatId int64, s model.Snipe) error {
+	data, err := s.JsonB()
+	if err != nil {
+		return err
+	}
+	if err := store.UserAddSnipe(chatId, s.Id, data); err != nil {
+		return err
+	}
+	reload()
+	return nil
+}
+
+// DeleteSnipe false if snipe is already deleted or fired
+func DeleteSnipe(chatId int64, id string) (bool, error) {
+	deleted, err := store.UserDeleteSnipe(chatId, id)
+	if deleted {
+		reload()
+	}
+	return deleted, err
+}
+
+// reload armed snipes in Run loop
+func reload() {
+	select {
+	case reloadNow <- struct{}{}:
+	default:
+	}
+}
+
+// loadArmed snipes of users hosted by this instance, group by token key
+func loadArmed() (map[string][]armed, error) {
+	users, err := store.SnipeUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	groups := map[string][]armed{}
+	for _, chatId := range users {
+		b, ok := alert.UserBot(chatId)
+		if !ok {
+			continue
+		}
+		for _, s := range UserSnipes(chatId) {
+			groups[s.TokenKey()] = append(groups[s.TokenKey()], armed{ChatId: chatId, Snipe: s, B: b})
+		}
+	}
+	return groups, nil
+}
+
+func armedOf(key string) []armed {
+	armedMu.RLock()
+	defer armedMu.RUnlock()
+	return armedGroups[key]
+}
+
+// Run watch tokens of armed snipes and fire them until ctx done
+func Run(ctx context.Context) {
+	watchers := map[string]context.CancelFunc{}
+	ticker := time.NewTicker(reloadInterval)
+	defer ticker.Stop()
+
+	for {
+		groups, err := loadArmed()
+		if err != nil {
+			log.Error().Err(err).Send()
+		} else {
+			armedMu.Lock()
+			armedGroups = groups
+			armedMu.Unlock()
+
+			for key, stop := range watchers {
+				if _, ok := groups[key]; !ok {
+					stop()
+					delete(watchers, key)
+				}
+			}
+			for key, list := range groups {
+				if _, ok := watchers[key]; ok {
+					continue
+				}
+				wctx, stop := context.WithCancel(ctx)
+				watchers[key] = stop
+				go watch(wctx, list[0].Snipe.ChainCode, list[0].Snipe.Token)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-reloadNow:
+		}
+	}
+}
+
+// watch check snipes of token on every chain activity, and periodically in case subscription is down
+func watch(ctx context.Context, chainCode, token string) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	var events <-chan gjson.Result
+	var last time.Time
+	for {
+		if events == nil {
+			ch, err := rpc.SubscribeTokenActivity(ctx, chainCode, token)
+			if err != nil {
+				log.Debug().Err(err).Str("token", token).Msg("subscribe token activity")
+			} else {
+				events = ch
+			}
+		}
+
+		if wait := minCheckGap - time.Since(last); wait > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+		}
+		last = time.Now()
+		check(ctx, model.AddressKey(chainCode, token))
+
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-events:
+			if !ok {
+				// 下次循环重新订阅
+				events = nil
+			}
+		case <-ticker.C:
+		}
+	}
+}
+
+func check(ctx context.Context, key string) {
+	list := armedOf(key)
+	if len(list) == 0 {
+		return
+	}
+	chains, err := api.GetChainConfigs()
+	if err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+	cfg, ok := chains.Get(list[0].Snipe.ChainCode)
+	if !ok {
+		return
+	}
+	for _, a := range list {
+		fire(ctx, a, cfg)
+	}
+}
+
+func notify(ctx context.Context, b *bot.Bot, chatId int64, key string, args ...any) {
+	util.QuickMessage(ctx, b, chatId, i18n.L(chatId, key, args...))
+}
+
+func cancelSnipe(ctx context.Context, b *bot.Bot, chatId int64, s model.Snipe, reason string) {
+	if _, err := DeleteSnipe(chatId, s.Id); err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+	notify(ctx, b, chatId, "sniper.cancelled", util.ShortAddress(s.Token), reason)
+}
+
+// fire buy if token is tradable and passes pre-checks, otherwise keep waiting
+func fire(ctx context.Context, a armed, cfg model.ChainConfig) {
+	s, chatId, b := a.Snipe, a.ChatId, a.B
+	userInfo, err := api.GetUserProfile(chatId)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+	wallet, ok := trade.Wallet(userInfo, s.ChainCode, s.WalletId)
+	if !ok {
+		cancelSnipe(ctx, b, chatId, s, i18n.L(chatId, "sniper.reason_wallet"))
+		return
+	}
+
+	// 还在 pump.fun 内盘时等待迁移
+	if s.Migration && s.IsPumpToken() {
+		found, complete, err := rpc.SOL_PumpCurve(s.Token)
+		if err != nil {
+			log.Debug().Err(err).Str("token", s.Token).Msg("get pump curve")
+			return
+		}
+		if found && !complete {
+			return
+		}
+	}
+
+	pos, err := api.GetPositionByWalletAddress(wallet.Wallet, s.Token, s.ChainCode, userInfo)
+	if err != nil {
+		log.Debug().Err(err).Str("token", s.Token).Msg("snipe get pair")
+		return
+	}
+	baseToken, quoteToken := pos.Data.BaseToken, pos.Data.QuoteToken
+	// 没有池子或者不是原生币池子时继续等待
+	if baseToken.Address == "" || pos.Data.PairAddress == "" || !cfg.IsNative(quoteToken.Address) {
+		return
+	}
+	if !s.LiquidityOK(pos.Data.Tvl) {
+		return
+	}
+
+	// 不使用缓存, 上线时的状态可能和之前不一样
+	safety, err := rpc.TokenSafety(s.ChainCode, baseToken.Address, pos.Data.PairAddress)
+	if err != nil {
+		log.Error().Err(err).Str("token", s.Token).Msg("snipe pre-check")
+	}
+	for _, c := range safety.Checks {
+		if cancelChecks[c.Key] {
+			cancelSnipe(ctx, b, chatId, s, i18n.L(chatId, c.Key, c.FmtArgs()...))
+			return
+		}
+	}
+
+	amount, _ := decimal.NewFromString(s.Amount)
+	raw := amount.Shift(int32(cast.ToInt(quoteToken.Decimals))).Truncate(0)
+	if sf, err := trade.Precheck(chatId, userInfo, wallet, pos.Data, true, raw); err != nil {
+		// 钱包或余额问题不会自己恢复, 取消狙击; 接口错误下次再试
+		if errors.Is(err, trade.ErrWalletChain) || errors.Is(err, trade.ErrInsufficient) || errors.Is(err, trade.ErrSafetyBlocked) {
+			cancelSnipe(ctx, b, chatId, s, trade.Reason(chatId, err, baseToken.Symbol, sf))
+			return
+		}
+		log.Debug().Err(err).Str("token", s.Token).Msg("snipe precheck")
+		return
+	}
+
+	// 删除成功才下单, 保证只触发一次
+	deleted, err := DeleteSnipe(chatId, s.Id)
+	if err != nil || !deleted {
+		return
+	}
+
+	swap := model.Swap{
+		WalletId:   wallet.WalletId,
+		WalletKey:  wallet.WalletKey,
+		Slippage:   userInfo.Data.Slippage,
+		Price:      pos.Data.Price,
+		TradeType:  trade.SwapTradeType,
+		ProfitFlag: 0,
+		Type:       trade.SwapBuy,
+	}
+	if slippage := cast.ToFloat64(s.Slippage); slippage > 0 {
+		swap.Slippage = userInfo.ToPercentage(slippage)
+	}
+	if fee, _ := decimal.NewFromString(s.PriorityFee); fee.IsPositive() {
+		swap.PriorityFee = fee.String()
+	}
+	swap.FromTokenAddress, swap.FromTokenDecimals = quoteToken.Address, cast.ToInt(quoteToken.Decimals)
+	swap.ToTokenAddress, swap.ToTokenDecimals = baseToken.Address, cast.ToInt(baseToken.Decimals)
+	swap.Amount = amount.Shift(int32(swap.FromTokenDecimals)).Truncate(0).String()
+
+	notify(ctx, b, chatId, "sniper.fired", baseToken.Symbol, amount.String(), quoteToken.Symbol)
+	err = queue.AddProcessingSwapQueue(&queue.SwapPayload{
+		B:               b,
+		SwapBody:        swap,
+		BaseToken:       baseToken,
+		QuoteToken:      quoteToken,
+		UserInfo:        userInfo,
+		UserID:          chatId,
+		HandleWallet:    wallet,
+		UserInputAmount: amount.String(),
+		Auto:            true,
+	})
+	if err != nil {
+		log.Error().Err(err).Int64("chatId", chatId).Msg("add snipe swap")
+		notify(ctx, b, chatId, "common.error")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,10 +18,10 @@ var redisClient *redis.Client
 
 func InitRedis() {
 	rdb := redis.NewClient(&redis.Options{
-		Addr:            fmt.Sprintf("%s:%d", config.YmlConfig.Redis.Ip, config.YmlConfig.Redis.Port),
-		Username:        config.YmlConfig.Redis.Username,
-		Password:        config.YmlConfig.Redis.Passwd,
-		DB:              config.YmlConfig.Redis.Db,
+		Addr:            fmt.Sprintf("%s:%d", config.Get().Redis.Ip, config.Get().Redis.Port),
+		Username:        config.Get().Redis.Username,
+		Password:        config.Get().Redis.Passwd,
+		DB:              config.Get().Redis.Db,
 		PoolSize:        10,
 		MinIdleConns:    5,
 		MaxIdleConns:    10,
@@ -35,7 +36,7 @@ func InitRedis() {
 
 	ctx := context.Background()
 	_, err := rdb.Ping(ctx).Result()
-	log.Debug().Msgf("connecting redis [%s:%d]", config.YmlConfig.Redis.Ip, config.YmlConfig.Redis.Port)
+	log.Debug().Msgf("connecting redis [%s:%d]", config.Get().Redis.Ip, config.Get().Redis.Port)
 	if err != nil {
 		log.Fatal().Err(err).Msg("redis init error")
 	}
@@ -157,11 +158,11 @@ func SubChannel(channelName string) (<-chan *redis.Message, error) {
 
 	ctx := context.Background()
 	redisC := NewRedisClient(
-		config.YmlConfig.RedisPush.Ip,
-		config.YmlConfig.RedisPush.Port,
-		config.YmlConfig.RedisPush.Username,
-		config.YmlConfig.RedisPush.Passwd,
-		config.YmlConfig.RedisPush.Db,
+		config.Get().RedisPush.Ip,
+		config.Get().RedisPush.Port,
+		config.Get().RedisPush.Username,
+		config.Get().RedisPush.Passwd,
+		config.Get().RedisPush.Db,
 	)
 
 	ch := make(chan *redis.Message, 1000)
@@ -287,14 +288,18 @@ func UserDeleteAiMonitorInfo(userId int64) {
 	}
 }
 
-func UserSetBot(userId int64, uuid string) error {
+// UserSetBot bot of user used by pusher, botId is empty for bot of env
+func UserSetBot(userId int64, botId string, uuid string) error {
 	checkRedis()
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	setKey := fmt.Sprintf("%s::%s", "userInBot", uuid)
 
-	id := GetEnv(BOT_ID)
+	id := botId
+	if id == "" {
+		id = GetEnv(BOT_ID)
+	}
 	log.Debug().Str("get bot id", id).Send()
 
 	value := fmt.Sprintf("%s::%d", id, userId)
@@ -412,6 +417,34 @@ func UserGetOrderHistory(chatId int64) ([]byte, bool) {
 	return data, true
 }
 
+// kind: trade / transfer / order, walletId 区分不同钱包的记录
+func UserSetHistory(chatId int64, kind string, walletId string, data []byte, expir time.Duration) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%s:%s:%d", "history", kind, walletId, chatId)
+
+	if err := redisClient.Set(ctx, key, data, expir).Err(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func UserGetHistory(chatId int64, kind string, walletId string) ([]byte, bool) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%s:%s:%d", "history", kind, walletId, chatId)
+	data, err := redisClient.Get(ctx, key).Bytes()
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
 func RedisSetState(chatId int64, stateName string, state string, expir time.Duration) error {
 	checkRedis()
 	ctx, cancel := context.WithCancel(context.Background())
@@ -578,3 +611,1063 @@ func GetBotsStatus() (map[string]string, error) {
 
 	return result, nil
 }
+
+// quick trade presets, hash field is chainCode.
+// backend user profile only keeps slippage and default wallet, so presets are kept here
+func UserSetTradePreset(chatId int64, chainCode string, data []byte) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "tradePreset", chatId)
+
+	if err := redisClient.HSet(ctx, key, chainCode, data).Err(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func UserGetTradePreset(chatId int64, chainCode string) ([]byte, bool) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "tradePreset", chatId)
+	data, err := redisClient.HGet(ctx, key, chainCode).Bytes()
+	if err != nil {
+		if err != redis.Nil {
+			log.Debug().Err(err).Send()
+		}
+		return nil, false
+	}
+	return data, true
+}
+
+func UserDeleteTradePreset(chatId int64, chainCode string) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "tradePreset", chatId)
+	redisClient.HDel(ctx, key, chainCode)
+}
+
+func GroupSetSetting(groupId int64, data []byte) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "groupSetting", groupId)
+
+	if err := redisClient.Set(ctx, key, data, 0).Err(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func GroupGetSetting(groupId int64) ([]byte, bool) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "groupSetting", groupId)
+	data, err := redisClient.Get(ctx, key).Bytes()
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// user language setting, empty is follow telegram
+func UserSetLang(chatId int64, lang string) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "lang", chatId)
+
+	if err := redisClient.Set(ctx, key, lang, 0).Err(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func UserGetLang(chatId int64) (string, bool) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "lang", chatId)
+	lang, err := redisClient.Get(ctx, key).Result()
+	if err != nil {
+		return "", false
+	}
+	return lang, true
+}
+
+// watchlist, hash field is token address
+func UserAddWatchToken(chatId int64, address string, data []byte) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "watchlist", chatId)
+
+	if err := redisClient.HSet(ctx, key, address, data).Err(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func UserGetWatchlist(chatId int64) (map[string]string, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "watchlist", chatId)
+	return redisClient.HGetAll(ctx, key).Result()
+}
+
+func UserHasWatchToken(chatId int64, address string) bool {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "watchlist", chatId)
+	has, err := redisClient.HExists(ctx, key, address).Result()
+	if err != nil {
+		log.Debug().Err(err).Send()
+		return false
+	}
+	return has
+}
+
+func UserDeleteWatchToken(chatId int64, address string) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "watchlist", chatId)
+	return redisClient.HDel(ctx, key, address).Err()
+}
+
+// address book, hash field is entry id
+func UserAddAddressBook(chatId int64, id string, data []byte) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "addressBook", chatId)
+
+	if err := redisClient.HSet(ctx, key, id, data).Err(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func UserGetAddressBook(chatId int64) (map[string]string, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "addressBook", chatId)
+	return redisClient.HGetAll(ctx, key).Result()
+}
+
+func UserDeleteAddressBook(chatId int64, id string) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "addressBook", chatId)
+	return redisClient.HDel(ctx, key, id).Err()
+}
+
+// addresses user has sent to, member is chainCode:address
+func UserMarkAddressUsed(chatId int64, member string) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "usedAddress", chatId)
+	return redisClient.SAdd(ctx, key, member).Err()
+}
+
+func UserAddressUsed(chatId int64, member string) bool {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "usedAddress", chatId)
+	has, err := redisClient.SIsMember(ctx, key, member).Result()
+	if err != nil {
+		log.Debug().Err(err).Send()
+		return false
+	}
+	return has
+}
+
+// security pin, only salt and hash are stored
+func UserSetPin(chatId int64, data []byte) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "securityPin", chatId)
+
+	if err := redisClient.Set(ctx, key, data, 0).Err(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// has is false and err is nil when user has no pin
+func UserGetPin(chatId int64) ([]byte, bool, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "securityPin", chatId)
+	data, err := redisClient.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+func UserDeletePin(chatId int64) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "securityPin", chatId)
+	return redisClient.Del(ctx, key).Err()
+}
+
+// UserIncrPinFail count pin attempt before checking it, key expires with the lockout window
+func UserIncrPinFail(chatId int64, window time.Duration) (int64, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "pinFail", chatId)
+	pipe := redisClient.TxPipeline()
+	incrCmd := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return incrCmd.Val(), nil
+}
+
+// UserGetPinFail return fail count and time left before it's cleared
+func UserGetPinFail(chatId int64) (int64, time.Duration, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "pinFail", chatId)
+	pipe := redisClient.Pipeline()
+	getCmd := pipe.Get(ctx, key)
+	ttlCmd := pipe.TTL(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return 0, 0, err
+	}
+	count, err := getCmd.Int64()
+	if err == redis.Nil {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	return count, ttlCmd.Val(), nil
+}
+
+func UserResetPinFail(chatId int64) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "pinFail", chatId)
+	return redisClient.Del(ctx, key).Err()
+}
+
+func UserSetWhitelist(chatId int64, data []byte) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "transferWhitelist", chatId)
+
+	if err := redisClient.Set(ctx, key, data, 0).Err(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// has is false and err is nil when user never set whitelist
+func UserGetWhitelist(chatId int64) ([]byte, bool, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "transferWhitelist", chatId)
+	data, err := redisClient.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+// copy trade, hash field is copy trade id
+// users with copy trade are kept in a set so the watcher can load all of them
+func UserAddCopyTrade(chatId int64, id string, data []byte) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "copyTrade", chatId)
+
+	if err := redisClient.HSet(ctx, key, id, data).Err(); err != nil {
+		return err
+	}
+
+	return redisClient.SAdd(ctx, "copyTradeUsers", chatId).Err()
+}
+
+func UserGetCopyTrades(chatId int64) (map[string]string, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "copyTrade", chatId)
+	return redisClient.HGetAll(ctx, key).Result()
+}
+
+func UserDeleteCopyTrade(chatId int64, id string) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "copyTrade", chatId)
+	if err := redisClient.HDel(ctx, key, id).Err(); err != nil {
+		return err
+	}
+
+	n, err := redisClient.HLen(ctx, key).Result()
+	if err != nil || n > 0 {
+		return err
+	}
+	return redisClient.SRem(ctx, "copyTradeUsers", chatId).Err()
+}
+
+func CopyTradeUsers() ([]int64, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	members, err := redisClient.SMembers(ctx, "copyTradeUsers").Result()
+	if err != nil {
+		return nil, err
+	}
+	users := make([]int64, 0, len(members))
+	for _, m := range members {
+		id, err := strconv.ParseInt(m, 10, 64)
+		if err != nil {
+			continue
+		}
+		users = append(users, id)
+	}
+	return users, nil
+}
+
+// last handled signature / block of watched wallet, leaderKey is chainCode:address
+func GetCopyTradeCursor(leaderKey string) string {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%s", "copyTradeCursor", leaderKey)
+	cursor, err := redisClient.Get(ctx, key).Result()
+	if err != nil && err != redis.Nil {
+		log.Debug().Err(err).Send()
+	}
+	return cursor
+}
+
+func SetCopyTradeCursor(leaderKey string, cursor string) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%s", "copyTradeCursor", leaderKey)
+	return redisClient.Set(ctx, key, cursor, 7*24*time.Hour).Err()
+}
+
+// token safety scan result cache
+func SetTokenSafety(chainCode string, token string, data []byte, expir time.Duration) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%s:%s", "tokenSafety", chainCode, token)
+	return redisClient.Set(ctx, key, data, expir).Err()
+}
+
+func GetTokenSafety(chainCode string, token string) ([]byte, bool) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%s:%s", "tokenSafety", chainCode, token)
+	data, err := redisClient.Get(ctx, key).Bytes()
+	if err != nil {
+		if err != redis.Nil {
+			log.Debug().Err(err).Send()
+		}
+		return nil, false
+	}
+	return data, true
+}
+
+// block buy when token risk score >= score, 0 means off
+func UserSetSafetyBlock(chatId int64, score int) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "safetyBlock", chatId)
+	return redisClient.Set(ctx, key, score, 0).Err()
+}
+
+func UserGetSafetyBlock(chatId int64) int {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "safetyBlock", chatId)
+	score, err := redisClient.Get(ctx, key).Int()
+	if err != nil {
+		if err != redis.Nil {
+			log.Debug().Err(err).Send()
+		}
+		return 0
+	}
+	return score
+}
+
+// armed snipes, hash field is snipe id
+func UserAddSnipe(chatId int64, id string, data []byte) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "snipe", chatId)
+
+	if err := redisClient.HSet(ctx, key, id, data).Err(); err != nil {
+		return err
+	}
+
+	return redisClient.SAdd(ctx, "snipeUsers", chatId).Err()
+}
+
+func UserGetSnipes(chatId int64) (map[string]string, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "snipe", chatId)
+	return redisClient.HGetAll(ctx, key).Result()
+}
+
+// deleted is false if snipe not exists, used to make sure a snipe only fires once
+func UserDeleteSnipe(chatId int64, id string) (deleted bool, err error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "snipe", chatId)
+	n, err := redisClient.HDel(ctx, key, id).Result()
+	if err != nil {
+		return false, err
+	}
+
+	left, err := redisClient.HLen(ctx, key).Result()
+	if err != nil || left > 0 {
+		return n > 0, err
+	}
+	return n > 0, redisClient.SRem(ctx, "snipeUsers", chatId).Err()
+}
+
+func SnipeUsers() ([]int64, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	members, err := redisClient.SMembers(ctx, "snipeUsers").Result()
+	if err != nil {
+		return nil, err
+	}
+	users := make([]int64, 0, len(members))
+	for _, m := range members {
+		id, err := strconv.ParseInt(m, 10, 64)
+		if err != nil {
+			continue
+		}
+		users = append(users, id)
+	}
+	return users, nil
+}
+
+// users have ai monitor rules, evaluated by local alert engine
+func AddAlertUser(chatId int64) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	return redisClient.SAdd(ctx, "alertUsers", chatId).Err()
+}
+
+func RemoveAlertUser(chatId int64) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	return redisClient.SRem(ctx, "alertUsers", chatId).Err()
+}
+
+func AlertUsers() ([]int64, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	members, err := redisClient.SMembers(ctx, "alertUsers").Result()
+	if err != nil {
+		return nil, err
+	}
+	users := make([]int64, 0, len(members))
+	for _, m := range members {
+		id, err := strconv.ParseInt(m, 10, 64)
+		if err != nil {
+			continue
+		}
+		users = append(users, id)
+	}
+	return users, nil
+}
+
+// op override of price rule, field is model.AlertOpKey
+func UserSetAlertOp(chatId int64, field, op string) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "alertOp", chatId)
+	if op == "" {
+		return redisClient.HDel(ctx, key, field).Err()
+	}
+	return redisClient.HSet(ctx, key, field, op).Err()
+}
+
+func UserGetAlertOps(chatId int64) (map[string]string, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "alertOp", chatId)
+	return redisClient.HGetAll(ctx, key).Result()
+}
+
+// last notice unix time of rules, field is rule key
+func UserGetAlertNotices(chatId int64) (map[string]string, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "alertNotice", chatId)
+	return redisClient.HGetAll(ctx, key).Result()
+}
+
+func UserSetAlertNotice(chatId int64, field string, at int64) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "alertNotice", chatId)
+	return redisClient.HSet(ctx, key, field, at).Err()
+}
+
+// whether compound alert held on last polled tick, shared by instances and kept after restart
+func UserGetAlertHold(chatId int64, field string) (bool, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "alertHold", chatId)
+	hold, err := redisClient.HGet(ctx, key, field).Bool()
+	if err == redis.Nil {
+		return false, nil
+	}
+	return hold, err
+}
+
+func UserSetAlertHold(chatId int64, field string, hold bool) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "alertHold", chatId)
+	return redisClient.HSet(ctx, key, field, hold).Err()
+}
+
+func UserAddCompoundAlert(chatId int64, id string, data []byte) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "compoundAlert", chatId)
+	return redisClient.HSet(ctx, key, id, data).Err()
+}
+
+func UserGetCompoundAlerts(chatId int64) (map[string]string, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "compoundAlert", chatId)
+	return redisClient.HGetAll(ctx, key).Result()
+}
+
+func UserDeleteCompoundAlert(chatId int64, id string) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "compoundAlert", chatId)
+	return redisClient.HDel(ctx, key, id).Err()
+}
+
+func UserGetAlertDelivery(chatId int64) (string, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "alertDelivery", chatId)
+	return redisClient.Get(ctx, key).Result()
+}
+
+func UserSetAlertDelivery(chatId int64, data []byte) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "alertDelivery", chatId)
+	return redisClient.Set(ctx, key, data, 0).Err()
+}
+
+// last delivered unix time of token and rule, for cooldown
+func UserGetAlertCooldown(chatId int64, field string) (int64, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "alertCooldown", chatId)
+	at, err := redisClient.HGet(ctx, key, field).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return at, err
+}
+
+func UserSetAlertCooldown(chatId int64, field string, at int64) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "alertCooldown", chatId)
+	return redisClient.HSet(ctx, key, field, at).Err()
+}
+
+// count alerts suppressed by cooldown since last delivery
+func UserIncrAlertSuppressed(chatId int64) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "alertSuppressed", chatId)
+	return redisClient.Incr(ctx, key).Err()
+}
+
+// get and reset suppressed count
+func UserTakeAlertSuppressed(chatId int64) (int64, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "alertSuppressed", chatId)
+	n, err := redisClient.GetDel(ctx, key).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return n, err
+}
+
+// alerts held by quiet hours or digest mode
+func UserQueueAlert(chatId int64, data []byte) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "alertQueue", chatId)
+	if err := redisClient.RPush(ctx, key, data).Err(); err != nil {
+		return err
+	}
+	return redisClient.SAdd(ctx, "alertQueued", chatId).Err()
+}
+
+// take all queued alerts of user
+func UserTakeAlertQueue(chatId int64) ([]string, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "alertQueue", chatId)
+	pipe := redisClient.TxPipeline()
+	items := pipe.LRange(ctx, key, 0, -1)
+	pipe.Del(ctx, key)
+	pipe.SRem(ctx, "alertQueued", chatId)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+	return items.Val(), nil
+}
+
+func AlertQueuedUsers() ([]int64, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	members, err := redisClient.SMembers(ctx, "alertQueued").Result()
+	if err != nil {
+		return nil, err
+	}
+	users := make([]int64, 0, len(members))
+	for _, m := range members {
+		id, err := strconv.ParseInt(m, 10, 64)
+		if err != nil {
+			continue
+		}
+		users = append(users, id)
+	}
+	return users, nil
+}
+
+// last digest unix time of user
+func UserGetAlertDigestAt(chatId int64) (int64, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	at, err := redisClient.HGet(ctx, "alertDigestAt", strconv.FormatInt(chatId, 10)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return at, err
+}
+
+func UserSetAlertDigestAt(chatId int64, at int64) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	return redisClient.HSet(ctx, "alertDigestAt", strconv.FormatInt(chatId, 10), at).Err()
+}
+
+// alert auto-trade actions, hash field is action id
+func UserAddAlertAction(chatId int64, id string, data []byte) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "alertAction", chatId)
+	return redisClient.HSet(ctx, key, id, data).Err()
+}
+
+func UserGetAlertActions(chatId int64) (map[string]string, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "alertAction", chatId)
+	return redisClient.HGetAll(ctx, key).Result()
+}
+
+func UserDeleteAlertAction(chatId int64, id string) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "alertAction", chatId)
+	return redisClient.HDel(ctx, key, id).Err()
+}
+
+// executions of alert action in day, incr by n and return the new count
+func UserIncrAlertActionCount(chatId int64, id string, day string, n int64) (int64, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d:%s", "alertActionCount", chatId, day)
+	count, err := redisClient.HIncrBy(ctx, key, id, n).Result()
+	if err != nil {
+		return 0, err
+	}
+	// 时区不同, 多保留一天
+	return count, redisClient.Expire(ctx, key, 48*time.Hour).Err()
+}
+
+func UserGetAlertActionCount(chatId int64, id string, day string) (int64, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d:%s", "alertActionCount", chatId, day)
+	count, err := redisClient.HGet(ctx, key, id).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return count, err
+}
+
+// alert history, newest first, keep max records
+func UserAddAlertRecord(chatId int64, data []byte, max int64) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "alertHistory", chatId)
+	pipe := redisClient.TxPipeline()
+	pipe.LPush(ctx, key, data)
+	pipe.LTrim(ctx, key, 0, max-1)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func UserGetAlertRecords(chatId int64, start, stop int64) ([]string, int64, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "alertHistory", chatId)
+	total, err := redisClient.LLen(ctx, key).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+	list, err := redisClient.LRange(ctx, key, start, stop).Result()
+	return list, total, err
+}
+
+// trigger count and last record of rule
+func UserAddAlertStat(chatId int64, field string, last []byte) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pipe := redisClient.TxPipeline()
+	pipe.HIncrBy(ctx, fmt.Sprintf("%s:%d", "alertStatCount", chatId), field, 1)
+	pipe.HSet(ctx, fmt.Sprintf("%s:%d", "alertStatLast", chatId), field, last)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func UserGetAlertStats(chatId int64) (counts map[string]string, last map[string]string, err error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	counts, err = redisClient.HGetAll(ctx, fmt.Sprintf("%s:%d", "alertStatCount", chatId)).Result()
+	if err != nil {
+		return nil, nil, err
+	}
+	last, err = redisClient.HGetAll(ctx, fmt.Sprintf("%s:%d", "alertStatLast", chatId)).Result()
+	return counts, last, err
+}
+
+func UserAlertQueueLen(chatId int64) (int64, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "alertQueue", chatId)
+	return redisClient.LLen(ctx, key).Result()
+}
+
+// bot that delivers pushes to user
+func UserSetPushBot(chatId int64, botId int64) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	return redisClient.HSet(ctx, "pushBot", strconv.FormatInt(chatId, 10), botId).Err()
+}
+
+// 0 if unknown
+func UserGetPushBot(chatId int64) (int64, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	id, err := redisClient.HGet(ctx, "pushBot", strconv.FormatInt(chatId, 10)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return id, err
+}
+
+// ClaimOnce true for the first caller of key in ttl, used by multiple instances
+func ClaimOnce(key string, ttl time.Duration) (bool, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	return redisClient.SetNX(ctx, "claim:"+key, 1, ttl).Result()
+}
+
+func UserAddSmartWallet(chatId int64, id string, data []byte) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "smartWallet", chatId)
+
+	if err := redisClient.HSet(ctx, key, id, data).Err(); err != nil {
+		return err
+	}
+
+	return redisClient.SAdd(ctx, "smartMoneyUsers", chatId).Err()
+}
+
+func UserGetSmartWallets(chatId int64) (map[string]string, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "smartWallet", chatId)
+	return redisClient.HGetAll(ctx, key).Result()
+}
+
+func UserDeleteSmartWallet(chatId int64, id string) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "smartWallet", chatId)
+	if err := redisClient.HDel(ctx, key, id).Err(); err != nil {
+		return err
+	}
+
+	n, err := redisClient.HLen(ctx, key).Result()
+	if err != nil || n > 0 {
+		return err
+	}
+	return redisClient.SRem(ctx, "smartMoneyUsers", chatId).Err()
+}
+
+func SmartMoneyUsers() ([]int64, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	members, err := redisClient.SMembers(ctx, "smartMoneyUsers").Result()
+	if err != nil {
+		return nil, err
+	}
+	users := make([]int64, 0, len(members))
+	for _, m := range members {
+		id, err := strconv.ParseInt(m, 10, 64)
+		if err != nil {
+			continue
+		}
+		users = append(users, id)
+	}
+	return users, nil
+}
+
+func UserGetTokenFeed(chatId int64) ([]byte, bool) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "tokenFeed", chatId)
+	data, err := redisClient.Get(ctx, key).Bytes()
+	if err != nil {
+		if err != redis.Nil {
+			log.Error().Err(err).Send()
+		}
+		return nil, false
+	}
+	return data, true
+}
+
+// UserSetTokenFeed save feed settings, enabled users are in tokenFeedUsers
+func UserSetTokenFeed(chatId int64, data []byte, enabled bool) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d", "tokenFeed", chatId)
+	if err := redisClient.Set(ctx, key, data, 0).Err(); err != nil {
+		return err
+	}
+	if enabled {
+		return redisClient.SAdd(ctx, "tokenFeedUsers", chatId).Err()
+	}
+	return redisClient.SRem(ctx, "tokenFeedUsers", chatId).Err()
+}
+
+func TokenFeedUsers() ([]int64, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	members, err := redisClient.SMembers(ctx, "tokenFeedUsers").Result()
+	if err != nil {
+		return nil, err
+	}
+	users := make([]int64, 0, len(members))
+	for _, m := range members {
+		id, err := strconv.ParseInt(m, 10, 64)
+		if err != nil {
+			continue
+		}
+		users = append(users, id)
+	}
+	return users, nil
+}
+
+// UserIncrTokenFeedCount count of feed pushes in hour, like 2006010215
+func UserIncrTokenFeedCount(chatId int64, hour string) (int64, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := fmt.Sprintf("%s:%d:%s", "tokenFeedCount", chatId, hour)
+	count, err := redisClient.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	return count, redisClient.Expire(ctx, key, 2*time.Hour).Err()
+}
@@ -0,0 +1,282 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/hellodex/tradingbot/config"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cast"
+)
+
+var (
+	// 阻塞读取的超时时间, 超时后检查 ctx
+	streamBlock = 5 * time.Second
+	// 其他消费者超过这个时间没有 ack 的消息会被接管
+	streamClaimIdle = time.Minute
+)
+
+// StreamMessage message of push stream, Ack after it is handled
+type StreamMessage struct {
+	ID      string
+	Payload string
+	// archive entry id of push replayed by admin, empty for new push
+	Replay string
+	Ack    func()
+}
+
+func newPushClient() *redis.Client {
+	return NewRedisClient(
+		config.Get().RedisPush.Ip,
+		config.Get().RedisPush.Port,
+		config.Get().RedisPush.Username,
+		config.Get().RedisPush.Passwd,
+		config.Get().RedisPush.Db,
+	)
+}
+
+func streamMessage(c *redis.Client, stream, group string, m redis.XMessage) StreamMessage {
+	id := m.ID
+	return StreamMessage{
+		ID:      id,
+		Payload: cast.ToString(m.Values["data"]),
+		Replay:  cast.ToString(m.Values["replay"]),
+		Ack: func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := c.XAck(ctx, stream, group, id).Err(); err != nil {
+				log.Error().Err(err).Str("id", id).Msg("ack push stream")
+			}
+		},
+	}
+}
+
+// SubStream consume push stream with consumer group until ctx done.
+// pending messages of consumer are delivered first, and messages idle in other consumers are claimed
+func SubStream(ctx context.Context, stream, group, consumer string) (<-chan StreamMessage, error) {
+	redisC := newPushClient()
+	err := redisC.XGroupCreateMkStream(ctx, stream, group, "$").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		redisC.Close()
+		return nil, err
+	}
+	log.Debug().Str("stream", stream).Str("group", group).Str("consumer", consumer).Msg("sub stream")
+
+	ch := make(chan StreamMessage, 1000)
+	go func() {
+		defer close(ch)
+
+		send := func(m redis.XMessage) bool {
+			select {
+			case ch <- streamMessage(redisC, stream, group, m):
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		// 先读取自己没有 ack 的消息, 读完后再读新消息
+		start := "0"
+		lastClaim := time.Now()
+		for ctx.Err() == nil {
+			if start == ">" && time.Since(lastClaim) > streamClaimIdle {
+				lastClaim = time.Now()
+				msgs, _, err := redisC.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+					Stream:   stream,
+					Group:    group,
+					Consumer: consumer,
+					MinIdle:  streamClaimIdle,
+					Start:    "0",
+					Count:    100,
+				}).Result()
+				if err != nil && ctx.Err() == nil {
+					log.Error().Err(err).Str("stream", stream).Msg("claim push stream")
+				}
+				for _, m := range msgs {
+					if !send(m) {
+						return
+					}
+				}
+			}
+
+			res, err := redisC.XReadGroup(ctx, &redis.XReadGroupArgs{
+				Group:    group,
+				Consumer: consumer,
+				Streams:  []string{stream, start},
+				Count:    100,
+				Block:    streamBlock,
+			}).Result()
+			if err != nil {
+				if err == redis.Nil || ctx.Err() != nil {
+					continue
+				}
+				log.Error().Err(err).Str("stream", stream).Msg("read push stream")
+				time.Sleep(time.Second)
+				continue
+			}
+
+			n := 0
+			for _, s := range res {
+				for _, m := range s.Messages {
+					if !send(m) {
+						return
+					}
+					n++
+					if start != ">" {
+						start = m.ID
+					}
+				}
+			}
+			if start != ">" && n == 0 {
+				start = ">"
+			}
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		// 等待消息处理完再关闭
+		time.Sleep(streamBlock)
+		redisC.Close()
+	}()
+
+	return ch, nil
+}
+
+// PushStreamAdd add message to push stream, replay is archive entry id of replayed push
+func PushStreamAdd(stream string, payload string, replay string) error {
+	redisC := newPushClient()
+	defer redisC.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	values := map[string]any{"data": payload}
+	if replay != "" {
+		values["replay"] = replay
+	}
+	return redisC.XAdd(ctx, &redis.XAddArgs{Stream: stream, Values: values}).Err()
+}
+
+type ArchivedPush struct {
+	ID   string
+	Data string
+}
+
+// ArchivePush keep received pushes for replay, about max messages are kept. return entry id
+func ArchivePush(payload string, max int64) (string, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	return redisClient.XAdd(ctx, &redis.XAddArgs{
+		Stream: "pushArchive",
+		MaxLen: max,
+		Approx: true,
+		Values: map[string]any{"data": payload},
+	}).Result()
+}
+
+// ArchivedPushes pushes received in [from, to], at most count
+func ArchivedPushes(from, to time.Time, count int64) ([]ArchivedPush, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	msgs, err := redisClient.XRangeN(ctx, "pushArchive", cast.ToString(from.UnixMilli()), cast.ToString(to.UnixMilli()), count).Result()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]ArchivedPush, 0, len(msgs))
+	for _, m := range msgs {
+		list = append(list, ArchivedPush{ID: m.ID, Data: cast.ToString(m.Values["data"])})
+	}
+	return list, nil
+}
+
+// SetPushSent mark archived push as sent, it is skipped by replay
+func SetPushSent(id string, ttl time.Duration) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	return redisClient.Set(ctx, "pushSent:"+id, 1, ttl).Err()
+}
+
+// PushesSent sent flag of each archived push
+func PushesSent(ids []string) ([]bool, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pipe := redisClient.Pipeline()
+	cmds := make([]*redis.IntCmd, 0, len(ids))
+	for _, id := range ids {
+		cmds = append(cmds, pipe.Exists(ctx, "pushSent:"+id))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+	sent := make([]bool, 0, len(ids))
+	for _, c := range cmds {
+		sent = append(sent, c.Val() > 0)
+	}
+	return sent, nil
+}
+
+// push waiting for retry, score is unix time of next attempt
+func PushRetryAdd(data []byte, at int64) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	return redisClient.ZAdd(ctx, "pushRetry", redis.Z{Score: float64(at), Member: data}).Err()
+}
+
+func PushRetryDue(now int64, count int64) ([]string, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	return redisClient.ZRangeByScore(ctx, "pushRetry", &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   cast.ToString(now),
+		Count: count,
+	}).Result()
+}
+
+// PushRetryTake false if the push is taken by another instance
+func PushRetryTake(member string) (bool, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	n, err := redisClient.ZRem(ctx, "pushRetry", member).Result()
+	return n > 0, err
+}
+
+// dead letter of pushes failed permanently, newest first
+func PushDeadAdd(data []byte, max int64) error {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pipe := redisClient.TxPipeline()
+	pipe.LPush(ctx, "pushDead", data)
+	pipe.LTrim(ctx, "pushDead", 0, max-1)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func PushDeadList(start, stop int64) ([]string, int64, error) {
+	checkRedis()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	total, err := redisClient.LLen(ctx, "pushDead").Result()
+	if err != nil {
+		return nil, 0, err
+	}
+	list, err := redisClient.LRange(ctx, "pushDead", start, stop).Result()
+	return list, total, err
+}
@@ -2,10 +2,12 @@ package template
 
 import (
 	"github.com/flosch/pongo2/v6"
+	"github.com/hellodex/tradingbot/i18n"
 	"github.com/rs/zerolog/log"
 )
 
-var MyCommissionSummaryTempl = `
+var MyCommissionSummaryTempl = map[string]string{
+	i18n.ZH: `
 返佣比例: {{ body.commissionRate | formatPer }}
 邀请码: <code>{{ invitationCode }}</code>
 
@@ -25,11 +27,33 @@ TG BOT邀请返佣：
 <code>https://hellodex.io/Refer?invitationCode={{ invitationCode  }}</code> 
 
 
-`
+`,
+	i18n.EN: `
+Commission rate: {{ body.commissionRate | formatPer }}
+Invite code: <code>{{ invitationCode }}</code>
 
-func RanderMyCommissionSummary(invitationCode string, botUserName string, body map[string]any) (string, error) {
+Total invitees: {{ body.inviteeNum }}
+Trading invitees: {{ body.InviteeTradingNum }}
+Invitee volume: ${{ body.inviteeTradingAmount }}
+Total commission: ${{ body.totalCommissionAmount }}
+
+Withdrawable: ${{ body.withdrawableCommissionAmount }}
+Under review: ${{ body.frozenCommissionAmount }}
+Withdrawn: ${{ body.issuedCommissionAmount }}
+
+TG BOT referral link:
+<code>https://t.me/{{ botUserName }}?start=I_{{ invitationCode  }}</code> 
+
+Web referral link:
+<code>https://hellodex.io/Refer?invitationCode={{ invitationCode  }}</code> 
+
+
+`,
+}
+
+func RanderMyCommissionSummary(lang string, invitationCode string, botUserName string, body map[string]any) (string, error) {
 	// Compile the template first (i. e. creating the AST)
-	tpl, err := pongo2.FromString(MyCommissionSummaryTempl)
+	tpl, err := fromLang(MyCommissionSummaryTempl, lang)
 	if err != nil {
 		log.Error().Err(err).Send()
 		return "", ErrRander
@@ -5,6 +5,9 @@ import (
 	"fmt"
 
 	"github.com/flosch/pongo2/v6"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/util"
 	"github.com/rs/zerolog/log"
 )
 
@@ -25,7 +28,8 @@ type SubInfo struct {
 	PairAddress    string `json:"pairAddress"`
 }
 
-var listAimonitor = `
+var listAimonitor = map[string]string{
+	i18n.ZH: `
 订阅列表:
 {% for sub in subList | slice:slice_range %}
 {{ sub.ChainCode | getChainName }}
@@ -36,12 +40,62 @@ var listAimonitor = `
 |--目标价格: <b>${{ sub.TargetPrice|formatNumber }}</b>
 |--开始价格: <b>${{ sub.StartPrice|formatNumber }}</b>
 |--类型: <b>{{ sub.Type }}</b>
-{% endfor %}
-`
+{% endfor %}{% if compounds %}
+🧩 组合监控:
+{% for c in compounds %}
+{{ c.ChainCode | getChainName }} <b>{{ c.Symbol }}</b>
+|--Token: <code>{{ c.Token }}</code>
+|--条件: {{ c.Rule }}
+{% endfor %}{% endif %}
+`,
+	i18n.EN: `
+Subscriptions:
+{% for sub in subList | slice:slice_range %}
+{{ sub.ChainCode | getChainName }}
+📊 Alert:
+|--Token: <b>{{ sub.BaseAddress }}</b>
+|--Symbol: <b>{{ sub.Symbol }}</b>
+|--Price: <b>${{ sub.Price|formatNumber }}</b>
+|--Target price: <b>${{ sub.TargetPrice|formatNumber }}</b>
+|--Start price: <b>${{ sub.StartPrice|formatNumber }}</b>
+|--Type: <b>{{ sub.Type }}</b>
+{% endfor %}{% if compounds %}
+🧩 Compound alerts:
+{% for c in compounds %}
+{{ c.ChainCode | getChainName }} <b>{{ c.Symbol }}</b>
+|--Token: <code>{{ c.Token }}</code>
+|--Condition: {{ c.Rule }}
+{% endfor %}{% endif %}
+`,
+}
+
+type compoundInfo struct {
+	ChainCode string
+	Symbol    string
+	Token     string
+	Rule      string
+}
+
+// RenderAlertExpr readable conditions of compound alert
+func RenderAlertExpr(lang string, x model.AlertExpr) string {
+	if x.IsEmpty() {
+		return i18n.T(lang, "alert.expr_empty")
+	}
+	return x.Format(func(leaf model.AlertExpr) string {
+		value := leaf.Value
+		switch leaf.Metric {
+		case model.MetricPrice, model.MetricMcap, model.MetricVolume1h:
+			value = "$" + util.FormatNumber(value)
+		case model.MetricChg1h:
+			value += "%"
+		}
+		return i18n.T(lang, "alert.leaf."+leaf.Op, i18n.T(lang, "alert.metric."+leaf.Metric), value)
+	}, i18n.T(lang, "alert.and"), i18n.T(lang, "alert.or"))
+}
 
-func RanderListAimonitor(data []byte) (string, error) {
+func RanderListAimonitor(lang string, data []byte, compounds []model.CompoundAlert) (string, error) {
 	// Compile the template first (i. e. creating the AST)
-	tpl, err := pongo2.FromString(listAimonitor)
+	tpl, err := fromLang(listAimonitor, lang)
 	if err != nil {
 		log.Error().Err(err).Send()
 		return "", ErrRander
@@ -59,10 +113,21 @@ func RanderListAimonitor(data []byte) (string, error) {
 		return "", ErrRander
 	}
 
+	infos := make([]compoundInfo, 0, len(compounds))
+	for _, c := range compounds {
+		infos = append(infos, compoundInfo{
+			ChainCode: c.ChainCode,
+			Symbol:    c.Symbol,
+			Token:     c.Token,
+			Rule:      RenderAlertExpr(lang, c.Expr),
+		})
+	}
+
 	out, err := tpl.Execute(pongo2.Context{
 		"subList":     subList,
 		"slice_range": sliceRange,
 		"size":        pageSize,
+		"compounds":   infos,
 	})
 	if err != nil {
 		log.Error().Err(err).Send()
@@ -5,6 +5,7 @@ import (
 
 	"github.com/flosch/pongo2/v6"
 	"github.com/hellodex/tradingbot/api"
+	"github.com/hellodex/tradingbot/i18n"
 	"github.com/hellodex/tradingbot/util"
 )
 
@@ -40,3 +41,12 @@ var _ = func() interface{} {
 }()
 
 var ErrRander = errors.New("出错了，请联系客服！")
+
+// 多语言模板: lang -> template, 没有对应语言时用默认语言
+func fromLang(tpls map[string]string, lang string) (*pongo2.Template, error) {
+	tpl, ok := tpls[lang]
+	if !ok {
+		tpl = tpls[i18n.Default]
+	}
+	return pongo2.FromString(tpl)
+}
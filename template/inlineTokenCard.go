@@ -0,0 +1,53 @@
+package template
+
+import (
+	"github.com/flosch/pongo2/v6"
+	"github.com/hellodex/tradingbot/api"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/rs/zerolog/log"
+)
+
+var inlineTokenCardTemplate = map[string]string{
+	i18n.ZH: `
+<a href="https://hellodex.io/k/{{ info.PairAddress }}?chainCode={{ info.ChainCode }}&timeType=15m">{{ info.Symbol }}</a> ({{ info.ChainCode | getChainName }})
+<code>{{ info.BaseAddress }}</code>
+
+💵价格: ${{ info.Price | formatNumber }}
+📈涨跌: 5m {{ info.Chg5m | formatNumber }}% | 1h {{ info.Chg1h | formatNumber }}% | 4h {{ info.Chg4h | formatNumber }}%
+💎市值: ${{ info.MarketCap | formatNumber }}
+👥持有人: {{ info.Holders }}
+{% if info.Dex %}🏦DEX: {{ info.Dex }}{% endif %}
+
+🤖 <a href="{{ startLink }}">使用 HelloDex Bot 秒级交易</a>`,
+	i18n.EN: `
+<a href="https://hellodex.io/k/{{ info.PairAddress }}?chainCode={{ info.ChainCode }}&timeType=15m">{{ info.Symbol }}</a> ({{ info.ChainCode | getChainName }})
+<code>{{ info.BaseAddress }}</code>
+
+💵Price: ${{ info.Price | formatNumber }}
+📈Change: 5m {{ info.Chg5m | formatNumber }}% | 1h {{ info.Chg1h | formatNumber }}% | 4h {{ info.Chg4h | formatNumber }}%
+💎Market cap: ${{ info.MarketCap | formatNumber }}
+👥Holders: {{ info.Holders }}
+{% if info.Dex %}🏦DEX: {{ info.Dex }}{% endif %}
+
+🤖 <a href="{{ startLink }}">Trade in seconds with HelloDex Bot</a>`,
+}
+
+// token card for inline query and group chat
+func RanderInlineTokenCard(lang string, info api.TokenInfo, startLink string) (string, error) {
+	tpl, err := fromLang(inlineTokenCardTemplate, lang)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return "", ErrRander
+	}
+
+	out, err := tpl.Execute(pongo2.Context{
+		"info":      info,
+		"startLink": startLink,
+	})
+	if err != nil {
+		log.Error().Err(err).Send()
+		return "", ErrRander
+	}
+
+	return out, nil
+}
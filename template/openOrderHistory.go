@@ -10,6 +10,7 @@ import (
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
 	"github.com/hellodex/tradingbot/api"
+	"github.com/hellodex/tradingbot/i18n"
 	"github.com/hellodex/tradingbot/model"
 	"github.com/hellodex/tradingbot/store"
 	"github.com/hellodex/tradingbot/util"
@@ -17,7 +18,8 @@ import (
 	"github.com/spf13/cast"
 )
 
-var listOpenOrdersHistoryTemplate = `
+var listOpenOrdersHistoryTemplate = map[string]string{
+	i18n.ZH: `
 {% for order in openOrders | slice:slice_range %}
 {{ order.ChainCode | getChainName }}
 委托信息: <b>{{ order.FromTokenSymbol }}/{{ order.ToTokenSymbol }}</b>
@@ -31,7 +33,23 @@ var listOpenOrdersHistoryTemplate = `
 交易哈希: <code>{{ order.Tx }}</code>{% endif %}
 时间: <b>{{ order.Timestamp|formatTime }}</b>
 {% endfor %}
-`
+`,
+	i18n.EN: `
+{% for order in openOrders | slice:slice_range %}
+{{ order.ChainCode | getChainName }}
+Order: <b>{{ order.FromTokenSymbol }}/{{ order.ToTokenSymbol }}</b>
+{{ limitTypeStr }}
+{{ trigger }}: $<b>{{ triggerAmount | formatNumber }}</b>
+Amount: <b>{{ order.Amount|formatNumber }} {{ order.FromTokenSymbol }}</b>
+Volume: <b>${{ order.Volume|formatNumber }}</b>
+Status: <b>{{ order.OrderStatusUI }}</b>
+Order No: <code><b>{{ order.OrderNo }}</b></code>
+{%- if order.Tx %}
+Tx hash: <code>{{ order.Tx }}</code>{% endif %}
+Time: <b>{{ order.Timestamp|formatTime }}</b>
+{% endfor %}
+`,
+}
 
 func RanderOpenOrderInlineKeyboard(openOrders []model.OpenOrderInner) (models.InlineKeyboardMarkup, error) {
 	kb := models.InlineKeyboardMarkup{
@@ -68,17 +86,17 @@ func CallbackHandlerViewOrder(ctx context.Context, b *bot.Bot, u *models.Update)
 		InlineKeyboard: [][]models.InlineKeyboardButton{
 			{
 				{
-					Text:         "取消委托",
+					Text:         i18n.L(chatId, "order.cancel"),
 					CallbackData: "cancelOrder::" + orderNo,
 				},
 			},
 			{
 				{
-					Text:         "返回上一级",
+					Text:         i18n.L(chatId, "common.back"),
 					CallbackData: "backToOrderList",
 				},
 				{
-					Text:         "返回主菜单",
+					Text:         i18n.L(chatId, "common.back_main"),
 					CallbackData: "backToMainMenu",
 				},
 			},
@@ -88,14 +106,14 @@ func CallbackHandlerViewOrder(ctx context.Context, b *bot.Bot, u *models.Update)
 	// get orderDetail info from redis cache
 	data, has := store.UserGetOrderHistory(chatId)
 	if !has {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 
 	var orderList []model.OpenOrderInner
 	err := json.Unmarshal(data, &orderList)
 	if err != nil {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 
@@ -108,9 +126,9 @@ func CallbackHandlerViewOrder(ctx context.Context, b *bot.Bot, u *models.Update)
 	}
 	orderList = orderList[:n]
 
-	orderDetail, err := RanderOpenOrdersHistory(orderList, 1, 5)
+	orderDetail, err := RanderOpenOrdersHistory(i18n.UserLang(chatId), orderList, 1, 5)
 	if err != nil {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 
@@ -133,25 +151,25 @@ func CallabckOrderCancelOrder(ctx context.Context, b *bot.Bot, u *models.Update)
 	orderNo := strings.TrimPrefix(callbackData, "cancelOrder::")
 	userInfo, err := api.GetUserProfile(chatId)
 	if err != nil {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 	_, err = api.CancelOrder(orderNo, userInfo)
 	if err != nil {
-		util.QuickMessage(ctx, b, chatId, fmt.Sprintf("出错了,%s", util.AdminUrl))
+		util.QuickMessage(ctx, b, chatId, i18n.L(chatId, "common.error"))
 		return
 	}
 
 	store.BotMessageAdd()
 	b.SendMessage(ctx, &bot.SendMessageParams{
-		Text:   fmt.Sprintf("取消: %s 成功", orderNo),
+		Text:   i18n.L(chatId, "order.cancel_success", orderNo),
 		ChatID: chatId,
 	})
 }
 
-func RanderOpenOrdersHistory(openOrders []model.OpenOrderInner, page, pageSize int) (string, error) {
+func RanderOpenOrdersHistory(lang string, openOrders []model.OpenOrderInner, page, pageSize int) (string, error) {
 	// Compile the template first (i. e. creating the AST)
-	tpl, err := pongo2.FromString(listOpenOrdersHistoryTemplate)
+	tpl, err := fromLang(listOpenOrdersHistoryTemplate, lang)
 	if err != nil {
 		log.Error().Err(err).Send()
 		return "", ErrRander
@@ -165,17 +183,17 @@ func RanderOpenOrdersHistory(openOrders []model.OpenOrderInner, page, pageSize i
 	for _, order := range openOrders {
 		limitType := order.LimitType
 		if limitType == "1" || limitType == "5" {
-			limitTypeStr = "高于价格后买入"
+			limitTypeStr = i18n.T(lang, "order.limit_type.breakout")
 		} else if limitType == "2" || limitType == "6" {
-			limitTypeStr = "抄底"
+			limitTypeStr = i18n.T(lang, "order.limit_type.dip")
 		} else if limitType == "3" || limitType == "7" {
-			limitTypeStr = "止盈"
+			limitTypeStr = i18n.T(lang, "order.limit_type.take_profit")
 		} else if limitType == "4" || limitType == "8" {
-			limitTypeStr = "止损"
+			limitTypeStr = i18n.T(lang, "order.limit_type.stop_loss")
 		}
 		if order.FromOrderNo != "" {
 			profitFlag := cast.ToInt64(order.ProfitFlag) * 100
-			limitTypeStr = "自定义涨幅" + cast.ToString(profitFlag) + "%出本"
+			limitTypeStr = i18n.T(lang, "order.limit_type.profit_flag", cast.ToString(profitFlag))
 		}
 	}
 
@@ -184,14 +202,14 @@ func RanderOpenOrdersHistory(openOrders []model.OpenOrderInner, page, pageSize i
 	for _, order := range openOrders {
 		limitType := cast.ToInt(order.LimitType)
 		if limitType > 4 {
-			trigger = "触发市值"
+			trigger = i18n.T(lang, "order.trigger.market_cap")
 			triggerAmount = order.MarketCap
 		} else {
-			trigger = "触发价格"
+			trigger = i18n.T(lang, "order.trigger.price")
 			triggerAmount = order.Price
 		}
 		if order.FromOrderNo != "" {
-			trigger = "触发价格"
+			trigger = i18n.T(lang, "order.trigger.price")
 			triggerAmount = order.Price
 		}
 
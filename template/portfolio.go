@@ -0,0 +1,114 @@
+package template
+
+import (
+	"github.com/flosch/pongo2/v6"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/util"
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+)
+
+var portfolioTemplate = map[string]string{
+	i18n.ZH: `
+📊 投资组合{% if chain %} ({{ chain | getChainName }}){% endif %}
+
+💰总价值: ${{ total }}
+📈未实现盈亏: {{ unrealised }}
+💵已实现盈亏: {{ realised }}
+{% if winners %}
+🏆盈利最多:
+{% for r in winners %}--{{ r.Symbol }} ({{ r.ChainCode | getChainName }}): {{ r.PnL }}
+{% endfor %}{% endif %}{% if losers %}
+💔亏损最多:
+{% for r in losers %}--{{ r.Symbol }} ({{ r.ChainCode | getChainName }}): {{ r.PnL }}
+{% endfor %}{% endif %}
+📋持仓 ({{ page }}/{{ totalPage }}):
+{% for r in rows %}{{ r.Index }}. <a href="https://hellodex.io/k/{{ r.PairAddress }}?chainCode={{ r.ChainCode }}&timeType=15m">{{ r.Symbol }}</a> ({{ r.ChainCode | getChainName }}) ${{ r.Value }} | {{ r.Percent }}%
+--盈亏: {{ r.PnL }}
+{% empty %}暂无持仓
+{% endfor %}`,
+	i18n.EN: `
+📊 Portfolio{% if chain %} ({{ chain | getChainName }}){% endif %}
+
+💰Total value: ${{ total }}
+📈Unrealised PnL: {{ unrealised }}
+💵Realised PnL: {{ realised }}
+{% if winners %}
+🏆Top winners:
+{% for r in winners %}--{{ r.Symbol }} ({{ r.ChainCode | getChainName }}): {{ r.PnL }}
+{% endfor %}{% endif %}{% if losers %}
+💔Top losers:
+{% for r in losers %}--{{ r.Symbol }} ({{ r.ChainCode | getChainName }}): {{ r.PnL }}
+{% endfor %}{% endif %}
+📋Positions ({{ page }}/{{ totalPage }}):
+{% for r in rows %}{{ r.Index }}. <a href="https://hellodex.io/k/{{ r.PairAddress }}?chainCode={{ r.ChainCode }}&timeType=15m">{{ r.Symbol }}</a> ({{ r.ChainCode | getChainName }}) ${{ r.Value }} | {{ r.Percent }}%
+--PnL: {{ r.PnL }}
+{% empty %}No positions
+{% endfor %}`,
+}
+
+type portfolioRow struct {
+	Index       int
+	Symbol      string
+	ChainCode   string
+	PairAddress string
+	Value       string
+	Percent     string
+	PnL         string
+}
+
+// +$1.2K / -$30
+func signedUsd(d decimal.Decimal) string {
+	if d.IsNegative() {
+		return "-$" + util.FormatNumber(d.Abs().String())
+	}
+	return "+$" + util.FormatNumber(d.String())
+}
+
+func toPortfolioRows(items []model.PortfolioItem, offset int) []portfolioRow {
+	rows := make([]portfolioRow, 0, len(items))
+	for i, item := range items {
+		rows = append(rows, portfolioRow{
+			Index:       offset + i + 1,
+			Symbol:      item.Symbol,
+			ChainCode:   item.ChainCode,
+			PairAddress: item.PairAddress,
+			Value:       util.FormatNumber(item.Value.String()),
+			Percent:     item.Percent.StringFixed(2),
+			PnL:         signedUsd(item.PnL()),
+		})
+	}
+	return rows
+}
+
+// chain is empty when show all chains
+func RanderPortfolio(lang string, p model.Portfolio, chain string, page int) (string, error) {
+	tpl, err := fromLang(portfolioTemplate, lang)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return "", ErrRander
+	}
+
+	items, totalPage := p.Page(page)
+	page = min(max(page, 1), totalPage)
+	winners, losers := p.Top(model.PortfolioTopCount)
+
+	out, err := tpl.Execute(pongo2.Context{
+		"chain":      chain,
+		"total":      util.FormatNumber(p.TotalValue.String()),
+		"unrealised": signedUsd(p.Unrealised),
+		"realised":   signedUsd(p.Realised),
+		"winners":    toPortfolioRows(winners, 0),
+		"losers":     toPortfolioRows(losers, 0),
+		"rows":       toPortfolioRows(items, (page-1)*model.PortfolioPageSize),
+		"page":       page,
+		"totalPage":  totalPage,
+	})
+	if err != nil {
+		log.Error().Err(err).Send()
+		return "", ErrRander
+	}
+
+	return out, nil
+}
@@ -2,52 +2,390 @@ package template
 
 import (
 	"github.com/flosch/pongo2/v6"
+	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/util"
 	"github.com/rs/zerolog/log"
-	"github.com/tidwall/gjson"
+	"github.com/shopspring/decimal"
 )
 
-var tgUserTokenPushTemplate = `
+var pricePushTemplate = map[string]string{
+	i18n.ZH: `
 HelloDex: AI监控价格
 
 {{ symbol }} ({{ chainCode | getChainName }})
 
-{% if baseAddress %}<code>{{ baseAddress }}</code>{% endif %}
+<code>{{ baseAddress }}</code>
 
-价格已到: ${{ price | formatNumber }}
+价格已到: ${{ price | formatNumber }}{% if volume %}
 交易数量: {{ amount | formatNumber }}
 交易总额: ${{ volume | formatNumber }}
-交易方向: {% if flag == 0 %}买入{% else %}卖出{% endif %}
-`
+交易方向: {% if isBuy %}买入{% else %}卖出{% endif %}{% endif %}
+`,
+	i18n.EN: `
+HelloDex: AI price alert
 
-func RenderTgUserTokenPush(data []byte) (string, error) {
-	tpl, err := pongo2.FromString(tgUserTokenPushTemplate)
+{{ symbol }} ({{ chainCode | getChainName }})
+
+<code>{{ baseAddress }}</code>
+
+Price reached: ${{ price | formatNumber }}{% if volume %}
+Amount: {{ amount | formatNumber }}
+Volume: ${{ volume | formatNumber }}
+Side: {% if isBuy %}Buy{% else %}Sell{% endif %}{% endif %}
+`,
+}
+
+var largeTradePushTemplate = map[string]string{
+	i18n.ZH: `
+HelloDex: AI监控大额交易
+
+{{ symbol }} ({{ chainCode | getChainName }})
+
+<code>{{ baseAddress }}</code>
+
+交易方向: {% if isBuy %}🟢 买入{% else %}🔴 卖出{% endif %}
+交易总额: ${{ volume | formatNumber }}{% if amount %}
+交易数量: {{ amount | formatNumber }}{% endif %}{% if price %}
+成交价格: ${{ price | formatNumber }}{% endif %}
+`,
+	i18n.EN: `
+HelloDex: AI large trade alert
+
+{{ symbol }} ({{ chainCode | getChainName }})
+
+<code>{{ baseAddress }}</code>
+
+Side: {% if isBuy %}🟢 Buy{% else %}🔴 Sell{% endif %}
+Volume: ${{ volume | formatNumber }}{% if amount %}
+Amount: {{ amount | formatNumber }}{% endif %}{% if price %}
+Price: ${{ price | formatNumber }}{% endif %}
+`,
+}
+
+var smartMoneyPushTemplate = map[string]string{
+	i18n.ZH: `
+HelloDex: 🐋 聪明钱{% if isBuy %}买入{% else %}卖出{% endif %}
+
+{{ symbol }} ({{ chainCode | getChainName }})
+
+<code>{{ baseAddress }}</code>
+
+钱包: {% if walletLabel %}{{ walletLabel }} {% endif %}<code>{{ wallet }}</code>
+交易总额: ${{ volume | formatNumber }}{% if amount %}
+交易数量: {{ amount | formatNumber }}{% endif %}{% if txs > 1 %}
+合并交易: {{ txs }} 笔{% endif %}{% if price %}
+成交价格: ${{ price | formatNumber }}{% endif %}{% if mcap %}
+市值: ${{ mcap | formatNumber }}{% endif %}
+`,
+	i18n.EN: `
+HelloDex: 🐋 Smart money {% if isBuy %}buy{% else %}sell{% endif %}
+
+{{ symbol }} ({{ chainCode | getChainName }})
+
+<code>{{ baseAddress }}</code>
+
+Wallet: {% if walletLabel %}{{ walletLabel }} {% endif %}<code>{{ wallet }}</code>
+Volume: ${{ volume | formatNumber }}{% if amount %}
+Amount: {{ amount | formatNumber }}{% endif %}{% if txs > 1 %}
+Merged trades: {{ txs }}{% endif %}{% if price %}
+Price: ${{ price | formatNumber }}{% endif %}{% if mcap %}
+Market cap: ${{ mcap | formatNumber }}{% endif %}
+`,
+}
+
+var newListingPushTemplate = map[string]string{
+	i18n.ZH: `
+HelloDex: 🆕 新币上线
+
+{{ symbol }} ({{ chainCode | getChainName }})
+
+<code>{{ baseAddress }}</code>
+
+交易对: <code>{{ pairAddress }}</code>{% if dex %}
+DEX: {{ dex }}{% endif %}{% if price %}
+价格: ${{ price | formatNumber }}{% endif %}{% if liquidity %}
+流动性: ${{ liquidity | formatNumber }}{% endif %}{% if mcap %}
+市值: ${{ mcap | formatNumber }}{% endif %}{% if devHolding %}
+创建者持仓: {{ devHolding }}%{% endif %}{% if twitter or telegram or website %}
+社交:{% if twitter %} <a href="{{ twitter }}">Twitter</a>{% endif %}{% if telegram %} <a href="{{ telegram }}">Telegram</a>{% endif %}{% if website %} <a href="{{ website }}">官网</a>{% endif %}{% endif %}
+`,
+	i18n.EN: `
+HelloDex: 🆕 New listing
+
+{{ symbol }} ({{ chainCode | getChainName }})
+
+<code>{{ baseAddress }}</code>
+
+Pair: <code>{{ pairAddress }}</code>{% if dex %}
+DEX: {{ dex }}{% endif %}{% if price %}
+Price: ${{ price | formatNumber }}{% endif %}{% if liquidity %}
+Liquidity: ${{ liquidity | formatNumber }}{% endif %}{% if mcap %}
+Market cap: ${{ mcap | formatNumber }}{% endif %}{% if devHolding %}
+Dev holding: {{ devHolding }}%{% endif %}{% if twitter or telegram or website %}
+Socials:{% if twitter %} <a href="{{ twitter }}">Twitter</a>{% endif %}{% if telegram %} <a href="{{ telegram }}">Telegram</a>{% endif %}{% if website %} <a href="{{ website }}">Website</a>{% endif %}{% endif %}
+`,
+}
+
+var orderFilledPushTemplate = map[string]string{
+	i18n.ZH: `
+HelloDex: ✅ 委托已成交
+
+{{ symbol }} ({{ chainCode | getChainName }})
+
+<code>{{ baseAddress }}</code>
+
+交易方向: {% if isBuy %}买入{% else %}卖出{% endif %}{% if orderType %}
+委托类型: {{ orderType }}{% endif %}{% if amount %}
+成交数量: {{ amount | formatNumber }}{% endif %}{% if volume %}
+成交金额: ${{ volume | formatNumber }}{% endif %}{% if price %}
+成交价格: ${{ price | formatNumber }}{% endif %}{% if orderNo %}
+订单号: <code>{{ orderNo }}</code>{% endif %}
+`,
+	i18n.EN: `
+HelloDex: ✅ Order filled
+
+{{ symbol }} ({{ chainCode | getChainName }})
+
+<code>{{ baseAddress }}</code>
+
+Side: {% if isBuy %}Buy{% else %}Sell{% endif %}{% if orderType %}
+Order type: {{ orderType }}{% endif %}{% if amount %}
+Amount: {{ amount | formatNumber }}{% endif %}{% if volume %}
+Volume: ${{ volume | formatNumber }}{% endif %}{% if price %}
+Price: ${{ price | formatNumber }}{% endif %}{% if orderNo %}
+Order No: <code>{{ orderNo }}</code>{% endif %}
+`,
+}
+
+// legacy push of AI monitor, every line is optional
+var legacyPushTemplate = map[string]string{
+	i18n.ZH: `
+HelloDex: AI监控
+
+{{ symbol }}{% if chainCode %} ({{ chainCode | getChainName }}){% endif %}
+{% if baseAddress %}
+<code>{{ baseAddress }}</code>
+{% endif %}{% if price %}
+价格: ${{ price | formatNumber }}{% endif %}{% if amount %}
+交易数量: {{ amount | formatNumber }}{% endif %}{% if volume %}
+交易总额: ${{ volume | formatNumber }}
+交易方向: {% if isBuy %}买入{% else %}卖出{% endif %}{% endif %}
+`,
+	i18n.EN: `
+HelloDex: AI monitor
+
+{{ symbol }}{% if chainCode %} ({{ chainCode | getChainName }}){% endif %}
+{% if baseAddress %}
+<code>{{ baseAddress }}</code>
+{% endif %}{% if price %}
+Price: ${{ price | formatNumber }}{% endif %}{% if amount %}
+Amount: {{ amount | formatNumber }}{% endif %}{% if volume %}
+Volume: ${{ volume | formatNumber }}
+Side: {% if isBuy %}Buy{% else %}Sell{% endif %}{% endif %}
+`,
+}
+
+// push kind -> template
+var pushTemplates = map[string]map[string]string{
+	model.PushKindPriceAlert:  pricePushTemplate,
+	model.PushKindLargeTrade:  largeTradePushTemplate,
+	model.PushKindSmartMoney:  smartMoneyPushTemplate,
+	model.PushKindNewListing:  newListingPushTemplate,
+	model.PushKindOrderFilled: orderFilledPushTemplate,
+	model.PushKindLegacy:      legacyPushTemplate,
+}
+
+// empty if zero, optional lines of template are hidden
+func optionalDecimal(d decimal.Decimal) string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.String()
+}
+
+// RenderPush message and keyboard of backend push by its kind
+func RenderPush(lang string, e model.PushEvent) (string, models.InlineKeyboardMarkup, error) {
+	tpls, ok := pushTemplates[e.Kind]
+	if !ok {
+		log.Error().Str("kind", e.Kind).Msg("push template not found")
+		return "", models.InlineKeyboardMarkup{}, ErrRander
+	}
+	tpl, err := fromLang(tpls, lang)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return "", models.InlineKeyboardMarkup{}, ErrRander
+	}
+
+	symbol := e.Symbol
+	if symbol == "" {
+		symbol = util.ShortAddress(e.BaseAddress)
+	}
+	out, err := tpl.Execute(pongo2.Context{
+		"symbol":      symbol,
+		"chainCode":   e.ChainCode,
+		"baseAddress": e.BaseAddress,
+		"price":       optionalDecimal(e.Price),
+		"amount":      optionalDecimal(e.Amount),
+		"volume":      optionalDecimal(e.Volume),
+		"isBuy":       e.IsBuy,
+		"wallet":      e.Wallet,
+		"walletLabel": e.WalletLabel,
+		"txs":         e.Txs,
+		"pairAddress": e.PairAddress,
+		"dex":         e.Dex,
+		"liquidity":   optionalDecimal(e.Liquidity),
+		"mcap":        optionalDecimal(e.MarketCap),
+		"devHolding":  optionalDecimal(e.DevHolding.Round(2)),
+		"twitter":     e.Twitter,
+		"telegram":    e.Telegram,
+		"website":     e.Website,
+		"orderNo":     e.OrderNo,
+		"orderType":   e.OrderType,
+	})
+	if err != nil {
+		log.Error().Err(err).Send()
+		return "", models.InlineKeyboardMarkup{}, ErrRander
+	}
+	return out, pushKeyboard(lang, e), nil
+}
+
+// pushKeyboard AI monitor pushes can manage their rule, others only open token card
+func pushKeyboard(lang string, e model.PushEvent) models.InlineKeyboardMarkup {
+	if e.FromMonitor() {
+		return util.NewAiMonitorPusherButton(lang)
+	}
+	trade := util.NewCallbackDataButton(i18n.T(lang, "aimonitor.pusher.trade"), "ai_sendNewupdate")
+	row := []models.InlineKeyboardButton{trade}
+	if e.Kind == model.PushKindOrderFilled && e.TxHash != "" {
+		row = []models.InlineKeyboardButton{
+			{Text: i18n.T(lang, "swap.view_scan"), URL: util.GetChainScanUrl(e.ChainCode, e.TxHash)},
+			trade,
+		}
+	}
+	return models.InlineKeyboardMarkup{InlineKeyboard: [][]models.InlineKeyboardButton{row}}
+}
+
+var alertPushTemplate = map[string]string{
+	i18n.ZH: `
+HelloDex: 监控提醒
+
+{{ symbol }} ({{ chainCode | getChainName }})
+
+<code>{{ baseAddress }}</code>
+{% if type == "price" %}
+价格{% if op == "above" %}涨破{% elif op == "below" %}跌破{% else %}穿过{% endif %}: ${{ value | formatNumber }}
+当前价格: ${{ price | formatNumber }}
+{% elif type == "chg" %}
+涨跌幅达到: {{ value }}%
+当前价格: ${{ price | formatNumber }}
+{% else %}
+单笔{% if type == "buy" %}买入{% else %}卖出{% endif %}超过: ${{ value | formatNumber }}
+交易数量: {{ amount | formatNumber }}
+交易总额: ${{ volume | formatNumber }}
+{% endif %}`,
+	i18n.EN: `
+HelloDex: Monitor alert
+
+{{ symbol }} ({{ chainCode | getChainName }})
+
+<code>{{ baseAddress }}</code>
+{% if type == "price" %}
+Price {% if op == "above" %}rose above{% elif op == "below" %}fell below{% else %}crossed{% endif %}: ${{ value | formatNumber }}
+Current price: ${{ price | formatNumber }}
+{% elif type == "chg" %}
+Price change reached: {{ value }}%
+Current price: ${{ price | formatNumber }}
+{% else %}
+Single {% if type == "buy" %}buy{% else %}sell{% endif %} over: ${{ value | formatNumber }}
+Amount: {{ amount | formatNumber }}
+Volume: ${{ volume | formatNumber }}
+{% endif %}`,
+}
+
+// RenderAlertPush message of rule fired by local alert engine
+func RenderAlertPush(lang string, r model.AlertRule, t model.AlertTick) (string, error) {
+	tpl, err := fromLang(alertPushTemplate, lang)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return "", ErrRander
+	}
+
+	symbol := r.Symbol
+	if symbol == "" {
+		symbol = t.Symbol
+	}
+	out, err := tpl.Execute(pongo2.Context{
+		"symbol":      symbol,
+		"chainCode":   r.ChainCode,
+		"baseAddress": r.Token,
+		"type":        r.Type,
+		"op":          r.Op,
+		"value":       r.Value.String(),
+		"price":       t.Price.String(),
+		"amount":      t.Amount.String(),
+		"volume":      t.Volume.String(),
+	})
+	if err != nil {
+		log.Error().Err(err).Send()
+		return "", ErrRander
+	}
+	return out, nil
+}
+
+var compoundAlertPushTemplate = map[string]string{
+	i18n.ZH: `
+HelloDex: 🧩 组合监控提醒
+
+{{ symbol }} ({{ chainCode | getChainName }})
+
+<code>{{ baseAddress }}</code>
+
+条件: {{ rule }}
+
+价格: ${{ price | formatNumber }}
+市值: ${{ mcap | formatNumber }}
+持有人: {{ holders }}
+1h 成交额: ${{ vol1h | formatNumber }}
+1h 涨跌: {{ chg1h }}%
+`,
+	i18n.EN: `
+HelloDex: 🧩 Compound alert
+
+{{ symbol }} ({{ chainCode | getChainName }})
+
+<code>{{ baseAddress }}</code>
+
+Condition: {{ rule }}
+
+Price: ${{ price | formatNumber }}
+Market cap: ${{ mcap | formatNumber }}
+Holders: {{ holders }}
+1h volume: ${{ vol1h | formatNumber }}
+1h change: {{ chg1h }}%
+`,
+}
+
+// RenderCompoundAlertPush message of compound alert fired by local alert engine
+func RenderCompoundAlertPush(lang string, c model.CompoundAlert, t model.AlertTick) (string, error) {
+	tpl, err := fromLang(compoundAlertPushTemplate, lang)
 	if err != nil {
 		log.Error().Err(err).Send()
 		return "", ErrRander
 	}
 
-	allParts := gjson.GetManyBytes(
-		data,
-		"payload.symbol",
-		"payload.chainCode",
-		"payload.baseAddress",
-		"payload.price",
-		"payload.amount",
-		"payload.volume",
-		"payload.flag",
-	)
-
-	dataMap := map[string]any{
-		"symbol":      allParts[0].String(),
-		"chainCode":   allParts[1].String(),
-		"baseAddress": allParts[2].String(),
-		"price":       allParts[3].String(),
-		"amount":      allParts[4].String(),
-		"volume":      allParts[5].String(),
-		"flag":        allParts[5].Int(),
-	}
-
-	out, err := tpl.Execute(pongo2.Context(dataMap))
+	out, err := tpl.Execute(pongo2.Context{
+		"symbol":      c.Symbol,
+		"chainCode":   c.ChainCode,
+		"baseAddress": c.Token,
+		"rule":        RenderAlertExpr(lang, c.Expr),
+		"price":       t.Price.String(),
+		"mcap":        t.MarketCap.String(),
+		"holders":     t.Holders.String(),
+		"vol1h":       t.Volume1h.String(),
+		"chg1h":       t.Chg1h.StringFixed(2),
+	})
 	if err != nil {
 		log.Error().Err(err).Send()
 		return "", ErrRander
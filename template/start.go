@@ -2,10 +2,12 @@ package template
 
 import (
 	"github.com/flosch/pongo2/v6"
+	"github.com/hellodex/tradingbot/i18n"
 	"github.com/rs/zerolog/log"
 )
 
-var StartTemPlate = `
+var StartTemPlate = map[string]string{
+	i18n.ZH: `
 登录 HelloDex 体验秒级交易 🤘🏻
 开创和主导Web3变革，教育平台利润80%分给用户
 
@@ -28,11 +30,36 @@ TG BOT邀请返佣：
 <a href="https://t.me/HelloDex_cn">帮助</a> • <a href="https://t.me/HelloDex_cn">中文社区</a>
 
 有史以来都是资本联合平台赚用户的钱、 请各位用户一起加入变革。
-`
+`,
+	i18n.EN: `
+Log in to HelloDex for lightning-fast trading 🤘🏻
+Leading the Web3 revolution: 80% of platform profits go to users
 
-func RanderStart(symbol string, walletAddress string, native_coin string, usd string, invitationCode string, botUserName string) (string, error) {
+Before trading, please send some {{ symbol }} to your HelloDex default wallet:
+
+<code>{{ walletAddress }}</code> 
+Balance: {{ native_coin }} {{ symbol }} (${{ usd }})
+
+After sending {{ symbol }}, tap Refresh to see your balance
+
+Available everywhere:
+iOS, Android, Web, TG bot and TG mini app, open source on <a href="https://github.com/hellodex">github</a>
+
+TG BOT referral link:
+<code>https://t.me/{{ botUserName }}?start=I_{{ invitationCode  }}</code>
+
+Web referral link:
+<code>https://hellodex.io/Refer?invitationCode={{ invitationCode  }}</code> 
+
+<a href="https://t.me/HelloDex_cn">Help</a> • <a href="https://t.me/HelloDex_cn">Community</a>
+
+Capital and platforms have always profited from users. Join us and change that.
+`,
+}
+
+func RanderStart(lang string, symbol string, walletAddress string, native_coin string, usd string, invitationCode string, botUserName string) (string, error) {
 	// Compile the template first (i. e. creating the AST)
-	tpl, err := pongo2.FromString(StartTemPlate)
+	tpl, err := fromLang(StartTemPlate, lang)
 	if err != nil {
 		log.Error().Err(err).Send()
 		return "", ErrRander
@@ -2,13 +2,14 @@ package template
 
 import (
 	"github.com/flosch/pongo2/v6"
+	"github.com/hellodex/tradingbot/i18n"
 	"github.com/hellodex/tradingbot/model"
 	"github.com/hellodex/tradingbot/store"
-	"github.com/hellodex/tradingbot/util"
 	"github.com/rs/zerolog/log"
 )
 
-var startLoginTemplate = `
+var startLoginTemplate = map[string]string{
+	i18n.ZH: `
 登录 HelloDex 体验秒级交易 🤘🏻
 开创和主导Web3变革，教育平台利润80%分给用户
 
@@ -24,11 +25,29 @@ TG BOT邀请返佣：
 <code>https://hellodex.io/Refer?invitationCode={{ invitationCode  }}</code> 
 
 有史以来都是资本联合平台赚用户的钱、 请各位用户一起加入变革。
-`
+`,
+	i18n.EN: `
+Log in to HelloDex for lightning-fast trading 🤘🏻
+Leading the Web3 revolution: 80% of platform profits go to users
 
-func RanderStartLogin(userInfo model.GetUserResp, commissionUrl string) (string, error) {
+💳 {{ chainCode | getChainName }}: {{ balance | formatNumber }} {{ symbol }} {% if balance == 0 %}(Insufficient balance, please deposit👇🏻){% endif %}
+<code>{{ address }}</code> (tap to copy) 
+
+Need help? {{ adminUrl | safe }}
+
+TG BOT referral link:
+<code>https://t.me/{{ botUserName }}?start=I_{{ invitationCode  }}</code> 
+
+Web referral link:
+<code>https://hellodex.io/Refer?invitationCode={{ invitationCode  }}</code> 
+
+Capital and platforms have always profited from users. Join us and change that.
+`,
+}
+
+func RanderStartLogin(lang string, userInfo model.GetUserResp, commissionUrl string) (string, error) {
 	// Compile the template first (i. e. creating the AST)
-	tpl, err := pongo2.FromString(startLoginTemplate)
+	tpl, err := fromLang(startLoginTemplate, lang)
 	if err != nil {
 		log.Error().Err(err).Send()
 		return "", ErrRander
@@ -52,7 +71,7 @@ func RanderStartLogin(userInfo model.GetUserResp, commissionUrl string) (string,
 		"symbol":         userInfo.Data.MainnetToken.Symbol,
 		"chainCode":      wallet.ChainCode,
 		"address":        wallet.Wallet,
-		"adminUrl":       util.AdminUrl,
+		"adminUrl":       i18n.T(lang, "common.admin_url"),
 		"commissionUrl":  commissionUrl,
 		"invitationCode": userInfo.Data.InviteCode,
 		"botUserName":    store.GetEnv(store.BOT_USERNAME),
@@ -1,13 +1,18 @@
 package template
 
 import (
+	"strings"
+
 	"github.com/flosch/pongo2/v6"
+	"github.com/hellodex/tradingbot/i18n"
 	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/safety"
 	"github.com/hellodex/tradingbot/util"
 	"github.com/rs/zerolog/log"
 )
 
-var tokenInfoTemplate = `
+var tokenInfoTemplate = map[string]string{
+	i18n.ZH: `
 <a href="https://hellodex.io/k/{{ data.PairAddress }}?chainCode={{ data.ChainCode }}&timeType=15m">{{ data.BaseToken.Symbol }}</a> ({{ data.ChainCode }})
 {% if view_token %}<code>{{ view_token }}</code>{% endif %}
 
@@ -24,11 +29,34 @@ var tokenInfoTemplate = `
 --总卖出: {{ data.TotalSellAmount | formatNumber }}
 --总卖出金额: ${{ data.TotalSellVolume | formatNumber }}
 --累计收益: {{ data.TotalEarn | formatNumber }}
---收益率: {{ data.TotalEarnRate | formatNumber }}%`
+--收益率: {{ data.TotalEarnRate | formatNumber }}%{% if safety %}
+
+{{ safety | safe }}{% endif %}`,
+	i18n.EN: `
+<a href="https://hellodex.io/k/{{ data.PairAddress }}?chainCode={{ data.ChainCode }}&timeType=15m">{{ data.BaseToken.Symbol }}</a> ({{ data.ChainCode }})
+{% if view_token %}<code>{{ view_token }}</code>{% endif %}
+
+💳Holding:
+--Balance: {{ data.Amount | formatNumber }}
+--Price: ${{ data.Price | formatNumber }}
+--Value: ${{ data.Volume | formatNumber }}
+--Pool: <code>{{data.PairAddress}}</code>
 
-func RanderTokenInfo(data model.PositionByWalletAddress) (string, error) {
+💴Position:
+--Total bought: {{ data.TotalBuyAmount | formatNumber }}
+--Total buy value: ${{ data.TotalBuyVolume | formatNumber }}
+--Avg buy price: ${{ data.AveragePrice | formatNumber }}
+--Total sold: {{ data.TotalSellAmount | formatNumber }}
+--Total sell value: ${{ data.TotalSellVolume | formatNumber }}
+--Total PnL: {{ data.TotalEarn | formatNumber }}
+--PnL rate: {{ data.TotalEarnRate | formatNumber }}%{% if safety %}
+
+{{ safety | safe }}{% endif %}`,
+}
+
+func RanderTokenInfo(lang string, data model.PositionByWalletAddress) (string, error) {
 	// Compile the template first (i. e. creating the AST)
-	tpl, err := pongo2.FromString(tokenInfoTemplate)
+	tpl, err := fromLang(tokenInfoTemplate, lang)
 	if err != nil {
 		log.Error().Err(err).Send()
 		return "", ErrRander
@@ -41,11 +69,17 @@ func RanderTokenInfo(data model.PositionByWalletAddress) (string, error) {
 		return ""
 	}
 
+	var safetyText string
+	if token := view_token(); token != "" {
+		safetyText = RenderTokenSafety(lang, safety.Scan(data.Data.ChainCode, token, data.Data.PairAddress))
+	}
+
 	// Now you can render the template with the given
 	// pongo2.Context how often you want to.
 	out, err := tpl.Execute(pongo2.Context{
 		"data":       data.Data,
 		"view_token": view_token,
+		"safety":     safetyText,
 	})
 	if err != nil {
 		log.Error().Err(err).Send()
@@ -54,3 +88,22 @@ func RanderTokenInfo(data model.PositionByWalletAddress) (string, error) {
 
 	return out, nil
 }
+
+var safetyLevelIcons = map[int]string{
+	model.SafetyOk:     "✅",
+	model.SafetyWarn:   "⚠️",
+	model.SafetyDanger: "🚫",
+	model.SafetyInfo:   "ℹ️",
+}
+
+// RenderTokenSafety risk score and one line for each check, empty if nothing checked
+func RenderTokenSafety(lang string, s model.TokenSafety) string {
+	if s.Empty() {
+		return ""
+	}
+	lines := []string{i18n.T(lang, "safety.title", s.Score, i18n.T(lang, s.LevelKey()))}
+	for _, c := range s.Checks {
+		lines = append(lines, safetyLevelIcons[c.Level]+" "+i18n.T(lang, c.Key, c.FmtArgs()...))
+	}
+	return strings.Join(lines, "\n")
+}
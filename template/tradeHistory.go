@@ -4,11 +4,13 @@ import (
 	"fmt"
 
 	"github.com/flosch/pongo2/v6"
+	"github.com/hellodex/tradingbot/i18n"
 	"github.com/hellodex/tradingbot/model"
 	"github.com/rs/zerolog/log"
 )
 
-var listTradeHistoryTemplate = `
+var listTradeHistoryTemplate = map[string]string{
+	i18n.ZH: `
 最近{{size}}笔交易记录：
 {% for trade in trades | slice:slice_range %}
 <b>{{ trade.BaseSymbol }}/{{ trade.QuoteSymbol }}</b> ({{ trade.ChainCode | getChainName}})
@@ -23,11 +25,28 @@ var listTradeHistoryTemplate = `
 |--交易哈希: <code>{{ trade.Tx }}</code>{% endif %}
 |--时间: <b>{{ trade.Timestamp|formatTime }}</b>
 {% endfor %}
-`
+`,
+	i18n.EN: `
+Last {{size}} trades:
+{% for trade in trades | slice:slice_range %}
+<b>{{ trade.BaseSymbol }}/{{ trade.QuoteSymbol }}</b> ({{ trade.ChainCode | getChainName}})
+📊 Trade:
+|--Side: <b>{% if trade.Direction == '1' %}Sell{% else %}Buy{% endif %}</b>
+|--Amount: <b>{{ trade.Amount }} {{ trade.BaseSymbol }}</b>
+|--Price: <b>${{ trade.Price|formatNumber }}</b>
+|--Volume: <b>${{ trade.Volume|formatNumber }}</b>
+|--Order No: <code><b>{{ trade.OrderNo }}</b></code>
+|--Status: <b>{{ trade.OrderStatusUI }}</b>
+{%- if trade.Tx %}
+|--Tx hash: <code>{{ trade.Tx }}</code>{% endif %}
+|--Time: <b>{{ trade.Timestamp|formatTime }}</b>
+{% endfor %}
+`,
+}
 
-func RanderListTradeHistory(trades []model.TradeHistoryInner, page, pageSize int) (string, error) {
+func RanderListTradeHistory(lang string, trades []model.TradeHistoryInner, page, pageSize int) (string, error) {
 	// Compile the template first (i. e. creating the AST)
-	tpl, err := pongo2.FromString(listTradeHistoryTemplate)
+	tpl, err := fromLang(listTradeHistoryTemplate, lang)
 	if err != nil {
 		log.Error().Err(err).Send()
 		return "", ErrRander
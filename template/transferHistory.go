@@ -4,11 +4,13 @@ import (
 	"fmt"
 
 	"github.com/flosch/pongo2/v6"
+	"github.com/hellodex/tradingbot/i18n"
 	"github.com/hellodex/tradingbot/model"
 	"github.com/rs/zerolog/log"
 )
 
-var listTransferHistoryTemplate = `
+var listTransferHistoryTemplate = map[string]string{
+	i18n.ZH: `
 最近{{size}}笔交易记录：
 {% for trade in transfers | slice:slice_range %}
 {{ trade.Chain }}
@@ -21,11 +23,26 @@ var listTransferHistoryTemplate = `
 |--交易哈希: <code>{{ trade.Hash }}</code>{% endif %}
 |--时间: <b>{{ trade.Timestamp|formatTime }}</b>
 {% endfor %}
-`
+`,
+	i18n.EN: `
+Last {{size}} transfers:
+{% for trade in transfers | slice:slice_range %}
+{{ trade.Chain }}
+📊 Transfer:
+|--Token: <b>{% if trade.TokenAddress %}{{ trade.TokenAddress }}{% else %}Native coin{% endif %}</b>
+|--Amount: <b>{{ trade.Amount|formatNumber }}</b>
+|--To: <b>{{ trade.ToAddress }}</b>
+|--Status: <b>{{ trade.OrderStatusUI }}</b>
+{%- if trade.Hash %}
+|--Tx hash: <code>{{ trade.Hash }}</code>{% endif %}
+|--Time: <b>{{ trade.Timestamp|formatTime }}</b>
+{% endfor %}
+`,
+}
 
-func RanderListTransferHistory(transfers []model.TransferHistoryInner, page, pageSize int) (string, error) {
+func RanderListTransferHistory(lang string, transfers []model.TransferHistoryInner, page, pageSize int) (string, error) {
 	// Compile the template first (i. e. creating the AST)
-	tpl, err := pongo2.FromString(listTransferHistoryTemplate)
+	tpl, err := fromLang(listTransferHistoryTemplate, lang)
 	if err != nil {
 		log.Error().Err(err).Send()
 		return "", ErrRander
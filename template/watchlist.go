@@ -0,0 +1,46 @@
+package template
+
+import (
+	"github.com/flosch/pongo2/v6"
+	"github.com/hellodex/tradingbot/api"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/rs/zerolog/log"
+)
+
+var watchlistTemplate = map[string]string{
+	i18n.ZH: `
+⭐ 自选列表 ({{ tokens|length }})
+{% for info in tokens %}
+{{ forloop.Counter }}. <a href="https://hellodex.io/k/{{ info.PairAddress }}?chainCode={{ info.ChainCode }}&timeType=15m">{{ info.Symbol }}</a> ({{ info.ChainCode | getChainName }})
+💵${{ info.Price | formatNumber }} | 5m {{ info.Chg5m | formatNumber }}% | 1h {{ info.Chg1h | formatNumber }}%
+💎市值: ${{ info.MarketCap | formatNumber }}
+{% endfor %}
+更新时间: {{ updateAt }}`,
+	i18n.EN: `
+⭐ Watchlist ({{ tokens|length }})
+{% for info in tokens %}
+{{ forloop.Counter }}. <a href="https://hellodex.io/k/{{ info.PairAddress }}?chainCode={{ info.ChainCode }}&timeType=15m">{{ info.Symbol }}</a> ({{ info.ChainCode | getChainName }})
+💵${{ info.Price | formatNumber }} | 5m {{ info.Chg5m | formatNumber }}% | 1h {{ info.Chg1h | formatNumber }}%
+💎MC: ${{ info.MarketCap | formatNumber }}
+{% endfor %}
+Updated: {{ updateAt }}`,
+}
+
+func RanderWatchlist(lang string, tokens []api.TokenInfo, updateAt string) (string, error) {
+	tpl, err := fromLang(watchlistTemplate, lang)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return "", ErrRander
+	}
+
+	out, err := tpl.Execute(pongo2.Context{
+		"tokens":   tokens,
+		"updateAt": updateAt,
+	})
+	if err != nil {
+		log.Error().Err(err).Send()
+		return "", ErrRander
+	}
+
+	return out, nil
+}
@@ -0,0 +1,161 @@
+package tokenfeed
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-telegram/bot/models"
+	"github.com/hellodex/tradingbot/alert"
+	"github.com/hellodex/tradingbot/entity"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/safety"
+	"github.com/hellodex/tradingbot/smartmoney"
+	"github.com/hellodex/tradingbot/store"
+	"github.com/hellodex/tradingbot/template"
+	"github.com/hellodex/tradingbot/util"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cast"
+)
+
+// 多个实例或者消费组收到同一个新币时每个用户只推送一次
+var feedClaimTTL = time.Hour
+
+// 新币推送排队处理, 安全检测和逐个发送不阻塞其他推送
+const queueLen = 1000
+
+var queue = make(chan model.PushEvent, queueLen)
+
+// Enqueue handle new listing in background, dropped if the queue is full.
+//
+// new tokens come from backend, the bot does not watch chains for them. backend publishes to push stream:
+//
+//	{"version": 2, "kind": "new_listing", "id": "...", "timestamp": 1700000000000, "payload": {
+//		"chainCode", "baseAddress", "pairAddress", "symbol", "dex", "price",
+//		"liquidity", "marketCap", "devHolding", "twitter", "telegram", "website"}}
+//
+// payload has no uuid, every instance sends it to feed users of its bots.
+// dex is launchpad name for launches (pumpfun, fourmeme), otherwise amm name.
+// liquidity and marketCap are in usd, devHolding is percent of supply held by creator
+func Enqueue(e model.PushEvent) {
+	select {
+	case queue <- e:
+	default:
+		log.Warn().Str("chainCode", e.ChainCode).Str("token", e.BaseAddress).Msg("token feed queue full, drop new listing")
+	}
+}
+
+// Run handle queued new listings until ctx done
+func Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e := <-queue:
+			Handle(e)
+		}
+	}
+}
+
+// UserFeed feed settings of user, default filters if not set
+func UserFeed(chatId int64) model.TokenFeed {
+	data, has := store.UserGetTokenFeed(chatId)
+	if !has {
+		return model.DefaultTokenFeed()
+	}
+	var f model.TokenFeed
+	if err := json.Unmarshal(data, &f); err != nil {
+		log.Error().Err(err).Send()
+		return model.DefaultTokenFeed()
+	}
+	return f
+}
+
+func SaveFeed(chatId int64, f model.TokenFeed) error {
+	data, err := f.JsonB()
+	if err != nil {
+		return err
+	}
+	return store.UserSetTokenFeed(chatId, data, f.Enabled)
+}
+
+// Handle push new listing to feed users hosted by this instance
+func Handle(e model.PushEvent) {
+	users, err := store.TokenFeedUsers()
+	if err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+
+	// 只在有用户需要时检测一次
+	var scan *model.TokenSafety
+	for _, chatId := range users {
+		if !alert.Hosted(chatId) {
+			continue
+		}
+		f := UserFeed(chatId)
+		if !f.Match(e) {
+			continue
+		}
+		if f.MaxRisk > 0 && scan == nil {
+			s := safety.Scan(e.ChainCode, e.BaseAddress, e.PairAddress)
+			scan = &s
+		}
+		if scan != nil && !f.RiskOK(*scan) {
+			continue
+		}
+
+		claimed, err := store.ClaimOnce("feed:"+cast.ToString(chatId)+":"+model.AddressKey(e.ChainCode, e.BaseAddress), feedClaimTTL)
+		if err != nil {
+			log.Error().Err(err).Send()
+		} else if !claimed {
+			continue
+		}
+		n, err := store.UserIncrTokenFeedCount(chatId, time.Now().UTC().Format("2006010215"))
+		if err != nil {
+			log.Error().Err(err).Send()
+			continue
+		}
+		if n > int64(f.HourLimit) {
+			continue
+		}
+		push(chatId, e, scan)
+	}
+}
+
+func push(chatId int64, e model.PushEvent, scan *model.TokenSafety) {
+	lang := i18n.UserLang(chatId)
+	text, _, err := template.RenderPush(lang, e)
+	if err != nil {
+		log.Error().Err(err).Int64("chatId", chatId).Msg("render token feed")
+		return
+	}
+	if scan != nil && !scan.Empty() {
+		text += i18n.T(lang, "tokenfeed.risk", scan.Score, i18n.T(lang, scan.LevelKey()))
+	}
+	kb := models.InlineKeyboardMarkup{InlineKeyboard: [][]models.InlineKeyboardButton{
+		smartmoney.QuickBuyRow(chatId, lang, e.ChainCode),
+		{
+			util.NewCallbackDataButton(i18n.T(lang, "aimonitor.pusher.trade"), "ai_sendNewupdate"),
+			entity.GetCallbackButtonLang(entity.TOKEN_FEED, lang),
+		},
+	}}
+
+	err = alert.Deliver(model.AlertPush{
+		ChatId: chatId,
+		Text:   text,
+		Req: &model.PusherHandlerReqData{
+			ChainCode:   e.ChainCode,
+			BaseAddress: e.BaseAddress,
+			MonitorType: e.Kind,
+		},
+		Markup: &kb,
+		Symbol: e.Symbol,
+		Type:   e.Type(),
+		Value:  e.Value().String(),
+	})
+	if err != nil {
+		log.Error().Err(err).Int64("chatId", chatId).Msg("deliver token feed")
+	}
+}
@@ -0,0 +1,114 @@
+package trade
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/hellodex/tradingbot/api"
+	"github.com/hellodex/tradingbot/i18n"
+	"github.com/hellodex/tradingbot/model"
+	"github.com/hellodex/tradingbot/safety"
+	"github.com/hellodex/tradingbot/util"
+	"github.com/shopspring/decimal"
+)
+
+// swap type of backend
+const (
+	SwapBuy       = "0"
+	SwapSell      = "1"
+	SwapTradeType = "M"
+)
+
+var (
+	ErrWalletChain   = errors.New("wallet not on chain of token")
+	ErrInsufficient  = errors.New("insufficient balance")
+	ErrSafetyBlocked = errors.New("token risk above block score")
+)
+
+// Wallet of user on chain by wallet id, false if the wallet is removed
+func Wallet(userInfo model.GetUserResp, chainCode, walletId string) (model.Wallet, bool) {
+	for _, w := range api.ListUserChainWallets(userInfo, chainCode) {
+		if w.WalletId == walletId {
+			return w, true
+		}
+	}
+	return model.Wallet{}, false
+}
+
+// DefaultWallet default wallet of user if it is on chain, otherwise the first wallet on chain
+func DefaultWallet(userInfo model.GetUserResp, chainCode string) (model.Wallet, bool) {
+	if w, ok := Wallet(userInfo, chainCode, userInfo.Data.TgDefaultWalletId); ok {
+		return w, true
+	}
+	wallets := api.ListUserChainWallets(userInfo, chainCode)
+	if len(wallets) == 0 {
+		return model.Wallet{}, false
+	}
+	return wallets[0], true
+}
+
+// Precheck run before every swap is queued, manual or automatic.
+// wallet must be on the chain of token, buy must pass safety block,
+// from token (native coin for buy) must have enough balance. amount is raw amount of from token
+func Precheck(chatId int64, userInfo model.GetUserResp, w model.Wallet, pos model.PositionDataInner, isBuy bool, amount decimal.Decimal) (model.TokenSafety, error) {
+	if pos.ChainCode != "" && w.ChainCode != pos.ChainCode {
+		return model.TokenSafety{}, ErrWalletChain
+	}
+
+	from := pos.BaseToken.Address
+	if isBuy {
+		if sf, blocked := safety.BuyBlocked(chatId, w.ChainCode, pos.BaseToken.Address, pos.PairAddress); blocked {
+			return sf, ErrSafetyBlocked
+		}
+		from = pos.QuoteToken.Address
+	}
+
+	balance, err := balanceOf(userInfo, w, from)
+	if err != nil {
+		return model.TokenSafety{}, err
+	}
+	if balance.LessThan(amount) {
+		return model.TokenSafety{}, ErrInsufficient
+	}
+	return model.TokenSafety{}, nil
+}
+
+// raw balance of token in wallet, wrapped native token is counted as native coin
+func balanceOf(userInfo model.GetUserResp, w model.Wallet, token string) (decimal.Decimal, error) {
+	tokens, err := api.GetTokensByWalletAddress(w.Wallet, w.ChainCode, userInfo)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	native := util.IsNativeCoion(token)
+	if !native {
+		chains, err := api.GetChainConfigs()
+		if err != nil {
+			return decimal.Zero, err
+		}
+		if cfg, ok := chains.Get(w.ChainCode); ok {
+			native = cfg.IsNative(token)
+		}
+	}
+	for _, t := range tokens.Data {
+		if (native && util.IsNativeCoion(t.Address)) || (!native && strings.EqualFold(t.Address, token)) {
+			balance, _ := decimal.NewFromString(t.Amount)
+			return balance, nil
+		}
+	}
+	return decimal.Zero, nil
+}
+
+// Reason text of Precheck error, symbol is the token to buy or sell
+func Reason(chatId int64, err error, symbol string, sf model.TokenSafety) string {
+	switch {
+	case errors.Is(err, ErrWalletChain):
+		return i18n.L(chatId, "trade.reason_chain")
+	case errors.Is(err, ErrInsufficient):
+		return i18n.L(chatId, "trade.reason_balance")
+	case errors.Is(err, ErrSafetyBlocked):
+		return i18n.L(chatId, "trade.reason_safety", symbol, sf.Score)
+	default:
+		return i18n.L(chatId, "common.error")
+	}
+}
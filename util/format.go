@@ -228,3 +228,11 @@ func FormatPercentage(per string) string {
 	perStr := strconv.FormatFloat(percentageRaw, 'f', 0, 64)
 	return perStr + "%"
 }
+
+// 0x1234..abcd
+func ShortAddress(addr string) string {
+	if len(addr) <= 10 {
+		return addr
+	}
+	return addr[:4] + ".." + addr[len(addr)-4:]
+}
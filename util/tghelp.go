@@ -10,6 +10,7 @@ import (
 	"github.com/go-telegram/bot/models"
 	"github.com/hellodex/tradingbot/config"
 	"github.com/hellodex/tradingbot/entity"
+	"github.com/hellodex/tradingbot/i18n"
 	"github.com/hellodex/tradingbot/store"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cast"
@@ -34,9 +35,9 @@ func NewCallbackDataButton(text, callbackData string) models.InlineKeyboardButto
 	}
 }
 
-func BackToMainMenu() models.InlineKeyboardButton {
+func BackToMainMenu(lang string) models.InlineKeyboardButton {
 	return models.InlineKeyboardButton{
-		Text:         "返回主菜单",
+		Text:         i18n.T(lang, "common.back_main"),
 		CallbackData: "backToMainMenu",
 	}
 }
@@ -66,6 +67,40 @@ type BuySellKeyBoardData struct {
 	QuoteToken          string // 对手代币，如 USDC
 	QuoteTokenChainCode string
 	QuoteTokenAddress   string
+	BuyPresets          []string // 快捷买入数额, 为空使用默认
+	SellPresets         []string // 快捷卖出百分比, 为空使用默认
+	Lang                string
+}
+
+var (
+	defaultBuyPresets  = []string{"0.1", "0.5", "1", "5", "10"}
+	defaultSellPresets = []string{"50", "100"}
+)
+
+// telegram callback data max 64 bytes
+const maxCallbackDataLen = 64
+
+// split buttons to lines, 3 buttons per line
+func chunkButtons(buttons []models.InlineKeyboardButton, size int) [][]models.InlineKeyboardButton {
+	var lines [][]models.InlineKeyboardButton
+	for i := 0; i < len(buttons); i += size {
+		end := min(i+size, len(buttons))
+		lines = append(lines, buttons[i:end])
+	}
+	return lines
+}
+
+func presetButtons(presets []string, callbackPrefix string, text func(string) string) []models.InlineKeyboardButton {
+	var buttons []models.InlineKeyboardButton
+	for _, p := range presets {
+		callbackData := callbackPrefix + p
+		if len(callbackData) > maxCallbackDataLen {
+			log.Error().Str("callbackData", callbackData).Msg("callback Data is too long")
+			continue
+		}
+		buttons = append(buttons, button(text(p), callbackData))
+	}
+	return buttons
 }
 
 func UrlButton(text, url string) models.InlineKeyboardButton {
@@ -84,68 +119,71 @@ func button(text, callbackData string) models.InlineKeyboardButton {
 
 func BuySellKeyBoard(data BuySellKeyBoardData) models.InlineKeyboardMarkup {
 	var kb models.InlineKeyboardMarkup
-	var titleLine, buyLine, lineTwo, lineThree, sellLine []models.InlineKeyboardButton
+	var titleLine, buyLine, sellLine []models.InlineKeyboardButton
 
 	titleLine = []models.InlineKeyboardButton{
-		entity.GetCallbackButton(entity.ReflashTokenInfo),
+		entity.GetCallbackButtonLang(entity.ReflashTokenInfo, data.Lang),
 		// button("📊看K线", "k_line"),
 		{
-			Text: "📊看K线",
-			URL:  fmt.Sprintf("%s%s?chainCode=%s", config.YmlConfig.Env.KchartUrl, data.PoolAddress, data.BaseTokenChainCode),
+			Text: i18n.T(data.Lang, "token.kline"),
+			URL:  fmt.Sprintf("%s%s?chainCode=%s", config.Get().Env.KchartUrl, data.PoolAddress, data.BaseTokenChainCode),
 		},
+		button(i18n.T(data.Lang, "chart.button"), "chart::15m::"+data.BaseTokenAddress),
+		button(i18n.T(data.Lang, "watchlist.watch"), "watch::add::"+data.BaseTokenAddress),
 	}
 
 	strTime := cast.ToString(time.Now().Unix())
 
 	buyLine = []models.InlineKeyboardButton{
-		button("----🟢买----", strTime),
+		button(i18n.T(data.Lang, "trade.buy_title"), strTime),
 	}
 
 	sellLine = []models.InlineKeyboardButton{
 		// button(fmt.Sprintf("----🔴卖( %s )----", data.BaseToken), "none"),
-		button("----🔴卖----", "none"),
+		button(i18n.T(data.Lang, "trade.sell_title"), "none"),
 	}
-	lineTwo = []models.InlineKeyboardButton{
-		button("买 0.1 "+data.QuoteToken, data.BuyCallBackData+"0.1"),
-		button("买 0.5 "+data.QuoteToken, data.BuyCallBackData+"0.5"),
-		button("买 1 "+data.QuoteToken, data.BuyCallBackData+"1"),
+
+	buyPresets := data.BuyPresets
+	if len(buyPresets) == 0 {
+		buyPresets = defaultBuyPresets
 	}
-	lineThree = []models.InlineKeyboardButton{
-		button("买 5 "+data.QuoteToken, data.BuyCallBackData+"5"),
-		button("买 10 "+data.QuoteToken, data.BuyCallBackData+"10"),
-		button("买 x "+data.QuoteToken, data.BuyCallBackData+"x"),
+	sellPresets := data.SellPresets
+	if len(sellPresets) == 0 {
+		sellPresets = defaultSellPresets
 	}
 
+	buyButtons := presetButtons(buyPresets, data.BuyCallBackData, func(p string) string {
+		return i18n.T(data.Lang, "trade.buy_preset", p, data.QuoteToken)
+	})
+	buyButtons = append(buyButtons, button(i18n.T(data.Lang, "trade.buy_preset", "x", data.QuoteToken), data.BuyCallBackData+"x"))
+
 	// lineFive := []models.InlineKeyboardButton{
 	// 	button("卖10%", data.SellCallBackData+"10"),
 	// 	button("卖25%", data.SellCallBackData+"25"),
 	// 	button("卖x", data.SellCallBackData+"y"),
 	// }
 
-	lineSix := []models.InlineKeyboardButton{
-		button("卖50%", data.SellCallBackData+"50"),
-		button("卖100%", data.SellCallBackData+"100"),
-		button("卖x%", data.SellCallBackData+"x"),
-	}
+	sellButtons := presetButtons(sellPresets, data.SellCallBackData, func(p string) string {
+		return i18n.T(data.Lang, "trade.sell_preset", p)
+	})
+	sellButtons = append(sellButtons, button(i18n.T(data.Lang, "trade.sell_preset", "x"), data.SellCallBackData+"x"))
 
 	lineTransfer := []models.InlineKeyboardButton{
 		// button("🔴转出 "+data.BaseToken, "tx_"+data.BaseTokenAddress),
 		// button("📌挂单 "+data.BaseToken, "order_"+data.BaseTokenAddress),
-		button("🔴转出", "tx_"+data.BaseTokenAddress),
-		button("📌挂单", "order_"+data.BaseTokenAddress),
-		button("资产列表", entity.ASSETS),
+		button(i18n.T(data.Lang, "trade.transfer"), "tx_"+data.BaseTokenAddress),
+		button(i18n.T(data.Lang, "trade.limit_order"), "order_"+data.BaseTokenAddress),
+		button(i18n.T(data.Lang, "trade.asset_list"), entity.ASSETS),
 	}
 
 	kb.InlineKeyboard = [][]models.InlineKeyboardButton{
 		titleLine,
 		buyLine,
-		lineTwo,
-		lineThree,
-		sellLine,
-		// lineFive,
-		lineSix,
-		lineTransfer,
 	}
+	kb.InlineKeyboard = append(kb.InlineKeyboard, chunkButtons(buyButtons, 3)...)
+	kb.InlineKeyboard = append(kb.InlineKeyboard, sellLine)
+	kb.InlineKeyboard = append(kb.InlineKeyboard, chunkButtons(sellButtons, 3)...)
+	kb.InlineKeyboard = append(kb.InlineKeyboard, lineTransfer)
 
 	// log.Debug().Func(func(e *zerolog.Event) {
 	// 	txe := logger.WithTxCategory(e)
@@ -158,30 +196,30 @@ func BuySellKeyBoard(data BuySellKeyBoardData) models.InlineKeyboardMarkup {
 	return kb
 }
 
-func GetLimitOrderPrefixText(callbackData string) string {
+func GetLimitOrderPrefixText(lang string, callbackData string) string {
 	switch callbackData {
 	case "limitOrder_sell_3":
-		return "止盈"
+		return i18n.T(lang, "order.limit_type.take_profit")
 	case "limitOrder_sell_4":
-		return "止损"
+		return i18n.T(lang, "order.limit_type.stop_loss")
 	case "limitOrder_buy_2":
-		return "抄底"
+		return i18n.T(lang, "order.limit_type.dip")
 	case "limitOrder_buy_1":
-		return "高于买入"
+		return i18n.T(lang, "order.limit_type.above")
 	}
 	return ""
 }
 
-func LimitOrderKeyBoard() models.InlineKeyboardMarkup {
+func LimitOrderKeyBoard(lang string) models.InlineKeyboardMarkup {
 	var kb models.InlineKeyboardMarkup
 
 	kb.InlineKeyboard = append(kb.InlineKeyboard, []models.InlineKeyboardButton{
-		button("止盈", "limitOrder_sell_3"),
-		button("止损", "limitOrder_sell_4"),
+		button(i18n.T(lang, "order.limit_type.take_profit"), "limitOrder_sell_3"),
+		button(i18n.T(lang, "order.limit_type.stop_loss"), "limitOrder_sell_4"),
 	})
 	kb.InlineKeyboard = append(kb.InlineKeyboard, []models.InlineKeyboardButton{
-		button("抄底", "limitOrder_buy_2"),
-		button("高于买入", "limitOrder_buy_1"),
+		button(i18n.T(lang, "order.limit_type.dip"), "limitOrder_buy_2"),
+		button(i18n.T(lang, "order.limit_type.above"), "limitOrder_buy_1"),
 	})
 
 	// log.Debug().Func(func(e *zerolog.Event) {
@@ -217,19 +255,20 @@ func CallBackAnswer(ctx context.Context, b *bot.Bot, callbackQuery *models.Callb
 	log.Info().Msg("callbackAnswer")
 }
 
-func NewAiMonitorPusherButton() models.InlineKeyboardMarkup {
+func NewAiMonitorPusherButton(lang string) models.InlineKeyboardMarkup {
 	var kb models.InlineKeyboardMarkup
 
 	// 第一行：三个监控操作按钮
 	row1 := []models.InlineKeyboardButton{
-		button("暂停监控", "pusher_pause"),
-		button("编辑监控", "pusher_edit"),
-		button("删除监控", "pusher_delete"),
+		button(i18n.T(lang, "aimonitor.pusher.pause"), "pusher_pause"),
+		button(i18n.T(lang, "aimonitor.pusher.edit"), "pusher_edit"),
+		button(i18n.T(lang, "aimonitor.pusher.delete"), "pusher_delete"),
 	}
 
 	// 第二行：两个交易相关按钮
 	row2 := []models.InlineKeyboardButton{
-		button("👉一键交易/挂单交易", "ai_sendNewupdate"),
+		button(i18n.T(lang, "aimonitor.pusher.trade"), "ai_sendNewupdate"),
+		button(i18n.T(lang, "alert.action_button"), "aa::push"),
 	}
 
 	kb.InlineKeyboard = [][]models.InlineKeyboardButton{
@@ -240,12 +279,12 @@ func NewAiMonitorPusherButton() models.InlineKeyboardMarkup {
 	return kb
 }
 
-func NewAiMonitorKeyboard() models.InlineKeyboardMarkup {
+func NewAiMonitorKeyboard(lang string) models.InlineKeyboardMarkup {
 	var kb models.InlineKeyboardMarkup
 	// define delivery method buttons
 	one := []models.InlineKeyboardButton{
-		button("监控列表", "ai_monitor_list"),
-		button("添加监控", "ai_add_monitor"),
+		button(i18n.T(lang, "code::aiMonitorList"), "ai_monitor_list"),
+		button(i18n.T(lang, "code::addAiMonitor"), "ai_add_monitor"),
 	}
 	kb.InlineKeyboard = [][]models.InlineKeyboardButton{
 		one,
@@ -257,10 +296,10 @@ func AiMonitorDeliverLineMarkup(data SettingsKeyBoardData) models.InlineKeyboard
 	var kb models.InlineKeyboardMarkup
 	// define delivery method buttons
 	deliveryline := []models.InlineKeyboardButton{
-		button("推送:", "none"),
-		GetToggleButton("TG", data.EnableTG),
-		GetToggleButton("网页", data.EnableWeb),
-		GetToggleButton("APP", data.EnableApp),
+		button(i18n.T(data.Lang, "aimonitor.push_label"), "none"),
+		GetToggleButton(data.Lang, "TG", data.EnableTG),
+		GetToggleButton(data.Lang, "网页", data.EnableWeb),
+		GetToggleButton(data.Lang, "APP", data.EnableApp),
 	}
 	// Combine all lines into keyboard
 	kb.InlineKeyboard = [][]models.InlineKeyboardButton{
@@ -275,24 +314,24 @@ func AiMonitorSettingsKeyBoard(data SettingsKeyBoardData) models.InlineKeyboardM
 
 	// define delivery method buttons
 	deliveryline := []models.InlineKeyboardButton{
-		button("推送:", "none"),
-		GetToggleButton("TG", data.EnableTG),
-		GetToggleButton("网页", data.EnableWeb),
-		GetToggleButton("APP", data.EnableApp),
+		button(i18n.T(data.Lang, "aimonitor.push_label"), "none"),
+		GetToggleButton(data.Lang, "TG", data.EnableTG),
+		GetToggleButton(data.Lang, "网页", data.EnableWeb),
+		GetToggleButton(data.Lang, "APP", data.EnableApp),
 	}
 
 	// Define frequency buttons
 	frequencyLine := []models.InlineKeyboardButton{
-		button("频率:", "none"),
-		GetFreqButton("一次", data.Frequency == "once"),
-		GetFreqButton("每天1次", data.Frequency == "daily"),
-		GetFreqButton("每次", data.Frequency == "every"),
+		button(i18n.T(data.Lang, "aimonitor.freq_label"), "none"),
+		GetFreqButton(data.Lang, "一次", data.Frequency == "once"),
+		GetFreqButton(data.Lang, "每天1次", data.Frequency == "daily"),
+		GetFreqButton(data.Lang, "每次", data.Frequency == "every"),
 	}
 
 	// Action buttons
 	actionLine := []models.InlineKeyboardButton{
-		button("添加代币推送", "add_token_alert"),
-		button("取消设置", "cancel_settings"),
+		button(i18n.T(data.Lang, "aimonitor.add_alert"), "add_token_alert"),
+		button(i18n.T(data.Lang, "aimonitor.cancel_setting"), "cancel_settings"),
 	}
 
 	// Combine all lines into keyboard
@@ -310,41 +349,41 @@ func AiMonitor_EditSettingsKeyBoard(data SettingsKeyBoardData, monitorType strin
 
 	var viewText string
 	if monitorType == "price" {
-		viewText = "目标价格"
+		viewText = i18n.T(data.Lang, "aimonitor.view.price")
 	} else if monitorType == "chg" {
-		viewText = "目标涨幅"
+		viewText = i18n.T(data.Lang, "aimonitor.view.chg")
 	} else if monitorType == "buy" {
-		viewText = "买入交易额"
+		viewText = i18n.T(data.Lang, "aimonitor.view.buy")
 	} else {
-		viewText = "卖出交易额"
+		viewText = i18n.T(data.Lang, "aimonitor.view.sell")
 	}
 
 	// define delivery method buttons
 	deliveryline := []models.InlineKeyboardButton{
-		button("推送:", "none"),
-		GetToggleButton("TG", data.EnableTG),
-		GetToggleButton("网页", data.EnableWeb),
-		GetToggleButton("APP", data.EnableApp),
+		button(i18n.T(data.Lang, "aimonitor.push_label"), "none"),
+		GetToggleButton(data.Lang, "TG", data.EnableTG),
+		GetToggleButton(data.Lang, "网页", data.EnableWeb),
+		GetToggleButton(data.Lang, "APP", data.EnableApp),
 	}
 
 	// Define frequency buttons
 	frequencyLine := []models.InlineKeyboardButton{
-		button("频率:", "none"),
-		GetFreqButton("一次", data.Frequency == "once"),
-		GetFreqButton("每天1次", data.Frequency == "daily"),
-		GetFreqButton("每次", data.Frequency == "every"),
+		button(i18n.T(data.Lang, "aimonitor.freq_label"), "none"),
+		GetFreqButton(data.Lang, "一次", data.Frequency == "once"),
+		GetFreqButton(data.Lang, "每天1次", data.Frequency == "daily"),
+		GetFreqButton(data.Lang, "每次", data.Frequency == "every"),
 	}
 
 	// Action buttons
 	actionLine := []models.InlineKeyboardButton{
-		button(fmt.Sprintf("编辑%s", viewText), "edit_current_"+monitorType),
-		button("保存更新", "save_current_"+monitorType),
+		button(i18n.T(data.Lang, "aimonitor.edit", viewText), "edit_current_"+monitorType),
+		button(i18n.T(data.Lang, "aimonitor.save"), "save_current_"+monitorType),
 	}
 
 	if noticeType == 0 {
-		actionLine = append(actionLine, button("启动推送", "enable_current_"+monitorType), button("删除推送", "delete_current_"+monitorType))
+		actionLine = append(actionLine, button(i18n.T(data.Lang, "aimonitor.enable"), "enable_current_"+monitorType), button(i18n.T(data.Lang, "aimonitor.delete"), "delete_current_"+monitorType))
 	} else {
-		actionLine = append(actionLine, button("暂停推送", "pause_current_"+monitorType), button("删除推送", "delete_current_"+monitorType))
+		actionLine = append(actionLine, button(i18n.T(data.Lang, "aimonitor.pause"), "pause_current_"+monitorType), button(i18n.T(data.Lang, "aimonitor.delete"), "delete_current_"+monitorType))
 	}
 
 	// Combine all lines into keyboard
@@ -352,18 +391,19 @@ func AiMonitor_EditSettingsKeyBoard(data SettingsKeyBoardData, monitorType strin
 		deliveryline,
 		frequencyLine,
 		actionLine,
+		{button(i18n.T(data.Lang, "alert.action_button"), "aa::edit")},
 	}
 
 	return kb
 }
 
-func WithdrawalKeyBoard() models.InlineKeyboardMarkup {
+func WithdrawalKeyBoard(lang string) models.InlineKeyboardMarkup {
 	var kb models.InlineKeyboardMarkup
 
 	// define delivery method buttons
 	line := []models.InlineKeyboardButton{
-		button("取消提现", "withdrawal_no"),
-		button("确认提现", "withdrawal_yes"),
+		button(i18n.T(lang, "withdrawal.cancel"), "withdrawal_no"),
+		button(i18n.T(lang, "withdrawal.confirm"), "withdrawal_yes"),
 	}
 
 	// Combine all lines into keyboard
@@ -374,24 +414,37 @@ func WithdrawalKeyBoard() models.InlineKeyboardMarkup {
 	return kb
 }
 
+// callback data keep the chinese text, only display text translated
+var toggleTextKey = map[string]string{
+	"TG":  "aimonitor.channel.tg",
+	"网页":  "aimonitor.channel.web",
+	"APP": "aimonitor.channel.app",
+}
+
+var freqTextKey = map[string]string{
+	"一次":   "aimonitor.freq.once",
+	"每天1次": "aimonitor.freq.daily",
+	"每次":   "aimonitor.freq.every",
+}
+
 // Helper function to create toggle buttons with checkmark or cross
-func GetToggleButton(text string, enabled bool) models.InlineKeyboardButton {
+func GetToggleButton(lang string, text string, enabled bool) models.InlineKeyboardButton {
 	var displayText string
 	if enabled {
-		displayText = "✅ " + text
+		displayText = "✅ " + i18n.T(lang, toggleTextKey[text])
 	} else {
-		displayText = "❌ " + text
+		displayText = "❌ " + i18n.T(lang, toggleTextKey[text])
 	}
 	return button(displayText, "toggle_"+strings.ToUpper(text))
 }
 
 // Helper function to create frequency selection buttons
-func GetFreqButton(text string, selected bool) models.InlineKeyboardButton {
+func GetFreqButton(lang string, text string, selected bool) models.InlineKeyboardButton {
 	var displayText string
 	if selected {
-		displayText = "✅ " + text
+		displayText = "✅ " + i18n.T(lang, freqTextKey[text])
 	} else {
-		displayText = text
+		displayText = i18n.T(lang, freqTextKey[text])
 	}
 	switch text {
 	case "一次":
@@ -410,4 +463,5 @@ type SettingsKeyBoardData struct {
 	EnableWeb bool
 	EnableApp bool
 	Frequency string // "once", "daily", "every"
+	Lang      string
 }
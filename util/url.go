@@ -1,6 +1,9 @@
 package util
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 func GetChainScanUrl(chainCode string, hash string) string {
 	var baseUrl string
@@ -26,3 +29,11 @@ func GetChainScanUrl(chainCode string, hash string) string {
 }
 
 var AdminUrl string = `<a href="https://t.me/HelloDex_cn">点击联系客服</a>`
+
+// deep link to bot with token and invitation code, start=B_<addr>_I_<code>
+func TokenStartLink(botUserName string, address string, inviteCode string) string {
+	if inviteCode == "" {
+		return fmt.Sprintf("https://t.me/%s?start=B_%s", botUserName, address)
+	}
+	return fmt.Sprintf("https://t.me/%s?start=B_%s_I_%s", botUserName, address, inviteCode)
+}
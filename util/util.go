@@ -9,6 +9,7 @@ import (
 	"errors"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gagliardetto/solana-go"
@@ -19,7 +20,7 @@ import (
 )
 
 func IsDebug() bool {
-	return os.Getenv("DEBUG") == "true" || config.YmlConfig.Env.Debug == "true"
+	return os.Getenv("DEBUG") == "true" || config.Get().Env.Debug == "true"
 }
 
 func IsCommand(str string) bool {
@@ -77,15 +78,15 @@ func UserIDtoByte(userID int64) []byte {
 }
 
 func Encrypt(userID int64) (string, error) {
-	if userID == config.YmlConfig.Env.BotMaker {
+	if userID == config.Get().Env.BotMaker {
 		return "test user", nil
 	}
 	s := cast.ToString(userID)
-	loadKey, err := base64.StdEncoding.DecodeString(config.YmlConfig.Env.AesKey)
+	loadKey, err := base64.StdEncoding.DecodeString(config.Get().Env.AesKey)
 	if err != nil {
 		log.Fatal().Err(err).Send()
 	}
-	if config.YmlConfig.Env.Encrypt_open {
+	if config.Get().Env.Encrypt_open {
 		return encrypt(loadKey, s)
 	}
 	return base64.StdEncoding.EncodeToString([]byte(s)), nil
@@ -97,7 +98,7 @@ func encrypt(key []byte, plaintext string) (string, error) {
 		return "", err
 	}
 
-	loadNonce, err := base64.StdEncoding.DecodeString(config.YmlConfig.Env.Nonce)
+	loadNonce, err := base64.StdEncoding.DecodeString(config.Get().Env.Nonce)
 	if err != nil {
 		log.Fatal().Err(err).Send()
 	}
@@ -140,6 +141,23 @@ func CheckValidAddress(address string) (isSolana bool, err error) {
 	return false, errors.New("无法识别的地址格式")
 }
 
+var ErrAddressChainNotMatch = errors.New("address not match chain")
+
+// CheckChainAddress check address format and chain, SOLANA use base58 address, others use evm address
+func CheckChainAddress(chainCode, address string) error {
+	if IsNativeCoion(address) {
+		return errors.New("native coin address")
+	}
+	isSolana, err := CheckValidAddress(address)
+	if err != nil {
+		return err
+	}
+	if isSolana != (chainCode == "SOLANA") {
+		return ErrAddressChainNotMatch
+	}
+	return nil
+}
+
 var NativeSol = "11111111111111111111111111111111"
 var NativeEvm = "0xeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee"
 
@@ -175,3 +193,24 @@ func IsNativeCoion(addr string) bool {
 func CtxWithValue(ctx context.Context, k any, value any) context.Context {
 	return context.WithValue(ctx, k, value)
 }
+
+// ParallelLimit run fn(0..n-1) with at most limit goroutines, wait all done
+func ParallelLimit(limit int, n int, fn func(i int)) {
+	if limit <= 0 {
+		limit = 1
+	}
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}